@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Arthx-x/arthxrecon/internal/distributed"
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agentCoordinator string // URL do coordinator
+	agentNetworks    string // Redes que o agent alcança (CIDRs ou arquivo)
+	agentName        string // Nome exibido no coordinator
+	agentTokenFile   string // Arquivo com o token compartilhado
+	agentCACert      string // CA do certificado do coordinator
+	agentMaxRate     int    // Teto local de pacotes por segundo (0 = o do coordinator)
+	agentKeepRunning bool   // Aguarda a próxima execução quando o coordinator termina
+)
+
+// AgentCmd conecta-se ao coordinator, executa os chunks de host discovery e port scan das redes
+// que alcança com as estratégias locais e devolve os resultados.
+var AgentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: util.AgentAppDescription,
+	Run: func(cmd *cobra.Command, args []string) {
+		if agentCoordinator == "" {
+			log.Fatal().Msgf("%s no coordinator: set --coordinator or distributed.coordinator", util.FatalErrAgent)
+		}
+		if agentNetworks == "" {
+			log.Fatal().Msgf("%s no networks: set --networks with the subnets this agent reaches", util.FatalErrAgent)
+		}
+		scope, err := util.LoadScope(agentNetworks)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrAgent, err)
+		}
+		networks := make([]string, 0, len(scope))
+		for _, network := range scope {
+			networks = append(networks, network.String())
+		}
+		token, generated, err := loadToken(util.AgentTokenEnv, agentTokenFile)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrAgent, err)
+		}
+		if generated {
+			log.Fatal().Msgf("%s no token: set $%s or distributed.token_file with the coordinator token", util.FatalErrAgent, util.AgentTokenEnv)
+		}
+
+		agent, err := distributed.NewAgent(distributed.AgentParams{
+			Coordinator: agentCoordinator,
+			Token:       token,
+			Name:        agentName,
+			Networks:    networks,
+			CACert:      agentCACert,
+			KeepRunning: agentKeepRunning,
+			Build:       buildChunkStages,
+		})
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrAgent, err)
+		}
+
+		fmt.Printf("%s %s Connecting to %s\n", util.MarkerCyan, util.GetFormattedTime(), util.Cyan(agentCoordinator))
+
+		// Ctrl+C (ou SIGTERM) encerra o agent; o chunk em execução volta à fila do coordinator.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := agent.Run(ctx); err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrAgent, err)
+		}
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+// buildChunkStages monta as etapas locais de um chunk com as opções de scan do coordinator. O
+// --max-rate do agent limita o do coordinator, e os arquivos levam o ID do chunk no nome.
+func buildChunkStages(chunk distributed.Chunk) ([]fullrecon.Stage, error) {
+	spec := chunk.Scan
+	if chunk.Kind != distributed.KindDiscovery && chunk.Kind != distributed.KindPortScan {
+		return nil, fmt.Errorf("unknown chunk kind %q", chunk.Kind)
+	}
+	if err := validateScanOptions(spec.Mode, spec.Category, spec.PortList, spec.UDPPorts); err != nil {
+		return nil, err
+	}
+	maxRate := spec.MaxRate
+	if agentMaxRate > 0 && (maxRate == 0 || agentMaxRate < maxRate) {
+		maxRate = agentMaxRate
+	}
+	return buildStages(reconOptions{
+		Discovery:  chunk.Kind == distributed.KindDiscovery,
+		PortScan:   chunk.Kind == distributed.KindPortScan,
+		Output:     "agent-" + chunk.ID,
+		Engine:     spec.Engine,
+		Mode:       spec.Mode,
+		PortList:   spec.PortList,
+		UDPPorts:   spec.UDPPorts,
+		Category:   spec.Category,
+		SimpleScan: spec.SimpleScan,
+		Scripts:    spec.Scripts,
+		Options:    spec.Options,
+		MaxRate:    maxRate,
+	})
+}
+
+func init() {
+	AgentCmd.Flags().StringVar(&agentCoordinator, "coordinator", "", "URL of the coordinator (e.g., https://10.0.0.5:8090)")
+	AgentCmd.Flags().StringVarP(&agentNetworks, "networks", "n", "", "Subnets this agent reaches, or path to file with subnets (for multiple, separate by commas)")
+	AgentCmd.Flags().StringVar(&agentName, "name", "", "Name of the agent shown by the coordinator (default hostname)")
+	AgentCmd.Flags().StringVar(&agentTokenFile, "token-file", "", "File holding the token shared with the coordinator (default $"+util.AgentTokenEnv+")")
+	AgentCmd.Flags().StringVar(&agentCACert, "ca-cert", "", "CA certificate (PEM) trusted for the coordinator certificate")
+	AgentCmd.Flags().IntVar(&agentMaxRate, "max-rate", 0, "Local cap on the packets per second requested by the coordinator (0 keeps it)")
+	AgentCmd.Flags().BoolVar(&agentKeepRunning, "keep-running", false, "Wait for the next coordinator run instead of exiting when it finishes")
+	configFlag(AgentCmd.Flags(), "coordinator", "distributed.coordinator")
+	configFlag(AgentCmd.Flags(), "token-file", "distributed.token_file")
+	configFlag(AgentCmd.Flags(), "ca-cert", "distributed.ca_cert")
+	configFlag(AgentCmd.Flags(), "max-rate", "limits.max_rate")
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+var (
+	auditFormat string // Formato da exportação: json, csv ou markdown
+	auditOutput string // Arquivo de saída da exportação (padrão: stdout)
+	auditRun    string // Exporta apenas a execução informada (run_id)
+	auditSince  string // Exporta apenas as entradas a partir da data (YYYY-MM-DD)
+)
+
+// AuditCmd agrupa os comandos do log de auditoria.
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: util.AuditAppDescription,
+}
+
+// AuditExportCmd exporta o log de auditoria para o relatório final.
+var AuditExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the audit log (json, csv or markdown) for the final report",
+	Run: func(cmd *cobra.Command, args []string) {
+		records := readAudit()
+		var since time.Time
+		if auditSince != "" {
+			var err error
+			if since, err = time.ParseInLocation("2006-01-02", auditSince, time.Local); err != nil {
+				log.Fatal().Msgf("Invalid --since date %q (expected YYYY-MM-DD)", auditSince)
+			}
+		}
+		var selected []util.AuditRecord
+		for _, r := range records {
+			if (auditRun == "" || r.RunID == auditRun) && !r.Time.Before(since) {
+				selected = append(selected, r)
+			}
+		}
+
+		var out io.Writer = os.Stdout
+		if auditOutput != "" {
+			file, err := os.Create(auditOutput)
+			if err != nil {
+				log.Fatal().Msgf("Failed to create %s: %v", auditOutput, err)
+			}
+			defer file.Close()
+			out = file
+		}
+		if err := util.ExportAudit(out, selected, auditFormat); err != nil {
+			log.Fatal().Msgf("%v", err)
+		}
+		if auditOutput != "" {
+			fmt.Printf("%s Creating: %s (%s entries)\n", util.MarkerGreen, util.Green(auditOutput), strconv.Itoa(len(selected)))
+		}
+	},
+}
+
+// AuditVerifyCmd confere o encadeamento de hashes do log de auditoria.
+var AuditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Checks the hash chain of the audit log and prints its head hash",
+	Run: func(cmd *cobra.Command, args []string) {
+		records := readAudit()
+		broken := 0
+		for _, r := range records {
+			if !r.Valid {
+				broken++
+				fmt.Printf("%s Line %d (%s, %s): does not match the previous line\n", util.MarkerRed, r.Line, r.Event, r.Time.Local().Format("2006-01-02 15:04:05"))
+			}
+		}
+		fmt.Printf("%s Entries: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(records))))
+		fmt.Printf("%s Head   : %s\n", util.MarkerGreen, util.Green(util.AuditHead(records)))
+		if broken > 0 {
+			log.Fatal().Msgf("Audit log %s has %d broken entries", util.AppConfig.Audit.File, broken)
+		}
+		fmt.Printf("%s Hash chain verified\n", util.MarkerGreen)
+	},
+}
+
+// readAudit lê o log de auditoria configurado em audit.file.
+func readAudit() []util.AuditRecord {
+	records, err := util.ReadAudit(util.AppConfig.Audit.File)
+	if err != nil {
+		log.Fatal().Msgf("Failed to read audit log: %v", err)
+	}
+	return records
+}
+
+func init() {
+	AuditExportCmd.Flags().StringVarP(&auditFormat, "format", "f", "markdown", "Export format: "+strings.Join(util.AuditFormats, ", "))
+	AuditExportCmd.Flags().StringVarP(&auditOutput, "output", "o", "", "Output file (default: stdout)")
+	AuditExportCmd.Flags().StringVar(&auditRun, "run", "", "Export only the entries of this run ID")
+	AuditExportCmd.Flags().StringVar(&auditSince, "since", "", "Export only the entries from this date on (YYYY-MM-DD)")
+	AuditCmd.AddCommand(AuditExportCmd)
+	AuditCmd.AddCommand(AuditVerifyCmd)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/banner"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var bannerAll bool // Se definido, coleta banner de todas as portas TCP, não apenas das não identificadas
+
+// BannerCmd coleta banners das portas TCP abertas e identifica serviço e versão.
+var BannerCmd = &cobra.Command{
+	Use:   banner.ModuleName,
+	Short: "Grabs raw TCP banners and matches them against built-in signatures",
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts := loadEnumerationHosts()
+
+		grabber := banner.NewGrabber()
+		grabber.Timeout = enumConnTimeout()
+		grabber.Threads = enumThreads
+		grabber.All = bannerAll
+
+		fmt.Printf("\n%s Banner Grabbing", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		grabbed := grabber.Run(context.Background(), hosts)
+		for _, r := range grabbed {
+			identified := r.Service
+			if r.Product != "" {
+				identified += " " + r.Product + " " + r.Version
+			}
+			fmt.Printf("%s %s:%d %s %s\n", util.MarkerGreen, r.Address, r.Port, util.Cyan(identified), r.Banner)
+		}
+
+		saveEnumerationHosts(hosts)
+		fmt.Printf("%s Banners: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(grabbed))))
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	BannerCmd.Flags().BoolVarP(&bannerAll, "all", "a", false, "Grab banners from every open TCP port, not only the ones nmap could not identify")
+	EnumerationCmd.AddCommand(BannerCmd)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+var categoriesName string // Mostra apenas a categoria informada
+
+// CategoriesCmd lista as categorias de portas (configuradas e top-N) com as portas expandidas.
+var CategoriesCmd = &cobra.Command{
+	Use:   "categories",
+	Short: "Lists the port categories with their expanded ports",
+	Run: func(cmd *cobra.Command, args []string) {
+		names := util.AppConfig.CategoryNames()
+		if categoriesName != "" {
+			names = []string{categoriesName}
+		}
+		for _, name := range names {
+			tcp, udp, err := util.AppConfig.ExpandCategory(name)
+			if err != nil {
+				log.Fatal().Msgf("%s %v", util.FatalErrConfig, err)
+			}
+			source := "union of the configured categories"
+			if spec, ok := util.AppConfig.Categories[name]; ok {
+				source = spec
+			} else if n, ok := util.PseudoCategories[name]; ok {
+				source = fmt.Sprintf("top %d TCP ports of nmap-services", n)
+			}
+			fmt.Printf("\n%s %s (%d TCP, %d UDP)\n", util.MarkerGreen, util.Green(name), len(tcp), len(udp))
+			fmt.Printf("    defined: %s\n", source)
+			if len(tcp) > 0 {
+				fmt.Printf("    tcp:     %s\n", util.FormatPorts(tcp))
+			}
+			if len(udp) > 0 {
+				fmt.Printf("    udp:     %s\n", util.FormatPorts(udp))
+			}
+		}
+	},
+}
+
+func init() {
+	CategoriesCmd.Flags().StringVarP(&categoriesName, "name", "n", "", "Show only this category (\"all\" shows the union of the configured categories)")
+}
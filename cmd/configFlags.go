@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// configAnnotation marca as flags cujo valor padrão vem do config.toml.
+const configAnnotation = "config"
+
+var (
+	rootConfig        string // Arquivo de configuração informado com --config
+	rootLogLevel      string // Nível de log informado com --log-level
+	rootOperator      string // Operador registrado no log de auditoria (--operator)
+	rootScopeFile     string // Arquivo de escopo cujo hash vai para o log de auditoria (--scope-file)
+	rootScanWindow    string // Janela diária de scan (--scan-window)
+	configOrigin      string // De onde veio o caminho do arquivo carregado (--config, variável, diretório)
+	configShowSources bool   // Lista cada configuração com a sua origem
+)
+
+// ConfigCmd agrupa os comandos de inspeção da configuração.
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: util.ConfigAppDescription,
+}
+
+// ConfigShowCmd imprime a configuração efetiva: os padrões embutidos mesclados com o arquivo e
+// com as variáveis ARTHXRECON_*.
+var ConfigShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Prints the effective configuration (defaults merged with the config file and ARTHXRECON_* variables)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if util.AppConfig.Path != "" {
+			fmt.Printf("# Effective configuration (file %s, from %s)\n", util.AppConfig.Path, configOrigin)
+		} else {
+			fmt.Println("# Effective configuration (no config file found, using defaults)")
+		}
+		if configShowSources {
+			for _, key := range util.AppConfig.Keys() {
+				if value, ok := util.AppConfig.Lookup(key); ok {
+					fmt.Printf("%-30s = %q  [%s]\n", key, value, util.AppConfig.Source(key))
+				} else {
+					fmt.Printf("%-30s   (see \"profiles list\")  [%s]\n", key, util.AppConfig.Source(key))
+				}
+			}
+			return
+		}
+		if err := toml.NewEncoder(os.Stdout).Encode(util.AppConfig); err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrConfig, err)
+		}
+	},
+}
+
+// loadConfig procura o arquivo de configuração, aplica a configuração efetiva e inicializa o logger
+// e o log de auditoria.
+// Erros de leitura ou validação encerram a aplicação, em vez de seguir com os padrões.
+func loadConfig() {
+	path, origin, err := util.FindConfig(rootConfig)
+	var cfg *util.Config
+	if err == nil {
+		cfg, err = util.LoadConfig(path)
+	}
+	// As flags globais substituem as configurações do arquivo e das variáveis de ambiente.
+	overridden := false
+	for _, o := range []struct{ key, value, flag string }{
+		{"log.level", rootLogLevel, "--log-level"},
+		{"audit.operator", rootOperator, "--operator"},
+		{"audit.scope_file", rootScopeFile, "--scope-file"},
+		{"limits.window", rootScanWindow, "--scan-window"},
+	} {
+		if err == nil && o.value != "" {
+			err, overridden = cfg.SetFrom(o.key, o.value, "flag "+o.flag), true
+		}
+	}
+	if err == nil && overridden {
+		err = cfg.Validate()
+	}
+	if err == nil {
+		err = util.InitializeAudit(cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s\n%v\n", util.MarkerRed, util.FatalErrConfig, err)
+		os.Exit(1)
+	}
+	configOrigin = origin
+	cfg.Apply()
+	util.InitializeLogger(cfg)
+	if path != "" {
+		log.Debug().Msgf("Configuration loaded from %s (%s)", path, origin)
+	}
+}
+
+// configFlag associa uma flag a uma chave do config.toml (ex.: "scan.mode"). Enquanto a flag não
+// for informada, ela assume o valor da configuração; com elems, o valor é o caminho
+// filepath.Join(valor, elems...).
+func configFlag(flags *pflag.FlagSet, name, key string, elems ...string) {
+	if err := flags.SetAnnotation(name, configAnnotation, append([]string{key}, elems...)); err != nil {
+		panic(err)
+	}
+}
+
+// applyConfigFlags copia para as flags do comando que não foram informadas os valores do perfil
+// selecionado com --profile ou, na falta deles, os da configuração.
+func applyConfigFlags(cmd *cobra.Command) {
+	var (
+		profile  util.Profile
+		selected = rootProfile != ""
+	)
+	if selected {
+		var err error
+		if profile, _, err = util.AppConfig.ResolveProfile(rootProfile); err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrConfig, err)
+		}
+		log.Debug().Msgf("Using profile %s", rootProfile)
+	}
+
+	apply := func(f *pflag.Flag) {
+		if f.Changed {
+			return
+		}
+		source, value, ok := "", "", false
+		if annotation, found := f.Annotations[profileAnnotation]; found && selected {
+			source = "profiles." + rootProfile + "." + annotation[0]
+			value, ok = profile.Lookup(annotation[0])
+		}
+		if annotation, found := f.Annotations[configAnnotation]; found && !ok {
+			source = annotation[0]
+			if value, ok = util.AppConfig.Lookup(annotation[0]); ok && len(annotation) > 1 {
+				value = filepath.Join(append([]string{value}, annotation[1:]...)...)
+			}
+		}
+		if !ok {
+			return
+		}
+		if err := f.Value.Set(value); err != nil {
+			log.Fatal().Msgf("%s %s: invalid value %q for --%s: %v", util.FatalErrConfig, source, value, f.Name, err)
+		}
+	}
+	cmd.Flags().VisitAll(apply)
+	cmd.InheritedFlags().VisitAll(apply)
+}
+
+// applyLimits aplica ao limitador global dos motores nativos o --max-rate do comando, já resolvido
+// a partir do perfil ou do limits.max_rate; sem a flag, vale o limits.max_rate aplicado em loadConfig.
+func applyLimits(cmd *cobra.Command) {
+	f := cmd.Flags().Lookup("max-rate")
+	if f == nil {
+		return
+	}
+	rate, err := strconv.Atoi(f.Value.String())
+	if err != nil || rate < 0 {
+		log.Fatal().Msgf("Invalid --max-rate %q: must be a non-negative number", f.Value.String())
+	}
+	util.SetMaxRate(rate)
+}
+
+func init() {
+	ConfigShowCmd.Flags().BoolVar(&configShowSources, "sources", false, "List every setting with its source (default, file or environment variable)")
+	ConfigCmd.AddCommand(ConfigShowCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// useConfig troca a configuração global e o perfil selecionado durante o teste.
+func useConfig(t *testing.T, cfg *util.Config, profile string) {
+	t.Helper()
+	oldConfig, oldProfile := util.AppConfig, rootProfile
+	util.AppConfig, rootProfile = cfg, profile
+	t.Cleanup(func() { util.AppConfig, rootProfile = oldConfig, oldProfile })
+}
+
+// newConfigCmd cria um comando com flags ligadas ao config.toml e ao perfil, como os do pacote.
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	flags := cmd.Flags()
+	flags.String("mode", "normal", "")
+	flags.String("category", "top12", "")
+	flags.Int("max-rate", 0, "")
+	flags.String("output", "portscan.xml", "")
+	configFlag(flags, "mode", "scan.mode")
+	configFlag(flags, "category", "scan.category")
+	configFlag(flags, "max-rate", "limits.max_rate")
+	configFlag(flags, "output", "output.port_scan", "portscan.xml")
+	profileFlag(flags, "mode", "mode")
+	profileFlag(flags, "max-rate", "max_rate")
+	return cmd
+}
+
+func TestApplyConfigFlags(t *testing.T) {
+	cfg := util.DefaultConfig()
+	cfg.Scan.Mode, cfg.Scan.Category, cfg.Limits.MaxRate, cfg.Output.PortScan = "stealth", "web", 200, "/srv/recon"
+	useConfig(t, cfg, "")
+
+	// Flags não informadas assumem a configuração; as informadas prevalecem sobre ela.
+	cmd := newConfigCmd()
+	if err := cmd.Flags().Set("category", "database"); err != nil {
+		t.Fatal(err)
+	}
+	applyConfigFlags(cmd)
+	for name, want := range map[string]string{
+		"mode":     "stealth",
+		"category": "database",
+		"max-rate": "200",
+		"output":   filepath.Join("/srv/recon", "portscan.xml"),
+	} {
+		if got := cmd.Flags().Lookup(name).Value.String(); got != want {
+			t.Errorf("--%s = %q, want %q", name, got, want)
+		}
+	}
+
+	// Com --profile, o perfil vem antes da configuração, e a flag informada antes do perfil.
+	useConfig(t, cfg, "external-stealth")
+	cmd = newConfigCmd()
+	if err := cmd.Flags().Set("max-rate", "10"); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Scan.Mode = "aggressive"
+	applyConfigFlags(cmd)
+	for name, want := range map[string]string{"mode": "stealth", "category": "web", "max-rate": "10"} {
+		if got := cmd.Flags().Lookup(name).Value.String(); got != want {
+			t.Errorf("with profile: --%s = %q, want %q", name, got, want)
+		}
+	}
+}
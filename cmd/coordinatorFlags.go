@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Arthx-x/arthxrecon/internal/distributed"
+	"github.com/Arthx-x/arthxrecon/internal/hostdiscovery"
+	"github.com/Arthx-x/arthxrecon/internal/nse"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	coordTarget        string        // Alvos (IPs, CIDRs ou arquivo)
+	coordMode          string        // Modo do scan: stealth, normal ou aggressive
+	coordPortList      string        // Lista ou range de portas TCP
+	coordUDPPorts      string        // Lista de portas UDP
+	coordCategory      string        // Categorias de portas
+	coordSimpleScan    bool          // Port scan simples (-sS)
+	coordScripts       string        // Seleção de scripts NSE
+	coordCustomOptions string        // Opções extras do Nmap, separadas por espaços
+	coordSkipDiscovery bool          // Pula o host discovery e varre os alvos diretamente
+	coordEngine        string        // Engine do host discovery: nmap ou masscan
+	coordMaxRate       int           // Máximo de pacotes por segundo de cada agent (0 = sem limite)
+	coordOutput        string        // Nome base dos arquivos de resultado
+	coordListen        string        // Endereço em que os agents se conectam
+	coordTokenFile     string        // Arquivo com o token compartilhado
+	coordTLSCert       string        // Certificado TLS do coordinator
+	coordTLSKey        string        // Chave privada do certificado TLS
+	coordChunkSize     int           // Endereços por chunk de host discovery
+	coordHostsPerChunk int           // Hosts por chunk de port scan
+	coordLeaseTimeout  time.Duration // Tempo sem heartbeat que devolve o chunk à fila
+	coordMaxAttempts   int           // Entregas de um chunk antes de ele falhar
+	coordUnreachable   time.Duration // Espera por um agent que alcance os chunks restantes (0 = sem limite)
+)
+
+// CoordinatorCmd divide o host discovery e o port scan em chunks executados pelos agents, conforme
+// as redes que cada agent alcança, e consolida os resultados.
+var CoordinatorCmd = &cobra.Command{
+	Use:   "coordinator",
+	Short: util.CoordAppDescription,
+	Run: func(cmd *cobra.Command, args []string) {
+		if coordTarget == "" {
+			log.Fatal().Msg(util.ErrInvalidTarget)
+		}
+		scope, err := util.LoadScope(coordTarget)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrCoord, err)
+		}
+		targets := make([]string, 0, len(scope))
+		for _, network := range scope {
+			targets = append(targets, network.String())
+		}
+		if _, err := hostdiscovery.NewStrategy(coordEngine); err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrCoord, err)
+		}
+		if err := validateScanOptions(coordMode, coordCategory, coordPortList, coordUDPPorts); err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrCoord, err)
+		}
+		token, generated, err := loadToken(util.AgentTokenEnv, coordTokenFile)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrCoord, err)
+		}
+
+		coordinator, err := distributed.NewCoordinator(distributed.CoordinatorParams{
+			Listen:  coordListen,
+			Token:   token,
+			TLSCert: coordTLSCert,
+			TLSKey:  coordTLSKey,
+			Targets: targets,
+			Scan: distributed.ScanSpec{
+				Engine:     coordEngine,
+				Mode:       coordMode,
+				PortList:   coordPortList,
+				UDPPorts:   coordUDPPorts,
+				Category:   coordCategory,
+				SimpleScan: coordSimpleScan,
+				Scripts:    coordScripts,
+				Options:    strings.Fields(coordCustomOptions),
+				MaxRate:    coordMaxRate,
+			},
+			SkipDiscovery: coordSkipDiscovery,
+			ChunkSize:     coordChunkSize,
+			HostsPerChunk: coordHostsPerChunk,
+			LeaseTimeout:  coordLeaseTimeout,
+			MaxAttempts:   coordMaxAttempts,
+
+			UnreachableTimeout: coordUnreachable,
+		})
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrCoord, err)
+		}
+
+		scheme := "http://"
+		if coordTLSCert != "" {
+			scheme = "https://"
+		}
+		fmt.Printf("%s Coordinator listening on %s\n", util.MarkerCyan, util.Cyan(scheme+coordListen))
+		if coordTLSCert == "" {
+			fmt.Printf("%s Without --tls-cert, messages are authenticated but not encrypted\n", util.MarkerYellow)
+		}
+		if generated {
+			fmt.Printf("%s No token configured ($%s or distributed.token_file), agents must use: %s\n", util.MarkerYellow, util.AgentTokenEnv, util.Yellow(token))
+		}
+		fmt.Printf("%s %s Waiting for agents\n", util.MarkerCyan, util.GetFormattedTime())
+
+		// Ctrl+C (ou SIGTERM) encerra o coordinator e grava os resultados recebidos até então.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		report, err := coordinator.Run(ctx)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrCoord, err)
+		}
+		saveReport(report)
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+// saveReport grava os hosts ativos e os serviços consolidados, registra os achados dos scripts NSE
+// e lista os chunks que não foram executados.
+func saveReport(report *distributed.Report) {
+	fmt.Printf("\n%s Discovered: %s %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(report.Alive))), util.Green("Hosts"))
+	if len(report.Alive) > 0 {
+		if err := util.EnsureDir(util.HostDiscoveryName); err != nil {
+			log.Error().Err(err).Msgf("Error creating directory %s", util.HostDiscoveryName)
+		} else {
+			path := filepath.Join(util.HostDiscoveryName, coordOutput+".txt")
+			if err := util.WriteTargetsToFile(path, report.Alive); err != nil {
+				log.Error().Err(err).Msg("Failed to save live hosts")
+			} else {
+				fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(path))
+			}
+		}
+	}
+
+	tcpPorts, udpPorts := 0, 0
+	for _, host := range report.Hosts {
+		tcpPorts += len(host.Services)
+		udpPorts += len(host.UDPServices)
+	}
+	fmt.Printf("%s Ports discovered: %s TCP, %s UDP\n", util.MarkerGreen, util.Green(strconv.Itoa(tcpPorts)), util.Green(strconv.Itoa(udpPorts)))
+	if len(report.Hosts) > 0 {
+		if err := util.EnsureDir(util.PortScanName); err != nil {
+			log.Error().Err(err).Msgf("Error creating directory %s", util.PortScanName)
+		} else {
+			path := filepath.Join(util.PortScanName, coordOutput+".json")
+			if err := results.SaveJSON(path, report.Hosts); err != nil {
+				log.Error().Err(err).Msg("Failed to save port scan results")
+			} else {
+				fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(path))
+			}
+		}
+		recordFindings(nse.Collect(report.Hosts))
+	}
+
+	for _, failed := range report.Failed {
+		fmt.Printf("%s Failed chunk %s (%s) on %s: %s\n", util.MarkerRed, failed.Chunk.ID, strings.Join(failed.Chunk.Targets, ", "), failed.Agent, failed.Error)
+	}
+	for _, chunk := range report.Pending {
+		fmt.Printf("%s Not scanned (%s): %s\n", util.MarkerYellow, chunk.Kind, strings.Join(chunk.Targets, ", "))
+	}
+}
+
+func init() {
+	CoordinatorCmd.Flags().StringVarP(&coordTarget, "target", "t", "", "Target IP(s) or CIDR range, or path to file with targets (for multiple, separate by commas)")
+	CoordinatorCmd.Flags().StringVarP(&coordMode, "mode", "m", "normal", "Scan mode: stealth, normal, or aggressive")
+	CoordinatorCmd.Flags().StringVarP(&coordPortList, "ports", "p", "", "Port range or list to scan (e.g., \"1-1024\" or \"22,80,U:53,161\")")
+	CoordinatorCmd.Flags().StringVarP(&coordUDPPorts, "udp", "u", "", "UDP ports to scan with -sU (e.g., \"161,500\")")
+	CoordinatorCmd.Flags().StringVarP(&coordCategory, "category", "c", "", "Port categories to include, separated by commas (e.g., top12, web, windows, top100, top1000, all; see \"categories\")")
+	configFlag(CoordinatorCmd.Flags(), "mode", "scan.mode")
+	configFlag(CoordinatorCmd.Flags(), "category", "scan.category")
+	CoordinatorCmd.Flags().BoolVarP(&coordSimpleScan, "simple", "s", false, "Use a simple port scan (e.g., -sS) instead of a detailed scan (-sV -sC)")
+	CoordinatorCmd.Flags().StringVar(&coordScripts, "scripts", "", "NSE scripts or categories replacing -sC, optionally per port category")
+	CoordinatorCmd.Flags().StringVarP(&coordCustomOptions, "custom", "x", "", "Custom timing and detection options for the port scan, separated by spaces (agents reject other nmap options)")
+	CoordinatorCmd.Flags().BoolVar(&coordSkipDiscovery, "skip-discovery", false, "Skip host discovery and port scan the targets directly")
+	CoordinatorCmd.Flags().StringVar(&coordEngine, "engine", "nmap", "Host discovery engine: nmap or masscan")
+	CoordinatorCmd.Flags().IntVar(&coordMaxRate, "max-rate", 0, "Maximum packets per second of the scanners of each agent (0 is unlimited)")
+	configFlag(CoordinatorCmd.Flags(), "max-rate", "limits.max_rate")
+	CoordinatorCmd.Flags().StringVarP(&coordOutput, "output", "o", "distributed", "Base name of the merged result files")
+	CoordinatorCmd.Flags().StringVar(&coordListen, "listen", "0.0.0.0:8090", "Address the agents connect to")
+	CoordinatorCmd.Flags().StringVar(&coordTokenFile, "token-file", "", "File holding the token shared with the agents (default $"+util.AgentTokenEnv+")")
+	CoordinatorCmd.Flags().StringVar(&coordTLSCert, "tls-cert", "", "TLS certificate of the coordinator (PEM)")
+	CoordinatorCmd.Flags().StringVar(&coordTLSKey, "tls-key", "", "Private key of the TLS certificate (PEM)")
+	CoordinatorCmd.Flags().IntVar(&coordChunkSize, "chunk-size", 256, "Addresses per host discovery chunk")
+	CoordinatorCmd.Flags().IntVar(&coordHostsPerChunk, "hosts-per-chunk", 16, "Live hosts per port scan chunk")
+	CoordinatorCmd.Flags().DurationVar(&coordLeaseTimeout, "lease-timeout", 2*time.Minute, "Time without a heartbeat from the agent that requeues a chunk")
+	CoordinatorCmd.Flags().IntVar(&coordMaxAttempts, "max-attempts", 3, "Deliveries of a chunk before it is reported as failed")
+	CoordinatorCmd.Flags().DurationVar(&coordUnreachable, "unreachable-timeout", 0, "Time to wait, with nothing running, for an agent that reaches the remaining chunks before ending the run (0 waits indefinitely)")
+	for flag, key := range map[string]string{"listen": "distributed.listen", "token-file": "distributed.token_file",
+		"tls-cert": "distributed.tls_cert", "tls-key": "distributed.tls_key", "chunk-size": "distributed.chunk_size",
+		"hosts-per-chunk": "distributed.hosts_per_chunk", "lease-timeout": "distributed.lease_timeout", "max-attempts": "distributed.max_attempts",
+		"unreachable-timeout": "distributed.unreachable_timeout"} {
+		configFlag(CoordinatorCmd.Flags(), flag, key)
+	}
+	for flag, key := range map[string]string{"engine": "engine", "mode": "mode", "category": "category", "simple": "simple",
+		"custom": "options", "max-rate": "max_rate"} {
+		profileFlag(CoordinatorCmd.Flags(), flag, key)
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/database"
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var dbNoLogin bool // Desabilita a tentativa de login como root sem senha no MySQL
+
+// DatabaseCmd identifica versão e autenticação dos serviços de banco de dados.
+var DatabaseCmd = &cobra.Command{
+	Use:   database.ModuleName,
+	Short: "Fingerprints MySQL, PostgreSQL, Redis, MongoDB, Elasticsearch and Memcached services",
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts := loadEnumerationHosts()
+
+		scanner := database.NewScanner()
+		scanner.EmptyPassword = !dbNoLogin
+		scanner.Timeout = enumConnTimeout()
+		scanner.Threads = enumThreads
+
+		fmt.Printf("\n%s Database Enumeration", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		found := scanner.Run(context.Background(), hosts)
+		open := 0
+		for _, info := range found {
+			auth := util.Green("auth required")
+			if info.Unauthenticated {
+				auth = util.Red("NO AUTH")
+				open++
+			}
+			fmt.Printf("%s %s:%d %s %s [%s]\n", util.MarkerGreen, info.Address, info.Port, util.Cyan(info.Engine), info.Version, auth)
+			if info.AuthMethod != "" {
+				fmt.Printf("    Auth Method     : %s\n", info.AuthMethod)
+			}
+			if info.TLS {
+				fmt.Printf("    TLS             : supported\n")
+			}
+			keys := make([]string, 0, len(info.Details))
+			for key := range info.Details {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("    %-16s: %s\n", key, info.Details[key])
+			}
+			for _, f := range info.Findings {
+				fmt.Printf("    [%s] %s\n", severityLabel(f.Severity), f.Title)
+			}
+		}
+
+		saveEnumerationHosts(hosts)
+		var list []findings.Finding
+		for _, info := range found {
+			list = append(list, info.Findings...)
+		}
+		recordFindings(list)
+		fmt.Printf("%s Database services: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(found))))
+		fmt.Printf("%s Unauthenticated access: %s\n", util.MarkerGreen, util.Red(strconv.Itoa(open)))
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	DatabaseCmd.Flags().BoolVar(&dbNoLogin, "no-login", false, "Do not try the MySQL root login with an empty password")
+	EnumerationCmd.AddCommand(DatabaseCmd)
+}
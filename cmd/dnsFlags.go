@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/dns"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dnsServer     string // Servidor DNS usado nas consultas PTR/SRV (host[:porta])
+	dnsDomains    string // Domínios para SRV e AXFR, separados por vírgula
+	dnsScope      string // Escopo autorizado (IPs/CIDRs ou arquivo); sem escopo, nada é adicionado aos alvos
+	dnsTargetFile string // Arquivo de alvos que recebe os endereços novos dentro do escopo
+)
+
+// DNSCmd executa PTR, SRV do AD e tentativas de AXFR, alimentando os alvos com endereços novos.
+var DNSCmd = &cobra.Command{
+	Use:   dns.ModuleName,
+	Short: "Runs reverse lookups, AD SRV discovery and zone transfer attempts",
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts := loadEnumerationHosts()
+
+		scanner := dns.NewScanner()
+		scanner.Server = dnsServer
+		scanner.Timeout = enumConnTimeout()
+		scanner.Threads = enumThreads
+		for _, d := range strings.Split(dnsDomains, ",") {
+			if d = strings.TrimSpace(d); d != "" {
+				scanner.Domains = append(scanner.Domains, d)
+			}
+		}
+		if dnsScope != "" {
+			scope, err := util.LoadScope(dnsScope)
+			if err != nil {
+				log.Fatal().Msgf("%s %v", util.FatalErrEnum, err)
+			}
+			scanner.Scope = scope
+		}
+
+		fmt.Printf("\n%s DNS Enumeration", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		report := scanner.Run(context.Background(), hosts)
+		fmt.Printf("%s PTR records: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(report.PTR)))
+		fmt.Printf("%s Domains: %s\n", util.MarkerGreen, util.Cyan(strings.Join(report.Domains, ", ")))
+		for _, srv := range report.SRV {
+			fmt.Printf("%s %s -> %s:%d %s\n", util.MarkerGreen, srv.Query, srv.Target, srv.Port, strings.Join(srv.Addresses, ", "))
+		}
+		for _, zt := range report.ZoneTransfers {
+			if zt.Allowed {
+				fmt.Printf("%s %s %s %s (%d records)\n", util.MarkerRed, zt.Server, zt.Zone, util.Red("zone transfer allowed"), len(zt.Records))
+			} else {
+				log.Debug().Msgf("AXFR %s on %s: %s", zt.Zone, zt.Server, zt.Error)
+			}
+		}
+
+		var newTargets []string
+		for _, d := range report.Discovered {
+			status := util.Yellow("out of scope")
+			if d.InScope {
+				status = util.Green("in scope")
+				newTargets = append(newTargets, d.Address)
+			}
+			fmt.Printf("%s New address %s (%s, via %s) [%s]\n", util.MarkerYellow, d.Address, d.Name, d.Source, status)
+		}
+		if len(newTargets) > 0 {
+			added, err := util.AppendTargetsToFile(dnsTargetFile, newTargets)
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to update %s", dnsTargetFile)
+			} else if len(added) > 0 {
+				fmt.Printf("%s Added %s targets to: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(added))), util.Green(dnsTargetFile))
+			}
+		}
+
+		saveEnumerationHosts(hosts)
+		recordFindings(dns.Findings(report))
+		reportFile := filepath.Join(util.EnumerationName, dns.ModuleName+".json")
+		if data, err := json.MarshalIndent(report, "", "  "); err == nil {
+			if err := os.WriteFile(reportFile, data, 0644); err != nil {
+				log.Error().Err(err).Msgf("Failed to write %s", reportFile)
+			} else {
+				fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(reportFile))
+			}
+		}
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	DNSCmd.Flags().StringVarP(&dnsServer, "server", "r", "", "DNS server used for PTR/SRV lookups (host[:port]); default is the system resolver")
+	DNSCmd.Flags().StringVarP(&dnsDomains, "domain", "d", "", "Domains for SRV and AXFR, separated by commas (derived domains are always included)")
+	DNSCmd.Flags().StringVar(&dnsScope, "scope", "", "Authorized scope (IPs/CIDRs separated by commas, or file); new addresses are only added when in scope")
+	DNSCmd.Flags().StringVar(&dnsTargetFile, "add-targets", filepath.Join(util.HostDiscoveryName, "targets.txt"), "Targets file that receives new in-scope addresses")
+	configFlag(DNSCmd.Flags(), "add-targets", "output.host_discovery", "targets.txt")
+	EnumerationCmd.AddCommand(DNSCmd)
+}
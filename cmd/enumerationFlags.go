@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	enumInput      string // Arquivo de entrada: XML do Nmap ou JSON de resultados de uma enumeração anterior
+	enumOutputFile string // Nome base para o arquivo de resultados (JSON)
+	enumTimeout    int    // Timeout por conexão, em segundos
+	enumThreads    int    // Quantidade de conexões simultâneas
+	enumMaxRate    int    // Máximo de conexões ou requisições por segundo (0 = sem limite)
+)
+
+// EnumerationCmd agrupa os módulos de enumeração executados sobre os resultados do port scan.
+var EnumerationCmd = &cobra.Command{
+	Use:   util.EnumerationName,
+	Short: util.EnumAppDescription,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+// enumOutputPath retorna o caminho do JSON de resultados da enumeração.
+func enumOutputPath() string {
+	return filepath.Join(util.EnumerationName, enumOutputFile+".json")
+}
+
+// loadEnumerationHosts carrega os hosts a serem enumerados.
+// Sem --input, continua a partir do JSON de uma enumeração anterior ou, na falta dele, do XML do port scan.
+func loadEnumerationHosts() []results.Host {
+	input := enumInput
+	if input == "" {
+		input = filepath.Join(util.PortScanName, "portscan.xml")
+		if _, err := os.Stat(enumOutputPath()); err == nil {
+			input = enumOutputPath()
+		}
+	}
+	hosts, err := results.LoadFile(input)
+	if err != nil {
+		log.Fatal().Msgf("%s %v", util.FatalErrEnum, err)
+	}
+	fmt.Printf("%s Loaded: %s (%s hosts)\n", util.MarkerGreen, util.Green(input), util.Green(fmt.Sprint(len(hosts))))
+	return hosts
+}
+
+// saveEnumerationHosts grava os hosts enriquecidos no JSON de resultados.
+func saveEnumerationHosts(hosts []results.Host) {
+	if err := util.EnsureDir(util.EnumerationName); err != nil {
+		log.Fatal().Msgf("Error creating directory %s: %v", util.EnumerationName, err)
+	}
+	if err := results.SaveJSON(enumOutputPath(), hosts); err != nil {
+		log.Fatal().Msgf("%s %v", util.FatalErrEnum, err)
+	}
+	fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(enumOutputPath()))
+}
+
+// enumConnTimeout converte a flag --timeout em time.Duration.
+func enumConnTimeout() time.Duration {
+	return time.Duration(enumTimeout) * time.Second
+}
+
+func init() {
+	EnumerationCmd.PersistentFlags().StringVarP(&enumInput, "input", "i", "", "Nmap XML or results JSON to enumerate (default: previous enumeration results or portScan/portscan.xml)")
+	EnumerationCmd.PersistentFlags().StringVarP(&enumOutputFile, "outfile", "o", "enumeration", "Base name for the results file")
+	EnumerationCmd.PersistentFlags().IntVar(&enumTimeout, "timeout", 5, "Connection timeout in seconds")
+	EnumerationCmd.PersistentFlags().IntVar(&enumThreads, "threads", 20, "Number of concurrent connections")
+	configFlag(EnumerationCmd.PersistentFlags(), "timeout", "enumeration.timeout")
+	configFlag(EnumerationCmd.PersistentFlags(), "threads", "enumeration.threads")
+	profileFlag(EnumerationCmd.PersistentFlags(), "threads", "threads")
+	EnumerationCmd.PersistentFlags().IntVar(&enumMaxRate, "max-rate", 0, "Maximum connections or requests per second of the modules (0 is unlimited)")
+	configFlag(EnumerationCmd.PersistentFlags(), "max-rate", "limits.max_rate")
+	profileFlag(EnumerationCmd.PersistentFlags(), "max-rate", "max_rate")
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/modules"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/plugins"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	enumEnable        string // Módulos habilitados, separados por vírgula (vazio = todos)
+	enumDisable       string // Módulos desabilitados, separados por vírgula
+	enumModuleTimeout string // Timeout por execução de módulo: padrão e/ou por módulo (ex.: "2m,ssh=30s")
+	enumPluginsDir    string // Diretório com os manifestos dos plugins externos
+)
+
+// EnumerationRunCmd executa todos os módulos habilitados que se aplicam a cada host.
+var EnumerationRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Runs every enabled enumeration module against the services it applies to",
+	Run: func(cmd *cobra.Command, args []string) {
+		registry, err := newModuleRegistry(enumConnTimeout(), enumPluginsDir)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrEnum, err)
+		}
+		if err := configureRegistry(registry, enumEnable, enumDisable, enumModuleTimeout, enumThreads); err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrEnum, err)
+		}
+		hosts := loadEnumerationHosts()
+
+		fmt.Printf("\n%s Enumeration", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		report := registry.Run(context.Background(), hosts)
+		for _, m := range report.Modules {
+			fmt.Printf("%s %-10s %s/%d results, %d errors, %d timeouts (%s)\n", util.MarkerGreen, m.Name,
+				util.Green(strconv.Itoa(m.Results)), m.Targets, m.Errors, m.Timeouts, m.Elapsed.Round(time.Millisecond))
+		}
+
+		saveEnumerationHosts(hosts)
+		recordFindings(report.Findings)
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+// EnumerationModulesCmd lista os módulos registrados e os serviços aos quais cada um se aplica.
+var EnumerationModulesCmd = &cobra.Command{
+	Use:   "modules",
+	Short: "Lists the registered enumeration modules and the services they apply to",
+	Run: func(cmd *cobra.Command, args []string) {
+		registry, err := newModuleRegistry(enumConnTimeout(), enumPluginsDir)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrEnum, err)
+		}
+		for _, m := range registry.Modules() {
+			fmt.Printf("%s %-10s %s\n", util.MarkerGreen, util.Green(m.Name()), describeSelector(m.Selector()))
+			if p, ok := m.(*plugins.Plugin); ok {
+				fmt.Printf("    plugin: %s (timeout %s)\n", p.Path, registry.TimeoutFor(p.Name()))
+			}
+		}
+	},
+}
+
+// newModuleRegistry cria o Registry com os módulos nativos e os plugins do diretório informado.
+// O timeout declarado no manifesto de cada plugin vale até ser substituído por --module-timeout.
+func newModuleRegistry(timeout time.Duration, pluginsDir string) (*enumeration.Registry, error) {
+	registry := modules.NewRegistry(timeout)
+	loaded, err := plugins.Load(pluginsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range loaded {
+		if err := registry.Register(p); err != nil {
+			return nil, fmt.Errorf("%w (plugin %s)", err, p.Path)
+		}
+		if p.Timeout() > 0 {
+			_ = registry.SetTimeout(p.Name(), p.Timeout())
+		}
+	}
+	return registry, nil
+}
+
+// configureRegistry aplica as flags de habilitação, timeout e paralelismo ao Registry.
+func configureRegistry(registry *enumeration.Registry, enable, disable, timeouts string, threads int) error {
+	if names := splitList(enable); len(names) > 0 {
+		if err := registry.Only(names...); err != nil {
+			return err
+		}
+	}
+	if err := registry.Disable(splitList(disable)...); err != nil {
+		return err
+	}
+	for _, entry := range splitList(timeouts) {
+		name, value, perModule := strings.Cut(entry, "=")
+		if !perModule {
+			value = name
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid module timeout %q", entry)
+		}
+		if !perModule {
+			registry.Timeout = timeout
+			continue
+		}
+		if err := registry.SetTimeout(strings.TrimSpace(name), timeout); err != nil {
+			return err
+		}
+	}
+	registry.Threads = threads
+	return nil
+}
+
+// describeSelector descreve em uma linha os serviços selecionados por um módulo.
+func describeSelector(s enumeration.Selector) string {
+	var parts []string
+	ints := func(ports []int) string {
+		var list []string
+		for _, p := range ports {
+			list = append(list, strconv.Itoa(p))
+		}
+		return strings.Join(list, ",")
+	}
+	if len(s.Ports) > 0 {
+		parts = append(parts, "tcp:"+ints(s.Ports))
+	}
+	if len(s.UDPPorts) > 0 {
+		parts = append(parts, "udp:"+ints(s.UDPPorts))
+	}
+	if len(s.Services) > 0 {
+		parts = append(parts, "services:"+strings.Join(s.Services, ","))
+	}
+	if len(s.Categories) > 0 {
+		parts = append(parts, "categories:"+strings.Join(s.Categories, ","))
+	}
+	if len(s.Protocols) > 0 {
+		parts = append(parts, "protocols:"+strings.Join(s.Protocols, ","))
+	}
+	if s.Unidentified {
+		parts = append(parts, "unidentified only")
+	}
+	if s.Host {
+		parts = append(parts, "every host")
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitList separa uma lista separada por vírgulas, ignorando itens vazios.
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// addModuleFlags registra as flags de seleção e timeout dos módulos em um comando.
+func addModuleFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&enumEnable, "enable", "", "Enumeration modules to run, separated by commas (default: all)")
+	cmd.Flags().StringVar(&enumDisable, "disable", "", "Enumeration modules to skip, separated by commas")
+	cmd.Flags().StringVar(&enumPluginsDir, "plugins", util.PluginsDir, "Directory with external plugin manifests")
+	cmd.Flags().StringVar(&enumModuleTimeout, "module-timeout", "2m", "Timeout per module run on a host, default and/or per module (e.g., \"2m,ssh=30s,snmp=1m\")")
+	configFlag(cmd.Flags(), "plugins", "paths.plugins")
+	profileFlag(cmd.Flags(), "enable", "modules")
+	configFlag(cmd.Flags(), "module-timeout", "enumeration.module_timeout")
+}
+
+func init() {
+	addModuleFlags(EnumerationRunCmd)
+	EnumerationModulesCmd.Flags().StringVar(&enumPluginsDir, "plugins", util.PluginsDir, "Directory with external plugin manifests")
+	configFlag(EnumerationModulesCmd.Flags(), "plugins", "paths.plugins")
+	EnumerationCmd.AddCommand(EnumerationRunCmd)
+	EnumerationCmd.AddCommand(EnumerationModulesCmd)
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	findingsMinSeverity string // Severidade mínima listada
+	findingsSource      string // Filtra por módulo de origem
+	findingsJSON        bool   // Imprime os achados em JSON
+)
+
+// FindingsCmd lista os achados registrados por todos os módulos, ordenados por severidade.
+var FindingsCmd = &cobra.Command{
+	Use:   util.FindingsName,
+	Short: "Lists the findings recorded by all modules, sorted by severity",
+	Run: func(cmd *cobra.Command, args []string) {
+		if findingsMinSeverity != "" && !findings.ValidSeverity(findingsMinSeverity) {
+			log.Fatal().Msgf("Invalid severity %q (expected one of: %s)", findingsMinSeverity, strings.Join(findings.Severities, ", "))
+		}
+		registry, err := findings.LoadRegistry(util.FindingsPath)
+		if err != nil {
+			log.Fatal().Msgf("%v", err)
+		}
+
+		var list []findings.Finding
+		for _, f := range registry.All() {
+			if findingsMinSeverity != "" && findings.Rank(f.Severity) > findings.Rank(findingsMinSeverity) {
+				continue
+			}
+			if findingsSource != "" && !slices.Contains(f.Sources, findingsSource) {
+				continue
+			}
+			list = append(list, f)
+		}
+
+		if findingsJSON {
+			data, _ := json.MarshalIndent(list, "", "  ")
+			fmt.Fprintln(os.Stdout, string(data))
+			return
+		}
+		fmt.Printf("\n%s Findings (%s)\n", util.MarkerCyan, util.Green(util.FindingsPath))
+		for _, f := range list {
+			fmt.Printf("%s [%s] %s %s (%s)\n", util.MarkerGreen, severityLabel(f.Severity), f.Target(), f.Title, strings.Join(f.Sources, ", "))
+			if f.Evidence != "" {
+				fmt.Printf("    Evidence  : %s\n", f.Evidence)
+			}
+			fmt.Printf("    Seen      : %s - %s\n", f.FirstSeen.Local().Format("2006-01-02 15:04"), f.LastSeen.Local().Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("%s Findings: %s (%s)\n", util.MarkerGreen, util.Green(strconv.Itoa(len(list))), findings.Summary(list))
+	},
+}
+
+// recordFindings registra os achados do módulo no registro compartilhado e exibe o resumo por severidade.
+func recordFindings(list []findings.Finding) {
+	if len(list) == 0 {
+		return
+	}
+	registry, err := findings.LoadRegistry(util.FindingsPath)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load findings registry")
+		return
+	}
+	added := registry.Add(list...)
+	if err := registry.Save(util.FindingsPath); err != nil {
+		log.Error().Err(err).Msg("Failed to save findings registry")
+		return
+	}
+	list = findings.Merge(list)
+	findings.Sort(list)
+	fmt.Printf("%s Findings: %s (%s new) -> %s\n", util.MarkerGreen, findings.Summary(list), util.Green(strconv.Itoa(added)), util.Green(util.FindingsPath))
+}
+
+// severityLabel colore a severidade de um achado para o console.
+func severityLabel(severity string) string {
+	switch severity {
+	case findings.SeverityCritical, findings.SeverityHigh:
+		return util.Red(severity)
+	case findings.SeverityMedium:
+		return util.Yellow(severity)
+	case findings.SeverityLow:
+		return util.Cyan(severity)
+	}
+	return severity
+}
+
+func init() {
+	FindingsCmd.Flags().StringVar(&findingsMinSeverity, "min-severity", "", "Only list findings with at least this severity (critical, high, medium, low, info)")
+	FindingsCmd.Flags().StringVar(&findingsSource, "source", "", "Only list findings from this module (e.g., ftp, nse, vulnanalysis)")
+	FindingsCmd.Flags().BoolVar(&findingsJSON, "json", false, "Print the findings as JSON")
+}
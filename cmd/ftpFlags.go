@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/ftp"
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ftpUser       string // Usuário do login anônimo
+	ftpPass       string // Senha do login anônimo
+	ftpCheckWrite bool   // Habilita o teste de escrita no diretório raiz
+	ftpMaxListing int    // Quantidade de linhas da listagem exibidas no console
+)
+
+// FTPCmd verifica login anônimo, listagem e escrita nos serviços FTP.
+var FTPCmd = &cobra.Command{
+	Use:   ftp.ModuleName,
+	Short: "Checks FTP services for anonymous login, root listing and write access",
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts := loadEnumerationHosts()
+
+		scanner := ftp.NewScanner()
+		scanner.Username = ftpUser
+		scanner.Password = ftpPass
+		scanner.CheckWrite = ftpCheckWrite
+		scanner.Timeout = enumConnTimeout()
+		scanner.Threads = enumThreads
+
+		fmt.Printf("\n%s FTP Enumeration", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		found := scanner.Run(context.Background(), hosts)
+		anonymous := 0
+		for _, info := range found {
+			fmt.Printf("%s %s:%d %s\n", util.MarkerGreen, info.Address, info.Port, util.Cyan(info.Banner))
+			if info.Anonymous {
+				anonymous++
+			}
+			for _, f := range info.Findings {
+				fmt.Printf("    [%s] %s\n", severityLabel(f.Severity), f.Title)
+			}
+			if info.ListError != "" {
+				fmt.Printf("    Listing error   : %s\n", info.ListError)
+			}
+			for i, line := range info.Listing {
+				if i == ftpMaxListing {
+					fmt.Printf("    ... %d more entries\n", len(info.Listing)-ftpMaxListing)
+					break
+				}
+				fmt.Printf("    %s\n", line)
+			}
+		}
+
+		saveEnumerationHosts(hosts)
+		var list []findings.Finding
+		for _, info := range found {
+			list = append(list, info.Findings...)
+		}
+		recordFindings(list)
+		fmt.Printf("%s FTP services: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(found))))
+		fmt.Printf("%s Anonymous login: %s\n", util.MarkerGreen, util.Yellow(strconv.Itoa(anonymous)))
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	FTPCmd.Flags().StringVar(&ftpUser, "user", "anonymous", "Username for the anonymous login")
+	FTPCmd.Flags().StringVar(&ftpPass, "pass", "anonymous@example.com", "Password for the anonymous login")
+	FTPCmd.Flags().BoolVar(&ftpCheckWrite, "check-write", false, "Test write access by creating and removing a directory (opt-in)")
+	FTPCmd.Flags().IntVar(&ftpMaxListing, "max-listing", 20, "Maximum listing entries printed to the console")
+	EnumerationCmd.AddCommand(FTPCmd)
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+	"github.com/Arthx-x/arthxrecon/internal/hostdiscovery"
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/portscan"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	frTarget        string  // Alvos (IPs, CIDRs ou arquivo)
+	frMode          string  // Modo do scan: stealth, normal ou aggressive
+	frPortList      string  // Lista ou range de portas TCP
+	frUDPPorts      string  // Lista de portas UDP
+	frCategory      string  // Categorias de portas
+	frSimpleScan    bool    // Port scan simples (-sS)
+	frScripts       string  // Seleção de scripts NSE
+	frCustomOptions string  // Opções extras do Nmap, separadas por espaços
+	frSkipDiscovery bool    // Pula o host discovery e varre os alvos diretamente
+	frTimeout       int     // Timeout de conexão dos módulos, em segundos
+	frThreads       int     // Execuções de módulos simultâneas
+	frFeed          string  // Base local de vulnerabilidades
+	frMinCVSS       float64 // CVSS mínimo para reportar
+	frEngine        string  // Engine do host discovery: nmap ou masscan
+	frMaxRate       int     // Máximo de pacotes por segundo (0 = sem limite)
+)
+
+// FullReconCmd executa o pipeline completo: host discovery, port scan, enumeration e vulnanalysis.
+var FullReconCmd = &cobra.Command{
+	Use:   "fullrecon",
+	Short: util.FullReconAppDescription,
+	Run: func(cmd *cobra.Command, args []string) {
+		targets, fileMode := util.ParseTargetInput(frTarget)
+		if len(targets) == 0 {
+			log.Fatal().Msg(util.ErrInvalidTarget)
+		}
+
+		dispatcher, err := notify.New(util.AppConfig.Notifiers)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrFR, err)
+		}
+		stages, err := buildStages(reconOptions{
+			Discovery:     !frSkipDiscovery,
+			PortScan:      true,
+			Enumeration:   true,
+			VulnAnalysis:  true,
+			Engine:        frEngine,
+			Mode:          frMode,
+			PortList:      frPortList,
+			UDPPorts:      frUDPPorts,
+			Category:      frCategory,
+			SimpleScan:    frSimpleScan,
+			Scripts:       frScripts,
+			Options:       strings.Fields(frCustomOptions),
+			MaxRate:       frMaxRate,
+			Timeout:       frTimeout,
+			Threads:       frThreads,
+			Enable:        enumEnable,
+			Disable:       enumDisable,
+			ModuleTimeout: enumModuleTimeout,
+			PluginsDir:    enumPluginsDir,
+			Feed:          frFeed,
+			MinCVSS:       frMinCVSS,
+		})
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrFR, err)
+		}
+
+		fmt.Printf("%s Full Recon", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		state := &fullrecon.State{Targets: targets, FileMode: fileMode}
+		orchestrator := fullrecon.NewFullReconOrchestrator(stages...)
+		orchestrator.Notifier = dispatcher
+		err = orchestrator.Run(context.Background(), state)
+		// Os achados das etapas concluídas são registrados mesmo que uma etapa posterior falhe.
+		recordFindings(state.Findings)
+		closeNotifier(dispatcher)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrFR, err)
+		}
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+// notifyFlushTimeout é quanto o comando espera, ao encerrar, pela entrega das notificações pendentes.
+const notifyFlushTimeout = 30 * time.Second
+
+// closeNotifier entrega as notificações ainda na fila antes de o comando encerrar.
+func closeNotifier(dispatcher *notify.Dispatcher) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyFlushTimeout)
+	defer cancel()
+	if err := dispatcher.Close(ctx); err != nil {
+		log.Warn().Err(err).Msg("Pending notifications were not delivered")
+	}
+}
+
+// reconOptions reúne as opções que montam as etapas do pipeline (fullrecon, monitor e jobs da API).
+type reconOptions struct {
+	Discovery     bool     // Inclui o host discovery
+	PortScan      bool     // Inclui o port scan
+	Enumeration   bool     // Inclui os módulos de enumeração
+	VulnAnalysis  bool     // Inclui a análise de vulnerabilidades
+	Output        string   // Nome base dos arquivos de todas as etapas (vazio = nomes padrão de cada etapa)
+	Engine        string   // Engine do host discovery: nmap ou masscan
+	Mode          string   // Modo do scan
+	PortList      string   // Lista ou range de portas TCP
+	UDPPorts      string   // Lista de portas UDP
+	Category      string   // Categorias de portas
+	SimpleScan    bool     // Port scan simples (-sS)
+	Scripts       string   // Seleção de scripts NSE
+	Options       []string // Opções extras do Nmap
+	MaxRate       int      // Máximo de pacotes por segundo (0 = sem limite)
+	Timeout       int      // Timeout de conexão dos módulos, em segundos
+	Threads       int      // Execuções de módulos simultâneas
+	Enable        string   // Módulos habilitados, separados por vírgulas
+	Disable       string   // Módulos desabilitados, separados por vírgulas
+	ModuleTimeout string   // Timeout por módulo (ex.: "2m,ssh=30s")
+	PluginsDir    string   // Diretório dos manifestos de plugins
+	Feed          string   // Base local de vulnerabilidades
+	MinCVSS       float64  // CVSS mínimo para reportar
+}
+
+// buildStages monta as etapas pedidas do pipeline, na ordem: host discovery, port scan,
+// enumeration e vulnanalysis.
+func buildStages(o reconOptions) ([]fullrecon.Stage, error) {
+	output := func(name string) string {
+		if o.Output != "" {
+			return o.Output
+		}
+		return name
+	}
+
+	var stages []fullrecon.Stage
+	if o.Discovery {
+		strategy, err := hostdiscovery.NewStrategy(o.Engine)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, &fullrecon.DiscoveryStage{
+			Params:   hostdiscovery.DiscoveryParams{OutputFile: output("targets"), Mode: o.Mode, MaxRate: o.MaxRate},
+			Strategy: strategy,
+		})
+	}
+	if o.PortScan {
+		stages = append(stages, &fullrecon.PortScanStage{
+			Params: portscan.PortScanParams{
+				OutputFile: output("portscan"),
+				Mode:       o.Mode,
+				Options:    o.Options,
+				PortList:   o.PortList,
+				UDPPorts:   o.UDPPorts,
+				Category:   o.Category,
+				SimpleScan: o.SimpleScan,
+				Scripts:    o.Scripts,
+				MaxRate:    o.MaxRate,
+			},
+			Strategy: portscan.NewNmapPortScanner(),
+		})
+	}
+	if o.Enumeration {
+		registry, err := newModuleRegistry(time.Duration(o.Timeout)*time.Second, o.PluginsDir)
+		if err != nil {
+			return nil, err
+		}
+		if err := configureRegistry(registry, o.Enable, o.Disable, o.ModuleTimeout, o.Threads); err != nil {
+			return nil, err
+		}
+		// O limite da execução é um teto adicional: jobs simultâneos do serve compartilham o limitador
+		// global do processo, de modo que juntos não passam do limits.max_rate.
+		stages = append(stages, &fullrecon.EnumerationStage{Registry: registry, OutputFile: output("enumeration"), Limiter: util.NewJobLimiter(o.MaxRate)})
+	}
+	if o.VulnAnalysis {
+		stages = append(stages, &fullrecon.VulnAnalysisStage{FeedPath: o.Feed, MinCVSS: o.MinCVSS, OutputFile: output("vulnanalysis")})
+	}
+	return stages, nil
+}
+
+func init() {
+	FullReconCmd.Flags().StringVarP(&frTarget, "target", "t", "", "Target IP(s) or CIDR range, or path to file with targets (for multiple, separate by commas)")
+	FullReconCmd.Flags().StringVarP(&frMode, "mode", "m", "normal", "Scan mode: stealth, normal, or aggressive")
+	FullReconCmd.Flags().StringVarP(&frPortList, "ports", "p", "", "Port range or list to scan (e.g., \"1-1024\" or \"22,80,U:53,161\")")
+	FullReconCmd.Flags().StringVarP(&frUDPPorts, "udp", "u", "", "UDP ports to scan with -sU (e.g., \"161,500\")")
+	FullReconCmd.Flags().StringVarP(&frCategory, "category", "c", "", "Port categories to include, separated by commas (e.g., top12, web, windows, top100, top1000, all; see \"categories\")")
+	configFlag(FullReconCmd.Flags(), "mode", "scan.mode")
+	configFlag(FullReconCmd.Flags(), "category", "scan.category")
+	FullReconCmd.Flags().BoolVarP(&frSimpleScan, "simple", "s", false, "Use a simple port scan (e.g., -sS) instead of a detailed scan (-sV -sC)")
+	FullReconCmd.Flags().StringVar(&frScripts, "scripts", "", "NSE scripts or categories replacing -sC, optionally per port category")
+	FullReconCmd.Flags().StringVarP(&frCustomOptions, "custom", "x", "", "Custom options for the port scan, separated by spaces")
+	FullReconCmd.Flags().BoolVar(&frSkipDiscovery, "skip-discovery", false, "Skip host discovery and port scan the targets directly")
+	FullReconCmd.Flags().IntVar(&frTimeout, "timeout", 5, "Connection timeout of the enumeration modules in seconds")
+	FullReconCmd.Flags().IntVar(&frThreads, "threads", 20, "Number of concurrent enumeration module runs")
+	configFlag(FullReconCmd.Flags(), "timeout", "enumeration.timeout")
+	configFlag(FullReconCmd.Flags(), "threads", "enumeration.threads")
+	FullReconCmd.Flags().StringVar(&frFeed, "feed", util.VulnFeedPath, "Path to the local vulnerability feed (skipped if missing)")
+	configFlag(FullReconCmd.Flags(), "feed", "paths.vuln_feed")
+	FullReconCmd.Flags().Float64Var(&frMinCVSS, "min-cvss", 0, "Only report vulnerabilities with at least this CVSS score")
+	FullReconCmd.Flags().StringVar(&frEngine, "engine", "nmap", "Host discovery engine: nmap or masscan")
+	FullReconCmd.Flags().IntVar(&frMaxRate, "max-rate", 0, "Maximum packets or connections per second of the scanners and enumeration modules (0 is unlimited)")
+	configFlag(FullReconCmd.Flags(), "max-rate", "limits.max_rate")
+	addModuleFlags(FullReconCmd)
+	for flag, key := range map[string]string{"engine": "engine", "mode": "mode", "category": "category", "simple": "simple",
+		"custom": "options", "threads": "threads", "max-rate": "max_rate"} {
+		profileFlag(FullReconCmd.Flags(), flag, key)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,6 +18,8 @@ var (
 	hostOutputFile    string // Nome base para os arquivos de saída
 	hostMode          string // Modo do scan: aggressive, normal ou passive
 	hostCustomOptions string
+	hostEngine        string // Engine do discovery: nmap ou masscan
+	hostMaxRate       int    // Máximo de pacotes por segundo (0 = sem limite)
 )
 
 // HostDiscoveryCmd é o comando para executar a descoberta de hosts.
@@ -45,17 +48,21 @@ var HostDiscoveryCmd = &cobra.Command{
 			Mode:       hostMode,       // Modo do scan.
 			Options:    options,        // Outras opções extras, se necessário.
 			FileMode:   fileMode,       // Indica se os targets vieram de um arquivo.
+			MaxRate:    hostMaxRate,    // Limite de pacotes por segundo.
 		}
 
-		// Seleciona a estratégia de descoberta (aqui usamos o Nmap como padrão)
-		strategy := hostdiscovery.NewNmapHostDiscovery()
+		// Seleciona a estratégia de descoberta do engine escolhido (Nmap por padrão)
+		strategy, err := hostdiscovery.NewStrategy(hostEngine)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrHD, err)
+		}
 
 		// Cria o orquestrador que gerencia o fluxo completo: configurar, executar e parsear
 		orchestrator := hostdiscovery.NewHostDiscoveryOrchestrator(strategy, params)
 
 		fmt.Printf("%s Host Discovery", util.MarkerCyan)
 		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
-		hosts, err := orchestrator.Run()
+		hosts, err := orchestrator.Run(context.Background())
 
 		if err != nil {
 			log.Fatal().Msgf("%s %v", util.FatalErrHD, err)
@@ -69,6 +76,13 @@ func init() {
 	HostDiscoveryCmd.Flags().StringVarP(&hostTarget, "target", "t", "", "Target IP(s) or CIDR range, or path to file containing targets (if file, provide file path; for multiple, separate by commas)")
 	HostDiscoveryCmd.Flags().StringVarP(&hostOutputFile, "outfile", "o", "targets", "Base name for output files")
 	HostDiscoveryCmd.Flags().StringVarP(&hostMode, "mode", "m", "normal", "Scan mode: 1.stealth, 2.normal, or 3.aggressive")
+	configFlag(HostDiscoveryCmd.Flags(), "mode", "scan.mode")
+	profileFlag(HostDiscoveryCmd.Flags(), "mode", "mode")
+	HostDiscoveryCmd.Flags().StringVar(&hostEngine, "engine", "nmap", "Host discovery engine: nmap or masscan")
+	profileFlag(HostDiscoveryCmd.Flags(), "engine", "engine")
+	HostDiscoveryCmd.Flags().IntVar(&hostMaxRate, "max-rate", 0, "Maximum packets per second (0 is unlimited)")
+	configFlag(HostDiscoveryCmd.Flags(), "max-rate", "limits.max_rate")
+	profileFlag(HostDiscoveryCmd.Flags(), "max-rate", "max_rate")
 	HostDiscoveryCmd.Flags().StringVarP(&hostCustomOptions, "custom", "c", "", "Custom options for the scan, separated by commas")
 	// Adicione o comando ao rootCmd em root.go.
 }
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/ldap"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+// LDAPCmd consulta anonimamente o rootDSE dos hosts com 389/636/3268/3269 abertas e identifica os DCs.
+var LDAPCmd = &cobra.Command{
+	Use:   ldap.ModuleName,
+	Short: "Queries the LDAP rootDSE anonymously and identifies domain controllers",
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts := loadEnumerationHosts()
+
+		scanner := ldap.NewScanner()
+		scanner.Timeout = enumConnTimeout()
+		scanner.Threads = enumThreads
+
+		fmt.Printf("\n%s LDAP Enumeration", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		found := scanner.Run(context.Background(), hosts)
+		var controllers []string
+		for _, dse := range found {
+			role := ""
+			if dse.DomainController {
+				role = util.Yellow("[DC]")
+				controllers = append(controllers, fmt.Sprintf("%s (%s)", dse.Address, dse.DNSHostName))
+			}
+			fmt.Printf("%s %s:%d %s %s\n", util.MarkerGreen, dse.Address, dse.Port, util.Cyan(dse.DefaultNamingContext), role)
+			if dse.DNSHostName != "" {
+				fmt.Printf("    DNS Host Name   : %s\n", dse.DNSHostName)
+			}
+			if dse.DomainFunctionality != "" {
+				fmt.Printf("    Domain Level    : %s\n", dse.DomainFunctionality)
+			}
+			if dse.ForestFunctionality != "" {
+				fmt.Printf("    Forest Level    : %s\n", dse.ForestFunctionality)
+			}
+			if len(dse.SASLMechanisms) > 0 {
+				fmt.Printf("    SASL Mechanisms : %s\n", strings.Join(dse.SASLMechanisms, ", "))
+			}
+			fmt.Printf("    Controls        : %d\n", len(dse.Controls))
+		}
+
+		saveEnumerationHosts(hosts)
+		recordFindings(ldap.Findings(found))
+		fmt.Printf("%s LDAP hosts: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(found))))
+		fmt.Printf("%s Domain controllers: %s\n", util.MarkerGreen, util.Yellow(strconv.Itoa(len(controllers))))
+		for _, dc := range controllers {
+			fmt.Printf("    %s\n", dc)
+		}
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	EnumerationCmd.AddCommand(LDAPCmd)
+}
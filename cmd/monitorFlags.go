@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Arthx-x/arthxrecon/internal/monitor"
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	monTarget        string        // Alvos (IPs, CIDRs ou arquivo)
+	monMode          string        // Modo do scan: stealth, normal ou aggressive
+	monPortList      string        // Lista ou range de portas TCP
+	monUDPPorts      string        // Lista de portas UDP
+	monCategory      string        // Categorias de portas
+	monSimpleScan    bool          // Port scan simples (-sS)
+	monScripts       string        // Seleção de scripts NSE
+	monCustomOptions string        // Opções extras do Nmap, separadas por espaços
+	monSkipDiscovery bool          // Pula o host discovery e varre os alvos diretamente
+	monEngine        string        // Engine do host discovery: nmap ou masscan
+	monMaxRate       int           // Máximo de pacotes por segundo (0 = sem limite)
+	monInterval      time.Duration // Intervalo entre o início de duas execuções
+	monKeep          int           // Execuções mantidas (0 mantém todas)
+	monOnce          bool          // Executa uma única vez
+)
+
+// MonitorCmd repete host discovery e port scan a cada intervalo e notifica as mudanças entre as execuções.
+var MonitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: util.MonitorAppDescription,
+	Run: func(cmd *cobra.Command, args []string) {
+		targets, fileMode := util.ParseTargetInput(monTarget)
+		if len(targets) == 0 {
+			log.Fatal().Msg(util.ErrInvalidTarget)
+		}
+		if monInterval <= 0 {
+			log.Fatal().Msgf("%s invalid --interval %s: must be positive", util.FatalErrMonitor, monInterval)
+		}
+		if monKeep < 0 {
+			log.Fatal().Msgf("%s invalid --keep %d: must not be negative", util.FatalErrMonitor, monKeep)
+		}
+
+		// Sem notificadores configurados, as mudanças são exibidas e registradas no log.
+		notifiers := util.AppConfig.Notifiers
+		if len(notifiers) == 0 {
+			notifiers = []util.NotifierConfig{{Type: "log"}}
+		}
+		dispatcher, err := notify.New(notifiers)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrMonitor, err)
+		}
+
+		stages, err := buildStages(reconOptions{
+			Discovery:  !monSkipDiscovery,
+			PortScan:   true,
+			Output:     "monitor",
+			Engine:     monEngine,
+			Mode:       monMode,
+			PortList:   monPortList,
+			UDPPorts:   monUDPPorts,
+			Category:   monCategory,
+			SimpleScan: monSimpleScan,
+			Scripts:    monScripts,
+			Options:    strings.Fields(monCustomOptions),
+			MaxRate:    monMaxRate,
+		})
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrMonitor, err)
+		}
+
+		params := monitor.MonitorParams{
+			Targets:  targets,
+			FileMode: fileMode,
+			Stages:   stages,
+			Interval: monInterval,
+			Keep:     monKeep,
+			Once:     monOnce,
+		}
+
+		fmt.Printf("%s Monitor: every %s, results in %s\n", util.MarkerCyan, util.Cyan(monInterval.String()), util.Cyan(util.MonitorName))
+		fmt.Printf("%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		// Ctrl+C (ou SIGTERM) encerra o monitoramento; a execução em andamento é descartada.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		err = monitor.NewMonitor(params, monitor.NewStore(util.MonitorName), dispatcher).Run(ctx)
+		closeNotifier(dispatcher)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrMonitor, err)
+		}
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	MonitorCmd.Flags().StringVarP(&monTarget, "target", "t", "", "Target IP(s) or CIDR range, or path to file with targets (for multiple, separate by commas)")
+	MonitorCmd.Flags().StringVarP(&monMode, "mode", "m", "normal", "Scan mode: stealth, normal, or aggressive")
+	MonitorCmd.Flags().StringVarP(&monPortList, "ports", "p", "", "Port range or list to scan (e.g., \"1-1024\" or \"22,80,U:53,161\")")
+	MonitorCmd.Flags().StringVarP(&monUDPPorts, "udp", "u", "", "UDP ports to scan with -sU (e.g., \"161,500\")")
+	MonitorCmd.Flags().StringVarP(&monCategory, "category", "c", "", "Port categories to include, separated by commas (e.g., top12, web, windows, top100, top1000, all; see \"categories\")")
+	configFlag(MonitorCmd.Flags(), "mode", "scan.mode")
+	configFlag(MonitorCmd.Flags(), "category", "scan.category")
+	MonitorCmd.Flags().BoolVarP(&monSimpleScan, "simple", "s", false, "Use a simple port scan (e.g., -sS) instead of a detailed scan (-sV -sC)")
+	MonitorCmd.Flags().StringVar(&monScripts, "scripts", "", "NSE scripts or categories replacing -sC, optionally per port category")
+	MonitorCmd.Flags().StringVarP(&monCustomOptions, "custom", "x", "", "Custom options for the port scan, separated by spaces")
+	MonitorCmd.Flags().BoolVar(&monSkipDiscovery, "skip-discovery", false, "Skip host discovery and port scan the targets directly")
+	MonitorCmd.Flags().StringVar(&monEngine, "engine", "nmap", "Host discovery engine: nmap or masscan")
+	MonitorCmd.Flags().IntVar(&monMaxRate, "max-rate", 0, "Maximum packets per second of the scanners (0 is unlimited)")
+	configFlag(MonitorCmd.Flags(), "max-rate", "limits.max_rate")
+	MonitorCmd.Flags().DurationVar(&monInterval, "interval", 6*time.Hour, "Time between the start of two runs (e.g., 30m, 6h)")
+	MonitorCmd.Flags().IntVar(&monKeep, "keep", 0, "Keep only the last N runs (0 keeps all)")
+	configFlag(MonitorCmd.Flags(), "interval", "monitor.interval")
+	configFlag(MonitorCmd.Flags(), "keep", "monitor.keep")
+	MonitorCmd.Flags().BoolVar(&monOnce, "once", false, "Run once, compare with the previous run and exit")
+	for flag, key := range map[string]string{"engine": "engine", "mode": "mode", "category": "category", "simple": "simple",
+		"custom": "options", "max-rate": "max_rate"} {
+		profileFlag(MonitorCmd.Flags(), flag, key)
+	}
+}
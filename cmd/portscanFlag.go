@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/Arthx-x/arthxrecon/internal/nse"
 	"github.com/Arthx-x/arthxrecon/internal/portscan"
 	"github.com/Arthx-x/arthxrecon/util"
 	"github.com/spf13/cobra"
@@ -16,11 +18,14 @@ var (
 	psTarget        string // Alvo único ou múltiplos (IP(s) ou CIDR) passado via flag ou arquivo
 	psOutputFile    string // Nome base para os arquivos de saída
 	psPortList      string // Lista ou range de portas (ex: "1-1024")
+	psUDPPorts      string // Lista de portas UDP (ex: "161,162"), varridas com -sU
 	psMode          string // Modo do scan: aggressive, normal ou passive
-	psCategory      string // Categorias de portas a incluir (ex: top12, database, web, network, firewall, windows, vpn, all)
+	psCategory      string // Categorias de portas a incluir (ex: top12, database, web, network, firewall, windows, vpn, udp, all)
 	psAllPorts      bool   // Se definido, varre todas as portas (-p-) em execução separada em background
 	psSimpleScan    bool   // Se definido, realiza um portScan simples (ex.: -sS); caso contrário, usa -sV -sC
 	psCustomOptions string // Opções customizadas extras para o scan, separadas por espaços
+	psScripts       string // Scripts NSE, globais ou por categoria (ex: "web=http-title;default")
+	psMaxRate       int    // Máximo de pacotes por segundo (0 = sem limite)
 )
 
 // PortScanCmd é o comando para executar a varredura de portas.
@@ -50,10 +55,13 @@ var PortScanCmd = &cobra.Command{
 			Mode:       psMode,       // Modo do scan.
 			Options:    options,      // Opções customizadas extras.
 			PortList:   psPortList,   // Lista ou range de portas.
+			UDPPorts:   psUDPPorts,   // Lista de portas UDP.
 			Category:   psCategory,   // Categoria de portas, se definida.
 			AllPorts:   psAllPorts,   // Flag para varredura de todas as portas.
 			SimpleScan: psSimpleScan, // Flag para usar um scan simples.
 			FileMode:   fileMode,     // Indica se os alvos vieram de um arquivo.
+			Scripts:    psScripts,    // Seleção de scripts NSE.
+			MaxRate:    psMaxRate,    // Limite de pacotes por segundo.
 		}
 
 		// Seleciona a estratégia de port scan (aqui usamos Nmap como padrão).
@@ -70,13 +78,19 @@ var PortScanCmd = &cobra.Command{
 		portscan.ShowConfiguration(params)
 
 		// Executa o scan.
-		ports, err := orchestrator.Run()
+		hosts, err := orchestrator.Run(context.Background())
 		if err != nil {
 			log.Fatal().Msgf("%s %v", util.FatalErrPS, err)
 		}
 
-		// Exibe o resultado (por exemplo, quantidade de portas descobertas).
-		fmt.Printf("%s Ports discovered 01: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(ports))))
+		// Exibe o resultado (quantidade de portas descobertas por protocolo).
+		tcpPorts, udpPorts := 0, 0
+		for _, host := range hosts {
+			tcpPorts += len(host.Services)
+			udpPorts += len(host.UDPServices)
+		}
+		fmt.Printf("%s Ports discovered: %s TCP, %s UDP\n", util.MarkerGreen, util.Green(strconv.Itoa(tcpPorts)), util.Green(strconv.Itoa(udpPorts)))
+		recordFindings(nse.Collect(hosts))
 		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
 	},
 }
@@ -84,10 +98,21 @@ var PortScanCmd = &cobra.Command{
 func init() {
 	PortScanCmd.Flags().StringVarP(&psTarget, "target", "t", "./hostDiscovery/targets.txt", "Target IP(s) or CIDR range, or path to file with targets (if file, provide path; for multiple, separate by commas)")
 	PortScanCmd.Flags().StringVarP(&psOutputFile, "outfile", "o", "portscan", "Base name for output files")
-	PortScanCmd.Flags().StringVarP(&psPortList, "ports", "p", "", "Port range or list to scan (e.g., \"1-1024\")")
+	PortScanCmd.Flags().StringVarP(&psPortList, "ports", "p", "", "Port range or list to scan (e.g., \"1-1024\" or \"22,80,U:53,161\")")
+	PortScanCmd.Flags().StringVarP(&psUDPPorts, "udp", "u", "", "UDP ports to scan with -sU (e.g., \"161,500\"); without TCP ports or category, only UDP is scanned")
 	PortScanCmd.Flags().StringVarP(&psMode, "mode", "m", "normal", "Scan mode: aggressive, normal, or passive")
-	PortScanCmd.Flags().StringVarP(&psCategory, "category", "c", "", "Port category to include (e.g., top12, database, web, network, firewall, windows, vpn, all)")
+	PortScanCmd.Flags().StringVarP(&psCategory, "category", "c", "", "Port categories to include, separated by commas (e.g., top12, web, windows, top100, top1000, all; see \"categories\")")
+	configFlag(PortScanCmd.Flags(), "mode", "scan.mode")
+	configFlag(PortScanCmd.Flags(), "category", "scan.category")
+	profileFlag(PortScanCmd.Flags(), "mode", "mode")
+	profileFlag(PortScanCmd.Flags(), "category", "category")
 	PortScanCmd.Flags().BoolVarP(&psAllPorts, "allports", "a", false, "Scan all ports (-p-) in background")
 	PortScanCmd.Flags().BoolVarP(&psSimpleScan, "simple", "s", false, "Use a simple port scan (e.g., -sS) instead of a detailed scan (-sV -sC)")
+	PortScanCmd.Flags().StringVar(&psScripts, "scripts", "", "NSE scripts or categories replacing -sC, optionally per port category (e.g., \"default,vuln\" or \"web=http-title,http-headers;windows=smb2-security-mode\")")
 	PortScanCmd.Flags().StringVarP(&psCustomOptions, "custom", "x", "", "Custom options for the scan, separated by spaces")
+	PortScanCmd.Flags().IntVar(&psMaxRate, "max-rate", 0, "Maximum packets per second (0 is unlimited)")
+	profileFlag(PortScanCmd.Flags(), "simple", "simple")
+	profileFlag(PortScanCmd.Flags(), "custom", "options")
+	configFlag(PortScanCmd.Flags(), "max-rate", "limits.max_rate")
+	profileFlag(PortScanCmd.Flags(), "max-rate", "max_rate")
 }
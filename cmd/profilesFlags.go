@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// profileAnnotation marca as flags cujo valor padrão pode vir do perfil selecionado.
+const profileAnnotation = "profile"
+
+var rootProfile string // Perfil selecionado com --profile
+
+// ProfilesCmd agrupa os comandos de perfis de scan.
+var ProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Manages the named scan profiles selected with --profile",
+}
+
+// ProfilesListCmd lista os perfis com as opções já resolvidas pela herança.
+var ProfilesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the scan profiles and their resolved options",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, name := range util.AppConfig.ProfileNames() {
+			profile, chain, err := util.AppConfig.ResolveProfile(name)
+			if err != nil {
+				log.Fatal().Msgf("%s %v", util.FatalErrConfig, err)
+			}
+			fmt.Printf("\n%s %s", util.MarkerGreen, util.Green(name))
+			if len(chain) > 1 {
+				fmt.Printf(" (inherits %s)", strings.Join(chain[1:], " -> "))
+			}
+			fmt.Println()
+			if profile.Description != "" {
+				fmt.Printf("    %s\n", profile.Description)
+			}
+			for _, key := range util.ProfileKeys {
+				value, ok := profile.Lookup(key)
+				if !ok {
+					continue
+				}
+				if value == "" {
+					value = "(none)"
+				}
+				fmt.Printf("    %-9s %s\n", key, value)
+			}
+		}
+	},
+}
+
+// profileFlag associa uma flag a uma opção de perfil (ex.: "mode"). Com --profile, a flag não
+// informada assume o valor do perfil, que tem precedência sobre o config.toml.
+func profileFlag(flags *pflag.FlagSet, name, key string) {
+	if err := flags.SetAnnotation(name, profileAnnotation, []string{key}); err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	ProfilesCmd.AddCommand(ProfilesListCmd)
+}
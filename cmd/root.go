@@ -14,6 +14,9 @@ var rootCmd = &cobra.Command{
 	Short: util.AppDescription,
 	Long:  util.AppDescription,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		loadConfig() // Carrega o config.toml e inicializa o logger antes de qualquer comando
+		applyConfigFlags(cmd)
+		applyLimits(cmd)
 		util.Banner() // Chama seu banner antes de qualquer comando ser executado
 	},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -33,5 +36,23 @@ func init() {
 	// Registra os subcomandos
 	rootCmd.AddCommand(HostDiscoveryCmd)
 	rootCmd.AddCommand(PortScanCmd)
+	rootCmd.AddCommand(EnumerationCmd)
+	rootCmd.AddCommand(VulnAnalysisCmd)
+	rootCmd.AddCommand(FindingsCmd)
+	rootCmd.AddCommand(FullReconCmd)
+	rootCmd.AddCommand(MonitorCmd)
+	rootCmd.AddCommand(ServeCmd)
+	rootCmd.AddCommand(CoordinatorCmd)
+	rootCmd.AddCommand(AgentCmd)
+	rootCmd.AddCommand(ConfigCmd)
+	rootCmd.AddCommand(ProfilesCmd)
+	rootCmd.AddCommand(CategoriesCmd)
+	rootCmd.AddCommand(AuditCmd)
+	rootCmd.PersistentFlags().StringVar(&rootConfig, "config", "", "Configuration file (default: $ARTHXRECON_CONFIG, $XDG_CONFIG_HOME/arthxrecon/config.toml or ./config/config.toml)")
+	rootCmd.PersistentFlags().StringVar(&rootLogLevel, "log-level", "", "Log level: trace, debug, info, warn or error (default: log.level from the config)")
+	rootCmd.PersistentFlags().StringVar(&rootOperator, "operator", "", "Operator recorded in the audit log (default: audit.operator from the config or the current user)")
+	rootCmd.PersistentFlags().StringVar(&rootScopeFile, "scope-file", "", "Authorized scope file whose SHA-256 is recorded in the audit log (default: audit.scope_file from the config)")
+	rootCmd.PersistentFlags().StringVar(&rootScanWindow, "scan-window", "", "Daily window when scanning is allowed, e.g., \"22:00-06:00\" (default: limits.window from the config); scanners pause outside it")
+	rootCmd.PersistentFlags().StringVarP(&rootProfile, "profile", "P", "", "Scan profile from config/config.toml (see \"profiles list\"); explicit flags override it")
 	// Você pode adicionar outros subcomandos, como portscan, enumeration, etc.
 }
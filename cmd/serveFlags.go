@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/server"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveListen  string // Endereço HTTP da API
+	serveWorkers int    // Jobs executados ao mesmo tempo
+	serveQueue   int    // Jobs aguardando na fila
+	serveKeep    int    // Jobs concluídos mantidos na API
+)
+
+// ServeCmd expõe a API REST que recebe jobs de scan e os executa com o orquestrador do fullrecon.
+var ServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: util.ServeAppDescription,
+	Run: func(cmd *cobra.Command, args []string) {
+		token, generated, err := loadToken(util.ServerTokenEnv, util.AppConfig.Server.TokenFile)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrServe, err)
+		}
+		dispatcher, err := notify.New(util.AppConfig.Notifiers)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrServe, err)
+		}
+		srv, err := server.NewServer(server.ServerParams{
+			Listen:    serveListen,
+			Token:     token,
+			Workers:   serveWorkers,
+			QueueSize: serveQueue,
+			Keep:      serveKeep,
+			Build:     buildJobStages,
+			Notifier:  dispatcher,
+			OnFinish: func(state *fullrecon.State) {
+				recordFindings(state.Findings)
+			},
+		})
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrServe, err)
+		}
+
+		fmt.Printf("%s API listening on %s (%d workers, queue of %d)\n", util.MarkerCyan, util.Cyan("http://"+serveListen), serveWorkers, serveQueue)
+		if generated {
+			fmt.Printf("%s No token configured ($%s or server.token_file), using: %s\n", util.MarkerYellow, util.ServerTokenEnv, util.Yellow(token))
+		}
+
+		// Ctrl+C (ou SIGTERM) encerra o servidor e cancela os jobs em execução.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		err = srv.ListenAndServe(ctx)
+		closeNotifier(dispatcher)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrServe, err)
+		}
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+// loadToken retorna o token de $env ou, na falta dele, o conteúdo de file; sem nenhum dos dois,
+// gera um token aleatório para esta execução (generated=true).
+func loadToken(env, file string) (token string, generated bool, err error) {
+	if token = strings.TrimSpace(os.Getenv(env)); token != "" {
+		return token, false, nil
+	}
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", false, err
+		}
+		if token = strings.TrimSpace(string(data)); token == "" {
+			return "", false, fmt.Errorf("token file %s is empty", file)
+		}
+		return token, false, nil
+	}
+	random := make([]byte, 24)
+	if _, err := rand.Read(random); err != nil {
+		return "", false, err
+	}
+	return hex.EncodeToString(random), true, nil
+}
+
+// buildJobStages monta as etapas de um job da API. Cada opção vem do pedido, do perfil pedido
+// (ou do --profile do servidor) e da configuração, nesta ordem; os arquivos de cada etapa levam
+// o ID do job no nome, para que jobs simultâneos não se sobrescrevam.
+func buildJobStages(id string, req server.JobRequest) ([]fullrecon.Stage, error) {
+	cfg := util.AppConfig
+	var profile util.Profile
+	if name := req.Profile; name != "" || rootProfile != "" {
+		if name == "" {
+			name = rootProfile
+		}
+		var err error
+		if profile, _, err = cfg.ResolveProfile(name); err != nil {
+			return nil, err
+		}
+	}
+	first := func(values ...string) string {
+		for _, v := range values {
+			if v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	o := reconOptions{
+		Discovery:     req.HasStage(server.StageDiscovery),
+		PortScan:      req.HasStage(server.StagePortScan),
+		Enumeration:   req.HasStage(server.StageEnumeration),
+		VulnAnalysis:  req.HasStage(server.StageVulnAnalysis),
+		Output:        "job-" + id,
+		Engine:        first(profile.Engine, "nmap"),
+		Mode:          first(req.Mode, profile.Mode, cfg.Scan.Mode),
+		PortList:      req.Ports,
+		UDPPorts:      req.UDPPorts,
+		Category:      first(req.Category, profile.Category, cfg.Scan.Category),
+		Options:       profile.Options,
+		MaxRate:       cfg.Limits.MaxRate,
+		Timeout:       cfg.Enumeration.Timeout,
+		Threads:       cfg.Enumeration.Threads,
+		Enable:        strings.Join(profile.Modules, ","),
+		ModuleTimeout: cfg.Enumeration.ModuleTimeout,
+		PluginsDir:    cfg.Paths.Plugins,
+		Feed:          cfg.Paths.VulnFeed,
+	}
+	if profile.Simple != nil {
+		o.SimpleScan = *profile.Simple
+	}
+	if profile.MaxRate != nil {
+		o.MaxRate = *profile.MaxRate
+	}
+	if profile.Threads != nil {
+		o.Threads = *profile.Threads
+	}
+
+	if err := validateScanOptions(o.Mode, o.Category, o.PortList, o.UDPPorts); err != nil {
+		return nil, err
+	}
+	return buildStages(o)
+}
+
+// validateScanOptions confere o modo, as categorias e as listas de portas recebidas de fora da
+// linha de comando (pedidos da API e chunks do coordinator).
+func validateScanOptions(mode, category string, portSpecs ...string) error {
+	cfg := util.AppConfig
+	if _, err := cfg.TimingOptions(mode); err != nil {
+		return err
+	}
+	for _, c := range splitList(strings.ToLower(category)) {
+		if c != "all" && !cfg.HasCategory(c) {
+			return fmt.Errorf("unknown category %q", c)
+		}
+	}
+	for _, spec := range portSpecs {
+		if spec == "" {
+			continue
+		}
+		if err := util.ValidatePortSpec(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	ServeCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "Address of the HTTP API")
+	ServeCmd.Flags().IntVar(&serveWorkers, "workers", 1, "Number of jobs run at the same time")
+	ServeCmd.Flags().IntVar(&serveQueue, "queue", 20, "Number of jobs waiting to run; further submissions are rejected")
+	ServeCmd.Flags().IntVar(&serveKeep, "keep", 100, "Number of finished jobs kept by the API; older ones are removed (0 keeps all)")
+	configFlag(ServeCmd.Flags(), "listen", "server.listen")
+	configFlag(ServeCmd.Flags(), "workers", "server.workers")
+	configFlag(ServeCmd.Flags(), "queue", "server.queue")
+	configFlag(ServeCmd.Flags(), "keep", "server.keep")
+}
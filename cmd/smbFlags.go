@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/smb"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+// SMBCmd enumera os hosts com 139/445 abertas: dialetos, assinatura, NTLM e sessão nula.
+var SMBCmd = &cobra.Command{
+	Use:   smb.ModuleName,
+	Short: "Enumerates SMB dialects, signing, NTLM host info and null-session shares",
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts := loadEnumerationHosts()
+
+		scanner := smb.NewScanner()
+		scanner.Timeout = enumConnTimeout()
+		scanner.Threads = enumThreads
+
+		fmt.Printf("\n%s SMB Enumeration", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		found := scanner.Run(context.Background(), hosts)
+		relayable := 0
+		for _, info := range found {
+			signing := util.Green("signing required")
+			switch {
+			case info.SigningUnknown:
+				signing = util.Yellow("signing unknown")
+			case !info.SigningRequired:
+				signing = util.Red("signing not required")
+				relayable++
+			}
+			fmt.Printf("%s %s:%d %s [%s] %s\n", util.MarkerGreen, info.Address, info.Port,
+				util.Cyan(strings.Join(info.Dialects, ", ")), signing, info.OS)
+			if info.NetBIOSName != "" || info.DNSDomain != "" {
+				fmt.Printf("    Name: %s  Domain: %s  DNS: %s\n", info.NetBIOSName, info.NetBIOSDomain, info.DNSHostname)
+			}
+			if info.SMBv1 {
+				fmt.Printf("    %s %s\n", util.MarkerYellow, util.Yellow("SMBv1 enabled"))
+			}
+			if info.NullSession {
+				fmt.Printf("    %s %s\n", util.MarkerYellow, util.Yellow("Null session allowed"))
+			}
+			for _, share := range info.Shares {
+				fmt.Printf("    %-15s %-16s %s\n", share.Name, share.Type, share.Remark)
+			}
+		}
+
+		saveEnumerationHosts(hosts)
+		recordFindings(smb.Findings(found))
+		fmt.Printf("%s SMB hosts: %s (%s without signing)\n", util.MarkerGreen,
+			util.Green(strconv.Itoa(len(found))), util.Red(strconv.Itoa(relayable)))
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	EnumerationCmd.AddCommand(SMBCmd)
+}
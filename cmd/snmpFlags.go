@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/snmp"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snmpCommunities string // Community strings testadas, separadas por vírgula
+	snmpPort        uint16 // Porta UDP do agente SNMP
+	snmpAllHosts    bool   // Testa todos os hosts, mesmo sem 161/udp nos resultados
+	snmpScope       string // Escopo autorizado para as redes sugeridas
+	snmpTargetFile  string // Se definido, recebe as redes sugeridas dentro do escopo
+)
+
+// SNMPCmd testa community strings e percorre system, interfaces, ARP e rotas dos agentes SNMP.
+var SNMPCmd = &cobra.Command{
+	Use:   snmp.ModuleName,
+	Short: "Checks SNMP community strings and walks system, interface, ARP and route tables",
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts := loadEnumerationHosts()
+
+		scanner := snmp.NewScanner()
+		scanner.Port = snmpPort
+		scanner.Timeout = enumConnTimeout()
+		scanner.Threads = enumThreads
+		scanner.AllHosts = snmpAllHosts
+		var communities []string
+		for _, c := range strings.Split(snmpCommunities, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				communities = append(communities, c)
+			}
+		}
+		if len(communities) > 0 {
+			scanner.Communities = communities
+		}
+		if snmpScope != "" {
+			scope, err := util.LoadScope(snmpScope)
+			if err != nil {
+				log.Fatal().Msgf("%s %v", util.FatalErrEnum, err)
+			}
+			scanner.Scope = scope
+		}
+
+		fmt.Printf("\n%s SNMP Enumeration", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		found, suggestions := scanner.Run(context.Background(), hosts)
+		for _, info := range found {
+			fmt.Printf("%s %s community %s (%s)\n", util.MarkerRed, info.Address, util.Red(info.Community), info.Version)
+			fmt.Printf("    %s %s\n", util.Cyan(info.System.Name), info.System.Descr)
+			fmt.Printf("    Interfaces: %d  ARP: %d  Routes: %d\n", len(info.Interfaces), len(info.ARP), len(info.Routes))
+		}
+
+		var newTargets []string
+		for _, s := range suggestions {
+			status := util.Yellow("out of scope")
+			if s.InScope {
+				status = util.Green("in scope")
+				newTargets = append(newTargets, s.Subnet)
+			}
+			fmt.Printf("%s Suggested subnet %s (via %s) [%s]\n", util.MarkerYellow, s.Subnet, s.Source, status)
+		}
+		if snmpTargetFile != "" && len(newTargets) > 0 {
+			added, err := util.AppendTargetsToFile(snmpTargetFile, newTargets)
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to update %s", snmpTargetFile)
+			} else if len(added) > 0 {
+				fmt.Printf("%s Added %s subnets to: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(added))), util.Green(snmpTargetFile))
+			}
+		}
+
+		saveEnumerationHosts(hosts)
+		recordFindings(scanner.Findings(found))
+		fmt.Printf("%s SNMP agents: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(found))))
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	SNMPCmd.Flags().StringVar(&snmpCommunities, "communities", strings.Join(snmp.DefaultCommunities, ","), "Community strings to try, separated by commas")
+	SNMPCmd.Flags().Uint16Var(&snmpPort, "port", 161, "SNMP agent UDP port")
+	SNMPCmd.Flags().BoolVar(&snmpAllHosts, "all-hosts", false, "Try every host, not only the ones with 161/udp open or open|filtered")
+	SNMPCmd.Flags().StringVar(&snmpScope, "scope", "", "Authorized scope (IPs/CIDRs separated by commas, or file) for suggested subnets")
+	SNMPCmd.Flags().StringVar(&snmpTargetFile, "add-targets", "", "Targets file that receives suggested in-scope subnets (default: only suggest)")
+	EnumerationCmd.AddCommand(SNMPCmd)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/ssh"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+// SSHCmd audita os serviços SSH: banner, algoritmos, chaves de host e chaves compartilhadas.
+var SSHCmd = &cobra.Command{
+	Use:   ssh.ModuleName,
+	Short: "Audits SSH services: banner, algorithms, host keys and weak algorithms",
+	Run: func(cmd *cobra.Command, args []string) {
+		hosts := loadEnumerationHosts()
+
+		auditor := ssh.NewAuditor()
+		auditor.Timeout = enumConnTimeout()
+		auditor.Threads = enumThreads
+
+		fmt.Printf("\n%s SSH Audit", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+
+		found, shared := auditor.Run(context.Background(), hosts)
+		weak := 0
+		for _, info := range found {
+			fmt.Printf("%s %s:%d %s\n", util.MarkerGreen, info.Address, info.Port, util.Cyan(info.Banner))
+			for _, key := range info.HostKeys {
+				bits := ""
+				if key.Bits > 0 {
+					bits = fmt.Sprintf(" (%d bits)", key.Bits)
+				}
+				fmt.Printf("    Host Key        : %s %s%s\n", key.Type, key.SHA256, bits)
+			}
+			if len(info.KexAlgorithms) > 0 {
+				fmt.Printf("    Key Exchange    : %s\n", strings.Join(info.KexAlgorithms, ", "))
+			}
+			if len(info.Ciphers) > 0 {
+				fmt.Printf("    Ciphers         : %s\n", strings.Join(info.Ciphers, ", "))
+			}
+			if len(info.MACs) > 0 {
+				fmt.Printf("    MACs            : %s\n", strings.Join(info.MACs, ", "))
+			}
+			if len(info.Weaknesses) > 0 {
+				weak++
+			}
+			for _, w := range info.Weaknesses {
+				fmt.Printf("    Weak %-11s: %s [%s] %s\n", w.Category, w.Algorithm, severityLabel(w.Severity), w.Reason)
+			}
+		}
+
+		saveEnumerationHosts(hosts)
+		recordFindings(ssh.Findings(found, shared))
+		fmt.Printf("%s SSH services: %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(found))))
+		fmt.Printf("%s Services with weak algorithms: %s\n", util.MarkerGreen, util.Yellow(strconv.Itoa(weak)))
+		fmt.Printf("%s Shared host keys: %s\n", util.MarkerGreen, util.Red(strconv.Itoa(len(shared))))
+		for _, sk := range shared {
+			fmt.Printf("    %s %s: %s\n", sk.Type, sk.Fingerprint, strings.Join(sk.Hosts, ", "))
+		}
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	EnumerationCmd.AddCommand(SSHCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/internal/vulnanalysis"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	vulnInput      string  // Arquivo de entrada: XML do Nmap ou JSON de resultados
+	vulnOutputFile string  // Nome base para o relatório de vulnerabilidades
+	vulnFeed       string  // Caminho da base local de vulnerabilidades
+	vulnMinCVSS    float64 // CVSS mínimo para reportar
+	vulnMerge      bool    // Mantém as entradas atuais da base ao importar
+)
+
+// VulnAnalysisCmd cruza os serviços identificados com a base local de CVEs.
+var VulnAnalysisCmd = &cobra.Command{
+	Use:   "vulnanalysis",
+	Short: util.VulnAppDescription,
+	Run: func(cmd *cobra.Command, args []string) {
+		feed, err := vulnanalysis.LoadFeed(vulnFeed)
+		if err != nil {
+			log.Fatal().Msgf("%s %v (run \"vulnanalysis update\" first)", util.FatalErrVuln, err)
+		}
+		index := vulnanalysis.NewIndex(feed)
+
+		input := vulnInput
+		if input == "" {
+			input = filepath.Join(util.PortScanName, "portscan.xml")
+			if _, err := os.Stat(enumOutputPath()); err == nil {
+				input = enumOutputPath()
+			}
+		}
+		hosts, err := results.LoadFile(input)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrVuln, err)
+		}
+
+		fmt.Printf("\n%s Vulnerability Analysis", util.MarkerCyan)
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+		fmt.Printf("%s Loaded: %s (%s hosts)\n", util.MarkerGreen, util.Green(input), util.Green(strconv.Itoa(len(hosts))))
+		fmt.Printf("%s Feed: %s (%s CVEs, updated %s)\n", util.MarkerGreen, util.Green(vulnFeed),
+			util.Green(strconv.Itoa(index.Entries())), feed.Updated.Format("2006-01-02"))
+
+		matches := index.Run(hosts, vulnMinCVSS)
+		for _, m := range matches {
+			fmt.Printf("%s %s:%d/%s %s %s [%s %.1f]\n", util.MarkerGreen, m.Address, m.Port, m.Protocol,
+				util.Cyan(m.Product+" "+m.Version), m.CVE, severityLabel(m.Severity), m.CVSS)
+		}
+
+		if err := util.EnsureDir(util.VulnAnalysisName); err != nil {
+			log.Fatal().Msgf("Error creating directory %s: %v", util.VulnAnalysisName, err)
+		}
+		outPath := filepath.Join(util.VulnAnalysisName, vulnOutputFile+".json")
+		if err := results.SaveJSON(outPath, hosts); err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrVuln, err)
+		}
+		fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(outPath))
+		fmt.Printf("%s Vulnerabilities: %s\n", util.MarkerGreen, util.Red(strconv.Itoa(len(matches))))
+		var list []findings.Finding
+		for _, m := range matches {
+			list = append(list, m.Finding())
+		}
+		recordFindings(list)
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+// VulnUpdateCmd importa feeds baixados (NVD JSON 1.1/2.0 ou formato compacto) para a base local.
+var VulnUpdateCmd = &cobra.Command{
+	Use:   "update <archive> [archive...]",
+	Short: "Updates the local CVE feed from downloaded archives (.json, .json.gz, .zip, .tar.gz)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("\n%s %s Importing %d archive(s)\n", util.MarkerCyan, util.GetFormattedTime(), len(args))
+		feed, err := vulnanalysis.Import(vulnFeed, args, vulnMerge)
+		if err != nil {
+			log.Fatal().Msgf("%s %v", util.FatalErrVuln, err)
+		}
+		fmt.Printf("%s Feed: %s (%s CVEs)\n", util.MarkerGreen, util.Green(vulnFeed), util.Green(strconv.Itoa(len(feed.Entries))))
+		fmt.Printf("\n%s %s Finished\n", util.MarkerCyan, util.GetFormattedTime())
+	},
+}
+
+func init() {
+	VulnAnalysisCmd.PersistentFlags().StringVar(&vulnFeed, "feed", util.VulnFeedPath, "Path to the local vulnerability feed")
+	configFlag(VulnAnalysisCmd.PersistentFlags(), "feed", "paths.vuln_feed")
+	VulnAnalysisCmd.Flags().StringVarP(&vulnInput, "input", "i", "", "Nmap XML or results JSON to analyze (default: enumeration results or portScan/portscan.xml)")
+	VulnAnalysisCmd.Flags().StringVarP(&vulnOutputFile, "outfile", "o", "vulnanalysis", "Base name for the results file")
+	VulnAnalysisCmd.Flags().Float64Var(&vulnMinCVSS, "min-cvss", 0, "Only report vulnerabilities with at least this CVSS score")
+	VulnUpdateCmd.Flags().BoolVar(&vulnMerge, "merge", false, "Keep the current feed entries and update them with the imported ones")
+	VulnAnalysisCmd.AddCommand(VulnUpdateCmd)
+}
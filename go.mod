@@ -5,15 +5,17 @@ go 1.24.0
 require (
 	github.com/BurntSushi/toml v1.4.0
 	github.com/fatih/color v1.18.0
+	github.com/gosnmp/gosnmp v1.45.0
 	github.com/rs/zerolog v1.33.0
 	github.com/spf13/cobra v1.9.1
+	github.com/spf13/pflag v1.0.6
 	github.com/tomsteele/go-nmap v0.0.0-20191202052157-3507e0b03523
+	golang.org/x/crypto v0.45.0
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
-	github.com/spf13/pflag v1.0.6 // indirect
-	golang.org/x/sys v0.25.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
 )
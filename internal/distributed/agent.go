@@ -0,0 +1,339 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// Intervalos de nova tentativa do agent quando o coordinator não responde.
+const (
+	retryBase = time.Second
+	retryMax  = 30 * time.Second
+)
+
+// Respostas do coordinator que mudam o fluxo do agent.
+var (
+	errFinished     = errors.New("coordinator finished")
+	errUnknownAgent = errors.New("agent not registered")
+	errNotLeased    = errors.New("chunk not leased to this agent")
+)
+
+// AgentParams reúne os parâmetros do agent.
+type AgentParams struct {
+	Coordinator string   // URL do coordinator (http:// ou https://)
+	Token       string   // Token compartilhado que assina as mensagens
+	Name        string   // Nome exibido no coordinator
+	Networks    []string // Redes (CIDRs ou IPs) que o agent alcança
+	CACert      string   // CA do certificado do coordinator (vazio: CAs do sistema)
+	KeepRunning bool     // Ao fim de uma execução, aguarda a próxima em vez de encerrar
+
+	// Build monta as etapas locais (host discovery ou port scan) de um chunk.
+	Build func(chunk Chunk) ([]fullrecon.Stage, error)
+}
+
+// Agent recebe chunks do coordinator, executa-os com as estratégias locais e devolve os resultados.
+type Agent struct {
+	Params AgentParams
+
+	base      *url.URL
+	client    *http.Client
+	id        string
+	heartbeat time.Duration
+}
+
+// NewAgent é a factory que cria o Agent.
+func NewAgent(params AgentParams) (*Agent, error) {
+	base, err := url.Parse(params.Coordinator)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid coordinator URL %q (expected http://host:port or https://host:port)", params.Coordinator)
+	}
+	if params.Token == "" {
+		return nil, errors.New("an agent token is required")
+	}
+	if len(params.Networks) == 0 {
+		return nil, errors.New("no networks: set the networks the agent reaches")
+	}
+	for _, network := range params.Networks {
+		if _, err := parseTarget(network); err != nil {
+			return nil, err
+		}
+	}
+	if params.Build == nil {
+		return nil, errors.New("no stage builder")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if params.CACert != "" {
+		if base.Scheme != "https" {
+			return nil, errors.New("a CA certificate needs an https coordinator URL")
+		}
+		data, err := os.ReadFile(params.CACert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificate in %s", params.CACert)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &Agent{
+		Params: params,
+		base:   base,
+		// O timeout cobre a espera do lease e o envio dos resultados.
+		client: &http.Client{Transport: transport, Timeout: leasePollTimeout + time.Minute},
+	}, nil
+}
+
+// Run registra o agent e executa os chunks recebidos até ctx ser cancelado ou, sem KeepRunning,
+// até o coordinator terminar. Falhas de conexão são repetidas com backoff.
+func (a *Agent) Run(ctx context.Context) error {
+	logger := util.StageLogger(ModuleName)
+	delay := retryBase
+	wait := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+			delay = min(2*delay, retryMax)
+			return true
+		}
+	}
+
+loop:
+	for ctx.Err() == nil {
+		if a.id == "" {
+			if err := a.register(ctx); err != nil {
+				if ctx.Err() != nil {
+					break loop
+				}
+				if errors.Is(err, errFinished) && !a.Params.KeepRunning {
+					fmt.Printf("%s Coordinator has no work left\n", util.MarkerCyan)
+					return nil
+				}
+				logger.Warn().Err(err).Dur("retry", delay).Msg("Registration failed")
+				if !wait() {
+					break loop
+				}
+				continue
+			}
+			delay = retryBase
+		}
+
+		chunk, err := a.lease(ctx)
+		switch {
+		case ctx.Err() != nil:
+			continue
+		case errors.Is(err, errFinished):
+			fmt.Printf("%s %s Coordinator finished\n", util.MarkerCyan, util.GetFormattedTime())
+			if !a.Params.KeepRunning {
+				return nil
+			}
+			a.id = ""
+			if !wait() {
+				break loop
+			}
+			continue
+		case errors.Is(err, errUnknownAgent):
+			logger.Warn().Msg("Coordinator does not know this agent, registering again")
+			a.id = ""
+			continue
+		case err != nil:
+			logger.Warn().Err(err).Dur("retry", delay).Msg("Lease failed")
+			if !wait() {
+				break loop
+			}
+			continue
+		case chunk == nil:
+			continue
+		}
+		delay = retryBase
+
+		result, ok := a.execute(ctx, chunk)
+		if !ok {
+			// Interrompido: o coordinator devolve o chunk à fila quando o lease vencer.
+			break loop
+		}
+		a.send(ctx, chunk, result)
+	}
+	return nil
+}
+
+// register registra o agent e as redes que ele alcança.
+func (a *Agent) register(ctx context.Context) error {
+	name := a.Params.Name
+	if name == "" {
+		name, _ = os.Hostname()
+	}
+	var resp RegisterResponse
+	if _, err := a.post(ctx, pathRegister, RegisterRequest{Name: name, Networks: a.Params.Networks}, &resp); err != nil {
+		return err
+	}
+	a.id = resp.AgentID
+	a.heartbeat = time.Duration(max(resp.Heartbeat, 1)) * time.Second
+	util.StageLogger(ModuleName).Info().Str("id", a.id).Str("coordinator", a.base.Redacted()).Strs("networks", a.Params.Networks).Msg("Agent registered")
+	fmt.Printf("%s Registered on %s as %s (%s)\n", util.MarkerGreen, util.Cyan(a.base.Host), util.Green(name), strings.Join(a.Params.Networks, ", "))
+	return nil
+}
+
+// lease pede o próximo chunk; retorna nil quando o coordinator não tem chunks para o agent.
+func (a *Agent) lease(ctx context.Context) (*Chunk, error) {
+	var chunk Chunk
+	status, err := a.post(ctx, pathLease, LeaseRequest{AgentID: a.id}, &chunk)
+	if err != nil || status == http.StatusNoContent {
+		return nil, err
+	}
+	return &chunk, nil
+}
+
+// execute roda o chunk com as etapas locais, enviando heartbeats enquanto ele executa. Retorna
+// false se ctx for cancelado antes do fim.
+func (a *Agent) execute(ctx context.Context, chunk *Chunk) (ChunkResult, bool) {
+	logger := util.StageLogger(ModuleName).With().Str("chunk", chunk.ID).Str("kind", chunk.Kind).Logger()
+	logger.Info().Strs("targets", chunk.Targets).Int("attempt", chunk.Attempt).Msg("Chunk received")
+	fmt.Printf("%s %s Chunk %s (%s): %s\n", util.MarkerCyan, util.GetFormattedTime(), chunk.ID, chunk.Kind, util.Cyan(strings.Join(chunk.Targets, ", ")))
+
+	done := make(chan ChunkResult, 1)
+	go func() {
+		result := ChunkResult{AgentID: a.id, ChunkID: chunk.ID}
+		state, err := a.runStages(ctx, chunk)
+		switch {
+		case errors.Is(err, fullrecon.ErrNoLiveHosts):
+			// Nenhum host ativo no chunk não é uma falha.
+		case err != nil:
+			result.Error = err.Error()
+		default:
+			result.Alive, result.Hosts = state.Alive, state.Hosts
+		}
+		done <- result
+	}()
+
+	ticker := time.NewTicker(a.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case result := <-done:
+			if result.Error != "" {
+				logger.Error().Str("error", result.Error).Msg("Chunk failed")
+			} else {
+				logger.Info().Int("alive", len(result.Alive)).Int("hosts", len(result.Hosts)).Msg("Chunk completed")
+			}
+			return result, true
+		case <-ticker.C:
+			if _, err := a.post(ctx, pathHeartbeat, HeartbeatRequest{AgentID: a.id, ChunkID: chunk.ID}, nil); err != nil && ctx.Err() == nil {
+				logger.Warn().Err(err).Msg("Heartbeat failed")
+			}
+		case <-ctx.Done():
+			return ChunkResult{}, false
+		}
+	}
+}
+
+// runStages executa as etapas do chunk sobre um State com os alvos do chunk. Panics viram erro do chunk.
+func (a *Agent) runStages(ctx context.Context, chunk *Chunk) (state *fullrecon.State, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if err := chunk.Scan.Validate(); err != nil {
+		return nil, err
+	}
+	stages, err := a.Params.Build(*chunk)
+	if err != nil {
+		return nil, err
+	}
+	state = &fullrecon.State{Targets: chunk.Targets}
+	return state, fullrecon.NewFullReconOrchestrator(stages...).Run(ctx, state)
+}
+
+// send entrega o resultado do chunk, repetindo enquanto o coordinator não responder.
+func (a *Agent) send(ctx context.Context, chunk *Chunk, result ChunkResult) {
+	logger := util.StageLogger(ModuleName).With().Str("chunk", chunk.ID).Logger()
+	delay := retryBase
+	for {
+		_, err := a.post(ctx, pathResult, result, nil)
+		switch {
+		case err == nil && result.Error != "":
+			fmt.Printf("%s Chunk %s failure reported: %s\n", util.MarkerRed, chunk.ID, result.Error)
+			return
+		case err == nil:
+			fmt.Printf("%s Chunk %s sent: %d alive, %d hosts with ports\n", util.MarkerGreen, chunk.ID, len(result.Alive), len(result.Hosts))
+			return
+		case errors.Is(err, errNotLeased), errors.Is(err, errUnknownAgent), errors.Is(err, errFinished):
+			// O lease venceu e o chunk foi entregue a outro agent (ou o coordinator reiniciou).
+			logger.Warn().Err(err).Msg("Result discarded by the coordinator")
+			return
+		}
+		logger.Warn().Err(err).Dur("retry", delay).Msg("Sending result failed")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+			delay = min(2*delay, retryMax)
+		}
+	}
+}
+
+// post envia a mensagem assinada e confere a assinatura da resposta. Respostas 404, 409 e 410
+// viram errUnknownAgent, errNotLeased e errFinished; a resposta 200 é decodificada em out.
+func (a *Agent) post(ctx context.Context, path string, message, out interface{}) (int, error) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.base.JoinPath(path).String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	signature := signHeaders(req.Header, a.Params.Token, http.MethodPost, req.URL.Path, "", body)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageBody))
+	if err != nil {
+		return 0, err
+	}
+	if err := verifyHeaders(resp.Header, a.Params.Token, http.MethodPost, req.URL.Path, signature, data); err != nil {
+		return resp.StatusCode, fmt.Errorf("unauthenticated response from the coordinator (%s): %w", resp.Status, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if out != nil {
+			return resp.StatusCode, json.Unmarshal(data, out)
+		}
+		return resp.StatusCode, nil
+	case http.StatusNoContent:
+		return resp.StatusCode, nil
+	case http.StatusNotFound:
+		return resp.StatusCode, errUnknownAgent
+	case http.StatusConflict:
+		return resp.StatusCode, errNotLeased
+	case http.StatusGone:
+		return resp.StatusCode, errFinished
+	}
+	var failure struct {
+		Error string `json:"error"`
+	}
+	_ = json.Unmarshal(data, &failure)
+	return resp.StatusCode, fmt.Errorf("coordinator returned %s: %s", resp.Status, failure.Error)
+}
@@ -0,0 +1,124 @@
+package distributed
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// task é um chunk no coordinator: as redes já interpretadas e, quando entregue, o agent e o prazo.
+type task struct {
+	chunk    Chunk
+	networks []*net.IPNet
+	agent    string    // Agent que recebeu o chunk (vazio enquanto pendente)
+	deadline time.Time // Prazo do lease, renovado pelos heartbeats
+}
+
+// parseTarget interpreta um IP ou CIDR IPv4 como rede.
+func parseTarget(target string) (*net.IPNet, error) {
+	if !strings.Contains(target, "/") {
+		target += "/32"
+	}
+	ip, network, err := net.ParseCIDR(target)
+	if err != nil || ip.To4() == nil {
+		return nil, fmt.Errorf("invalid target %q (expected an IPv4 address or CIDR range)", target)
+	}
+	return network, nil
+}
+
+// size retorna o número de endereços da rede.
+func size(network *net.IPNet) uint64 {
+	ones, total := network.Mask.Size()
+	return 1 << uint(total-ones)
+}
+
+// halves divide a rede em duas metades; uma rede /32 não pode ser dividida.
+func halves(network *net.IPNet) (*net.IPNet, *net.IPNet, bool) {
+	ones, total := network.Mask.Size()
+	if ones >= total {
+		return nil, nil, false
+	}
+	mask := net.CIDRMask(ones+1, total)
+	first := binary.BigEndian.Uint32(network.IP.To4())
+	second := make(net.IP, 4)
+	binary.BigEndian.PutUint32(second, first|1<<uint(total-ones-1))
+	return &net.IPNet{IP: network.IP.To4(), Mask: mask}, &net.IPNet{IP: second, Mask: mask}, true
+}
+
+// subnets divide a rede em redes de no máximo limit endereços.
+func subnets(network *net.IPNet, limit uint64) []*net.IPNet {
+	if size(network) <= limit {
+		return []*net.IPNet{network}
+	}
+	first, second, _ := halves(network)
+	return append(subnets(first, limit), subnets(second, limit)...)
+}
+
+// overlaps indica se as redes têm algum endereço em comum.
+func overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// contains indica se a rede está inteiramente contida em outra.
+func contains(outer, inner *net.IPNet) bool {
+	outerOnes, _ := outer.Mask.Size()
+	innerOnes, _ := inner.Mask.Size()
+	return outer.Contains(inner.IP) && outerOnes <= innerOnes
+}
+
+// carve separa a rede nas partes alcançadas pelas redes do agent e nas demais, dividindo-a ao
+// meio enquanto ela for alcançada só em parte.
+func carve(network *net.IPNet, reach []*net.IPNet) (inside, outside []*net.IPNet) {
+	overlapping := false
+	for _, r := range reach {
+		if contains(r, network) {
+			return []*net.IPNet{network}, nil
+		}
+		overlapping = overlapping || overlaps(r, network)
+	}
+	if !overlapping {
+		return nil, []*net.IPNet{network}
+	}
+	first, second, _ := halves(network) // Uma /32 sobreposta sempre está contida
+	in1, out1 := carve(first, reach)
+	in2, out2 := carve(second, reach)
+	return append(in1, in2...), append(out1, out2...)
+}
+
+// discoveryChunks divide os alvos em grupos de até chunkSize endereços: redes maiores são
+// divididas e redes menores (ou IPs avulsos) são agrupadas.
+func discoveryChunks(targets []*net.IPNet, chunkSize int) [][]*net.IPNet {
+	limit := uint64(chunkSize)
+	var groups [][]*net.IPNet
+	var current []*net.IPNet
+	var addresses uint64
+	for _, target := range targets {
+		for _, network := range subnets(target, limit) {
+			if addresses+size(network) > limit && len(current) > 0 {
+				groups = append(groups, current)
+				current, addresses = nil, 0
+			}
+			current = append(current, network)
+			addresses += size(network)
+		}
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+// targetStrings converte as redes para os alvos do chunk: IPs para /32 e CIDRs para as demais.
+func targetStrings(networks []*net.IPNet) []string {
+	list := make([]string, 0, len(networks))
+	for _, network := range networks {
+		if ones, _ := network.Mask.Size(); ones == 32 {
+			list = append(list, network.IP.String())
+		} else {
+			list = append(list, network.String())
+		}
+	}
+	return list
+}
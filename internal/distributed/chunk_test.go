@@ -0,0 +1,214 @@
+package distributed
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// networks interpreta os alvos como redes, falhando o teste se algum for inválido.
+func networks(t *testing.T, targets ...string) []*net.IPNet {
+	t.Helper()
+	var list []*net.IPNet
+	for _, target := range targets {
+		network, err := parseTarget(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, network)
+	}
+	return list
+}
+
+func TestCarve(t *testing.T) {
+	for name, tc := range map[string]struct {
+		network string
+		reach   []string
+		inside  []string
+		outside []string
+	}{
+		"contained":        {"10.0.0.0/24", []string{"10.0.0.0/16"}, []string{"10.0.0.0/24"}, nil},
+		"disjoint":         {"10.0.0.0/24", []string{"10.1.0.0/16"}, nil, []string{"10.0.0.0/24"}},
+		"first half":       {"10.0.0.0/24", []string{"10.0.0.0/25"}, []string{"10.0.0.0/25"}, []string{"10.0.0.128/25"}},
+		"last quarter":     {"10.0.0.0/24", []string{"10.0.0.192/26"}, []string{"10.0.0.192/26"}, []string{"10.0.0.0/25", "10.0.0.128/26"}},
+		"two agents nets":  {"10.0.0.0/30", []string{"10.0.0.0/32", "10.0.0.3/32"}, []string{"10.0.0.0", "10.0.0.3"}, []string{"10.0.0.1", "10.0.0.2"}},
+		"single address":   {"10.0.0.7", []string{"10.0.0.0/29"}, []string{"10.0.0.7"}, nil},
+		"address outside":  {"10.0.0.9", []string{"10.0.0.0/29"}, nil, []string{"10.0.0.9"}},
+		"reach inside /32": {"10.0.0.5", []string{"10.0.0.5/32"}, []string{"10.0.0.5"}, nil},
+	} {
+		network := networks(t, tc.network)[0]
+		inside, outside := carve(network, networks(t, tc.reach...))
+		if got := targetStrings(inside); !equalStrings(got, tc.inside) {
+			t.Errorf("%s: inside %v, want %v", name, got, tc.inside)
+		}
+		if got := targetStrings(outside); !equalStrings(got, tc.outside) {
+			t.Errorf("%s: outside %v, want %v", name, got, tc.outside)
+		}
+	}
+}
+
+func TestDiscoveryChunks(t *testing.T) {
+	for name, tc := range map[string]struct {
+		targets []string
+		size    int
+		want    [][]string
+	}{
+		"split":           {[]string{"10.0.0.0/24"}, 128, [][]string{{"10.0.0.0/25"}, {"10.0.0.128/25"}}},
+		"group small":     {[]string{"10.0.0.0/26", "10.0.1.0/26", "10.0.2.0/26"}, 128, [][]string{{"10.0.0.0/26", "10.0.1.0/26"}, {"10.0.2.0/26"}}},
+		"group addresses": {[]string{"10.0.0.1", "10.0.0.9", "10.0.1.1"}, 2, [][]string{{"10.0.0.1", "10.0.0.9"}, {"10.0.1.1"}}},
+		"one per chunk":   {[]string{"10.0.0.0/31"}, 1, [][]string{{"10.0.0.0"}, {"10.0.0.1"}}},
+		"mixed":           {[]string{"10.0.0.5", "10.1.0.0/25", "10.2.0.0/30"}, 128, [][]string{{"10.0.0.5"}, {"10.1.0.0/25"}, {"10.2.0.0/30"}}},
+	} {
+		var got [][]string
+		for _, group := range discoveryChunks(networks(t, tc.targets...), tc.size) {
+			got = append(got, targetStrings(group))
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: chunks %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestLeaseSplitsPartialChunk(t *testing.T) {
+	c, err := NewCoordinator(CoordinatorParams{Token: "token", Targets: []string{"10.0.0.0/24", "10.9.0.1"}, ChunkSize: 512,
+		HostsPerChunk: 16, LeaseTimeout: time.Minute, MaxAttempts: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.pending) != 1 {
+		t.Fatalf("%d initial chunks, want 1", len(c.pending))
+	}
+	c.pending[0].chunk.Attempt = 1 // Já entregue uma vez e devolvido à fila
+	agent := &agentInfo{ID: "a1", Name: "a1", Networks: networks(t, "10.0.0.128/25")}
+	c.agents[agent.ID] = agent
+
+	leased := c.leaseLocked(agent)
+	if leased == nil || !equalStrings(leased.chunk.Targets, []string{"10.0.0.128/25"}) || leased.chunk.Attempt != 2 {
+		t.Fatalf("leased chunk: %+v", leased)
+	}
+	if leased.agent != "a1" || c.leased[leased.chunk.ID] != leased || agent.Chunks != 1 {
+		t.Errorf("lease not recorded: agent %q, chunks %d", leased.agent, agent.Chunks)
+	}
+	// O restante volta à fila como um novo chunk, na mesma tentativa do original.
+	if len(c.pending) != 1 {
+		t.Fatalf("%d pending chunks, want the remaining one", len(c.pending))
+	}
+	rest := c.pending[0]
+	if rest.chunk.ID == leased.chunk.ID || !equalStrings(rest.chunk.Targets, []string{"10.0.0.0/25", "10.9.0.1"}) || rest.chunk.Attempt != 1 {
+		t.Errorf("remaining chunk: %+v", rest.chunk)
+	}
+	if c.leaseLocked(agent) != nil {
+		t.Error("a chunk the agent does not reach was leased")
+	}
+	other := &agentInfo{ID: "a2", Networks: networks(t, "10.9.0.0/16", "10.0.0.0/24")}
+	if t2 := c.leaseLocked(other); t2 == nil || t2 != rest || len(c.pending) != 0 {
+		t.Errorf("chunk fully reached by a2: %+v, pending %d", t2, len(c.pending))
+	}
+}
+
+func TestDiscoveryResultCreatesPortScanChunks(t *testing.T) {
+	c, err := NewCoordinator(CoordinatorParams{Token: "token", Targets: []string{"10.0.0.0/29"}, ChunkSize: 256,
+		HostsPerChunk: 2, LeaseTimeout: time.Minute, MaxAttempts: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent := &agentInfo{ID: "a1", Name: "a1", Networks: networks(t, "10.0.0.0/24")}
+	c.agents[agent.ID] = agent
+	leased := c.leaseLocked(agent)
+
+	body, _ := json.Marshal(ChunkResult{AgentID: "a1", ChunkID: leased.chunk.ID,
+		Alive: []string{"10.0.0.1", "10.0.0.3", "10.0.0.3", "10.0.0.5", "10.0.0.9", "192.168.1.1"}})
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, signedRequest(pathResult, body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("result: %d %s", rec.Code, rec.Body)
+	}
+
+	// Os IPs fora do chunk (10.0.0.9, 192.168.1.1) são descartados e os repetidos contam uma vez.
+	var chunks [][]string
+	for _, p := range c.pending {
+		if p.chunk.Kind != KindPortScan || p.chunk.Attempt != 0 {
+			t.Errorf("new chunk: %+v", p.chunk)
+		}
+		chunks = append(chunks, p.chunk.Targets)
+	}
+	if want := [][]string{{"10.0.0.1", "10.0.0.3"}, {"10.0.0.5"}}; !reflect.DeepEqual(chunks, want) {
+		t.Errorf("port scan chunks %v, want %v", chunks, want)
+	}
+	if report := c.report(); !equalStrings(report.Alive, []string{"10.0.0.1", "10.0.0.3", "10.0.0.5"}) {
+		t.Errorf("alive hosts: %v", report.Alive)
+	}
+
+	// Port scan: hosts fora do chunk também são descartados.
+	scan := c.leaseLocked(agent)
+	body, _ = json.Marshal(ChunkResult{AgentID: "a1", ChunkID: scan.chunk.ID,
+		Hosts: []results.Host{{Address: "10.0.0.1"}, {Address: "10.0.0.5"}}})
+	rec = httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, signedRequest(pathResult, body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("port scan result: %d %s", rec.Code, rec.Body)
+	}
+	if report := c.report(); len(report.Hosts) != 1 || report.Hosts[0].Address != "10.0.0.1" {
+		t.Errorf("hosts: %+v", report.Hosts)
+	}
+}
+
+func TestUnreachableTimeout(t *testing.T) {
+	c, err := NewCoordinator(CoordinatorParams{Token: "token", Targets: []string{"10.0.0.0/30", "10.5.0.0/30"}, ChunkSize: 4,
+		HostsPerChunk: 16, LeaseTimeout: time.Minute, MaxAttempts: 1, UnreachableTimeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent := &agentInfo{ID: "a1", Name: "a1", Networks: networks(t, "10.0.0.0/24"), LastSeen: time.Now()}
+	c.agents[agent.ID] = agent
+
+	// Com um chunk em execução, a espera não começa.
+	leased := c.leaseLocked(agent)
+	c.expire()
+	if !c.stuckSince.IsZero() {
+		t.Fatal("waiting for an agent while a chunk runs")
+	}
+	delete(c.leased, leased.chunk.ID)
+
+	c.expire()
+	if c.stuckSince.IsZero() || c.done() {
+		t.Fatalf("only unreachable chunks left: stuck since %s, done %t", c.stuckSince, c.done())
+	}
+	c.stuckSince = time.Now().Add(-2 * time.Minute)
+	c.expire()
+	if !c.done() {
+		t.Fatal("run not finished after the unreachable timeout")
+	}
+	report := c.report()
+	if len(report.Pending) != 1 || !equalStrings(report.Pending[0].Targets, []string{"10.5.0.0/30"}) {
+		t.Errorf("pending chunks: %+v", report.Pending)
+	}
+
+	// Sem timeout, a espera é indefinida.
+	c2, _ := NewCoordinator(CoordinatorParams{Token: "token", Targets: []string{"10.5.0.0/30"}, ChunkSize: 4,
+		HostsPerChunk: 16, LeaseTimeout: time.Minute, MaxAttempts: 1})
+	c2.expire()
+	c2.stuckSince = time.Now().Add(-24 * time.Hour)
+	c2.expire()
+	if c2.done() {
+		t.Error("run finished without an unreachable timeout")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,681 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// Limites do coordinator.
+const (
+	maxMessageBody   = 32 << 20         // Tamanho máximo das mensagens dos agents (resultados de port scan)
+	leasePollTimeout = 25 * time.Second // Espera máxima do POST /agent/lease quando não há chunks para o agent
+	finishGrace      = 5 * time.Second  // Espera, ao terminar, para os agents receberem o aviso de encerramento
+	shutdownTimeout  = 10 * time.Second // Espera pelas requisições em andamento ao encerrar
+)
+
+// CoordinatorParams reúne os parâmetros do coordinator.
+type CoordinatorParams struct {
+	Listen        string        // Endereço HTTP(S) em que os agents se conectam (ex.: 0.0.0.0:8090)
+	Token         string        // Token compartilhado que assina as mensagens
+	TLSCert       string        // Certificado TLS (vazio: HTTP sem criptografia, mas ainda autenticado)
+	TLSKey        string        // Chave privada do certificado TLS
+	Targets       []string      // IPs ou CIDRs do escopo
+	Scan          ScanSpec      // Opções de scan enviadas aos agents
+	SkipDiscovery bool          // Divide os alvos direto em chunks de port scan
+	ChunkSize     int           // Endereços por chunk de host discovery
+	HostsPerChunk int           // Hosts por chunk de port scan
+	LeaseTimeout  time.Duration // Tempo sem heartbeat que devolve o chunk à fila
+	MaxAttempts   int           // Entregas de um chunk antes de ele ser dado como falho
+
+	// UnreachableTimeout encerra a execução quando, sem chunks em execução, os pendentes ficam esse
+	// tempo sem agent que os alcance; eles vão para Report.Pending. 0 espera indefinidamente.
+	UnreachableTimeout time.Duration
+}
+
+// FailedChunk é um chunk que falhou em todas as tentativas.
+type FailedChunk struct {
+	Chunk Chunk  `json:"chunk"`
+	Agent string `json:"agent"` // Agent da última tentativa
+	Error string `json:"error"`
+}
+
+// Report é o resultado consolidado da execução distribuída.
+type Report struct {
+	Alive   []string       // Hosts ativos encontrados pelos chunks de discovery
+	Hosts   []results.Host // Serviços encontrados pelos chunks de port scan
+	Failed  []FailedChunk  // Chunks que falharam em todas as tentativas
+	Pending []Chunk        // Chunks não executados (execução interrompida ou redes que nenhum agent alcança)
+}
+
+// agentInfo é um agent registrado.
+type agentInfo struct {
+	ID       string
+	Name     string
+	Addr     string
+	Networks []*net.IPNet
+	LastSeen time.Time
+	Chunks   int  // Chunks entregues ao agent
+	notified bool // Recebeu o aviso de encerramento
+}
+
+// Coordinator divide o escopo em chunks e os entrega aos agents conforme as redes que cada um alcança.
+type Coordinator struct {
+	Params CoordinatorParams
+
+	mu          sync.Mutex
+	agents      map[string]*agentInfo
+	pending     []*task
+	leased      map[string]*task
+	alive       map[string]bool
+	hosts       map[string]results.Host
+	failed      []FailedChunk
+	completed   int
+	seq         int
+	nonces      map[string]time.Time // Nonces das mensagens aceitas e quando elas saem da tolerância de relógio
+	unreachable int                  // Chunks pendentes sem agent que os alcance, no último aviso
+	stuckSince  time.Time            // Desde quando só restam chunks que nenhum agent alcança (zero: há trabalho possível)
+	changed     chan struct{}        // Fechado e substituído a cada mudança na fila, para acordar os leases
+	finished    chan struct{}        // Fechado quando todos os chunks terminaram
+}
+
+// NewCoordinator é a factory que cria o Coordinator com os chunks iniciais na fila.
+func NewCoordinator(params CoordinatorParams) (*Coordinator, error) {
+	if params.Token == "" {
+		return nil, errors.New("an agent token is required")
+	}
+	if (params.TLSCert == "") != (params.TLSKey == "") {
+		return nil, errors.New("TLS needs both a certificate and a key")
+	}
+	if params.ChunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", params.ChunkSize)
+	}
+	if params.HostsPerChunk <= 0 {
+		return nil, fmt.Errorf("hosts per chunk must be positive, got %d", params.HostsPerChunk)
+	}
+	if params.LeaseTimeout < 10*time.Second {
+		return nil, fmt.Errorf("lease timeout must be at least 10s, got %s", params.LeaseTimeout)
+	}
+	if params.MaxAttempts <= 0 {
+		return nil, fmt.Errorf("max attempts must be positive, got %d", params.MaxAttempts)
+	}
+	if params.UnreachableTimeout < 0 {
+		return nil, fmt.Errorf("unreachable timeout must not be negative, got %s", params.UnreachableTimeout)
+	}
+	if err := params.Scan.Validate(); err != nil {
+		return nil, err
+	}
+	var targets []*net.IPNet
+	for _, target := range params.Targets {
+		network, err := parseTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, network)
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no targets")
+	}
+
+	c := &Coordinator{
+		Params:   params,
+		agents:   make(map[string]*agentInfo),
+		leased:   make(map[string]*task),
+		alive:    make(map[string]bool),
+		hosts:    make(map[string]results.Host),
+		nonces:   make(map[string]time.Time),
+		changed:  make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	// Sem host discovery, os alvos vão direto para o port scan, em grupos de HostsPerChunk endereços.
+	kind, limit := KindDiscovery, params.ChunkSize
+	if params.SkipDiscovery {
+		kind, limit = KindPortScan, params.HostsPerChunk
+	}
+	for _, group := range discoveryChunks(targets, limit) {
+		c.pending = append(c.pending, c.newTaskLocked(kind, group))
+	}
+	return c, nil
+}
+
+// newTaskLocked cria um chunk com o próximo ID; c.mu deve estar obtido (ou o Coordinator ainda não compartilhado).
+func (c *Coordinator) newTaskLocked(kind string, networks []*net.IPNet) *task {
+	c.seq++
+	return &task{
+		chunk:    Chunk{ID: "c" + strconv.Itoa(c.seq), Kind: kind, Targets: targetStrings(networks), Scan: c.Params.Scan},
+		networks: networks,
+	}
+}
+
+// Handler retorna as rotas usadas pelos agents, todas autenticadas.
+func (c *Coordinator) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST "+pathRegister, c.signed(c.handleRegister))
+	mux.Handle("POST "+pathLease, c.signed(c.handleLease))
+	mux.Handle("POST "+pathHeartbeat, c.signed(c.handleHeartbeat))
+	mux.Handle("POST "+pathResult, c.signed(c.handleResult))
+	return mux
+}
+
+// Run atende os agents até todos os chunks terminarem ou ctx ser cancelado e retorna o resultado
+// consolidado (parcial, quando interrompido).
+func (c *Coordinator) Run(ctx context.Context) (*Report, error) {
+	listener, err := net.Listen("tcp", c.Params.Listen)
+	if err != nil {
+		return nil, err
+	}
+	return c.Serve(ctx, listener)
+}
+
+// Serve é Run sobre um listener já aberto.
+func (c *Coordinator) Serve(ctx context.Context, listener net.Listener) (*Report, error) {
+	logger := util.StageLogger(ModuleName)
+	httpServer := &http.Server{
+		Handler:           c.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	errc := make(chan error, 1)
+	go func() {
+		if c.Params.TLSCert != "" {
+			errc <- httpServer.ServeTLS(listener, c.Params.TLSCert, c.Params.TLSKey)
+			return
+		}
+		errc <- httpServer.Serve(listener)
+	}()
+	c.mu.Lock()
+	logger.Info().Str("listen", listener.Addr().String()).Bool("tls", c.Params.TLSCert != "").Int("chunks", len(c.pending)).Msg("Coordinator started")
+	c.mu.Unlock()
+
+	ticker := time.NewTicker(min(c.Params.LeaseTimeout/4, 5*time.Second))
+	defer ticker.Stop()
+	var err error
+loop:
+	for {
+		select {
+		case err = <-errc:
+			break loop
+		case <-ctx.Done():
+			break loop
+		case <-c.finished:
+			c.waitNotified(ctx)
+			break loop
+		case <-ticker.C:
+			c.expire()
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if shutdownErr := httpServer.Shutdown(shutdownCtx); err == nil {
+		err = shutdownErr
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return c.report(), err
+}
+
+// waitNotified espera, por até finishGrace, que todos os agents recebam o aviso de encerramento.
+func (c *Coordinator) waitNotified(ctx context.Context) {
+	deadline := time.After(finishGrace)
+	poll := time.NewTicker(100 * time.Millisecond)
+	defer poll.Stop()
+	for {
+		c.mu.Lock()
+		pending := 0
+		for _, agent := range c.agents {
+			if !agent.notified {
+				pending++
+			}
+		}
+		c.mu.Unlock()
+		if pending == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			return
+		case <-poll.C:
+		}
+	}
+}
+
+// expire devolve à fila os chunks cujo lease venceu, esquece os agents sem contato, avisa
+// quando há chunks que nenhum agent alcança e, passado UnreachableTimeout sem nada em execução,
+// encerra a execução com eles pendentes.
+func (c *Coordinator) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for _, t := range c.leased {
+		if now.After(t.deadline) {
+			agent := c.agentName(t.agent)
+			util.StageLogger(ModuleName).Warn().Str("chunk", t.chunk.ID).Str("agent", agent).Msg("Lease expired")
+			c.retryLocked(t, agent, "lease expired without heartbeat")
+		}
+	}
+	stale := max(c.Params.LeaseTimeout, 2*leasePollTimeout)
+	for id, agent := range c.agents {
+		if now.Sub(agent.LastSeen) > stale && !c.holdsLease(id) {
+			delete(c.agents, id)
+			util.StageLogger(ModuleName).Warn().Str("agent", agent.Name).Msg("Agent lost")
+			fmt.Printf("%s Agent %s lost (no contact for %s)\n", util.MarkerYellow, util.Yellow(agent.Name), stale)
+		}
+	}
+	for nonce, expiry := range c.nonces {
+		if now.After(expiry) {
+			delete(c.nonces, nonce)
+		}
+	}
+
+	var unreachable []string
+	for _, t := range c.pending {
+		if !c.reachable(t) {
+			unreachable = append(unreachable, t.chunk.Targets...)
+		}
+	}
+	if len(unreachable) != c.unreachable && len(unreachable) > 0 {
+		fmt.Printf("%s Waiting for an agent that reaches: %s\n", util.MarkerYellow, util.Yellow(strings.Join(unreachable, ", ")))
+	}
+	c.unreachable = len(unreachable)
+
+	stuck := len(c.leased) == 0 && len(c.pending) > 0 && c.unreachable == len(c.pending)
+	switch {
+	case !stuck:
+		c.stuckSince = time.Time{}
+	case c.stuckSince.IsZero():
+		c.stuckSince = now
+	case c.Params.UnreachableTimeout > 0 && now.Sub(c.stuckSince) >= c.Params.UnreachableTimeout && !c.done():
+		util.StageLogger(ModuleName).Warn().Int("chunks", len(c.pending)).Dur("waited", now.Sub(c.stuckSince)).Msg("No agent reaches the remaining chunks, finishing")
+		fmt.Printf("%s No agent reached the remaining chunks for %s, finishing\n", util.MarkerYellow, c.Params.UnreachableTimeout)
+		close(c.finished)
+		c.wakeLocked()
+	}
+}
+
+// reachable indica se algum agent registrado alcança parte do chunk.
+func (c *Coordinator) reachable(t *task) bool {
+	for _, agent := range c.agents {
+		for _, network := range t.networks {
+			for _, r := range agent.Networks {
+				if overlaps(r, network) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// holdsLease indica se o agent está executando algum chunk.
+func (c *Coordinator) holdsLease(agentID string) bool {
+	for _, t := range c.leased {
+		if t.agent == agentID {
+			return true
+		}
+	}
+	return false
+}
+
+// agentName retorna o nome do agent (ou o ID, se ele não estiver mais registrado).
+func (c *Coordinator) agentName(id string) string {
+	if agent := c.agents[id]; agent != nil {
+		return agent.Name
+	}
+	return id
+}
+
+// retryLocked tira o chunk do agent e o devolve ao fim da fila ou, esgotadas as tentativas, o dá como falho.
+func (c *Coordinator) retryLocked(t *task, agent, reason string) {
+	delete(c.leased, t.chunk.ID)
+	t.agent = ""
+	if t.chunk.Attempt >= c.Params.MaxAttempts {
+		c.failed = append(c.failed, FailedChunk{Chunk: t.chunk, Agent: agent, Error: reason})
+		fmt.Printf("%s Chunk %s (%s) failed after %d attempts: %s\n", util.MarkerRed, t.chunk.ID, strings.Join(t.chunk.Targets, ", "), t.chunk.Attempt, reason)
+		c.checkFinishedLocked()
+		return
+	}
+	c.pending = append(c.pending, t)
+	c.wakeLocked()
+}
+
+// checkFinishedLocked encerra a execução quando não há chunks pendentes nem em execução.
+func (c *Coordinator) checkFinishedLocked() {
+	if len(c.pending) > 0 || len(c.leased) > 0 {
+		return
+	}
+	select {
+	case <-c.finished:
+	default:
+		close(c.finished)
+		c.wakeLocked()
+	}
+}
+
+// done indica se todos os chunks terminaram.
+func (c *Coordinator) done() bool {
+	select {
+	case <-c.finished:
+		return true
+	default:
+		return false
+	}
+}
+
+// wakeLocked acorda os leases em espera.
+func (c *Coordinator) wakeLocked() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// report consolida os resultados recebidos, com os hosts em ordem de endereço.
+func (c *Coordinator) report() *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	report := &Report{Failed: c.failed}
+	for ip := range c.alive {
+		report.Alive = append(report.Alive, ip)
+	}
+	sort.Slice(report.Alive, func(i, j int) bool { return lessIP(report.Alive[i], report.Alive[j]) })
+	for _, host := range c.hosts {
+		report.Hosts = append(report.Hosts, host)
+	}
+	sort.Slice(report.Hosts, func(i, j int) bool { return lessIP(report.Hosts[i].Address, report.Hosts[j].Address) })
+	for _, t := range c.pending {
+		report.Pending = append(report.Pending, t.chunk)
+	}
+	for _, t := range c.leased {
+		report.Pending = append(report.Pending, t.chunk)
+	}
+	return report
+}
+
+// lessIP compara dois endereços IPv4 numericamente.
+func lessIP(a, b string) bool {
+	return bytes.Compare(net.ParseIP(a).To4(), net.ParseIP(b).To4()) < 0
+}
+
+// signed confere a assinatura da requisição e rejeita mensagens repetidas antes de chamar o handler
+// com o corpo lido.
+func (c *Coordinator) signed(next func(w http.ResponseWriter, r *http.Request, body []byte)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageBody))
+		if err != nil {
+			c.reply(w, r, http.StatusBadRequest, errorBody("invalid request: "+err.Error()))
+			return
+		}
+		if err := verifyHeaders(r.Header, c.Params.Token, r.Method, r.URL.Path, "", body); err != nil {
+			util.StageLogger(ModuleName).Warn().Str("remote", r.RemoteAddr).Err(err).Msg("Rejected agent message")
+			c.reply(w, r, http.StatusUnauthorized, errorBody(err.Error()))
+			return
+		}
+		nonce, expiry := nonceExpiry(r.Header)
+		c.mu.Lock()
+		_, replayed := c.nonces[nonce]
+		if !replayed {
+			c.nonces[nonce] = expiry
+		}
+		c.mu.Unlock()
+		if replayed {
+			util.StageLogger(ModuleName).Warn().Str("remote", r.RemoteAddr).Msg("Rejected replayed agent message")
+			c.reply(w, r, http.StatusUnauthorized, errorBody("replayed message"))
+			return
+		}
+		next(w, r, body)
+	})
+}
+
+// reply responde com o valor em JSON, assinado e vinculado à assinatura da requisição.
+func (c *Coordinator) reply(w http.ResponseWriter, r *http.Request, status int, value interface{}) {
+	var body []byte
+	if value != nil {
+		body, _ = json.Marshal(value)
+		w.Header().Set("Content-Type", "application/json")
+	}
+	signHeaders(w.Header(), c.Params.Token, r.Method, r.URL.Path, r.Header.Get(headerSignature), body)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// errorBody é o corpo {"error": message} das respostas de erro.
+func errorBody(message string) map[string]string {
+	return map[string]string{"error": message}
+}
+
+// decode interpreta o corpo JSON; em caso de erro, responde 400 e retorna false.
+func (c *Coordinator) decode(w http.ResponseWriter, r *http.Request, body []byte, out interface{}) bool {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		c.reply(w, r, http.StatusBadRequest, errorBody("invalid request: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+// agentLocked busca o agent registrado e atualiza o último contato; quando não existe, responde 404
+// (o agent se registra novamente) e retorna nil. c.mu deve estar obtido.
+func (c *Coordinator) agentLocked(w http.ResponseWriter, r *http.Request, id string) *agentInfo {
+	agent := c.agents[id]
+	if agent == nil {
+		c.reply(w, r, http.StatusNotFound, errorBody("unknown agent"))
+		return nil
+	}
+	agent.LastSeen = time.Now()
+	return agent
+}
+
+// handleRegister registra um agent e as redes que ele alcança.
+func (c *Coordinator) handleRegister(w http.ResponseWriter, r *http.Request, body []byte) {
+	var req RegisterRequest
+	if !c.decode(w, r, body, &req) {
+		return
+	}
+	var networks []*net.IPNet
+	for _, entry := range req.Networks {
+		network, err := parseTarget(entry)
+		if err != nil {
+			c.reply(w, r, http.StatusBadRequest, errorBody(err.Error()))
+			return
+		}
+		networks = append(networks, network)
+	}
+	if len(networks) == 0 {
+		c.reply(w, r, http.StatusBadRequest, errorBody("networks is required"))
+		return
+	}
+	if c.done() {
+		c.reply(w, r, http.StatusGone, errorBody("coordinator finished"))
+		return
+	}
+
+	id := make([]byte, 6)
+	_, _ = rand.Read(id)
+	agent := &agentInfo{ID: hex.EncodeToString(id), Name: req.Name, Addr: r.RemoteAddr, Networks: networks, LastSeen: time.Now()}
+	if agent.Name == "" {
+		agent.Name = r.RemoteAddr
+	}
+	c.mu.Lock()
+	c.agents[agent.ID] = agent
+	c.wakeLocked()
+	c.mu.Unlock()
+
+	util.StageLogger(ModuleName).Info().Str("agent", agent.Name).Str("id", agent.ID).Str("remote", agent.Addr).Strs("networks", targetStrings(networks)).Msg("Agent registered")
+	fmt.Printf("%s Agent %s registered from %s: %s\n", util.MarkerGreen, util.Green(agent.Name), agent.Addr, strings.Join(targetStrings(networks), ", "))
+	c.reply(w, r, http.StatusOK, RegisterResponse{AgentID: agent.ID, Heartbeat: int(max(c.Params.LeaseTimeout/3, time.Second) / time.Second)})
+}
+
+// handleLease entrega ao agent o próximo chunk que ele alcança. Sem chunks para ele, espera até
+// leasePollTimeout e responde 204; com todos os chunks terminados, responde 410.
+func (c *Coordinator) handleLease(w http.ResponseWriter, r *http.Request, body []byte) {
+	var req LeaseRequest
+	if !c.decode(w, r, body, &req) {
+		return
+	}
+	timeout := time.NewTimer(leasePollTimeout)
+	defer timeout.Stop()
+	for {
+		c.mu.Lock()
+		agent := c.agentLocked(w, r, req.AgentID)
+		if agent == nil {
+			c.mu.Unlock()
+			return
+		}
+		if c.done() {
+			agent.notified = true
+			c.mu.Unlock()
+			c.reply(w, r, http.StatusGone, errorBody("coordinator finished"))
+			return
+		}
+		t := c.leaseLocked(agent)
+		changed := c.changed
+		c.mu.Unlock()
+		if t != nil {
+			util.StageLogger(ModuleName).Info().Str("chunk", t.chunk.ID).Str("kind", t.chunk.Kind).Str("agent", agent.Name).
+				Strs("targets", t.chunk.Targets).Int("attempt", t.chunk.Attempt).Msg("Chunk leased")
+			c.reply(w, r, http.StatusOK, t.chunk)
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout.C:
+			c.reply(w, r, http.StatusNoContent, nil)
+			return
+		case <-r.Context().Done():
+			// Coordinator encerrando (ou agent desconectado): o agent tenta novamente mais tarde.
+			c.reply(w, r, http.StatusServiceUnavailable, errorBody("coordinator shutting down"))
+			return
+		}
+	}
+}
+
+// leaseLocked escolhe o primeiro chunk pendente que o agent alcança. Se ele alcança só parte do
+// chunk, o chunk é dividido e o restante volta à fila como um novo chunk.
+func (c *Coordinator) leaseLocked(agent *agentInfo) *task {
+	for i, t := range c.pending {
+		var inside, outside []*net.IPNet
+		for _, network := range t.networks {
+			in, out := carve(network, agent.Networks)
+			inside, outside = append(inside, in...), append(outside, out...)
+		}
+		if len(inside) == 0 {
+			continue
+		}
+		c.pending = append(c.pending[:i:i], c.pending[i+1:]...)
+		if len(outside) > 0 {
+			rest := c.newTaskLocked(t.chunk.Kind, outside)
+			rest.chunk.Attempt = t.chunk.Attempt
+			c.pending = append(c.pending[:i:i], append([]*task{rest}, c.pending[i:]...)...)
+			t.networks, t.chunk.Targets = inside, targetStrings(inside)
+		}
+		t.agent = agent.ID
+		t.chunk.Attempt++
+		t.deadline = time.Now().Add(c.Params.LeaseTimeout)
+		c.leased[t.chunk.ID] = t
+		agent.Chunks++
+		return t
+	}
+	return nil
+}
+
+// handleHeartbeat renova o lease do chunk em execução; responde 409 se o chunk não é mais do agent.
+func (c *Coordinator) handleHeartbeat(w http.ResponseWriter, r *http.Request, body []byte) {
+	var req HeartbeatRequest
+	if !c.decode(w, r, body, &req) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.agentLocked(w, r, req.AgentID) == nil {
+		return
+	}
+	t := c.leased[req.ChunkID]
+	if t == nil || t.agent != req.AgentID {
+		c.reply(w, r, http.StatusConflict, errorBody("chunk not leased to this agent"))
+		return
+	}
+	t.deadline = time.Now().Add(c.Params.LeaseTimeout)
+	c.reply(w, r, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleResult recebe o resultado de um chunk: os hosts ativos geram chunks de port scan e os
+// serviços são consolidados. Resultados fora dos alvos do chunk são descartados.
+func (c *Coordinator) handleResult(w http.ResponseWriter, r *http.Request, body []byte) {
+	var res ChunkResult
+	if !c.decode(w, r, body, &res) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	agent := c.agentLocked(w, r, res.AgentID)
+	if agent == nil {
+		return
+	}
+	t := c.leased[res.ChunkID]
+	if t == nil || t.agent != res.AgentID {
+		c.reply(w, r, http.StatusConflict, errorBody("chunk not leased to this agent"))
+		return
+	}
+	logger := util.StageLogger(ModuleName).With().Str("chunk", t.chunk.ID).Str("agent", agent.Name).Logger()
+	if res.Error != "" {
+		logger.Warn().Str("error", res.Error).Int("attempt", t.chunk.Attempt).Msg("Chunk failed")
+		fmt.Printf("%s Chunk %s failed on %s: %s\n", util.MarkerYellow, t.chunk.ID, util.Yellow(agent.Name), res.Error)
+		c.retryLocked(t, agent.Name, res.Error)
+		c.reply(w, r, http.StatusOK, map[string]string{"status": "ok"})
+		return
+	}
+
+	delete(c.leased, t.chunk.ID)
+	c.completed++
+	var summary string
+	switch t.chunk.Kind {
+	case KindDiscovery:
+		var alive []*net.IPNet
+		for _, ip := range res.Alive {
+			if !util.InScope(ip, t.networks) || c.alive[ip] {
+				continue
+			}
+			c.alive[ip] = true
+			network, _ := parseTarget(ip)
+			alive = append(alive, network)
+		}
+		for _, group := range discoveryChunks(alive, c.Params.HostsPerChunk) {
+			c.pending = append(c.pending, c.newTaskLocked(KindPortScan, group))
+		}
+		summary = fmt.Sprintf("%d hosts alive", len(alive))
+	case KindPortScan:
+		ports := 0
+		for _, host := range res.Hosts {
+			if !util.InScope(host.Address, t.networks) {
+				continue
+			}
+			c.hosts[host.Address] = host
+			ports += len(host.Services) + len(host.UDPServices)
+		}
+		summary = fmt.Sprintf("%d ports", ports)
+	}
+	logger.Info().Str("kind", t.chunk.Kind).Msg("Chunk completed: " + summary)
+	fmt.Printf("%s Chunk %s (%s) done by %s: %s [%d done, %d running, %d pending]\n", util.MarkerGreen, t.chunk.ID, t.chunk.Kind,
+		util.Green(agent.Name), util.Green(summary), c.completed, len(c.leased), len(c.pending))
+	c.wakeLocked()
+	c.checkFinishedLocked()
+	c.reply(w, r, http.StatusOK, map[string]string{"status": "ok"})
+}
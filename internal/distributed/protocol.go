@@ -0,0 +1,196 @@
+package distributed
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// ModuleName é o nome do estágio nos logs.
+const ModuleName = "distributed"
+
+// Tipos de chunk.
+const (
+	KindDiscovery = "discovery"
+	KindPortScan  = "portscan"
+)
+
+// Rotas do coordinator usadas pelos agents.
+const (
+	pathRegister  = "/agent/register"
+	pathLease     = "/agent/lease"
+	pathHeartbeat = "/agent/heartbeat"
+	pathResult    = "/agent/result"
+)
+
+// Cabeçalhos da autenticação: cada requisição e cada resposta levam o instante, um nonce e o
+// HMAC-SHA256 do conteúdo com o token compartilhado, que nunca trafega.
+const (
+	headerTimestamp = "X-Arthxrecon-Timestamp"
+	headerNonce     = "X-Arthxrecon-Nonce"
+	headerSignature = "X-Arthxrecon-Signature"
+	maxClockSkew    = 2 * time.Minute // Diferença máxima entre os relógios do agent e do coordinator
+)
+
+// ScanSpec são as opções de scan definidas no coordinator e aplicadas por todos os agents.
+type ScanSpec struct {
+	Engine     string   `json:"engine"`
+	Mode       string   `json:"mode"`
+	PortList   string   `json:"ports,omitempty"`
+	UDPPorts   string   `json:"udp_ports,omitempty"`
+	Category   string   `json:"category,omitempty"`
+	SimpleScan bool     `json:"simple,omitempty"`
+	Scripts    string   `json:"scripts,omitempty"`
+	Options    []string `json:"options,omitempty"`
+	MaxRate    int      `json:"max_rate,omitempty"`
+}
+
+// Opções do Nmap aceitas do coordinator: só timing e detecção. Opções de saída, de arquivos de
+// entrada, de scripts e de diretórios de dados ficam de fora, pois o agent costuma rodar o Nmap
+// como root e qualquer portador do token poderia ler ou gravar arquivos nele.
+var (
+	allowedFlags = map[string]bool{
+		"-Pn": true, "-n": true, "-sS": true, "-sT": true, "-sV": true, "-O": true, "--open": true, "--reason": true,
+		"--version-light": true, "--version-all": true, "--osscan-limit": true, "--osscan-guess": true,
+		"--defeat-rst-ratelimit": true,
+	}
+	allowedValueOptions = map[string]bool{
+		"--min-rate": true, "--max-rate": true, "--min-parallelism": true, "--max-parallelism": true,
+		"--min-hostgroup": true, "--max-hostgroup": true, "--min-rtt-timeout": true, "--max-rtt-timeout": true,
+		"--initial-rtt-timeout": true, "--max-retries": true, "--host-timeout": true, "--scan-delay": true,
+		"--max-scan-delay": true, "--version-intensity": true,
+	}
+	timingTemplate = regexp.MustCompile(`^-T[0-5]$`)
+	optionValue    = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(ms|s|m|h)?$`)
+)
+
+// Validate confere as opções de scan recebidas do coordinator antes de executá-las no agent.
+func (s ScanSpec) Validate() error {
+	for i := 0; i < len(s.Options); i++ {
+		option := s.Options[i]
+		if allowedFlags[option] || timingTemplate.MatchString(option) {
+			continue
+		}
+		name, value, inline := strings.Cut(option, "=")
+		if !allowedValueOptions[name] {
+			return fmt.Errorf("nmap option %q is not allowed from the coordinator", option)
+		}
+		if !inline {
+			if i+1 >= len(s.Options) {
+				return fmt.Errorf("nmap option %s needs a value", name)
+			}
+			i++
+			value = s.Options[i]
+		}
+		if !optionValue.MatchString(value) {
+			return fmt.Errorf("invalid value %q for nmap option %s", value, name)
+		}
+	}
+	if strings.ContainsAny(s.Scripts, `/\`) {
+		return fmt.Errorf("scripts %q: script paths are not allowed from the coordinator", s.Scripts)
+	}
+	return nil
+}
+
+// Chunk é uma parte do escopo entregue a um agent: host discovery de redes ou port scan de hosts.
+type Chunk struct {
+	ID      string   `json:"id"`
+	Kind    string   `json:"kind"`
+	Targets []string `json:"targets"` // IPs ou CIDRs
+	Scan    ScanSpec `json:"scan"`
+	Attempt int      `json:"attempt"` // Tentativa atual (1 na primeira entrega)
+}
+
+// RegisterRequest é o registro de um agent: o nome e as redes que ele alcança.
+type RegisterRequest struct {
+	Name     string   `json:"name"`
+	Networks []string `json:"networks"`
+}
+
+// RegisterResponse confirma o registro.
+type RegisterResponse struct {
+	AgentID   string `json:"agent_id"`
+	Heartbeat int    `json:"heartbeat"` // Intervalo, em segundos, dos heartbeats durante um chunk
+}
+
+// LeaseRequest pede o próximo chunk ao coordinator.
+type LeaseRequest struct {
+	AgentID string `json:"agent_id"`
+}
+
+// HeartbeatRequest informa que o agent continua executando o chunk.
+type HeartbeatRequest struct {
+	AgentID string `json:"agent_id"`
+	ChunkID string `json:"chunk_id"`
+}
+
+// ChunkResult é o resultado de um chunk: os hosts ativos (discovery) ou os serviços (portscan).
+type ChunkResult struct {
+	AgentID string         `json:"agent_id"`
+	ChunkID string         `json:"chunk_id"`
+	Alive   []string       `json:"alive,omitempty"`
+	Hosts   []results.Host `json:"hosts,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// sign calcula o HMAC-SHA256 de uma mensagem: método, caminho, instante, nonce e corpo. As respostas
+// incluem a assinatura da requisição em context, para não serem reaproveitadas em outra requisição.
+func sign(token, method, path, timestamp, nonce, context string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s\n%s\n", method, path, timestamp, nonce, context)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signHeaders acrescenta o instante, o nonce e a assinatura aos cabeçalhos. O nonce torna
+// únicas mensagens iguais enviadas no mesmo segundo (ex.: leases seguidos).
+func signHeaders(header http.Header, token, method, path, context string, body []byte) string {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	random := make([]byte, 12)
+	_, _ = rand.Read(random)
+	nonce := hex.EncodeToString(random)
+	signature := sign(token, method, path, timestamp, nonce, context, body)
+	header.Set(headerTimestamp, timestamp)
+	header.Set(headerNonce, nonce)
+	header.Set(headerSignature, signature)
+	return signature
+}
+
+// verifyHeaders confere a assinatura, o instante e a presença do nonce de uma mensagem recebida.
+// Quem recebe deve também recusar nonces repetidos (ver nonceExpiry).
+func verifyHeaders(header http.Header, token, method, path, context string, body []byte) error {
+	timestamp := header.Get(headerTimestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("missing or invalid timestamp")
+	}
+	if skew := time.Since(time.Unix(seconds, 0)); skew > maxClockSkew || skew < -maxClockSkew {
+		return fmt.Errorf("timestamp outside the allowed clock skew (%s)", skew.Round(time.Second))
+	}
+	if header.Get(headerNonce) == "" {
+		return errors.New("missing nonce")
+	}
+	expected := sign(token, method, path, timestamp, header.Get(headerNonce), context, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(headerSignature))) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// nonceExpiry retorna o nonce de uma mensagem aceita por verifyHeaders e o instante em que ela sai
+// da tolerância de relógio. Até lá, o nonce deve ser lembrado para recusar a mesma mensagem repetida;
+// depois, verifyHeaders já a recusa pelo instante.
+func nonceExpiry(header http.Header) (string, time.Time) {
+	seconds, _ := strconv.ParseInt(header.Get(headerTimestamp), 10, 64)
+	return header.Get(headerNonce), time.Unix(seconds, 0).Add(maxClockSkew)
+}
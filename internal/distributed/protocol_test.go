@@ -0,0 +1,150 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+)
+
+// newTestCoordinator cria um coordinator com um único chunk e o token "token".
+func newTestCoordinator(t *testing.T) *Coordinator {
+	t.Helper()
+	c, err := NewCoordinator(CoordinatorParams{Token: "token", Targets: []string{"10.0.0.0/30"}, ChunkSize: 256,
+		HostsPerChunk: 16, LeaseTimeout: time.Minute, MaxAttempts: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// signedRequest monta uma requisição assinada com o token do coordinator de teste.
+func signedRequest(path string, body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	signHeaders(req.Header, "token", http.MethodPost, path, "", body)
+	return req
+}
+
+// replay copia a requisição, com os mesmos cabeçalhos e corpo.
+func replay(req *http.Request, body []byte) *http.Request {
+	copied := httptest.NewRequest(req.Method, req.URL.Path, bytes.NewReader(body))
+	copied.Header = req.Header.Clone()
+	return copied
+}
+
+func TestSignedRejectsReplayedNonce(t *testing.T) {
+	c := newTestCoordinator(t)
+	calls := 0
+	h := c.signed(func(w http.ResponseWriter, r *http.Request, body []byte) {
+		calls++
+		c.reply(w, r, http.StatusOK, map[string]string{"ok": "yes"})
+	})
+	body := []byte(`{"agent_id":"a1"}`)
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := signedRequest(pathLease, body)
+	if rec := serve(first); rec.Code != http.StatusOK {
+		t.Fatalf("first message: %d %s", rec.Code, rec.Body)
+	}
+	rec := serve(replay(first, body))
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "replayed message") {
+		t.Errorf("replayed message: %d %s", rec.Code, rec.Body)
+	}
+	// A mesma mensagem com outro nonce (ex.: leases seguidos no mesmo segundo) é aceita.
+	if rec := serve(signedRequest(pathLease, body)); rec.Code != http.StatusOK {
+		t.Errorf("new nonce: %d %s", rec.Code, rec.Body)
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+
+	noNonce := signedRequest(pathLease, body)
+	noNonce.Header.Del(headerNonce)
+	if rec := serve(noNonce); rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "missing nonce") {
+		t.Errorf("message without nonce: %d %s", rec.Code, rec.Body)
+	}
+	forged := replay(first, body)
+	forged.Header.Set(headerNonce, "0123456789abcdef01234567")
+	if rec := serve(forged); rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "invalid signature") {
+		t.Errorf("message with a changed nonce: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestNonceExpiry(t *testing.T) {
+	c := newTestCoordinator(t)
+	header := http.Header{}
+	timestamp := time.Now().Add(-time.Minute).Truncate(time.Second)
+	header.Set(headerTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	header.Set(headerNonce, "n1")
+	nonce, expiry := nonceExpiry(header)
+	if nonce != "n1" || !expiry.Equal(timestamp.Add(maxClockSkew)) {
+		t.Errorf("nonceExpiry = %q, %s", nonce, expiry)
+	}
+
+	c.nonces["old"] = time.Now().Add(-time.Second)
+	c.nonces["recent"] = time.Now().Add(time.Minute)
+	c.expire()
+	if _, ok := c.nonces["old"]; ok {
+		t.Error("a nonce past the clock skew was kept")
+	}
+	if _, ok := c.nonces["recent"]; !ok {
+		t.Error("a nonce still within the clock skew was removed")
+	}
+}
+
+func TestScanSpecValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		spec ScanSpec
+		ok   bool
+	}{
+		"empty":           {ScanSpec{}, true},
+		"timing":          {ScanSpec{Options: []string{"-T4", "--min-rate", "500", "--host-timeout=30m", "--max-retries", "2"}}, true},
+		"detection":       {ScanSpec{Options: []string{"-sV", "--version-intensity", "5", "-O", "-Pn", "--open"}}, true},
+		"script names":    {ScanSpec{Scripts: "default,web=http-title"}, true},
+		"normal output":   {ScanSpec{Options: []string{"-oN", "/etc/cron.d/x"}}, false},
+		"xml output":      {ScanSpec{Options: []string{"-oX=/root/.ssh/authorized_keys"}}, false},
+		"input list":      {ScanSpec{Options: []string{"-iL", "/etc/shadow"}}, false},
+		"datadir":         {ScanSpec{Options: []string{"--datadir", "/tmp/nmap"}}, false},
+		"script option":   {ScanSpec{Options: []string{"--script", "/tmp/evil.nse"}}, false},
+		"script args":     {ScanSpec{Options: []string{"--script-args", "a=b"}}, false},
+		"resume":          {ScanSpec{Options: []string{"--resume", "/tmp/x"}}, false},
+		"value is a flag": {ScanSpec{Options: []string{"--min-rate", "-oN"}}, false},
+		"value is a path": {ScanSpec{Options: []string{"--host-timeout=/etc/passwd"}}, false},
+		"missing value":   {ScanSpec{Options: []string{"--max-rate"}}, false},
+		"bad timing":      {ScanSpec{Options: []string{"-T9"}}, false},
+		"script path":     {ScanSpec{Scripts: "/tmp/evil.nse"}, false},
+		"windows script":  {ScanSpec{Scripts: `web=..\evil`}, false},
+	} {
+		if err := tc.spec.Validate(); (err == nil) != tc.ok {
+			t.Errorf("%s: Validate() = %v", name, err)
+		}
+	}
+}
+
+func TestAgentRejectsUnsafeOptions(t *testing.T) {
+	built := false
+	a := &Agent{Params: AgentParams{Build: func(Chunk) ([]fullrecon.Stage, error) {
+		built = true
+		return nil, nil
+	}}}
+	chunk := &Chunk{ID: "c1", Kind: KindPortScan, Targets: []string{"10.0.0.1"}, Scan: ScanSpec{Options: []string{"-oN", "/etc/passwd"}}}
+	if _, err := a.runStages(context.Background(), chunk); err == nil || built {
+		t.Errorf("runStages = %v, stages built %v", err, built)
+	}
+
+	// O coordinator também recusa as opções ao iniciar.
+	if _, err := NewCoordinator(CoordinatorParams{Token: "token", Targets: []string{"10.0.0.0/30"}, ChunkSize: 256,
+		HostsPerChunk: 16, LeaseTimeout: time.Minute, MaxAttempts: 1, Scan: ScanSpec{Options: []string{"--script", "x"}}}); err == nil {
+		t.Error("coordinator accepted a script option")
+	}
+}
@@ -0,0 +1,187 @@
+package banner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ModuleName é o nome do módulo usado em logs e nos resultados.
+const ModuleName = "banner"
+
+// Result contém o banner coletado em uma porta e o serviço identificado a partir dele.
+type Result struct {
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	Probe   string `json:"probe"`             // Probe utilizado (ssh, smtp, generic...)
+	Banner  string `json:"banner"`            // Banner em formato imprimível
+	Service string `json:"service,omitempty"` // Serviço identificado pela tabela de assinaturas
+	Product string `json:"product,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Grabber conecta em portas TCP e coleta os primeiros bytes retornados pelo serviço.
+type Grabber struct {
+	Timeout  time.Duration // Tempo máximo de conexão e de espera pela primeira resposta
+	ReadSize int           // Quantidade máxima de bytes armazenados por banner
+	Threads  int           // Quantidade de conexões simultâneas
+	All      bool          // Se verdadeiro, coleta banner de todas as portas, mesmo as já identificadas pelo -sV
+}
+
+// NewGrabber é a factory que cria um Grabber com valores padrão.
+func NewGrabber() *Grabber {
+	return &Grabber{
+		Timeout:  5 * time.Second,
+		ReadSize: 512,
+		Threads:  20,
+	}
+}
+
+// Grab conecta em address:port, envia o probe adequado ao serviço (se houver) e retorna o banner.
+// O parâmetro service é o nome reportado pelo Nmap e pode ser vazio.
+func (g *Grabber) Grab(ctx context.Context, address string, port int, service string) (*Result, error) {
+	dialer := net.Dialer{Timeout: g.Timeout}
+	conn, err := util.DialContext(ctx, &dialer, "tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s:%d: %w", address, port, err)
+	}
+	defer conn.Close()
+
+	probe := selectProbe(port, service)
+	var data []byte
+	if !probe.SendFirst {
+		// Serviços como SSH, FTP e SMTP se apresentam logo após a conexão.
+		data = g.read(conn, g.Timeout)
+	}
+	if len(data) == 0 && probe.Payload != nil {
+		if _, err := conn.Write(probe.Payload); err != nil {
+			return nil, fmt.Errorf("failed to send %s probe to %s:%d: %w", probe.Name, address, port, err)
+		}
+		data = g.read(conn, g.Timeout)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no banner received from %s:%d", address, port)
+	}
+
+	result := &Result{
+		Address: address,
+		Port:    port,
+		Probe:   probe.Name,
+		Banner:  Printable(data),
+	}
+	if sig := Match(data); sig != nil {
+		result.Service = sig.Service
+		result.Product = sig.Product
+		result.Version = sig.Version
+	}
+	return result, nil
+}
+
+// read lê até ReadSize bytes. Após o primeiro pedaço recebido, aguarda apenas um curto intervalo
+// por dados adicionais, para não segurar a conexão até o timeout completo.
+func (g *Grabber) read(conn net.Conn, wait time.Duration) []byte {
+	buf := make([]byte, g.ReadSize)
+	total := 0
+	_ = conn.SetReadDeadline(time.Now().Add(wait))
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			break
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+	}
+	return buf[:total]
+}
+
+// Run coleta banners dos serviços TCP dos hosts e atualiza os registros de serviço em hosts.
+// Por padrão apenas serviços sem produto/versão identificados são consultados.
+func (g *Grabber) Run(ctx context.Context, hosts []results.Host) []Result {
+	type job struct {
+		host, svc int
+	}
+	var jobs []job
+	for h := range hosts {
+		for s, svc := range hosts[h].Services {
+			if svc.Protocol != "tcp" || (!g.All && svc.Identified()) {
+				continue
+			}
+			jobs = append(jobs, job{h, s})
+		}
+	}
+
+	threads := g.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	batch := util.NewProbeBatch(ctx, ModuleName, threads)
+	for _, j := range jobs {
+		batch.Add(hosts[j.host].Address, "tcp", hosts[j.host].Services[j.svc].Port)
+	}
+	batch.Start()
+	defer batch.Finish()
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		grabbed []Result
+		sem     = make(chan struct{}, threads)
+	)
+	for _, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		util.WaitWindow(ctx)
+		go func(j job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			host := &hosts[j.host]
+			svc := host.Services[j.svc]
+			result, err := g.Grab(ctx, host.Address, svc.Port, svc.Name)
+			if err != nil {
+				util.ModuleLogger(ModuleName).Debug().Err(err).Msg("Banner grab failed")
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			applyResult(&host.Services[j.svc], result)
+			grabbed = append(grabbed, *result)
+		}(j)
+	}
+	wg.Wait()
+	return grabbed
+}
+
+// applyResult grava o banner no serviço e completa nome, produto e versão quando o Nmap não os identificou.
+func applyResult(svc *results.Service, result *Result) {
+	svc.Banner = result.Banner
+	if result.Service == "" {
+		return
+	}
+	svc.Identify(ModuleName, result.Service, result.Product, result.Version)
+}
+
+// Printable converte os bytes recebidos em texto, escapando caracteres não imprimíveis.
+func Printable(data []byte) string {
+	var sb strings.Builder
+	for _, b := range data {
+		switch {
+		case b == '\r':
+			sb.WriteString(`\r`)
+		case b == '\n':
+			sb.WriteString(`\n`)
+		case b >= 0x20 && b < 0x7f:
+			sb.WriteByte(b)
+		default:
+			fmt.Fprintf(&sb, `\x%02x`, b)
+		}
+	}
+	return sb.String()
+}
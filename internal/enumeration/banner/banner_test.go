@@ -0,0 +1,200 @@
+package banner
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+func TestMatch(t *testing.T) {
+	for name, tc := range map[string]struct {
+		banner                    string
+		service, product, version string
+	}{
+		"openssh":          {"SSH-2.0-OpenSSH_8.9p1 Ubuntu-3ubuntu0.6\r\n", "ssh", "OpenSSH", "8.9p1"},
+		"dropbear":         {"SSH-2.0-dropbear_2020.81\r\n", "ssh", "Dropbear sshd", "2020.81"},
+		"other ssh":        {"SSH-2.0-Cisco-1.25\r\n", "ssh", "Cisco-1.25", ""},
+		"vsftpd":           {"220 (vsFTPd 3.0.3)\r\n", "ftp", "vsftpd", "3.0.3"},
+		"proftpd":          {"220 ProFTPD 1.3.5e Server (Debian) [::ffff:10.0.0.5]\r\n", "ftp", "ProFTPD", "1.3.5e"},
+		"filezilla":        {"220-FileZilla Server version 0.9.60 beta\r\n", "ftp", "FileZilla ftpd", "0.9.60"},
+		"microsoft ftp":    {"220 Microsoft FTP Service\r\n", "ftp", "Microsoft ftpd", ""},
+		"generic ftp":      {"220 Welcome to the corporate ftp server\r\n", "ftp", "", ""},
+		"postfix":          {"220 mail.corp.local ESMTP Postfix (Ubuntu)\r\n", "smtp", "Postfix smtpd", ""},
+		"exim":             {"220 mx.corp.local ESMTP Exim 4.96 Mon, 01 Jan 2024 00:00:00 +0000\r\n", "smtp", "Exim smtpd", "4.96"},
+		"exchange no ver":  {"220 EXCH01 Microsoft ESMTP MAIL Service ready at Mon, 1 Jan 2024\r\n", "smtp", "Microsoft ESMTP", ""},
+		"exchange version": {"220 EXCH01 Microsoft ESMTP MAIL Service, Version: 10.0.17763.1 ready\r\n", "smtp", "Microsoft ESMTP", "10.0.17763.1"},
+		"generic smtp":     {"220 relay SMTP ready\r\n", "smtp", "", ""},
+		"dovecot pop3":     {"+OK Dovecot (Ubuntu) ready.\r\n", "pop3", "Dovecot pop3d", ""},
+		"dovecot imap":     {"* OK [CAPABILITY IMAP4rev1] Dovecot ready.\r\n", "imap", "Dovecot imapd", ""},
+		"exchange imap":    {"* OK The Microsoft Exchange IMAP4 service is ready.\r\n", "imap", "Microsoft Exchange imapd", ""},
+		"redis info":       {"$120\r\n# Server\r\nredis_version:7.2.4\r\nredis_mode:standalone\r\n", "redis", "Redis key-value store", "7.2.4"},
+		"redis auth":       {"-NOAUTH Authentication required.\r\n", "redis", "Redis key-value store", ""},
+		"http server":      {"HTTP/1.1 400 Bad Request\r\nDate: Mon, 01 Jan 2024 00:00:00 GMT\r\nserver: nginx/1.24.0\r\n\r\n", "http", "nginx/1.24.0", ""},
+		"http":             {"HTTP/1.0 200 OK\r\n\r\n", "http", "", ""},
+		"vnc":              {"RFB 003.008\n", "vnc", "", "003.008"},
+		"unknown":          {"hello\r\n", "", "", ""},
+	} {
+		assertMatch(t, name, []byte(tc.banner), tc.service, tc.product, tc.version)
+	}
+}
+
+func TestMatchBinary(t *testing.T) {
+	// Pacotes binários: cada byte é comparado como latin-1, inclusive os bytes >= 0x80.
+	for name, tc := range map[string]struct {
+		banner                    []byte
+		service, product, version string
+	}{
+		"mysql":             {greeting(0x4a, "8.0.36-0ubuntu0.22.04.1"), "mysql", "MySQL", "8.0.36-0ubuntu0.22.04.1"},
+		"mysql long header": {greeting(0x85, "5.7.44-log"), "mysql", "MySQL", "5.7.44-log"},
+		"mariadb":           {greeting(0x5b, "5.5.5-10.11.6-MariaDB-0+deb12u1"), "mysql", "MariaDB", "10.11.6"},
+		"mysql not allowed": {append([]byte{0x45, 0x00, 0x00, 0x00, 0xff, 0x6a, 0x04}, "Host '10.0.0.9' is not allowed to connect to this MySQL server"...), "mysql", "MySQL", ""},
+		"telnet":            {[]byte{0xff, 0xfd, 0x18, 0xff, 0xfd, 0x20}, "telnet", "", ""},
+		"latin-1 ftp":       {[]byte("220 Servidor FTP da Administra\xe7\xe3o\r\n"), "ftp", "", ""},
+		"latin-1 ssh":       {[]byte("SSH-2.0-Servi\xe7o\r\n"), "ssh", "Serviço", ""},
+		"garbage":           {[]byte{0x80, 0x81, 0xfe, 0x00, 0x0a}, "", "", ""},
+	} {
+		assertMatch(t, name, tc.banner, tc.service, tc.product, tc.version)
+	}
+}
+
+// greeting monta o pacote de greeting do MySQL: cabeçalho de 4 bytes, protocolo 10 e versão.
+func greeting(length byte, version string) []byte {
+	packet := []byte{length, 0x00, 0x00, 0x00, 0x0a}
+	packet = append(packet, version...)
+	return append(packet, 0x00, 0x08, 0x00, 0x00, 0x00, 0xa5, 0x3f, 0x1e)
+}
+
+func assertMatch(t *testing.T, name string, banner []byte, service, product, version string) {
+	t.Helper()
+	id := Match(banner)
+	if service == "" {
+		if id != nil {
+			t.Errorf("%s: matched %+v", name, id)
+		}
+		return
+	}
+	if id == nil || id.Service != service || id.Product != product || id.Version != version {
+		t.Errorf("%s: Match = %+v, want %s/%s/%s", name, id, service, product, version)
+	}
+}
+
+func TestPrintable(t *testing.T) {
+	if got := Printable([]byte("220 ok\r\n\x00\xe7\x7f")); got != `220 ok\r\n\x00\xe7\x7f` {
+		t.Errorf("Printable = %q", got)
+	}
+}
+
+func TestSelectProbe(t *testing.T) {
+	for _, tc := range []struct {
+		port    int
+		service string
+		want    string
+	}{
+		{2222, "", "ssh"},
+		{8025, "smtp", "smtp"},
+		{6379, "", "redis"},
+		{21, "ssh", "ssh"}, // O nome do Nmap vence a porta
+		{21, "http", "ftp"},
+		{9999, "", "generic"},
+	} {
+		if got := selectProbe(tc.port, tc.service).Name; got != tc.want {
+			t.Errorf("selectProbe(%d, %q) = %s, want %s", tc.port, tc.service, got, tc.want)
+		}
+	}
+}
+
+// fakeService é um serviço em 127.0.0.1 que envia greeting na conexão e, se receber expect,
+// responde reply. Retorna a porta.
+func fakeService(t *testing.T, greeting, expect, reply string) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+				if greeting != "" {
+					_, _ = conn.Write([]byte(greeting))
+				}
+				if expect == "" {
+					return
+				}
+				buf := make([]byte, 64)
+				n, _ := conn.Read(buf)
+				if bytes.HasPrefix(buf[:n], []byte(expect)) {
+					_, _ = conn.Write([]byte(reply))
+				}
+			}()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestGrab(t *testing.T) {
+	g := NewGrabber()
+	g.Timeout = 500 * time.Millisecond
+	ctx := context.Background()
+
+	// O serviço se apresenta sozinho.
+	port := fakeService(t, "SSH-2.0-OpenSSH_9.6\r\n", "", "")
+	result, err := g.Grab(ctx, "127.0.0.1", port, "ssh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Probe != "ssh" || result.Banner != `SSH-2.0-OpenSSH_9.6\r\n` || result.Product != "OpenSSH" || result.Version != "9.6" {
+		t.Errorf("ssh result: %+v", result)
+	}
+
+	// O servidor só responde ao payload do probe genérico.
+	port = fakeService(t, "", "\r\n\r\n", "HTTP/1.1 400 Bad Request\r\nServer: Apache\r\n\r\n")
+	if result, err = g.Grab(ctx, "127.0.0.1", port, ""); err != nil || result.Probe != "generic" || result.Service != "http" || result.Product != "Apache" {
+		t.Errorf("generic result: %+v, %v", result, err)
+	}
+
+	// O payload do redis é enviado logo após conectar.
+	port = fakeService(t, "", "INFO server", "$30\r\n# Server\r\nredis_version:6.0.16\r\n")
+	if result, err = g.Grab(ctx, "127.0.0.1", port, "redis"); err != nil || result.Version != "6.0.16" {
+		t.Errorf("redis result: %+v, %v", result, err)
+	}
+
+	// Sem resposta, Grab falha.
+	port = fakeService(t, "", "nothing", "")
+	if result, err = g.Grab(ctx, "127.0.0.1", port, ""); err == nil {
+		t.Errorf("silent service: %+v", result)
+	}
+}
+
+func TestRunIdentifiesServices(t *testing.T) {
+	port := fakeService(t, "220 (vsFTPd 3.0.5)\r\n", "", "")
+	known := fakeService(t, "SSH-2.0-OpenSSH_9.6\r\n", "", "")
+	hosts := []results.Host{{Address: "127.0.0.1", Services: []results.Service{
+		{Protocol: "tcp", Port: port, Name: "unknown"},
+		{Protocol: "tcp", Port: known, Name: "ssh", Product: "OpenSSH", Version: "9.6"},
+	}}}
+	g := NewGrabber()
+	g.Timeout = 500 * time.Millisecond
+	grabbed := g.Run(context.Background(), hosts)
+
+	// Serviços já identificados pelo -sV não são consultados sem All.
+	if len(grabbed) != 1 || grabbed[0].Port != port {
+		t.Fatalf("grabbed: %+v", grabbed)
+	}
+	if svc := hosts[0].Services[0]; svc.Name != "ftp" || svc.Product != "vsftpd" || svc.Version != "3.0.5" || svc.Method != ModuleName || svc.Banner == "" {
+		t.Errorf("identified service: %+v", svc)
+	}
+	if svc := hosts[0].Services[1]; svc.Banner != "" {
+		t.Errorf("identified service grabbed: %+v", svc)
+	}
+}
@@ -0,0 +1,57 @@
+package banner
+
+import (
+	"context"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+)
+
+// Module expõe o Grabber como enumeration.Enumerator. Roda no estágio de identificação,
+// antes dos demais módulos, para que eles vejam os serviços identificados pelo banner.
+type Module struct {
+	Grabber *Grabber
+}
+
+// NewModule é a factory que cria o módulo a partir de um Grabber.
+func NewModule(g *Grabber) *Module {
+	return &Module{Grabber: g}
+}
+
+// Name retorna o nome do módulo.
+func (m *Module) Name() string { return ModuleName }
+
+// Stage coloca o módulo no estágio de identificação.
+func (m *Module) Stage() int { return enumeration.StageIdentify }
+
+// Selector seleciona os serviços TCP ainda não identificados (ou todos, com All).
+func (m *Module) Selector() enumeration.Selector {
+	return enumeration.Selector{Protocols: []string{"tcp"}, Unidentified: !m.Grabber.All}
+}
+
+// Enumerate coleta o banner de cada serviço selecionado.
+func (m *Module) Enumerate(ctx context.Context, target enumeration.Target) (*enumeration.Result, error) {
+	result := &enumeration.Result{}
+	var lastErr error
+	for _, svc := range target.Services {
+		if svc.Protocol != "tcp" {
+			continue
+		}
+		grabbed, err := m.Grabber.Grab(ctx, target.Host.Address, svc.Port, svc.Name)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		result.Services = append(result.Services, enumeration.ServiceResult{
+			Protocol: svc.Protocol,
+			Port:     svc.Port,
+			Name:     grabbed.Service,
+			Product:  grabbed.Product,
+			Version:  grabbed.Version,
+			Banner:   grabbed.Banner,
+		})
+	}
+	if len(result.Services) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package banner
+
+// Probe descreve o que enviar para um serviço a fim de obter seu banner.
+type Probe struct {
+	Name      string   // Nome do probe
+	Ports     []int    // Portas padrão do serviço
+	Services  []string // Nomes de serviço do Nmap que usam este probe
+	Payload   []byte   // Dados enviados caso o serviço não se apresente sozinho (nil = apenas leitura)
+	SendFirst bool     // Envia o payload logo após conectar, sem aguardar o servidor
+}
+
+// genericProbe é usado quando nenhum probe específico se aplica à porta.
+var genericProbe = Probe{
+	Name:    "generic",
+	Payload: []byte("\r\n\r\n"),
+}
+
+// probes contém os probes específicos por protocolo.
+var probes = []Probe{
+	{Name: "ssh", Ports: []int{22, 2222}, Services: []string{"ssh"}},
+	{Name: "ftp", Ports: []int{21}, Services: []string{"ftp"}},
+	{Name: "smtp", Ports: []int{25, 587}, Services: []string{"smtp", "submission"}, Payload: []byte("EHLO arthxrecon\r\n")},
+	{Name: "pop3", Ports: []int{110}, Services: []string{"pop3"}},
+	{Name: "imap", Ports: []int{143}, Services: []string{"imap"}},
+	{Name: "mysql", Ports: []int{3306}, Services: []string{"mysql"}},
+	{Name: "redis", Ports: []int{6379}, Services: []string{"redis"}, Payload: []byte("INFO server\r\n"), SendFirst: true},
+}
+
+// selectProbe escolhe o probe pelo nome do serviço reportado pelo Nmap e, na falta dele, pela porta.
+func selectProbe(port int, service string) Probe {
+	if service != "" {
+		for _, p := range probes {
+			for _, s := range p.Services {
+				if s == service {
+					return p
+				}
+			}
+		}
+	}
+	for _, p := range probes {
+		for _, pp := range p.Ports {
+			if pp == port {
+				return p
+			}
+		}
+	}
+	return genericProbe
+}
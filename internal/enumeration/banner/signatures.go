@@ -0,0 +1,87 @@
+package banner
+
+import "regexp"
+
+// Signature associa um padrão de banner a um serviço.
+// Product e Version aceitam referências aos grupos do padrão ($1, $2, ...).
+type Signature struct {
+	Service string
+	Product string
+	Version string
+	Pattern *regexp.Regexp
+}
+
+// Identification é o resultado da aplicação de uma assinatura sobre um banner.
+type Identification struct {
+	Service string
+	Product string
+	Version string
+}
+
+// signatures é a tabela de assinaturas embutida, avaliada em ordem: as mais específicas primeiro.
+var signatures = []Signature{
+	// SSH
+	{Service: "ssh", Product: "OpenSSH", Version: "$1", Pattern: regexp.MustCompile(`^SSH-[\d.]+-OpenSSH_([\w.]+)`)},
+	{Service: "ssh", Product: "Dropbear sshd", Version: "$1", Pattern: regexp.MustCompile(`^SSH-[\d.]+-dropbear_([\w.]+)`)},
+	{Service: "ssh", Product: "$1", Pattern: regexp.MustCompile(`^SSH-[\d.]+-([^\s\r\n]+)`)},
+
+	// FTP
+	{Service: "ftp", Product: "vsftpd", Version: "$1", Pattern: regexp.MustCompile(`^220[- ].*\(vsFTPd ([\w.]+)\)`)},
+	{Service: "ftp", Product: "ProFTPD", Version: "$1", Pattern: regexp.MustCompile(`^220[- ].*ProFTPD ([\w.]+)`)},
+	{Service: "ftp", Product: "FileZilla ftpd", Version: "$1", Pattern: regexp.MustCompile(`^220[- ].*FileZilla Server(?: version)? ([\w.]+)`)},
+	{Service: "ftp", Product: "Pure-FTPd", Pattern: regexp.MustCompile(`^220[- ].*Pure-FTPd`)},
+	{Service: "ftp", Product: "Microsoft ftpd", Pattern: regexp.MustCompile(`^220[- ].*Microsoft FTP Service`)},
+
+	// SMTP
+	{Service: "smtp", Product: "Postfix smtpd", Pattern: regexp.MustCompile(`^220[- ].*ESMTP Postfix`)},
+	{Service: "smtp", Product: "Exim smtpd", Version: "$1", Pattern: regexp.MustCompile(`^220[- ].*ESMTP Exim ([\w.]+)`)},
+	{Service: "smtp", Product: "Sendmail", Version: "$1", Pattern: regexp.MustCompile(`^220[- ].*ESMTP Sendmail ([\w.]+)`)},
+	{Service: "smtp", Product: "Microsoft ESMTP", Version: "$1", Pattern: regexp.MustCompile(`^220[- ].*Microsoft ESMTP MAIL Service(?:, Version: ([\w.]+))?`)},
+	{Service: "smtp", Pattern: regexp.MustCompile(`^220[- ].*E?SMTP`)},
+	{Service: "ftp", Pattern: regexp.MustCompile(`(?i)^220[- ].*FTP`)},
+
+	// POP3 / IMAP
+	{Service: "pop3", Product: "Dovecot pop3d", Pattern: regexp.MustCompile(`^\+OK.*Dovecot`)},
+	{Service: "pop3", Pattern: regexp.MustCompile(`^\+OK`)},
+	{Service: "imap", Product: "Dovecot imapd", Pattern: regexp.MustCompile(`^\* OK.*Dovecot`)},
+	{Service: "imap", Product: "Microsoft Exchange imapd", Pattern: regexp.MustCompile(`^\* OK.*Microsoft Exchange`)},
+	{Service: "imap", Pattern: regexp.MustCompile(`^\* OK`)},
+
+	// Redis
+	{Service: "redis", Product: "Redis key-value store", Version: "$1", Pattern: regexp.MustCompile(`redis_version:([\w.]+)`)},
+	{Service: "redis", Product: "Redis key-value store", Pattern: regexp.MustCompile(`^-(?:NOAUTH|DENIED)`)},
+
+	// MySQL / MariaDB: pacote de greeting (cabeçalho de 4 bytes + protocolo 10 + versão terminada em \x00).
+	{Service: "mysql", Product: "MariaDB", Version: "$1", Pattern: regexp.MustCompile(`(?s)^.{4}\x0a(?:5\.5\.5-)?([\w.]+)-MariaDB`)},
+	{Service: "mysql", Product: "MySQL", Version: "$1", Pattern: regexp.MustCompile(`(?s)^.{4}\x0a([\w.\-~+]+)\x00`)},
+	{Service: "mysql", Product: "MySQL", Pattern: regexp.MustCompile(`(?s)^.{4}\xff.{2}Host .* is not allowed to connect`)},
+
+	// Outros
+	{Service: "http", Product: "$1", Pattern: regexp.MustCompile(`(?s)^HTTP/1\.[01] \d{3}.*?\r\n(?i:server): ([^\r\n]+)`)},
+	{Service: "http", Pattern: regexp.MustCompile(`^HTTP/1\.[01] \d{3}`)},
+	{Service: "vnc", Version: "$1", Pattern: regexp.MustCompile(`^RFB (\d{3}\.\d{3})`)},
+	{Service: "telnet", Pattern: regexp.MustCompile(`^\xff[\xfb-\xfe]`)},
+}
+
+// Match aplica a tabela de assinaturas sobre o banner bruto e retorna a primeira identificação encontrada.
+func Match(data []byte) *Identification {
+	// Cada byte vira um rune (latin1), assim padrões como \xff e .{4} operam sobre bytes, não sobre UTF-8.
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	text := string(runes)
+
+	for _, sig := range signatures {
+		idx := sig.Pattern.FindStringSubmatchIndex(text)
+		if idx == nil {
+			continue
+		}
+		return &Identification{
+			Service: sig.Service,
+			Product: string(sig.Pattern.ExpandString(nil, sig.Product, text, idx)),
+			Version: string(sig.Pattern.ExpandString(nil, sig.Version, text, idx)),
+		}
+	}
+	return nil
+}
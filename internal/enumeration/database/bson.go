@@ -0,0 +1,169 @@
+package database
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// bsonElement é um par chave/valor de um documento BSON, preservando a ordem.
+type bsonElement struct {
+	Key   string
+	Value interface{}
+}
+
+// bsonEncode serializa um documento simples (string, int32, float64, bool e documentos aninhados).
+func bsonEncode(doc []bsonElement) []byte {
+	var body bytes.Buffer
+	for _, e := range doc {
+		switch v := e.Value.(type) {
+		case float64:
+			body.WriteByte(0x01)
+			body.WriteString(e.Key + "\x00")
+			_ = binary.Write(&body, binary.LittleEndian, math.Float64bits(v))
+		case string:
+			body.WriteByte(0x02)
+			body.WriteString(e.Key + "\x00")
+			_ = binary.Write(&body, binary.LittleEndian, int32(len(v)+1))
+			body.WriteString(v + "\x00")
+		case []bsonElement:
+			body.WriteByte(0x03)
+			body.WriteString(e.Key + "\x00")
+			body.Write(bsonEncode(v))
+		case bool:
+			body.WriteByte(0x08)
+			body.WriteString(e.Key + "\x00")
+			if v {
+				body.WriteByte(1)
+			} else {
+				body.WriteByte(0)
+			}
+		case int32:
+			body.WriteByte(0x10)
+			body.WriteString(e.Key + "\x00")
+			_ = binary.Write(&body, binary.LittleEndian, v)
+		}
+	}
+	out := make([]byte, 4, body.Len()+5)
+	binary.LittleEndian.PutUint32(out, uint32(body.Len()+5))
+	out = append(out, body.Bytes()...)
+	return append(out, 0)
+}
+
+// bsonDecode interpreta um documento BSON em um mapa. Tipos não tratados são ignorados.
+func bsonDecode(data []byte) (map[string]interface{}, error) {
+	if len(data) < 5 {
+		return nil, errors.New("truncated BSON document")
+	}
+	size := int(binary.LittleEndian.Uint32(data))
+	if size < 5 || size > len(data) {
+		return nil, errors.New("invalid BSON document length")
+	}
+	doc := make(map[string]interface{})
+	data = data[4 : size-1]
+	for len(data) > 0 {
+		kind := data[0]
+		end := bytes.IndexByte(data[1:], 0)
+		if end < 0 {
+			return nil, errors.New("truncated BSON key")
+		}
+		key := string(data[1 : end+1])
+		data = data[end+2:]
+
+		var n int
+		switch kind {
+		case 0x01: // double
+			if len(data) < 8 {
+				return nil, errors.New("truncated BSON double")
+			}
+			doc[key] = math.Float64frombits(binary.LittleEndian.Uint64(data))
+			n = 8
+		case 0x02, 0x0d, 0x0e: // string, javascript, symbol
+			if len(data) < 4 {
+				return nil, errors.New("truncated BSON string")
+			}
+			l := int(binary.LittleEndian.Uint32(data))
+			if l < 1 || 4+l > len(data) {
+				return nil, errors.New("invalid BSON string length")
+			}
+			doc[key] = string(data[4 : 4+l-1])
+			n = 4 + l
+		case 0x03, 0x04: // documento, array
+			if len(data) < 4 {
+				return nil, errors.New("truncated BSON document")
+			}
+			n = int(binary.LittleEndian.Uint32(data))
+			if n < 5 || n > len(data) {
+				return nil, errors.New("invalid BSON document length")
+			}
+			sub, err := bsonDecode(data[:n])
+			if err != nil {
+				return nil, err
+			}
+			if kind == 0x04 {
+				var list []interface{}
+				for i := 0; ; i++ {
+					v, ok := sub[fmt.Sprint(i)]
+					if !ok {
+						break
+					}
+					list = append(list, v)
+				}
+				doc[key] = list
+			} else {
+				doc[key] = sub
+			}
+		case 0x05: // binário
+			if len(data) < 5 {
+				return nil, errors.New("truncated BSON binary")
+			}
+			n = 5 + int(binary.LittleEndian.Uint32(data))
+		case 0x06, 0x0a, 0x7f, 0xff: // undefined, null, maxkey, minkey
+			doc[key] = nil
+		case 0x07: // ObjectId
+			n = 12
+		case 0x08: // bool
+			if len(data) < 1 {
+				return nil, errors.New("truncated BSON bool")
+			}
+			doc[key] = data[0] == 1
+			n = 1
+		case 0x09, 0x11, 0x12: // datetime, timestamp, int64
+			if len(data) < 8 {
+				return nil, errors.New("truncated BSON int64")
+			}
+			doc[key] = int64(binary.LittleEndian.Uint64(data))
+			n = 8
+		case 0x10: // int32
+			if len(data) < 4 {
+				return nil, errors.New("truncated BSON int32")
+			}
+			doc[key] = int32(binary.LittleEndian.Uint32(data))
+			n = 4
+		case 0x13: // decimal128
+			n = 16
+		default:
+			return nil, fmt.Errorf("unsupported BSON type 0x%02x", kind)
+		}
+		if n > len(data) {
+			return nil, errors.New("truncated BSON value")
+		}
+		data = data[n:]
+	}
+	return doc, nil
+}
+
+// bsonInt converte um valor numérico BSON para int.
+func bsonInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ModuleName é o nome do módulo usado em logs e nos resultados.
+const ModuleName = "database"
+
+// TagUnauthenticated marca hosts com algum banco de dados acessível sem autenticação.
+const TagUnauthenticated = "db-unauthenticated"
+
+// Info contém o resultado do fingerprint de um serviço de banco de dados.
+type Info struct {
+	Address         string             `json:"address"`
+	Port            int                `json:"port"`
+	Engine          string             `json:"engine"`
+	Version         string             `json:"version,omitempty"`
+	AuthRequired    bool               `json:"auth_required"`
+	AuthMethod      string             `json:"auth_method,omitempty"`
+	TLS             bool               `json:"tls,omitempty"`
+	Unauthenticated bool               `json:"unauthenticated"` // Acesso sem credenciais confirmado
+	Details         map[string]string  `json:"details,omitempty"`
+	Findings        []findings.Finding `json:"findings,omitempty"`
+}
+
+// setDetail registra uma informação adicional retornada pelo handshake.
+func (i *Info) setDetail(key, value string) {
+	if value == "" {
+		return
+	}
+	if i.Details == nil {
+		i.Details = make(map[string]string)
+	}
+	i.Details[key] = value
+}
+
+// flagUnauthenticated marca o serviço como acessível sem autenticação.
+func (i *Info) flagUnauthenticated(evidence string) {
+	i.Unauthenticated = true
+	i.AuthRequired = false
+	title := fmt.Sprintf("Unauthenticated %s access", i.Engine)
+	i.Findings = append(i.Findings, findings.New(ModuleName, findings.Slug(i.Engine, "unauthenticated"), title, findings.SeverityHigh, i.Address, i.Port, "tcp").
+		WithEvidence(evidence).
+		WithDetails("engine", i.Engine, "version", i.Version))
+}
+
+// driver fala o mínimo do protocolo de um banco para obter versão e requisitos de autenticação.
+type driver struct {
+	Engine   string   // Nome do banco
+	Ports    []int    // Portas padrão
+	Services []string // Nomes de serviço do Nmap
+	Probe    func(ctx context.Context, sc *Scanner, target string, info *Info) error
+}
+
+// drivers contém os bancos suportados, em ordem de preferência.
+var drivers = []driver{
+	{Engine: "mysql", Ports: []int{3306}, Services: []string{"mysql"}, Probe: probeMySQL},
+	{Engine: "postgresql", Ports: []int{5432}, Services: []string{"postgresql"}, Probe: probePostgres},
+	{Engine: "redis", Ports: []int{6379}, Services: []string{"redis"}, Probe: probeRedis},
+	{Engine: "mongodb", Ports: []int{27017, 27018, 27019}, Services: []string{"mongodb", "mongod"}, Probe: probeMongo},
+	{Engine: "elasticsearch", Ports: []int{9200}, Services: []string{"elasticsearch", "wap-wsp"}, Probe: probeElasticsearch},
+	{Engine: "memcached", Ports: []int{11211}, Services: []string{"memcached", "memcache"}, Probe: probeMemcached},
+}
+
+// selectDriver escolhe o driver pelo nome do serviço reportado pelo Nmap e, na falta dele, pela porta.
+func selectDriver(svc results.Service) (driver, bool) {
+	if svc.Name != "" {
+		for _, d := range drivers {
+			for _, s := range d.Services {
+				if s == svc.Name {
+					return d, true
+				}
+			}
+		}
+	}
+	for _, d := range drivers {
+		for _, p := range d.Ports {
+			if p == svc.Port {
+				return d, true
+			}
+		}
+	}
+	return driver{}, false
+}
+
+// Scanner identifica versão e requisitos de autenticação de serviços de banco de dados.
+type Scanner struct {
+	EmptyPassword bool          // Tenta login como root sem senha no MySQL
+	Timeout       time.Duration // Timeout por conexão e por resposta
+	Threads       int           // Quantidade de serviços verificados simultaneamente
+}
+
+// NewScanner é a factory que cria um Scanner com valores padrão.
+func NewScanner() *Scanner {
+	return &Scanner{
+		EmptyPassword: true,
+		Timeout:       5 * time.Second,
+		Threads:       10,
+	}
+}
+
+// dial abre uma conexão TCP com deadline já configurado.
+func (sc *Scanner) dial(ctx context.Context, target string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: sc.Timeout}
+	conn, err := util.DialContext(ctx, dialer, "tcp", target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(2 * sc.Timeout))
+	return conn, nil
+}
+
+// Fingerprint executa o handshake do banco informado em address:port.
+func (sc *Scanner) Fingerprint(ctx context.Context, engine, address string, port int) (*Info, error) {
+	for _, d := range drivers {
+		if d.Engine != engine {
+			continue
+		}
+		info := &Info{Address: address, Port: port, Engine: engine, AuthRequired: true}
+		if err := d.Probe(ctx, sc, net.JoinHostPort(address, strconv.Itoa(port)), info); err != nil {
+			return nil, fmt.Errorf("%s fingerprint failed: %w", engine, err)
+		}
+		return info, nil
+	}
+	return nil, fmt.Errorf("unsupported database engine: %s", engine)
+}
+
+// Run verifica os serviços de banco de dados dos hosts e anexa o resultado ao registro do serviço.
+func (sc *Scanner) Run(ctx context.Context, hosts []results.Host) []Info {
+	threads := sc.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	batch := util.NewProbeBatch(ctx, ModuleName, threads)
+	for i := range hosts {
+		for _, svc := range hosts[i].Services {
+			if _, ok := selectDriver(svc); ok {
+				batch.Add(hosts[i].Address, "tcp", svc.Port)
+			}
+		}
+	}
+	batch.Start()
+	defer batch.Finish()
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found []Info
+		sem   = make(chan struct{}, threads)
+	)
+	for i := range hosts {
+		for j := range hosts[i].Services {
+			d, ok := selectDriver(hosts[i].Services[j])
+			if !ok {
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			util.WaitWindow(ctx)
+			go func(host *results.Host, svc *results.Service, engine string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				info, err := sc.Fingerprint(ctx, engine, host.Address, svc.Port)
+				if err != nil {
+					util.ModuleLogger(ModuleName).Debug().Err(err).Msg("database fingerprint failed")
+					return
+				}
+
+				mu.Lock()
+				svc.SetEnrichment(ModuleName, info)
+				if svc.Product == "" {
+					svc.Product = info.Engine
+				}
+				if svc.Version == "" {
+					svc.Version = info.Version
+				}
+				if info.Unauthenticated {
+					host.AddTag(TagUnauthenticated)
+				}
+				found = append(found, *info)
+				mu.Unlock()
+			}(&hosts[i], &hosts[i].Services[j], d.Engine)
+		}
+	}
+	wg.Wait()
+	return found
+}
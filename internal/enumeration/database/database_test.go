@@ -0,0 +1,441 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// stub é um servidor em 127.0.0.1 que atende cada conexão com serve. Retorna a porta.
+func stub(t *testing.T, serve func(conn net.Conn)) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+				serve(conn)
+			}()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// fingerprint executa o driver engine contra a porta local.
+func fingerprint(t *testing.T, engine string, port int) *Info {
+	t.Helper()
+	sc := NewScanner()
+	sc.Timeout = time.Second
+	info, err := sc.Fingerprint(context.Background(), engine, "127.0.0.1", port)
+	if err != nil {
+		t.Fatalf("%s: %v", engine, err)
+	}
+	return info
+}
+
+// assertAccess confere o método de autenticação e o achado de acesso sem autenticação.
+func assertAccess(t *testing.T, name string, info *Info, method string, open bool) {
+	t.Helper()
+	if info.AuthMethod != method || info.Unauthenticated != open || info.AuthRequired == open {
+		t.Errorf("%s: auth method %q, unauthenticated %t, auth required %t", name, info.AuthMethod, info.Unauthenticated, info.AuthRequired)
+	}
+	if !open {
+		if len(info.Findings) != 0 {
+			t.Errorf("%s: findings %+v", name, info.Findings)
+		}
+		return
+	}
+	if len(info.Findings) != 1 || info.Findings[0].ID != info.Engine+"-unauthenticated" || info.Findings[0].Severity != findings.SeverityHigh {
+		t.Errorf("%s: findings %+v", name, info.Findings)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	for name, tc := range map[string]struct {
+		reply   string
+		method  string
+		open    bool
+		version string
+	}{
+		"open":      {"$56\r\n# Server\r\nredis_version:7.2.4\r\nredis_mode:standalone\r\n\r\n", "none", true, "7.2.4"},
+		"noauth":    {"-NOAUTH Authentication required.\r\n", "password", false, ""},
+		"protected": {"-DENIED Redis is running in protected mode\r\n", "", false, ""},
+	} {
+		port := stub(t, func(conn net.Conn) {
+			line := make([]byte, len("INFO server\r\n"))
+			if _, err := io.ReadFull(conn, line); err == nil && string(line) == "INFO server\r\n" {
+				_, _ = conn.Write([]byte(tc.reply))
+			}
+		})
+		info := fingerprint(t, "redis", port)
+		assertAccess(t, name, info, tc.method, tc.open)
+		if info.Version != tc.version {
+			t.Errorf("%s: version %q", name, info.Version)
+		}
+	}
+	if info := fingerprint(t, "redis", stub(t, func(conn net.Conn) {
+		_, _ = conn.Write([]byte("$55\r\n# Server\r\nredis_mode:standalone\r\n\r\nredis_mode:cluster\r\n"))
+	})); info.Details["mode"] != "cluster" {
+		t.Errorf("redis details: %v", info.Details)
+	}
+}
+
+func TestMemcached(t *testing.T) {
+	for name, tc := range map[string]struct {
+		reply  string
+		method string
+		open   bool
+	}{
+		"open": {"STAT pid 1\r\nSTAT version 1.6.21\r\nSTAT curr_items 42\r\nEND\r\n", "none", true},
+		"sasl": {"ERROR\r\n", "sasl", false},
+	} {
+		port := stub(t, func(conn net.Conn) {
+			buf := make([]byte, 7)
+			if _, err := io.ReadFull(conn, buf); err == nil && string(buf) == "stats\r\n" {
+				_, _ = conn.Write([]byte(tc.reply))
+			}
+		})
+		info := fingerprint(t, "memcached", port)
+		assertAccess(t, name, info, tc.method, tc.open)
+		if tc.open && (info.Version != "1.6.21" || info.Details["curr_items"] != "42") {
+			t.Errorf("%s: version %q, details %v", name, info.Version, info.Details)
+		}
+	}
+}
+
+func TestElasticsearch(t *testing.T) {
+	for name, tc := range map[string]struct {
+		status int
+		header string
+		body   string
+		method string
+		open   bool
+	}{
+		"open":     {http.StatusOK, "", `{"name":"node-1","cluster_name":"logs","version":{"number":"7.17.9"},"tagline":"You Know, for Search"}`, "none", true},
+		"security": {http.StatusUnauthorized, `Basic realm="security" charset="UTF-8"`, `{"error":"unauthorized"}`, "basic", false},
+		"opaque":   {http.StatusForbidden, "", "", "http", false},
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tc.header != "" {
+				w.Header().Set("WWW-Authenticate", tc.header)
+			}
+			w.WriteHeader(tc.status)
+			_, _ = io.WriteString(w, tc.body)
+		}))
+		port, _ := strconv.Atoi(srv.URL[strings.LastIndex(srv.URL, ":")+1:])
+		info := fingerprint(t, "elasticsearch", port)
+		srv.Close()
+		assertAccess(t, name, info, tc.method, tc.open)
+		if tc.open && (info.Version != "7.17.9" || info.Details["cluster_name"] != "logs" || info.TLS) {
+			t.Errorf("%s: version %q, details %v", name, info.Version, info.Details)
+		}
+	}
+
+	// Um listener HTTPS recusa HTTP puro com 400; o probe tenta de novo com TLS.
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"name":"os-1","cluster_name":"search","version":{"distribution":"opensearch","number":"2.11.0"}}`)
+	}))
+	defer srv.Close()
+	port, _ := strconv.Atoi(srv.URL[strings.LastIndex(srv.URL, ":")+1:])
+	info := fingerprint(t, "elasticsearch", port)
+	if !info.TLS || info.Version != "2.11.0" || info.Details["distribution"] != "opensearch" || !info.Unauthenticated {
+		t.Errorf("https: %+v", info)
+	}
+}
+
+// pgMessage monta uma mensagem do servidor PostgreSQL.
+func pgMessage(kind byte, payload []byte) []byte {
+	msg := []byte{kind, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(msg[1:], uint32(len(payload)+4))
+	return append(msg, payload...)
+}
+
+// pgAuth monta um AuthenticationRequest com o código e os dados informados.
+func pgAuth(code uint32, data string) []byte {
+	payload := binary.BigEndian.AppendUint32(nil, code)
+	return pgMessage('R', append(payload, data...))
+}
+
+// postgresStub responde 'S' ou 'N' ao SSLRequest e reply à StartupMessage do usuário postgres.
+func postgresStub(t *testing.T, ssl byte, reply ...[]byte) int {
+	return stub(t, func(conn net.Conn) {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		body := make([]byte, binary.BigEndian.Uint32(header)-4)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+		if binary.BigEndian.Uint32(body) == pgSSLRequestCode {
+			_, _ = conn.Write([]byte{ssl})
+			return
+		}
+		if binary.BigEndian.Uint32(body) != pgProtocolVersion3 || !bytes.Contains(body, []byte("user\x00postgres\x00")) {
+			t.Errorf("unexpected startup message: %q", body)
+			return
+		}
+		for _, r := range reply {
+			_, _ = conn.Write(r)
+		}
+	})
+}
+
+func TestPostgres(t *testing.T) {
+	trust := postgresStub(t, 'N', pgAuth(0, ""), pgMessage('S', []byte("server_version\x0016.2\x00")), pgMessage('S', []byte("TimeZone\x00UTC\x00")), pgMessage('Z', []byte{'I'}))
+	info := fingerprint(t, "postgresql", trust)
+	assertAccess(t, "trust", info, "trust", true)
+	if info.Version != "16.2" || info.TLS {
+		t.Errorf("trust: version %q, tls %t", info.Version, info.TLS)
+	}
+
+	scram := postgresStub(t, 'S', pgAuth(10, "SCRAM-SHA-256-PLUS\x00SCRAM-SHA-256\x00\x00"))
+	info = fingerprint(t, "postgresql", scram)
+	assertAccess(t, "scram", info, "sasl", false)
+	if !info.TLS || info.Details["sasl_mechanisms"] != "SCRAM-SHA-256-PLUS,SCRAM-SHA-256" {
+		t.Errorf("scram: tls %t, details %v", info.TLS, info.Details)
+	}
+
+	info = fingerprint(t, "postgresql", postgresStub(t, 'N', pgAuth(5, "salt")))
+	assertAccess(t, "md5", info, "md5", false)
+
+	hba := postgresStub(t, 'N', pgMessage('E', []byte("SFATAL\x00C28000\x00Mno pg_hba.conf entry for host \"10.0.0.9\"\x00RClientAuthentication\x00\x00")))
+	info = fingerprint(t, "postgresql", hba)
+	assertAccess(t, "pg_hba", info, "", false)
+	if info.Details["error_code"] != "28000" || !strings.HasPrefix(info.Details["error"], "no pg_hba.conf entry") {
+		t.Errorf("pg_hba: details %v", info.Details)
+	}
+}
+
+// mysqlGreeting monta a saudação do protocolo 10 com o plugin de autenticação informado.
+func mysqlGreeting(version, plugin string, ssl bool) []byte {
+	lower := uint16(mysqlClientProtocol41 | mysqlClientSecureConn)
+	if ssl {
+		lower |= mysqlClientSSL
+	}
+	var g bytes.Buffer
+	g.WriteByte(10)
+	g.WriteString(version + "\x00")
+	g.Write([]byte{1, 0, 0, 0})   // connection id
+	g.WriteString("abcdefgh\x00") // auth-plugin-data-part-1 + filler
+	g.Write(binary.LittleEndian.AppendUint16(nil, lower))
+	g.Write([]byte{33, 2, 0}) // charset + status
+	g.Write(binary.LittleEndian.AppendUint16(nil, mysqlClientPluginAuth>>16))
+	g.WriteByte(21)                   // tamanho do auth data
+	g.Write(make([]byte, 10))         // reservado
+	g.WriteString("ijklmnopqrst\x00") // auth-plugin-data-part-2
+	g.WriteString(plugin + "\x00")
+	return g.Bytes()
+}
+
+// mysqlStub envia greeting e, após o login, as respostas em replies (uma por pacote recebido).
+func mysqlStub(t *testing.T, greeting []byte, replies ...[]byte) int {
+	return stub(t, func(conn net.Conn) {
+		if err := mysqlWritePacket(conn, 0, greeting); err != nil {
+			return
+		}
+		for _, reply := range replies {
+			payload, seq, err := mysqlReadPacket(conn)
+			if err != nil {
+				return
+			}
+			if seq == 1 && !bytes.Contains(payload, []byte("root\x00\x00")) {
+				t.Errorf("login packet without root and empty password: %q", payload)
+			}
+			if err := mysqlWritePacket(conn, seq+1, reply); err != nil {
+				return
+			}
+		}
+	})
+}
+
+// mysqlErr monta um pacote ERR com SQLSTATE.
+func mysqlErr(code uint16, state, msg string) []byte {
+	return append(binary.LittleEndian.AppendUint16([]byte{0xff}, code), "#"+state+msg...)
+}
+
+func TestMySQL(t *testing.T) {
+	ok := []byte{0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00}
+	open := mysqlStub(t, mysqlGreeting("8.0.36", "caching_sha2_password", true), ok)
+	info := fingerprint(t, "mysql", open)
+	assertAccess(t, "empty root password", info, "caching_sha2_password", true)
+	if info.Version != "8.0.36" || !info.TLS {
+		t.Errorf("greeting: version %q, tls %t", info.Version, info.TLS)
+	}
+
+	// O servidor troca o plugin (auth switch) antes de aceitar a senha vazia.
+	authSwitch := append([]byte{0xfe}, "mysql_native_password\x00abcdefghijklmnopqrst\x00"...)
+	info = fingerprint(t, "mysql", mysqlStub(t, mysqlGreeting("5.5.5-10.11.6-MariaDB", "mysql_native_password", false), authSwitch, ok))
+	assertAccess(t, "auth switch", info, "mysql_native_password", true)
+	if info.Details["flavor"] != "mariadb" {
+		t.Errorf("mariadb details: %v", info.Details)
+	}
+
+	denied := mysqlStub(t, mysqlGreeting("8.0.36", "caching_sha2_password", false), mysqlErr(1045, "28000", "Access denied for user 'root'@'10.0.0.9' (using password: NO)"))
+	info = fingerprint(t, "mysql", denied)
+	assertAccess(t, "denied", info, "caching_sha2_password", false)
+	if !strings.HasPrefix(info.Details["login_error"], "1045 Access denied") {
+		t.Errorf("denied details: %v", info.Details)
+	}
+
+	// Host não autorizado: o servidor envia ERR no lugar da saudação.
+	host := stub(t, func(conn net.Conn) {
+		_ = mysqlWritePacket(conn, 0, append(binary.LittleEndian.AppendUint16([]byte{0xff}, mysqlErrHostNotPrivileged), "Host '10.0.0.9' is not allowed to connect to this MySQL server"...))
+	})
+	info = fingerprint(t, "mysql", host)
+	assertAccess(t, "host not allowed", info, "host-based", false)
+
+	// Sem EmptyPassword, a tentativa de login não é feita.
+	sc := NewScanner()
+	sc.EmptyPassword = false
+	if info, err := sc.Fingerprint(context.Background(), "mysql", "127.0.0.1", open); err != nil || info.Unauthenticated {
+		t.Errorf("login without EmptyPassword: %+v, %v", info, err)
+	}
+}
+
+// bsonArray codifica o documento e converte o subdocumento key em array (índices como chaves).
+func bsonArray(doc []bsonElement, key string) []byte {
+	data := bsonEncode(doc)
+	i := bytes.Index(data, []byte("\x03"+key+"\x00"))
+	data[i] = 0x04
+	return data
+}
+
+// mongoStub responde aos comandos do probe; databases nil simula um servidor com autenticação.
+// maxWire define se as respostas usam OP_REPLY (< 6) ou OP_MSG.
+func mongoStub(t *testing.T, maxWire int32, databases []string) int {
+	return stub(t, func(conn net.Conn) {
+		for {
+			header := make([]byte, 16)
+			if _, err := io.ReadFull(conn, header); err != nil {
+				return
+			}
+			body := make([]byte, binary.LittleEndian.Uint32(header)-16)
+			if _, err := io.ReadFull(conn, body); err != nil {
+				return
+			}
+			opcode := binary.LittleEndian.Uint32(header[12:])
+			var doc map[string]interface{}
+			var err error
+			switch opcode {
+			case mongoOpQuery:
+				// flags (4) + "admin.$cmd\x00" + numberToSkip (4) + numberToReturn (4)
+				doc, err = bsonDecode(body[4+len("admin.$cmd\x00")+8:])
+			case mongoOpMsg:
+				doc, err = bsonDecode(body[5:])
+				if doc["$db"] != "admin" {
+					t.Errorf("OP_MSG without $db: %v", doc)
+				}
+			}
+			if err != nil {
+				t.Errorf("invalid command (opcode %d): %v", opcode, err)
+				return
+			}
+
+			var reply []byte
+			switch {
+			case doc["isMaster"] != nil:
+				reply = bsonEncode([]bsonElement{{"ismaster", true}, {"maxWireVersion", maxWire}, {"setName", "rs0"}, {"ok", 1.0}})
+			case doc["buildInfo"] != nil:
+				reply = bsonEncode([]bsonElement{{"version", "7.0.5"}, {"ok", 1.0}})
+			case doc["listDatabases"] != nil && databases == nil:
+				reply = bsonEncode([]bsonElement{{"ok", 0.0}, {"errmsg", "command listDatabases requires authentication"}, {"code", int32(mongoErrUnauthorized)}})
+			case doc["listDatabases"] != nil:
+				var list []bsonElement
+				for i, name := range databases {
+					list = append(list, bsonElement{strconv.Itoa(i), []bsonElement{{"name", name}}})
+				}
+				reply = bsonArray([]bsonElement{{"databases", list}, {"ok", 1.0}}, "databases")
+			default:
+				t.Errorf("unexpected command: %v", doc)
+				return
+			}
+
+			var payload []byte
+			if opcode == mongoOpMsg {
+				payload = append([]byte{0, 0, 0, 0, 0}, reply...)
+			} else {
+				payload = append(make([]byte, 20), reply...)
+			}
+			out := make([]byte, 16)
+			binary.LittleEndian.PutUint32(out, uint32(16+len(payload)))
+			binary.LittleEndian.PutUint32(out[8:], binary.LittleEndian.Uint32(header[4:]))
+			if opcode == mongoOpMsg {
+				binary.LittleEndian.PutUint32(out[12:], mongoOpMsg)
+			} else {
+				binary.LittleEndian.PutUint32(out[12:], mongoOpReply)
+			}
+			if _, err := conn.Write(append(out, payload...)); err != nil {
+				return
+			}
+		}
+	})
+}
+
+func TestMongoDB(t *testing.T) {
+	for name, tc := range map[string]struct {
+		wire      int32
+		databases []string
+		method    string
+		open      bool
+	}{
+		"open op_msg":   {17, []string{"admin", "config", "app"}, "none", true},
+		"open op_query": {5, []string{"admin", "legacy"}, "none", true},
+		"auth":          {17, nil, "scram", false},
+	} {
+		info := fingerprint(t, "mongodb", mongoStub(t, tc.wire, tc.databases))
+		assertAccess(t, name, info, tc.method, tc.open)
+		if info.Version != "7.0.5" || info.Details["replica_set"] != "rs0" {
+			t.Errorf("%s: version %q, details %v", name, info.Version, info.Details)
+		}
+		if tc.open && info.Details["databases"] != strings.Join(tc.databases, ",") {
+			t.Errorf("%s: databases %q", name, info.Details["databases"])
+		}
+		if !tc.open && info.Details["error"] == "" {
+			t.Errorf("%s: details %v", name, info.Details)
+		}
+	}
+}
+
+func TestRunTagsUnauthenticated(t *testing.T) {
+	redis := stub(t, func(conn net.Conn) { _, _ = conn.Write([]byte("$31\r\n# Server\r\nredis_version:7.2.4\r\n")) })
+	memcached := stub(t, func(conn net.Conn) { _, _ = conn.Write([]byte("ERROR\r\n")) })
+	hosts := []results.Host{{Address: "127.0.0.1", Services: []results.Service{
+		{Protocol: "tcp", Port: redis, Name: "redis"},
+		{Protocol: "tcp", Port: memcached, Name: "memcache"},
+		{Protocol: "tcp", Port: 22, Name: "ssh"},
+	}}}
+	sc := NewScanner()
+	sc.Timeout = time.Second
+	found := sc.Run(context.Background(), hosts)
+	if len(found) != 2 || len(hosts[0].Tags) != 1 || hosts[0].Tags[0] != TagUnauthenticated {
+		t.Fatalf("found %+v, tags %v", found, hosts[0].Tags)
+	}
+	if svc := hosts[0].Services[0]; svc.Product != "redis" || svc.Version != "7.2.4" {
+		t.Errorf("redis service: %+v", svc)
+	}
+	var info Info
+	if !hosts[0].Services[1].Enrichment(ModuleName, &info) || info.AuthMethod != "sasl" {
+		t.Errorf("memcached enrichment: %+v", info)
+	}
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// probeElasticsearch faz GET / via HTTP e, se o servidor exigir, via HTTPS. Um 200 com o JSON
+// de identificação do cluster significa acesso sem autenticação; 401 indica security habilitado.
+func probeElasticsearch(ctx context.Context, sc *Scanner, target string, info *Info) error {
+	client := &http.Client{
+		Timeout: sc.Timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return util.DialContext(ctx, &net.Dialer{Timeout: sc.Timeout}, network, address)
+			},
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	var lastErr error
+	for _, scheme := range []string{"http", "https"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+target+"/", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+		// Um listener HTTPS responde 400 ao receber HTTP puro.
+		if scheme == "http" && resp.StatusCode == http.StatusBadRequest {
+			lastErr = errors.New("HTTP 400 on plain HTTP")
+			continue
+		}
+		info.TLS = scheme == "https"
+		return parseElasticsearch(resp, body, info)
+	}
+	return lastErr
+}
+
+// parseElasticsearch interpreta a resposta de GET /.
+func parseElasticsearch(resp *http.Response, body []byte, info *Info) error {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		info.AuthMethod = "http"
+		if challenge := resp.Header.Get("WWW-Authenticate"); challenge != "" {
+			info.AuthMethod = strings.ToLower(strings.SplitN(challenge, " ", 2)[0])
+			info.setDetail("challenge", challenge)
+		}
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	var root struct {
+		Name        string `json:"name"`
+		ClusterName string `json:"cluster_name"`
+		Tagline     string `json:"tagline"`
+		Version     struct {
+			Number       string `json:"number"`
+			Distribution string `json:"distribution"`
+		} `json:"version"`
+	}
+	if err := json.Unmarshal(body, &root); err != nil || root.Version.Number == "" {
+		return errors.New("not an Elasticsearch root response")
+	}
+	info.Version = root.Version.Number
+	info.setDetail("cluster_name", root.ClusterName)
+	info.setDetail("node_name", root.Name)
+	info.setDetail("distribution", root.Version.Distribution) // opensearch
+	info.AuthMethod = "none"
+	info.flagUnauthenticated(fmt.Sprintf("GET / returned cluster %q without authentication", root.ClusterName))
+	return nil
+}
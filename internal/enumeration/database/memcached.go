@@ -0,0 +1,56 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// probeMemcached envia "stats" no protocolo texto. Servidores sem SASL respondem com as
+// estatísticas, o que significa acesso aberto ao cache.
+func probeMemcached(ctx context.Context, sc *Scanner, target string, info *Info) error {
+	conn, err := sc.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("stats\r\n")); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(conn)
+	stats := make(map[string]string)
+	for i := 0; i < 256; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if len(stats) == 0 {
+				return fmt.Errorf("no answer to stats: %w", err)
+			}
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "END" {
+			break
+		}
+		if strings.HasPrefix(line, "STAT ") {
+			fields := strings.SplitN(line, " ", 3)
+			if len(fields) == 3 {
+				stats[fields[1]] = fields[2]
+			}
+			continue
+		}
+		// ERROR / CLIENT_ERROR: o protocolo texto foi recusado (SASL habilitado).
+		info.AuthMethod = "sasl"
+		info.setDetail("error", line)
+		return nil
+	}
+
+	info.Version = stats["version"]
+	info.setDetail("curr_items", stats["curr_items"])
+	info.setDetail("curr_connections", stats["curr_connections"])
+	info.setDetail("uptime", stats["uptime"])
+	info.AuthMethod = "none"
+	info.flagUnauthenticated("stats answered without authentication")
+	return nil
+}
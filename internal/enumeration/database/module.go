@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+)
+
+// Module expõe o Scanner como enumeration.Enumerator.
+type Module struct {
+	Scanner *Scanner
+}
+
+// NewModule é a factory que cria o módulo a partir de um Scanner.
+func NewModule(sc *Scanner) *Module {
+	return &Module{Scanner: sc}
+}
+
+// Name retorna o nome do módulo.
+func (m *Module) Name() string { return ModuleName }
+
+// Selector seleciona as portas e os nomes de serviço de todos os bancos suportados.
+func (m *Module) Selector() enumeration.Selector {
+	var selector enumeration.Selector
+	for _, d := range drivers {
+		selector.Ports = append(selector.Ports, d.Ports...)
+		selector.Services = append(selector.Services, d.Services...)
+	}
+	return selector
+}
+
+// Enumerate executa o fingerprint de cada serviço de banco de dados selecionado do host.
+func (m *Module) Enumerate(ctx context.Context, target enumeration.Target) (*enumeration.Result, error) {
+	result := &enumeration.Result{}
+	var lastErr error
+	for _, svc := range target.Services {
+		d, ok := selectDriver(svc)
+		if !ok || svc.Protocol != "tcp" {
+			continue
+		}
+		info, err := m.Scanner.Fingerprint(ctx, d.Engine, target.Host.Address, svc.Port)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		result.Services = append(result.Services, enumeration.ServiceResult{
+			Protocol:   svc.Protocol,
+			Port:       svc.Port,
+			Product:    info.Engine,
+			Version:    info.Version,
+			Enrichment: info,
+		})
+		if info.Unauthenticated {
+			result.Tags = append(result.Tags, TagUnauthenticated)
+		}
+		result.Findings = append(result.Findings, info.Findings...)
+	}
+	if len(result.Services) == 0 {
+		return nil, lastErr
+	}
+	return result, nil
+}
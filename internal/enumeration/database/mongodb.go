@@ -0,0 +1,148 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// Opcodes do protocolo de wire do MongoDB.
+const (
+	mongoOpReply = 1
+	mongoOpQuery = 2004
+	mongoOpMsg   = 2013
+
+	mongoMinWireOpMsg    = 6 // 3.6+: OP_MSG disponível
+	mongoErrUnauthorized = 13
+)
+
+// mongoConn mantém a conexão e o identificador das requisições.
+type mongoConn struct {
+	conn      net.Conn
+	requestID int32
+	opMsg     bool // usa OP_MSG em vez de OP_QUERY
+}
+
+// command executa um comando no banco admin e devolve o documento de resposta.
+func (mc *mongoConn) command(cmd []bsonElement) (map[string]interface{}, error) {
+	mc.requestID++
+	var body bytes.Buffer
+	opcode := int32(mongoOpQuery)
+	if mc.opMsg {
+		opcode = mongoOpMsg
+		cmd = append(cmd, bsonElement{"$db", "admin"})
+		_ = binary.Write(&body, binary.LittleEndian, uint32(0)) // flagBits
+		body.WriteByte(0)                                       // seção do tipo body
+		body.Write(bsonEncode(cmd))
+	} else {
+		_ = binary.Write(&body, binary.LittleEndian, int32(0)) // flags
+		body.WriteString("admin.$cmd\x00")
+		_ = binary.Write(&body, binary.LittleEndian, int32(0))  // numberToSkip
+		_ = binary.Write(&body, binary.LittleEndian, int32(-1)) // numberToReturn
+		body.Write(bsonEncode(cmd))
+	}
+
+	header := make([]byte, 16)
+	binary.LittleEndian.PutUint32(header[0:], uint32(16+body.Len()))
+	binary.LittleEndian.PutUint32(header[4:], uint32(mc.requestID))
+	binary.LittleEndian.PutUint32(header[12:], uint32(opcode))
+	if _, err := mc.conn.Write(append(header, body.Bytes()...)); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(mc.conn, header); err != nil {
+		return nil, err
+	}
+	size := int(binary.LittleEndian.Uint32(header[0:]))
+	if size < 16 || size > 16*1024*1024 {
+		return nil, errors.New("invalid MongoDB message length")
+	}
+	payload := make([]byte, size-16)
+	if _, err := io.ReadFull(mc.conn, payload); err != nil {
+		return nil, err
+	}
+	switch binary.LittleEndian.Uint32(header[12:]) {
+	case mongoOpReply:
+		// responseFlags (4) + cursorID (8) + startingFrom (4) + numberReturned (4)
+		if len(payload) < 20 {
+			return nil, errors.New("truncated OP_REPLY")
+		}
+		return bsonDecode(payload[20:])
+	case mongoOpMsg:
+		if len(payload) < 5 || payload[4] != 0 {
+			return nil, errors.New("unexpected OP_MSG layout")
+		}
+		return bsonDecode(payload[5:])
+	}
+	return nil, errors.New("unexpected MongoDB opcode")
+}
+
+// probeMongo executa isMaster (aceito sem autenticação em todas as versões), buildInfo para
+// obter a versão e listDatabases para saber se o servidor exige autenticação.
+func probeMongo(ctx context.Context, sc *Scanner, target string, info *Info) error {
+	conn, err := sc.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	mc := &mongoConn{conn: conn}
+
+	hello, err := mc.command([]bsonElement{{"isMaster", int32(1)}})
+	if err != nil {
+		return fmt.Errorf("isMaster failed: %w", err)
+	}
+	if wire, ok := bsonInt(hello["maxWireVersion"]); ok {
+		info.setDetail("max_wire_version", fmt.Sprint(wire))
+		mc.opMsg = wire >= mongoMinWireOpMsg
+	}
+	if name, ok := hello["setName"].(string); ok {
+		info.setDetail("replica_set", name)
+	}
+	if msg, ok := hello["msg"].(string); ok {
+		info.setDetail("msg", msg) // "isdbgrid" em roteadores mongos
+	}
+	if mechs, ok := hello["saslSupportedMechs"].([]interface{}); ok {
+		var names []string
+		for _, m := range mechs {
+			names = append(names, fmt.Sprint(m))
+		}
+		info.setDetail("sasl_mechanisms", strings.Join(names, ","))
+	}
+
+	if build, err := mc.command([]bsonElement{{"buildInfo", int32(1)}}); err == nil {
+		if version, ok := build["version"].(string); ok {
+			info.Version = version
+		}
+	}
+
+	dbs, err := mc.command([]bsonElement{{"listDatabases", int32(1)}, {"nameOnly", true}})
+	if err != nil {
+		return nil
+	}
+	if ok, _ := bsonInt(dbs["ok"]); ok == 1 {
+		var names []string
+		if list, ok := dbs["databases"].([]interface{}); ok {
+			for _, d := range list {
+				if doc, ok := d.(map[string]interface{}); ok {
+					names = append(names, fmt.Sprint(doc["name"]))
+				}
+			}
+		}
+		info.AuthMethod = "none"
+		info.setDetail("databases", strings.Join(names, ","))
+		info.flagUnauthenticated(fmt.Sprintf("listDatabases returned %d databases without authentication", len(names)))
+		return nil
+	}
+	if code, _ := bsonInt(dbs["code"]); code == mongoErrUnauthorized {
+		info.AuthMethod = "scram"
+	}
+	if errmsg, ok := dbs["errmsg"].(string); ok {
+		info.setDetail("error", errmsg)
+	}
+	return nil
+}
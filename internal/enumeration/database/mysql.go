@@ -0,0 +1,176 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// Flags de capacidade do protocolo MySQL usadas no handshake.
+const (
+	mysqlClientLongPassword   = 0x00000001
+	mysqlClientProtocol41     = 0x00000200
+	mysqlClientSSL            = 0x00000800
+	mysqlClientSecureConn     = 0x00008000
+	mysqlClientPluginAuth     = 0x00080000
+	mysqlMaxPacket            = 16 * 1024 * 1024
+	mysqlDefaultAuthPlugin    = "mysql_native_password"
+	mysqlErrHostNotPrivileged = 1130
+)
+
+// mysqlReadPacket lê um pacote do protocolo MySQL (3 bytes de tamanho + 1 de sequência).
+func mysqlReadPacket(r io.Reader) ([]byte, byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, 0, err
+	}
+	size := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, 0, err
+	}
+	return payload, header[3], nil
+}
+
+// mysqlWritePacket envia um pacote com o número de sequência informado.
+func mysqlWritePacket(w io.Writer, seq byte, payload []byte) error {
+	header := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), seq}
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+// mysqlError decodifica um pacote ERR (0xff).
+func mysqlError(payload []byte) (uint16, string) {
+	if len(payload) < 3 {
+		return 0, ""
+	}
+	code := binary.LittleEndian.Uint16(payload[1:3])
+	msg := payload[3:]
+	if len(msg) > 6 && msg[0] == '#' {
+		msg = msg[6:] // marcador de SQLSTATE
+	}
+	return code, string(msg)
+}
+
+// probeMySQL lê a saudação inicial (versão, plugin de autenticação, suporte a TLS) e,
+// se habilitado, tenta login como root sem senha.
+func probeMySQL(ctx context.Context, sc *Scanner, target string, info *Info) error {
+	conn, err := sc.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	payload, _, err := mysqlReadPacket(conn)
+	if err != nil {
+		return fmt.Errorf("failed to read greeting: %w", err)
+	}
+	if len(payload) > 0 && payload[0] == 0xff {
+		// Servidor recusa o cliente antes da saudação (ex.: host não autorizado).
+		code, msg := mysqlError(payload)
+		info.setDetail("error", fmt.Sprintf("%d %s", code, msg))
+		if code == mysqlErrHostNotPrivileged {
+			info.AuthMethod = "host-based"
+		}
+		return nil
+	}
+	if len(payload) < 1 || payload[0] != 10 {
+		return errors.New("unsupported MySQL protocol version")
+	}
+
+	rest := payload[1:]
+	end := bytes.IndexByte(rest, 0)
+	if end < 0 {
+		return errors.New("truncated greeting")
+	}
+	info.Version = string(rest[:end])
+	if strings.Contains(strings.ToLower(info.Version), "mariadb") {
+		info.setDetail("flavor", "mariadb")
+	}
+	rest = rest[end+1:]
+
+	// connection id (4) + auth-plugin-data-part-1 (8) + filler (1) + capability flags inferiores (2)
+	if len(rest) < 15 {
+		return nil
+	}
+	capabilities := uint32(binary.LittleEndian.Uint16(rest[13:15]))
+	rest = rest[15:]
+	// charset (1) + status (2) + capability flags superiores (2) + tamanho do auth data (1) + reservado (10)
+	if len(rest) >= 16 {
+		capabilities |= uint32(binary.LittleEndian.Uint16(rest[3:5])) << 16
+		authLen := int(rest[5])
+		rest = rest[16:]
+		if capabilities&mysqlClientSecureConn != 0 {
+			part2 := authLen - 8
+			if part2 < 13 {
+				part2 = 13
+			}
+			if len(rest) >= part2 {
+				rest = rest[part2:]
+			}
+		}
+		if capabilities&mysqlClientPluginAuth != 0 {
+			if end := bytes.IndexByte(rest, 0); end >= 0 {
+				info.AuthMethod = string(rest[:end])
+			} else {
+				info.AuthMethod = string(rest)
+			}
+		}
+	}
+	info.TLS = capabilities&mysqlClientSSL != 0
+	if info.AuthMethod == "" {
+		info.AuthMethod = mysqlDefaultAuthPlugin
+	}
+
+	if !sc.EmptyPassword {
+		return nil
+	}
+	return mysqlEmptyLogin(conn, info)
+}
+
+// mysqlEmptyLogin envia um HandshakeResponse41 como root com senha vazia.
+func mysqlEmptyLogin(conn net.Conn, info *Info) error {
+	var resp bytes.Buffer
+	flags := uint32(mysqlClientLongPassword | mysqlClientProtocol41 | mysqlClientSecureConn | mysqlClientPluginAuth)
+	_ = binary.Write(&resp, binary.LittleEndian, flags)
+	_ = binary.Write(&resp, binary.LittleEndian, uint32(mysqlMaxPacket))
+	resp.WriteByte(33) // utf8_general_ci
+	resp.Write(make([]byte, 23))
+	resp.WriteString("root\x00")
+	resp.WriteByte(0) // auth response vazio
+	resp.WriteString(info.AuthMethod + "\x00")
+	if err := mysqlWritePacket(conn, 1, resp.Bytes()); err != nil {
+		return nil
+	}
+
+	seq := byte(2)
+	for attempt := 0; attempt < 3; attempt++ {
+		payload, s, err := mysqlReadPacket(conn)
+		if err != nil || len(payload) == 0 {
+			return nil
+		}
+		switch payload[0] {
+		case 0x00:
+			info.flagUnauthenticated("root login with empty password accepted")
+			return nil
+		case 0xff:
+			code, msg := mysqlError(payload)
+			info.setDetail("login_error", fmt.Sprintf("%d %s", code, msg))
+			return nil
+		case 0xfe, 0x01:
+			// Auth switch ou dados extras do plugin: responde novamente com senha vazia.
+			seq = s + 1
+			if err := mysqlWritePacket(conn, seq, nil); err != nil {
+				return nil
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
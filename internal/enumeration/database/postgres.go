@@ -0,0 +1,155 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Códigos do protocolo PostgreSQL usados no fingerprint.
+const (
+	pgSSLRequestCode   = 80877103
+	pgProtocolVersion3 = 3 << 16
+)
+
+// pgAuthMethods mapeia os códigos de AuthenticationRequest para nomes legíveis.
+var pgAuthMethods = map[uint32]string{
+	0:  "trust",
+	2:  "kerberos-v5",
+	3:  "password",
+	5:  "md5",
+	7:  "gss",
+	9:  "sspi",
+	10: "sasl",
+}
+
+// probePostgres envia um SSLRequest para saber se o servidor aceita TLS e, em outra conexão,
+// uma StartupMessage como "postgres". A resposta revela o método de autenticação exigido
+// ou, em caso de erro, a mensagem do servidor (ex.: ausência de entrada no pg_hba.conf).
+func probePostgres(ctx context.Context, sc *Scanner, target string, info *Info) error {
+	conn, err := sc.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint32(req[0:4], 8)
+	binary.BigEndian.PutUint32(req[4:8], pgSSLRequestCode)
+	if _, err := conn.Write(req); err != nil {
+		conn.Close()
+		return err
+	}
+	answer := make([]byte, 1)
+	_, err = io.ReadFull(conn, answer)
+	conn.Close()
+	if err != nil {
+		return fmt.Errorf("no answer to SSLRequest: %w", err)
+	}
+	switch answer[0] {
+	case 'S':
+		info.TLS = true
+	case 'N':
+	case 'E':
+		// Servidores anteriores ao 7.1 respondem SSLRequest com erro.
+		info.setDetail("ssl_request", "error")
+	default:
+		return errors.New("unexpected answer to SSLRequest")
+	}
+
+	conn, err = sc.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var body bytes.Buffer
+	_ = binary.Write(&body, binary.BigEndian, uint32(pgProtocolVersion3))
+	body.WriteString("user\x00postgres\x00database\x00postgres\x00application_name\x00arthxrecon\x00\x00")
+	msg := make([]byte, 4)
+	binary.BigEndian.PutUint32(msg, uint32(body.Len()+4))
+	if _, err := conn.Write(append(msg, body.Bytes()...)); err != nil {
+		return err
+	}
+
+	for i := 0; i < 32; i++ {
+		header := make([]byte, 5)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			if i == 0 {
+				return fmt.Errorf("no answer to startup message: %w", err)
+			}
+			return nil
+		}
+		size := int(binary.BigEndian.Uint32(header[1:5])) - 4
+		if size < 0 || size > 64*1024 {
+			return errors.New("invalid PostgreSQL message length")
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return nil
+		}
+
+		switch header[0] {
+		case 'R':
+			if len(payload) < 4 {
+				return nil
+			}
+			code := binary.BigEndian.Uint32(payload[:4])
+			if code != 0 {
+				info.AuthMethod = pgAuthMethods[code]
+				if info.AuthMethod == "" {
+					info.AuthMethod = fmt.Sprintf("auth-%d", code)
+				}
+				if code == 10 {
+					info.setDetail("sasl_mechanisms", pgSASLMechanisms(payload[4:]))
+				}
+				return nil
+			}
+			info.AuthMethod = "trust"
+			info.flagUnauthenticated("startup as postgres accepted without password")
+		case 'S':
+			// ParameterStatus após AuthenticationOk traz server_version.
+			parts := bytes.Split(payload, []byte{0})
+			if len(parts) >= 2 && string(parts[0]) == "server_version" {
+				info.Version = string(parts[1])
+			}
+		case 'E':
+			fields := pgErrorFields(payload)
+			info.setDetail("error", fields['M'])
+			info.setDetail("error_code", fields['C'])
+			info.setDetail("error_routine", fields['R'])
+			return nil
+		case 'Z':
+			// ReadyForQuery: sessão estabelecida.
+			return nil
+		}
+	}
+	return nil
+}
+
+// pgErrorFields decodifica os campos de uma ErrorResponse.
+func pgErrorFields(payload []byte) map[byte]string {
+	fields := make(map[byte]string)
+	for len(payload) > 1 {
+		end := bytes.IndexByte(payload[1:], 0)
+		if end < 0 {
+			break
+		}
+		fields[payload[0]] = string(payload[1 : end+1])
+		payload = payload[end+2:]
+	}
+	return fields
+}
+
+// pgSASLMechanisms lista os mecanismos anunciados em AuthenticationSASL.
+func pgSASLMechanisms(payload []byte) string {
+	var mechs []string
+	for _, m := range bytes.Split(payload, []byte{0}) {
+		if len(m) > 0 {
+			mechs = append(mechs, string(m))
+		}
+	}
+	return strings.Join(mechs, ",")
+}
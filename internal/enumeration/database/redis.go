@@ -0,0 +1,73 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// probeRedis envia INFO sem autenticação. Uma resposta em bulk string indica acesso aberto;
+// -NOAUTH (ou -ERR ... password) indica que o servidor exige AUTH.
+func probeRedis(ctx context.Context, sc *Scanner, target string, info *Info) error {
+	conn, err := sc.dial(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("INFO server\r\n")); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("no answer to INFO: %w", err)
+	}
+	line = strings.TrimRight(line, "\r\n")
+
+	switch {
+	case strings.HasPrefix(line, "-"):
+		// -NOAUTH exige senha; -DENIED indica protected mode (acesso apenas local).
+		info.setDetail("error", strings.TrimPrefix(line, "-"))
+		if strings.Contains(strings.ToLower(line), "auth") {
+			info.AuthMethod = "password"
+		}
+		return nil
+	case strings.HasPrefix(line, "$"):
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 || size > 1024*1024 {
+			return fmt.Errorf("invalid INFO reply: %s", line)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return fmt.Errorf("truncated INFO reply: %w", err)
+		}
+		fields := parseKeyValues(string(data), ":")
+		info.Version = fields["redis_version"]
+		info.setDetail("mode", fields["redis_mode"])
+		info.setDetail("os", fields["os"])
+		info.setDetail("config_file", fields["config_file"])
+		info.AuthMethod = "none"
+		info.flagUnauthenticated("INFO answered without AUTH")
+		return nil
+	}
+	return fmt.Errorf("unexpected Redis reply: %s", line)
+}
+
+// parseKeyValues interpreta linhas "chave<sep>valor", ignorando comentários e linhas vazias.
+func parseKeyValues(data, sep string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, sep); ok {
+			fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
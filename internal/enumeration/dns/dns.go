@@ -0,0 +1,385 @@
+package dns
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ModuleName é o nome do módulo usado em logs e nos resultados.
+const ModuleName = "dns"
+
+// srvServices são os registros SRV do Active Directory consultados para cada domínio.
+var srvServices = []string{"_ldap._tcp", "_kerberos._tcp", "_ldap._tcp.dc._msdcs", "_gc._tcp"}
+
+// HostInfo é o resultado anexado a cada host.
+type HostInfo struct {
+	PTR           []string       `json:"ptr,omitempty"`
+	ZoneTransfers []ZoneTransfer `json:"zone_transfers,omitempty"` // Tentativas de AXFR contra o host (servidor DNS)
+}
+
+// ZoneTransfer é o resultado de uma tentativa de AXFR.
+type ZoneTransfer struct {
+	Server  string   `json:"server"`
+	Zone    string   `json:"zone"`
+	Allowed bool     `json:"allowed"`
+	Records []Record `json:"records,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// SRVRecord é um registro SRV do AD encontrado para um domínio.
+type SRVRecord struct {
+	Query     string   `json:"query"`
+	Target    string   `json:"target"`
+	Port      uint16   `json:"port"`
+	Priority  uint16   `json:"priority"`
+	Weight    uint16   `json:"weight"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// Discovery é um endereço novo aprendido via DNS, ainda não presente nos resultados.
+type Discovery struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Source  string `json:"source"` // axfr, srv
+	InScope bool   `json:"in_scope"`
+}
+
+// Report consolida tudo o que foi encontrado pelo módulo.
+type Report struct {
+	Domains       []string       `json:"domains"`
+	PTR           int            `json:"ptr"`
+	ZoneTransfers []ZoneTransfer `json:"zone_transfers,omitempty"`
+	SRV           []SRVRecord    `json:"srv,omitempty"`
+	Discovered    []Discovery    `json:"discovered,omitempty"`
+}
+
+// Scanner executa as consultas DNS do módulo.
+type Scanner struct {
+	Server  string        // Servidor DNS (host:porta) para PTR/SRV/A; vazio usa o resolver do sistema
+	Domains []string      // Domínios informados pelo operador (somados aos derivados dos resultados)
+	Scope   []*net.IPNet  // Escopo autorizado; endereços descobertos fora dele não viram alvos
+	Timeout time.Duration // Timeout por consulta
+	Threads int           // Quantidade de consultas PTR simultâneas
+}
+
+// NewScanner é a factory que cria um Scanner com valores padrão.
+func NewScanner() *Scanner {
+	return &Scanner{
+		Timeout: 5 * time.Second,
+		Threads: 20,
+	}
+}
+
+// resolver retorna o resolver configurado: o do sistema ou um apontado para Server. Com limite
+// de taxa em ctx, as consultas passam pelo resolver Go para que cada uma respeite o limite.
+func (sc *Scanner) resolver(ctx context.Context) *net.Resolver {
+	if sc.Server == "" && util.LimiterFrom(ctx).Rate == 0 {
+		return net.DefaultResolver
+	}
+	server := sc.Server
+	if _, _, err := net.SplitHostPort(server); server != "" && err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			if server != "" {
+				address = server
+			}
+			dialer := net.Dialer{Timeout: sc.Timeout}
+			return util.DialContext(ctx, &dialer, network, address)
+		},
+	}
+}
+
+// lookupContext cria um contexto com o timeout de uma consulta.
+func (sc *Scanner) lookupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, sc.Timeout)
+}
+
+// AXFR tenta uma transferência de zona de zone contra server (host ou host:porta) via TCP.
+func (sc *Scanner) AXFR(ctx context.Context, server, zone string) (*ZoneTransfer, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	transfer := &ZoneTransfer{Server: server, Zone: zone}
+
+	query, err := buildQuery(uint16(rand.Intn(0xffff)), zone, typeAXFR)
+	if err != nil {
+		return nil, err
+	}
+	dialer := net.Dialer{Timeout: sc.Timeout}
+	conn, err := util.DialContext(ctx, &dialer, "tcp", server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(4 * sc.Timeout))
+
+	frame := binary.BigEndian.AppendUint16(nil, uint16(len(query)))
+	if _, err := conn.Write(append(frame, query...)); err != nil {
+		return nil, fmt.Errorf("failed to send AXFR query to %s: %w", server, err)
+	}
+
+	// A zona começa e termina com o registro SOA, podendo vir em várias mensagens.
+	reader := bufio.NewReader(conn)
+	soaCount := 0
+	for soaCount < 2 {
+		lenBuf := make([]byte, 2)
+		if _, err := io.ReadFull(reader, lenBuf); err != nil {
+			transfer.Error = err.Error()
+			return transfer, nil
+		}
+		data := make([]byte, binary.BigEndian.Uint16(lenBuf))
+		if _, err := io.ReadFull(reader, data); err != nil {
+			transfer.Error = err.Error()
+			return transfer, nil
+		}
+		msg, err := parseMessage(data)
+		if err != nil {
+			transfer.Error = err.Error()
+			return transfer, nil
+		}
+		if msg.RCode != 0 {
+			transfer.Error = fmt.Sprintf("refused (rcode %d)", msg.RCode)
+			return transfer, nil
+		}
+		if len(msg.Answers) == 0 {
+			transfer.Error = "empty response"
+			return transfer, nil
+		}
+		for _, rr := range msg.Answers {
+			if rr.Type == "SOA" {
+				soaCount++
+				if soaCount == 2 {
+					break
+				}
+			}
+			transfer.Records = append(transfer.Records, rr)
+		}
+	}
+	transfer.Allowed = len(transfer.Records) > 0
+	return transfer, nil
+}
+
+// Run executa PTR para todos os hosts, SRV do AD para os domínios conhecidos e AXFR contra os hosts com 53 aberta.
+// Os resultados por host são anexados a hosts; o relatório retorna também os endereços novos descobertos.
+func (sc *Scanner) Run(ctx context.Context, hosts []results.Host) *Report {
+	report := &Report{}
+	resolver := sc.resolver(ctx)
+
+	// 1. PTR para cada host.
+	infos := make([]HostInfo, len(hosts))
+	threads := sc.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	batch := util.NewProbeBatch(ctx, ModuleName, threads)
+	for i := range hosts {
+		batch.Add(hosts[i].Address, "", 0)
+	}
+	batch.Start()
+	defer batch.Finish()
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, threads)
+	)
+	for i := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		util.WaitWindow(ctx)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			lctx, cancel := sc.lookupContext(ctx)
+			defer cancel()
+			names, err := resolver.LookupAddr(lctx, hosts[i].Address)
+			if err != nil {
+				util.ModuleLogger(ModuleName).Debug().Err(err).Msgf("PTR lookup failed for %s", hosts[i].Address)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			infos[i].PTR = names
+			for _, name := range names {
+				hosts[i].AddHostname(strings.TrimSuffix(name, "."))
+			}
+			report.PTR++
+		}(i)
+	}
+	wg.Wait()
+
+	sc.resolveDomains(ctx, hosts, infos, report)
+	return report
+}
+
+// resolveDomains executa as etapas que dependem de todos os hosts: SRV do AD para os domínios
+// conhecidos e AXFR contra os hosts com 53 aberta. infos traz o PTR já obtido de cada host;
+// o resultado consolidado é anexado aos hosts e os endereços novos são registrados no relatório.
+func (sc *Scanner) resolveDomains(ctx context.Context, hosts []results.Host, infos []HostInfo, report *Report) {
+	resolver := sc.resolver(ctx)
+	report.Domains = sc.candidateDomains(hosts)
+	known := make(map[string]bool)
+	for _, h := range hosts {
+		known[h.Address] = true
+	}
+	discovered := make(map[string]Discovery)
+	addDiscovery := func(name, address, source string) {
+		if known[address] {
+			return
+		}
+		if _, ok := discovered[address]; ok {
+			return
+		}
+		discovered[address] = Discovery{
+			Name:    strings.TrimSuffix(name, "."),
+			Address: address,
+			Source:  source,
+			InScope: util.InScope(address, sc.Scope),
+		}
+	}
+
+	// 2. Registros SRV do AD para cada domínio.
+	for _, domain := range report.Domains {
+		for _, service := range srvServices {
+			query := service + "." + domain
+			lctx, cancel := sc.lookupContext(ctx)
+			_, srvs, err := resolver.LookupSRV(lctx, "", "", query)
+			cancel()
+			if err != nil {
+				util.ModuleLogger(ModuleName).Debug().Err(err).Msgf("SRV lookup failed for %s", query)
+				continue
+			}
+			for _, srv := range srvs {
+				record := SRVRecord{Query: query, Target: srv.Target, Port: srv.Port, Priority: srv.Priority, Weight: srv.Weight}
+				lctx, cancel := sc.lookupContext(ctx)
+				record.Addresses, _ = resolver.LookupHost(lctx, srv.Target)
+				cancel()
+				for _, addr := range record.Addresses {
+					addDiscovery(srv.Target, addr, "srv")
+				}
+				report.SRV = append(report.SRV, record)
+			}
+		}
+	}
+
+	// 3. AXFR contra cada servidor DNS para cada domínio.
+	batch := util.NewProbeBatch(ctx, ModuleName, 1)
+	for i := range hosts {
+		if len(report.Domains) > 0 && (hosts[i].HasPort("tcp", 53) || hosts[i].HasPort("udp", 53)) {
+			batch.Add(hosts[i].Address, "tcp", 53)
+		}
+	}
+	batch.Start()
+	defer batch.Finish()
+	for i := range hosts {
+		if !hosts[i].HasPort("tcp", 53) && !hosts[i].HasPort("udp", 53) {
+			continue
+		}
+		util.WaitWindow(ctx)
+		for _, domain := range report.Domains {
+			transfer, err := sc.AXFR(ctx, hosts[i].Address, domain)
+			if err != nil {
+				util.ModuleLogger(ModuleName).Debug().Err(err).Msg("AXFR failed")
+				continue
+			}
+			infos[i].ZoneTransfers = append(infos[i].ZoneTransfers, *transfer)
+			report.ZoneTransfers = append(report.ZoneTransfers, *transfer)
+			for _, rr := range transfer.Records {
+				if rr.Type == "A" || rr.Type == "AAAA" {
+					addDiscovery(rr.Name, rr.Data, "axfr")
+				}
+			}
+		}
+	}
+
+	for i := range hosts {
+		if len(infos[i].PTR) > 0 || len(infos[i].ZoneTransfers) > 0 {
+			hosts[i].SetEnrichment(ModuleName, infos[i])
+		}
+	}
+	for _, d := range discovered {
+		report.Discovered = append(report.Discovered, d)
+	}
+	sort.Slice(report.Discovered, func(a, b int) bool { return report.Discovered[a].Address < report.Discovered[b].Address })
+}
+
+// candidateDomains junta os domínios informados com os derivados dos resultados:
+// nomes PTR, domínio DNS anunciado via SMB/NTLM e naming context do LDAP.
+func (sc *Scanner) candidateDomains(hosts []results.Host) []string {
+	set := make(map[string]bool)
+	add := func(domain string) {
+		domain = strings.ToLower(strings.Trim(strings.TrimSpace(domain), "."))
+		if strings.Contains(domain, ".") {
+			set[domain] = true
+		}
+	}
+	for _, d := range sc.Domains {
+		add(d)
+	}
+	for i := range hosts {
+		for _, name := range hosts[i].Hostnames {
+			if idx := strings.Index(name, "."); idx > 0 {
+				add(name[idx+1:])
+			}
+		}
+		var smbInfo struct {
+			DNSDomain string `json:"dns_domain"`
+		}
+		if hosts[i].Enrichment("smb", &smbInfo) {
+			add(smbInfo.DNSDomain)
+		}
+		var ldapInfo struct {
+			DefaultNamingContext string `json:"default_naming_context"`
+		}
+		if hosts[i].Enrichment("ldap", &ldapInfo) {
+			add(namingContextToDomain(ldapInfo.DefaultNamingContext))
+		}
+	}
+	var domains []string
+	for d := range set {
+		domains = append(domains, d)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// namingContextToDomain converte "DC=corp,DC=local" em "corp.local".
+func namingContextToDomain(nc string) string {
+	var labels []string
+	for _, part := range strings.Split(nc, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) > 3 && strings.EqualFold(part[:3], "DC=") {
+			labels = append(labels, part[3:])
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+// Findings converte as transferências de zona permitidas em achados.
+func Findings(report *Report) []findings.Finding {
+	var list []findings.Finding
+	for _, zt := range report.ZoneTransfers {
+		if !zt.Allowed {
+			continue
+		}
+		list = append(list, findings.New(ModuleName, findings.Slug("dns-zone-transfer", zt.Zone), "DNS zone transfer allowed for "+zt.Zone, findings.SeverityHigh, zt.Server, 53, "tcp").
+			WithEvidence(fmt.Sprintf("AXFR returned %d records", len(zt.Records))).
+			WithDetails("zone", zt.Zone))
+	}
+	return list
+}
@@ -0,0 +1,132 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// axfrServer responde à primeira consulta recebida em 127.0.0.1 com as mensagens de reply,
+// trocando o ID pelo da consulta. Retorna o endereço do servidor.
+func axfrServer(t *testing.T, reply func(query []byte) [][]byte) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				lenBuf := make([]byte, 2)
+				if _, err := io.ReadFull(conn, lenBuf); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(lenBuf))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+				for _, msg := range reply(query) {
+					copy(msg, query[:2])
+					frame := binary.BigEndian.AppendUint16(nil, uint16(len(msg)))
+					if _, err := conn.Write(append(frame, msg...)); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// zoneSOA monta o rdata de um SOA de corp.local.
+func zoneSOA() []byte {
+	soa := []byte{2, 'n', 's', 0xc0, 12, 5, 'a', 'd', 'm', 'i', 'n', 0xc0, 12}
+	soa = binary.BigEndian.AppendUint32(soa, 1)
+	return append(soa, make([]byte, 16)...)
+}
+
+func TestAXFR(t *testing.T) {
+	zone := []byte{4, 'c', 'o', 'r', 'p', 5, 'l', 'o', 'c', 'a', 'l', 0}
+	tests := []struct {
+		name    string
+		reply   func(query []byte) [][]byte
+		allowed bool
+		records int
+		err     string
+	}{
+		{
+			name: "allowed in two messages",
+			reply: func([]byte) [][]byte {
+				first := record(header(0, 0, 0, 2), zone, typeSOA, 60, zoneSOA())
+				first = record(first, pointer(12), typeA, 60, []byte{10, 0, 0, 10})
+				second := record(header(0, 0, 0, 2), zone, typeA, 60, []byte{10, 0, 0, 11})
+				second = record(second, pointer(12), typeSOA, 60, zoneSOA())
+				return [][]byte{first, second}
+			},
+			allowed: true,
+			records: 3,
+		},
+		{
+			name:  "refused",
+			reply: func([]byte) [][]byte { return [][]byte{header(0, 5, 0, 0)} },
+			err:   "refused (rcode 5)",
+		},
+		{
+			name:  "empty",
+			reply: func([]byte) [][]byte { return [][]byte{header(0, 0, 0, 0)} },
+			err:   "empty response",
+		},
+		{
+			name:  "malformed",
+			reply: func([]byte) [][]byte { return [][]byte{append(header(0, 0, 0, 1), pointer(12)...)} },
+			err:   "DNS name compression loop",
+		},
+		{
+			name:  "closed",
+			reply: func([]byte) [][]byte { return nil },
+			err:   "EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := axfrServer(t, tt.reply)
+			sc := NewScanner()
+			sc.Timeout = 2 * time.Second
+
+			transfer, err := sc.AXFR(context.Background(), server, "corp.local")
+			if err != nil {
+				t.Fatalf("AXFR: %v", err)
+			}
+			if transfer.Allowed != tt.allowed || len(transfer.Records) != tt.records || transfer.Error != tt.err {
+				t.Errorf("got allowed %v, %d records, error %q", transfer.Allowed, len(transfer.Records), transfer.Error)
+			}
+			found := Findings(&Report{ZoneTransfers: []ZoneTransfer{*transfer}})
+			if (len(found) == 1) != tt.allowed {
+				t.Errorf("%d findings for allowed %v", len(found), tt.allowed)
+			}
+		})
+	}
+}
+
+func TestNamingContextToDomain(t *testing.T) {
+	tests := map[string]string{
+		"DC=corp,DC=local":             "corp.local",
+		"CN=Users, dc=corp , DC=local": "corp.local",
+		"":                             "",
+		"DC=":                          "",
+	}
+	for nc, want := range tests {
+		if got := namingContextToDomain(nc); got != want {
+			t.Errorf("namingContextToDomain(%q) = %q, want %q", nc, got, want)
+		}
+	}
+}
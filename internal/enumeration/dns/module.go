@@ -0,0 +1,60 @@
+package dns
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// Module expõe o Scanner como enumeration.Enumerator: o PTR roda por host e as consultas
+// SRV e AXFR rodam no Finalize, quando os domínios de todos os hosts já são conhecidos.
+type Module struct {
+	Scanner *Scanner
+	Report  *Report // Relatório da última execução, preenchido pelo Finalize
+}
+
+// NewModule é a factory que cria o módulo a partir de um Scanner.
+func NewModule(sc *Scanner) *Module {
+	return &Module{Scanner: sc}
+}
+
+// Name retorna o nome do módulo.
+func (m *Module) Name() string { return ModuleName }
+
+// Selector executa o módulo uma vez para cada host.
+func (m *Module) Selector() enumeration.Selector {
+	return enumeration.Selector{Host: true}
+}
+
+// Enumerate resolve o PTR do host.
+func (m *Module) Enumerate(ctx context.Context, target enumeration.Target) (*enumeration.Result, error) {
+	lctx, cancel := m.Scanner.lookupContext(ctx)
+	defer cancel()
+	names, err := m.Scanner.resolver(ctx).LookupAddr(lctx, target.Host.Address)
+	if err != nil {
+		return nil, err
+	}
+	result := &enumeration.Result{Enrichment: HostInfo{PTR: names}}
+	for _, name := range names {
+		result.Hostnames = append(result.Hostnames, strings.TrimSuffix(name, "."))
+	}
+	return result, nil
+}
+
+// Finalize consulta os registros SRV e tenta AXFR para os domínios derivados dos hosts.
+func (m *Module) Finalize(ctx context.Context, hosts []results.Host) ([]findings.Finding, error) {
+	report := &Report{}
+	infos := make([]HostInfo, len(hosts))
+	for i := range hosts {
+		if hosts[i].Enrichment(ModuleName, &infos[i]) && len(infos[i].PTR) > 0 {
+			report.PTR++
+		}
+		infos[i].ZoneTransfers = nil
+	}
+	m.Scanner.resolveDomains(ctx, hosts, infos, report)
+	m.Report = report
+	return Findings(report), nil
+}
@@ -0,0 +1,200 @@
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Tipos de registro DNS tratados pelo módulo.
+const (
+	typeA     uint16 = 1
+	typeNS    uint16 = 2
+	typeCNAME uint16 = 5
+	typeSOA   uint16 = 6
+	typePTR   uint16 = 12
+	typeMX    uint16 = 15
+	typeTXT   uint16 = 16
+	typeAAAA  uint16 = 28
+	typeSRV   uint16 = 33
+	typeAXFR  uint16 = 252
+	classINET uint16 = 1
+)
+
+var typeNames = map[uint16]string{
+	typeA: "A", typeNS: "NS", typeCNAME: "CNAME", typeSOA: "SOA", typePTR: "PTR",
+	typeMX: "MX", typeTXT: "TXT", typeAAAA: "AAAA", typeSRV: "SRV",
+}
+
+// Record é um registro de recurso decodificado.
+type Record struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	TTL  uint32 `json:"ttl"`
+	Data string `json:"data"`
+}
+
+// message é uma resposta DNS decodificada (apenas o necessário para AXFR).
+type message struct {
+	ID      uint16
+	RCode   int
+	Answers []Record
+}
+
+// buildQuery monta uma consulta DNS simples para name/qtype.
+func buildQuery(id uint16, name string, qtype uint16) ([]byte, error) {
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[4:], 1) // QDCOUNT
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, fmt.Errorf("invalid DNS name %q", name)
+		}
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	return binary.BigEndian.AppendUint16(msg, classINET), nil
+}
+
+// parseMessage decodifica uma resposta DNS.
+func parseMessage(data []byte) (*message, error) {
+	if len(data) < 12 {
+		return nil, errors.New("short DNS message")
+	}
+	msg := &message{
+		ID:    binary.BigEndian.Uint16(data[0:]),
+		RCode: int(binary.BigEndian.Uint16(data[2:]) & 0x000f),
+	}
+	qdcount := int(binary.BigEndian.Uint16(data[4:]))
+	ancount := int(binary.BigEndian.Uint16(data[6:]))
+
+	off := 12
+	for i := 0; i < qdcount; i++ {
+		_, next, err := readName(data, off)
+		if err != nil {
+			return nil, err
+		}
+		off = next + 4
+	}
+	for i := 0; i < ancount; i++ {
+		name, next, err := readName(data, off)
+		if err != nil {
+			return nil, err
+		}
+		if next+10 > len(data) {
+			return nil, errors.New("truncated DNS record")
+		}
+		rtype := binary.BigEndian.Uint16(data[next:])
+		ttl := binary.BigEndian.Uint32(data[next+4:])
+		rdlen := int(binary.BigEndian.Uint16(data[next+8:]))
+		rdata := next + 10
+		if rdata+rdlen > len(data) {
+			return nil, errors.New("truncated DNS rdata")
+		}
+		record := Record{Name: name, Type: typeName(rtype), TTL: ttl}
+		record.Data, err = decodeRData(data, rtype, rdata, rdlen)
+		if err != nil {
+			return nil, err
+		}
+		msg.Answers = append(msg.Answers, record)
+		off = rdata + rdlen
+	}
+	return msg, nil
+}
+
+// readName lê um nome DNS (com suporte a compressão) a partir de off.
+// Retorna o nome e o offset logo após o nome na posição original.
+func readName(data []byte, off int) (string, int, error) {
+	var labels []string
+	next := -1
+	for jumps := 0; ; {
+		if off >= len(data) {
+			return "", 0, errors.New("truncated DNS name")
+		}
+		length := int(data[off])
+		switch {
+		case length == 0:
+			if next < 0 {
+				next = off + 1
+			}
+			return strings.Join(labels, ".") + ".", next, nil
+		case length&0xc0 == 0xc0:
+			if off+1 >= len(data) {
+				return "", 0, errors.New("truncated DNS pointer")
+			}
+			if next < 0 {
+				next = off + 2
+			}
+			jumps++
+			if jumps > 32 {
+				return "", 0, errors.New("DNS name compression loop")
+			}
+			off = int(binary.BigEndian.Uint16(data[off:]) & 0x3fff)
+		default:
+			if off+1+length > len(data) {
+				return "", 0, errors.New("truncated DNS label")
+			}
+			labels = append(labels, string(data[off+1:off+1+length]))
+			off += 1 + length
+		}
+	}
+}
+
+// decodeRData converte o rdata de um registro em texto.
+func decodeRData(data []byte, rtype uint16, off, length int) (string, error) {
+	rdata := data[off : off+length]
+	switch rtype {
+	case typeA, typeAAAA:
+		return net.IP(rdata).String(), nil
+	case typeNS, typeCNAME, typePTR:
+		name, _, err := readName(data, off)
+		return name, err
+	case typeMX:
+		if length < 3 {
+			return "", errors.New("invalid MX record")
+		}
+		name, _, err := readName(data, off+2)
+		return fmt.Sprintf("%d %s", binary.BigEndian.Uint16(rdata), name), err
+	case typeSRV:
+		if length < 7 {
+			return "", errors.New("invalid SRV record")
+		}
+		name, _, err := readName(data, off+6)
+		return fmt.Sprintf("%d %d %d %s", binary.BigEndian.Uint16(rdata), binary.BigEndian.Uint16(rdata[2:]),
+			binary.BigEndian.Uint16(rdata[4:]), name), err
+	case typeSOA:
+		mname, next, err := readName(data, off)
+		if err != nil {
+			return "", err
+		}
+		rname, next, err := readName(data, next)
+		if err != nil || next+20 > off+length {
+			return "", errors.New("invalid SOA record")
+		}
+		return fmt.Sprintf("%s %s %d", mname, rname, binary.BigEndian.Uint32(data[next:])), nil
+	case typeTXT:
+		var parts []string
+		for i := 0; i < len(rdata); {
+			n := int(rdata[i])
+			if i+1+n > len(rdata) {
+				break
+			}
+			parts = append(parts, fmt.Sprintf("%q", rdata[i+1:i+1+n]))
+			i += 1 + n
+		}
+		return strings.Join(parts, " "), nil
+	}
+	return fmt.Sprintf("\\# %d %x", length, rdata), nil
+}
+
+// typeName retorna o mnemônico de um tipo de registro.
+func typeName(t uint16) string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("TYPE%d", t)
+}
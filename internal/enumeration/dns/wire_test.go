@@ -0,0 +1,192 @@
+package dns
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// header monta o cabeçalho de uma resposta com os contadores informados.
+func header(id uint16, rcode, qdcount, ancount int) []byte {
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], 0x8400|uint16(rcode))
+	binary.BigEndian.PutUint16(msg[4:], uint16(qdcount))
+	binary.BigEndian.PutUint16(msg[6:], uint16(ancount))
+	return msg
+}
+
+// record acrescenta um registro com o nome já codificado em name.
+func record(msg, name []byte, rtype uint16, ttl uint32, rdata []byte) []byte {
+	msg = append(msg, name...)
+	msg = binary.BigEndian.AppendUint16(msg, rtype)
+	msg = binary.BigEndian.AppendUint16(msg, classINET)
+	msg = binary.BigEndian.AppendUint32(msg, ttl)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(len(rdata)))
+	return append(msg, rdata...)
+}
+
+// pointer codifica um ponteiro de compressão para off.
+func pointer(off int) []byte {
+	return []byte{0xc0 | byte(off>>8), byte(off)}
+}
+
+func TestBuildQuery(t *testing.T) {
+	query, err := buildQuery(0x1234, "corp.local.", typeAXFR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := parseMessage(query)
+	if err != nil {
+		t.Fatalf("parseMessage: %v", err)
+	}
+	if msg.ID != 0x1234 || len(msg.Answers) != 0 {
+		t.Errorf("got %+v", msg)
+	}
+	name, next, err := readName(query, 12)
+	if err != nil || name != "corp.local." || binary.BigEndian.Uint16(query[next:]) != typeAXFR {
+		t.Errorf("question = %q, %v", name, err)
+	}
+
+	for _, bad := range []string{"", "corp..local", string(make([]byte, 64)) + ".local"} {
+		if _, err := buildQuery(1, bad, typeA); err == nil {
+			t.Errorf("buildQuery(%q) accepted an invalid name", bad)
+		}
+	}
+}
+
+func TestReadNameCompression(t *testing.T) {
+	// 12: corp.local. | 24: dc01 -> 12 | 31: ponteiro -> 24
+	data := header(1, 0, 0, 0)
+	data = append(data, 4, 'c', 'o', 'r', 'p', 5, 'l', 'o', 'c', 'a', 'l', 0)
+	data = append(data, 4, 'd', 'c', '0', '1')
+	data = append(data, pointer(12)...)
+	data = append(data, pointer(24)...)
+
+	tests := []struct {
+		off  int
+		name string
+		next int
+	}{
+		{off: 12, name: "corp.local.", next: 24},
+		{off: 24, name: "dc01.corp.local.", next: 31},
+		{off: 31, name: "dc01.corp.local.", next: 33},
+		{off: 23, name: ".", next: 24},
+	}
+	for _, tt := range tests {
+		name, next, err := readName(data, tt.off)
+		if err != nil || name != tt.name || next != tt.next {
+			t.Errorf("readName(%d) = %q, %d, %v; want %q, %d", tt.off, name, next, err, tt.name, tt.next)
+		}
+	}
+}
+
+func TestReadNameMalformed(t *testing.T) {
+	base := header(1, 0, 0, 0)
+	tests := map[string][]byte{
+		"past the end":       base,
+		"self pointer":       append(append([]byte{}, base...), pointer(12)...),
+		"pointer loop":       append(append(append([]byte{}, base...), pointer(14)...), pointer(12)...),
+		"loop through label": append(append(append([]byte{}, base...), 1, 'a'), pointer(12)...),
+		"pointer past end":   append(append([]byte{}, base...), pointer(0x3fff)...),
+		"truncated pointer":  append(append([]byte{}, base...), 0xc0),
+		"truncated label":    append(append([]byte{}, base...), 10, 'a', 'b'),
+		"no terminator":      append(append([]byte{}, base...), 1, 'a'),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got, _, err := readName(data, 12); err == nil {
+				t.Errorf("readName accepted malformed name %q", got)
+			}
+		})
+	}
+}
+
+func TestParseMessage(t *testing.T) {
+	query, _ := buildQuery(7, "corp.local", typeAXFR)
+	data := header(7, 0, 1, 0)
+	data = append(data, query[12:]...)
+	zone := pointer(12)
+
+	soa := append(append([]byte{2, 'n', 's'}, zone...), 5, 'a', 'd', 'm', 'i', 'n')
+	soa = append(soa, zone...)
+	soa = binary.BigEndian.AppendUint32(soa, 2024010101)
+	soa = append(soa, make([]byte, 16)...)
+	mx := append([]byte{0, 10, 4, 'm', 'a', 'i', 'l'}, zone...)
+	srv := append([]byte{0, 0, 0, 100, 0x01, 0x85, 2, 'd', 'c'}, zone...)
+	records := []struct {
+		rtype uint16
+		rdata []byte
+		want  string
+	}{
+		{typeSOA, soa, "ns.corp.local. admin.corp.local. 2024010101"},
+		{typeA, []byte{10, 0, 0, 5}, "10.0.0.5"},
+		{typeAAAA, append([]byte{0xfe, 0x80}, make([]byte, 13)...), "fe80::"},
+		{typeMX, mx, "10 mail.corp.local."},
+		{typeSRV, srv, "0 100 389 dc.corp.local."},
+		{typeCNAME, zone, "corp.local."},
+		{typeTXT, []byte("\x05hello\x02hi\x09cut"), `"hello" "hi"`},
+		{99, []byte{0xab, 0xcd}, `\# 2 abcd`},
+	}
+	// O AAAA acima tem 15 bytes: completa os 16.
+	records[2].rdata = append(records[2].rdata, 0)
+	binary.BigEndian.PutUint16(data[6:], uint16(len(records)))
+	for _, r := range records {
+		data = record(data, zone, r.rtype, 3600, r.rdata)
+	}
+
+	msg, err := parseMessage(data)
+	if err != nil {
+		t.Fatalf("parseMessage: %v", err)
+	}
+	if len(msg.Answers) != len(records) {
+		t.Fatalf("%d answers, want %d", len(msg.Answers), len(records))
+	}
+	for i, r := range records {
+		got := msg.Answers[i]
+		if got.Name != "corp.local." || got.TTL != 3600 || got.Data != r.want || got.Type != typeName(r.rtype) {
+			t.Errorf("answer %d = %+v, want %s %q", i, got, typeName(r.rtype), r.want)
+		}
+	}
+
+	refused := header(7, 5, 0, 0)
+	if msg, err := parseMessage(refused); err != nil || msg.RCode != 5 {
+		t.Errorf("refused: %+v, %v", msg, err)
+	}
+}
+
+func TestParseMessageMalformed(t *testing.T) {
+	zone := []byte{4, 'c', 'o', 'r', 'p', 0}
+	valid := record(header(1, 0, 0, 1), zone, typeA, 60, []byte{10, 0, 0, 1})
+
+	tests := map[string][]byte{
+		"empty":             nil,
+		"short header":      valid[:11],
+		"missing answer":    header(1, 0, 0, 1),
+		"missing question":  header(1, 0, 3, 0),
+		"truncated fixed":   valid[:len(valid)-8],
+		"truncated rdata":   valid[:len(valid)-1],
+		"mx too short":      record(header(1, 0, 0, 1), zone, typeMX, 60, []byte{0, 1}),
+		"srv too short":     record(header(1, 0, 0, 1), zone, typeSRV, 60, []byte{0, 1, 0, 1, 0, 1}),
+		"soa without nums":  record(header(1, 0, 0, 1), zone, typeSOA, 60, append(append([]byte{}, zone...), zone...)),
+		"ns pointer loop":   record(header(1, 0, 0, 1), zone, typeNS, 60, pointer(12+len(zone)+10)),
+		"answer name loop":  append(header(1, 0, 0, 1), pointer(12)...),
+		"counts past data":  header(1, 0, 0xffff, 0xffff),
+		"cname out of data": record(header(1, 0, 0, 1), zone, typeCNAME, 60, []byte{3, 'a'}),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if msg, err := parseMessage(data); err == nil {
+				t.Errorf("parseMessage accepted malformed message: %+v", msg)
+			}
+		})
+	}
+}
+
+func FuzzParseMessage(f *testing.F) {
+	query, _ := buildQuery(1, "corp.local", typeAXFR)
+	f.Add(query)
+	f.Add(append(header(1, 0, 0, 1), pointer(12)...))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = parseMessage(data)
+	})
+}
@@ -0,0 +1,163 @@
+package enumeration
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/portscan"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// Estágios de execução. Os módulos de identificação rodam antes dos demais,
+// para que os seletores por nome de serviço já vejam os serviços identificados.
+const (
+	StageIdentify = iota
+	StageEnumerate
+)
+
+// Enumerator é um módulo de enumeração executado pelo Registry sobre os resultados do port scan.
+type Enumerator interface {
+	// Name retorna o nome do módulo, usado nos enrichments, nos logs e nas flags de habilitação.
+	Name() string
+	// Selector descreve os serviços aos quais o módulo se aplica.
+	Selector() Selector
+	// Enumerate executa o módulo contra um host e os serviços dele selecionados. Deve retornar assim
+	// que ctx terminar: depois do timeout o resultado é descartado, mas a execução continua ocupando
+	// uma das vagas de Registry.Threads até retornar.
+	Enumerate(ctx context.Context, target Target) (*Result, error)
+}
+
+// Staged é implementado pelos módulos que rodam em um estágio diferente de StageEnumerate.
+type Staged interface {
+	Stage() int
+}
+
+// Finalizer é implementado pelos módulos que precisam de uma etapa sobre todos os hosts depois
+// das execuções individuais (ex.: chaves SSH compartilhadas, SRV e AXFR dos domínios encontrados).
+type Finalizer interface {
+	Finalize(ctx context.Context, hosts []results.Host) ([]findings.Finding, error)
+}
+
+// Selector descreve os serviços aos quais um módulo se aplica. Um serviço é selecionado se
+// coincidir com qualquer um dos critérios de porta, nome, categoria ou protocolo.
+type Selector struct {
+	Ports        []int    // Portas TCP
+	UDPPorts     []int    // Portas UDP
+	Services     []string // Nomes de serviço reportados pelo Nmap (ex.: ssh, ftp)
+	Categories   []string // Categorias de portas do port scan (ex.: database, windows)
+	Protocols    []string // Todos os serviços dos protocolos informados (ex.: tcp)
+	Unidentified bool     // Restringe a seleção aos serviços ainda sem produto/versão
+	Host         bool     // Executa uma vez por host, mesmo sem serviços selecionados
+}
+
+// Matches retorna os serviços TCP e UDP do host selecionados.
+func (s Selector) Matches(host results.Host) []results.Service {
+	var tcp, udp []int
+	tcp = append(tcp, s.Ports...)
+	udp = append(udp, s.UDPPorts...)
+	for _, category := range s.Categories {
+		catTCP, catUDP, _ := portscan.CategoryPorts(category)
+		tcp = append(tcp, catTCP...)
+		udp = append(udp, catUDP...)
+	}
+
+	var matched []results.Service
+	for _, services := range [][]results.Service{host.Services, host.UDPServices} {
+		for _, svc := range services {
+			ports := tcp
+			if svc.Protocol == "udp" {
+				ports = udp
+			}
+			if s.Unidentified && svc.Identified() {
+				continue
+			}
+			if containsInt(ports, svc.Port) || containsFold(s.Services, svc.Name) || containsFold(s.Protocols, svc.Protocol) {
+				matched = append(matched, svc)
+			}
+		}
+	}
+	return matched
+}
+
+// Target é a entrada de um módulo: uma cópia do host e os serviços dele selecionados.
+// O módulo não deve alterar o host; as alterações são aplicadas pelo Registry a partir do Result.
+type Target struct {
+	Host     results.Host      `json:"host"`
+	Services []results.Service `json:"services,omitempty"`
+}
+
+// Result é o que um módulo produz para um host.
+type Result struct {
+	Enrichment interface{}        `json:"enrichment,omitempty"` // Anexado ao host sob o nome do módulo
+	Services   []ServiceResult    `json:"services,omitempty"`
+	Hostnames  []string           `json:"hostnames,omitempty"`
+	Tags       []string           `json:"tags,omitempty"`
+	Findings   []findings.Finding `json:"findings,omitempty"`
+}
+
+// ServiceResult é o que um módulo produz para um serviço do host.
+type ServiceResult struct {
+	Protocol   string      `json:"protocol"`
+	Port       int         `json:"port"`
+	Name       string      `json:"name,omitempty"` // Nome, produto e versão aplicados apenas se o Nmap não identificou o serviço
+	Product    string      `json:"product,omitempty"`
+	Version    string      `json:"version,omitempty"`
+	Banner     string      `json:"banner,omitempty"`     // Gravado apenas se o serviço ainda não tiver banner
+	Enrichment interface{} `json:"enrichment,omitempty"` // Anexado ao serviço sob o nome do módulo
+}
+
+// apply grava o resultado do módulo no host.
+func (r *Result) apply(module string, host *results.Host) {
+	if r.Enrichment != nil {
+		host.SetEnrichment(module, r.Enrichment)
+	}
+	for _, name := range r.Hostnames {
+		host.AddHostname(name)
+	}
+	for _, tag := range r.Tags {
+		host.AddTag(tag)
+	}
+	for _, sr := range r.Services {
+		svc := host.Service(sr.Protocol, sr.Port)
+		if svc == nil {
+			continue
+		}
+		if svc.Banner == "" {
+			svc.Banner = sr.Banner
+		}
+		svc.Identify(module, sr.Name, sr.Product, sr.Version)
+		if sr.Enrichment != nil {
+			svc.SetEnrichment(module, sr.Enrichment)
+		}
+	}
+}
+
+// stageOf retorna o estágio de execução do módulo.
+func stageOf(e Enumerator) int {
+	if s, ok := e.(Staged); ok {
+		return s.Stage()
+	}
+	return StageEnumerate
+}
+
+func containsInt(list []int, value int) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,307 @@
+package ftp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ModuleName é o nome do módulo usado em logs e nos resultados.
+const ModuleName = "ftp"
+
+// TagAnonymous marca hosts com login anônimo habilitado no FTP.
+const TagAnonymous = "ftp-anonymous"
+
+// TagWritable marca hosts cujo diretório raiz anônimo aceita escrita.
+const TagWritable = "ftp-anonymous-writable"
+
+// DefaultPorts são as portas tratadas como FTP mesmo quando o Nmap não identifica o serviço.
+var DefaultPorts = []int{21}
+
+// maxListing limita a quantidade de bytes lidos da listagem do diretório raiz.
+const maxListing = 64 * 1024
+
+// Info contém o resultado da verificação de um servidor FTP.
+type Info struct {
+	Address     string             `json:"address"`
+	Port        int                `json:"port"`
+	Banner      string             `json:"banner"`
+	Anonymous   bool               `json:"anonymous"`
+	LoginReply  string             `json:"login_reply,omitempty"`
+	Directory   string             `json:"directory,omitempty"`
+	Listing     []string           `json:"listing,omitempty"`
+	ListError   string             `json:"list_error,omitempty"`
+	WriteTested bool               `json:"write_tested"`
+	Writable    bool               `json:"writable"`
+	Findings    []findings.Finding `json:"findings,omitempty"`
+}
+
+// addFinding registra um achado do servidor.
+func (i *Info) addFinding(id, title, severity, evidence string) {
+	i.Findings = append(i.Findings, findings.New(ModuleName, id, title, severity, i.Address, i.Port, "tcp").WithEvidence(evidence))
+}
+
+// Scanner verifica login anônimo em servidores FTP.
+type Scanner struct {
+	Username   string        // Usuário usado no login anônimo
+	Password   string        // Senha enviada no login anônimo
+	CheckWrite bool          // Testa escrita criando e removendo um diretório (opt-in)
+	Timeout    time.Duration // Timeout por conexão e por resposta
+	Threads    int           // Quantidade de servidores verificados simultaneamente
+}
+
+// NewScanner é a factory que cria um Scanner com valores padrão.
+func NewScanner() *Scanner {
+	return &Scanner{
+		Username: "anonymous",
+		Password: "anonymous@example.com",
+		Timeout:  5 * time.Second,
+		Threads:  10,
+	}
+}
+
+// Check conecta em address:port, lê o banner, tenta o login anônimo e, se aceito,
+// lista o diretório raiz e (opcionalmente) testa se ele aceita escrita.
+func (sc *Scanner) Check(ctx context.Context, address string, port int) (*Info, error) {
+	target := net.JoinHostPort(address, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: sc.Timeout}
+	conn, err := util.DialContext(ctx, dialer, "tcp", target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(4 * sc.Timeout))
+	tp := textproto.NewConn(conn)
+
+	_, banner, err := tp.ReadResponse(220)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FTP greeting from %s: %w", target, err)
+	}
+	info := &Info{Address: address, Port: port, Banner: strings.ReplaceAll(banner, "\n", " ")}
+	info.addFinding("ftp-banner", "FTP server banner disclosed", findings.SeverityInfo, info.Banner)
+
+	code, msg, err := sc.cmd(tp, "USER %s", sc.Username)
+	if err != nil {
+		return info, nil
+	}
+	if code == 331 || code == 332 {
+		code, msg, err = sc.cmd(tp, "PASS %s", sc.Password)
+		if err != nil {
+			return info, nil
+		}
+	}
+	info.LoginReply = fmt.Sprintf("%d %s", code, msg)
+	if code != 230 {
+		return info, nil
+	}
+	info.Anonymous = true
+	info.addFinding(findings.IDFTPAnonymous, "Anonymous FTP login allowed", findings.SeverityMedium, info.LoginReply)
+
+	if code, msg, err := sc.cmd(tp, "PWD"); err == nil && code == 257 {
+		info.Directory = parsePWD(msg)
+	}
+
+	listing, err := sc.list(ctx, tp, address)
+	if err != nil {
+		info.ListError = err.Error()
+	} else {
+		info.Listing = listing
+	}
+
+	if sc.CheckWrite {
+		info.WriteTested = true
+		if sc.writable(tp) {
+			info.Writable = true
+			info.addFinding(findings.IDFTPAnonymousWritable, "Anonymous FTP root directory is writable", findings.SeverityHigh, info.Directory)
+		}
+	}
+
+	_, _, _ = sc.cmd(tp, "QUIT")
+	return info, nil
+}
+
+// cmd envia um comando e lê a resposta completa (inclusive multilinha).
+func (sc *Scanner) cmd(tp *textproto.Conn, format string, args ...interface{}) (int, string, error) {
+	if err := tp.PrintfLine(format, args...); err != nil {
+		return 0, "", err
+	}
+	code, msg, err := tp.ReadResponse(0)
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code, protoErr.Msg, nil
+	}
+	return code, msg, err
+}
+
+// pasvRe extrai h1,h2,h3,h4,p1,p2 da resposta 227 ao PASV.
+var pasvRe = regexp.MustCompile(`(\d+),(\d+),(\d+),(\d+),(\d+),(\d+)`)
+
+// epsvRe extrai a porta da resposta 229 ao EPSV (ex.: "(|||6446|)").
+var epsvRe = regexp.MustCompile(`\(\|\|\|(\d+)\|\)`)
+
+// list abre uma conexão de dados passiva e lê a listagem (LIST) do diretório atual.
+// O endereço informado no PASV é ignorado: a conexão de dados sempre vai para o próprio host.
+func (sc *Scanner) list(ctx context.Context, tp *textproto.Conn, address string) ([]string, error) {
+	dataPort := 0
+	if code, msg, err := sc.cmd(tp, "EPSV"); err == nil && code == 229 {
+		if m := epsvRe.FindStringSubmatch(msg); m != nil {
+			dataPort, _ = strconv.Atoi(m[1])
+		}
+	}
+	if dataPort == 0 {
+		code, msg, err := sc.cmd(tp, "PASV")
+		if err != nil {
+			return nil, err
+		}
+		m := pasvRe.FindStringSubmatch(msg)
+		if code != 227 || m == nil {
+			return nil, fmt.Errorf("passive mode refused: %d %s", code, msg)
+		}
+		p1, _ := strconv.Atoi(m[5])
+		p2, _ := strconv.Atoi(m[6])
+		dataPort = p1*256 + p2
+	}
+
+	dialer := &net.Dialer{Timeout: sc.Timeout}
+	data, err := util.DialContext(ctx, dialer, "tcp", net.JoinHostPort(address, strconv.Itoa(dataPort)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data connection: %w", err)
+	}
+	defer data.Close()
+	_ = data.SetDeadline(time.Now().Add(sc.Timeout))
+
+	code, msg, err := sc.cmd(tp, "LIST")
+	if err != nil {
+		return nil, err
+	}
+	if code != 125 && code != 150 {
+		return nil, fmt.Errorf("LIST refused: %d %s", code, msg)
+	}
+	raw, err := io.ReadAll(io.LimitReader(data, maxListing))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read listing: %w", err)
+	}
+	data.Close()
+	_, _, _ = tp.ReadResponse(0) // 226 Transfer complete
+
+	var lines []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// writable tenta criar e remover um diretório com nome aleatório no diretório atual.
+func (sc *Scanner) writable(tp *textproto.Conn) bool {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	name := "arthxrecon-" + hex.EncodeToString(suffix)
+	code, _, err := sc.cmd(tp, "MKD %s", name)
+	if err != nil || code != 257 {
+		return false
+	}
+	if code, msg, err := sc.cmd(tp, "RMD %s", name); err != nil || code != 250 {
+		util.ModuleLogger(ModuleName).Warn().Msgf("Failed to remove test directory %s: %d %s", name, code, msg)
+	}
+	return true
+}
+
+// parsePWD extrai o diretório entre aspas da resposta 257.
+func parsePWD(msg string) string {
+	start := strings.Index(msg, `"`)
+	end := strings.LastIndex(msg, `"`)
+	if start < 0 || end <= start {
+		return msg
+	}
+	return msg[start+1 : end]
+}
+
+// isFTPService indica se o serviço deve ser verificado.
+func isFTPService(svc results.Service) bool {
+	if svc.Name == "ftp" {
+		return true
+	}
+	for _, p := range DefaultPorts {
+		if svc.Port == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Run verifica todos os serviços FTP dos hosts e anexa o resultado ao registro do serviço.
+func (sc *Scanner) Run(ctx context.Context, hosts []results.Host) []Info {
+	threads := sc.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	batch := util.NewProbeBatch(ctx, ModuleName, threads)
+	for i := range hosts {
+		for _, svc := range hosts[i].Services {
+			if isFTPService(svc) {
+				batch.Add(hosts[i].Address, "tcp", svc.Port)
+			}
+		}
+	}
+	batch.Start()
+	defer batch.Finish()
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found []Info
+		sem   = make(chan struct{}, threads)
+	)
+	for i := range hosts {
+		for j := range hosts[i].Services {
+			if !isFTPService(hosts[i].Services[j]) {
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			util.WaitWindow(ctx)
+			go func(host *results.Host, svc *results.Service) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				info, err := sc.Check(ctx, host.Address, svc.Port)
+				if err != nil {
+					util.ModuleLogger(ModuleName).Debug().Err(err).Msg("FTP check failed")
+					return
+				}
+
+				mu.Lock()
+				svc.SetEnrichment(ModuleName, info)
+				if svc.Banner == "" {
+					svc.Banner = info.Banner
+				}
+				if info.Anonymous {
+					host.AddTag(TagAnonymous)
+				}
+				if info.Writable {
+					host.AddTag(TagWritable)
+				}
+				found = append(found, *info)
+				mu.Unlock()
+			}(&hosts[i], &hosts[i].Services[j])
+		}
+	}
+	wg.Wait()
+	return found
+}
@@ -0,0 +1,220 @@
+package ftp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeFTP é um servidor FTP mínimo em 127.0.0.1. Greeting é enviado na conexão e Replies mapeia
+// cada comando (sem argumentos) para a resposta crua; LIST usa a conexão de dados passiva. Sem
+// Replies, a conexão é encerrada logo após o greeting.
+type fakeFTP struct {
+	Greeting string
+	Replies  map[string]string
+	Listing  string
+	Passive  string // EPSV ou PASV: o modo passivo aceito
+}
+
+func (f *fakeFTP) start(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(t, conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func (f *fakeFTP) serve(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte(f.Greeting)); err != nil || f.Replies == nil {
+		return
+	}
+	var data net.Listener
+	defer func() {
+		if data != nil {
+			data.Close()
+		}
+	}()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.Fields(line)[0])
+		reply, ok := f.Replies[command]
+		switch {
+		case command == f.Passive && data == nil:
+			data, err = net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			port := data.Addr().(*net.TCPAddr).Port
+			if command == "EPSV" {
+				reply = fmt.Sprintf("229 Entering Extended Passive Mode (|||%d|)\r\n", port)
+			} else {
+				// O endereço anunciado é ignorado pelo scanner, que sempre usa o próprio host.
+				reply = fmt.Sprintf("227 Entering Passive Mode (192,0,2,1,%d,%d)\r\n", port>>8, port&0xff)
+			}
+		case command == "LIST" && data != nil:
+			_, _ = conn.Write([]byte("150 Here comes the directory listing.\r\n"))
+			dc, err := data.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = dc.Write([]byte(f.Listing))
+			dc.Close()
+			reply = "226 Directory send OK.\r\n"
+		case command == "QUIT":
+			_, _ = conn.Write([]byte("221 Goodbye.\r\n"))
+			return
+		case !ok:
+			reply = "502 Command not implemented.\r\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func check(t *testing.T, server *fakeFTP, checkWrite bool) (*Info, error) {
+	t.Helper()
+	sc := NewScanner()
+	sc.Timeout = 2 * time.Second
+	sc.CheckWrite = checkWrite
+	return sc.Check(context.Background(), "127.0.0.1", server.start(t))
+}
+
+func TestCheckAnonymous(t *testing.T) {
+	for _, passive := range []string{"EPSV", "PASV"} {
+		t.Run(passive, func(t *testing.T) {
+			server := &fakeFTP{
+				Greeting: "220-Welcome to the archive\r\n220-Authorized use only\r\n220 ready\r\n",
+				Replies: map[string]string{
+					"USER": "331 Please specify the password.\r\n",
+					"PASS": "230-Guest login ok\r\n 230 lines without a code are kept\r\n230 Restrictions apply.\r\n",
+					"PWD":  "257 \"/pub\" is the current directory\r\n",
+					"MKD":  "257 \"/pub/x\" created\r\n",
+					"RMD":  "250 Remove directory operation successful.\r\n",
+				},
+				Listing: "drwxr-xr-x 2 0 0 4096 Jan 01 00:00 incoming\r\n-rw-r--r-- 1 0 0 12 Jan 01 00:00 README\r\n\r\n",
+				Passive: passive,
+			}
+			info, err := check(t, server, true)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if info.Banner != "Welcome to the archive Authorized use only ready" {
+				t.Errorf("Banner = %q", info.Banner)
+			}
+			if !info.Anonymous || !strings.HasPrefix(info.LoginReply, "230 Guest login ok") || !strings.HasSuffix(info.LoginReply, "Restrictions apply.") {
+				t.Errorf("Anonymous = %v, LoginReply = %q", info.Anonymous, info.LoginReply)
+			}
+			if info.Directory != "/pub" {
+				t.Errorf("Directory = %q", info.Directory)
+			}
+			if len(info.Listing) != 2 || !strings.HasSuffix(info.Listing[1], "README") || info.ListError != "" {
+				t.Errorf("Listing = %q, ListError = %q", info.Listing, info.ListError)
+			}
+			if !info.WriteTested || !info.Writable {
+				t.Errorf("WriteTested = %v, Writable = %v", info.WriteTested, info.Writable)
+			}
+			if len(info.Findings) != 3 {
+				t.Errorf("%d findings: %+v", len(info.Findings), info.Findings)
+			}
+		})
+	}
+}
+
+func TestCheckLoginDenied(t *testing.T) {
+	server := &fakeFTP{
+		Greeting: "220 FTP server ready\r\n",
+		Replies: map[string]string{
+			"USER": "331 Password required\r\n",
+			"PASS": "530-Login incorrect.\r\n530 Anonymous access disabled.\r\n",
+		},
+	}
+	info, err := check(t, server, true)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if info.Anonymous || info.WriteTested || info.LoginReply != "530 Login incorrect.\nAnonymous access disabled." {
+		t.Errorf("Anonymous = %v, WriteTested = %v, LoginReply = %q", info.Anonymous, info.WriteTested, info.LoginReply)
+	}
+	if len(info.Findings) != 1 {
+		t.Errorf("%d findings: %+v", len(info.Findings), info.Findings)
+	}
+}
+
+func TestCheckListingRefused(t *testing.T) {
+	server := &fakeFTP{
+		Greeting: "220 ready\r\n",
+		Replies: map[string]string{
+			"USER": "230 Login successful.\r\n",
+			"PWD":  "257 no quotes here\r\n",
+			"PASV": "227 Entering Passive Mode (garbage)\r\n",
+		},
+	}
+	info, err := check(t, server, false)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if !info.Anonymous || info.Directory != "no quotes here" || info.WriteTested {
+		t.Errorf("got %+v", info)
+	}
+	if !strings.HasPrefix(info.ListError, "passive mode refused: 227") {
+		t.Errorf("ListError = %q", info.ListError)
+	}
+}
+
+func TestCheckMalformedGreeting(t *testing.T) {
+	tests := map[string]string{
+		"service not available": "421 Too many connections\r\n",
+		"not ftp":               "SSH-2.0-OpenSSH_9.6\r\n",
+		"unterminated":          "220-Welcome\r\n220-still going\r\n",
+		"short code":            "22 ready\r\n",
+		"empty":                 "",
+	}
+	for name, greeting := range tests {
+		t.Run(name, func(t *testing.T) {
+			sc := NewScanner()
+			sc.Timeout = 500 * time.Millisecond
+			port := (&fakeFTP{Greeting: greeting}).start(t)
+			if info, err := sc.Check(context.Background(), "127.0.0.1", port); err == nil {
+				t.Errorf("Check accepted greeting %q: %+v", greeting, info)
+			}
+		})
+	}
+}
+
+func TestParsePWD(t *testing.T) {
+	tests := map[string]string{
+		`"/" is the current directory`:       "/",
+		`"/home/ftp/""quoted""" is current`:  `/home/ftp/""quoted""`,
+		`MVS "'USER.'" is working directory`: `'USER.'`,
+		`no quotes`:                          "no quotes",
+		`"`:                                  `"`,
+	}
+	for msg, want := range tests {
+		if got := parsePWD(msg); got != want {
+			t.Errorf("parsePWD(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package ftp
+
+import (
+	"context"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+)
+
+// Module expõe o Scanner como enumeration.Enumerator.
+type Module struct {
+	Scanner *Scanner
+}
+
+// NewModule é a factory que cria o módulo a partir de um Scanner.
+func NewModule(sc *Scanner) *Module {
+	return &Module{Scanner: sc}
+}
+
+// Name retorna o nome do módulo.
+func (m *Module) Name() string { return ModuleName }
+
+// Selector seleciona os serviços ftp e as portas FTP padrão.
+func (m *Module) Selector() enumeration.Selector {
+	return enumeration.Selector{Ports: DefaultPorts, Services: []string{"ftp"}}
+}
+
+// Enumerate verifica cada serviço FTP selecionado do host.
+func (m *Module) Enumerate(ctx context.Context, target enumeration.Target) (*enumeration.Result, error) {
+	result := &enumeration.Result{}
+	var lastErr error
+	for _, svc := range target.Services {
+		if svc.Protocol != "tcp" {
+			continue
+		}
+		info, err := m.Scanner.Check(ctx, target.Host.Address, svc.Port)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		result.Services = append(result.Services, enumeration.ServiceResult{
+			Protocol:   svc.Protocol,
+			Port:       svc.Port,
+			Banner:     info.Banner,
+			Enrichment: info,
+		})
+		if info.Anonymous {
+			result.Tags = append(result.Tags, TagAnonymous)
+		}
+		if info.Writable {
+			result.Tags = append(result.Tags, TagWritable)
+		}
+		result.Findings = append(result.Findings, info.Findings...)
+	}
+	if len(result.Services) == 0 {
+		return nil, lastErr
+	}
+	return result, nil
+}
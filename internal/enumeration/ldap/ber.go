@@ -0,0 +1,145 @@
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Tags BER utilizadas nas mensagens LDAP.
+const (
+	tagInteger         byte = 0x02
+	tagOctetString     byte = 0x04
+	tagBoolean         byte = 0x01
+	tagEnumerated      byte = 0x0a
+	tagSequence        byte = 0x30
+	tagSet             byte = 0x31
+	tagSearchRequest   byte = 0x63 // [APPLICATION 3]
+	tagSearchEntry     byte = 0x64 // [APPLICATION 4]
+	tagSearchDone      byte = 0x65 // [APPLICATION 5]
+	tagFilterPresent   byte = 0x87 // [7] primitivo
+	maxBERMessageBytes      = 4 << 20
+)
+
+// berElement é um elemento TLV decodificado.
+type berElement struct {
+	Tag     byte
+	Content []byte
+}
+
+// berEncode codifica um elemento TLV.
+func berEncode(tag byte, content []byte) []byte {
+	out := []byte{tag}
+	n := len(content)
+	switch {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n < 0x100:
+		out = append(out, 0x81, byte(n))
+	case n < 0x10000:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	default:
+		out = append(out, 0x83, byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(out, content...)
+}
+
+// berInt codifica um inteiro pequeno e não negativo.
+func berInt(tag byte, v int) []byte {
+	var content []byte
+	for {
+		content = append([]byte{byte(v)}, content...)
+		v >>= 8
+		if v == 0 {
+			break
+		}
+	}
+	if content[0]&0x80 != 0 {
+		content = append([]byte{0}, content...)
+	}
+	return berEncode(tag, content)
+}
+
+// berConcat concatena elementos já codificados.
+func berConcat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// berRead lê um elemento TLV completo de r.
+func berRead(r *bufio.Reader) (*berElement, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	first, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	length := int(first)
+	if first&0x80 != 0 {
+		n := int(first & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, errors.New("unsupported BER length")
+		}
+		length = 0
+		for i := 0; i < n; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxBERMessageBytes {
+		return nil, fmt.Errorf("BER element too large (%d bytes)", length)
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+	return &berElement{Tag: tag, Content: content}, nil
+}
+
+// berChildren decodifica os elementos contidos em um tipo construído.
+func berChildren(content []byte) ([]berElement, error) {
+	var children []berElement
+	for len(content) > 0 {
+		if len(content) < 2 {
+			return nil, errors.New("truncated BER element")
+		}
+		tag := content[0]
+		length := int(content[1])
+		hdr := 2
+		if content[1]&0x80 != 0 {
+			n := int(content[1] & 0x7f)
+			if n == 0 || n > 4 || len(content) < 2+n {
+				return nil, errors.New("invalid BER length")
+			}
+			length = 0
+			for _, b := range content[2 : 2+n] {
+				length = length<<8 | int(b)
+			}
+			hdr += n
+		}
+		if hdr+length > len(content) {
+			return nil, errors.New("truncated BER element")
+		}
+		children = append(children, berElement{Tag: tag, Content: content[hdr : hdr+length]})
+		content = content[hdr+length:]
+	}
+	return children, nil
+}
+
+// berToInt decodifica o conteúdo de um INTEGER/ENUMERATED.
+func berToInt(content []byte) int {
+	v := 0
+	for _, b := range content {
+		v = v<<8 | int(b)
+	}
+	return v
+}
@@ -0,0 +1,339 @@
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ModuleName é o nome do módulo usado em logs e nos resultados.
+const ModuleName = "ldap"
+
+// TagDomainController marca hosts identificados como controladores de domínio.
+const TagDomainController = "domain-controller"
+
+// Ports são as portas LDAP consultadas, em ordem de preferência. 636 e 3269 usam TLS.
+var Ports = []int{389, 3268, 636, 3269}
+
+// OIDs de capacidades anunciadas em supportedCapabilities.
+const (
+	oidActiveDirectory    = "1.2.840.113556.1.4.800"  // LDAP_CAP_ACTIVE_DIRECTORY_OID
+	oidActiveDirectoryLDS = "1.2.840.113556.1.4.1851" // LDAP_CAP_ACTIVE_DIRECTORY_ADAM_OID
+)
+
+// rootDSEAttributes são os atributos solicitados na consulta anônima ao rootDSE.
+var rootDSEAttributes = []string{
+	"defaultNamingContext",
+	"rootDomainNamingContext",
+	"configurationNamingContext",
+	"namingContexts",
+	"domainFunctionality",
+	"forestFunctionality",
+	"domainControllerFunctionality",
+	"dnsHostName",
+	"serverName",
+	"ldapServiceName",
+	"isGlobalCatalogReady",
+	"supportedLDAPVersion",
+	"supportedSASLMechanisms",
+	"supportedControl",
+	"supportedCapabilities",
+	"vendorName",
+	"vendorVersion",
+}
+
+// RootDSE contém o resultado da consulta anônima ao rootDSE de um host.
+type RootDSE struct {
+	Address                 string              `json:"address"`
+	Port                    int                 `json:"port"`
+	DefaultNamingContext    string              `json:"default_naming_context,omitempty"`
+	RootDomainNamingContext string              `json:"root_domain_naming_context,omitempty"`
+	DNSHostName             string              `json:"dns_hostname,omitempty"`
+	DomainFunctionality     string              `json:"domain_functional_level,omitempty"`
+	ForestFunctionality     string              `json:"forest_functional_level,omitempty"`
+	DCFunctionality         string              `json:"dc_functional_level,omitempty"`
+	GlobalCatalog           bool                `json:"global_catalog"`
+	DomainController        bool                `json:"domain_controller"`
+	SASLMechanisms          []string            `json:"sasl_mechanisms,omitempty"`
+	Controls                []string            `json:"controls,omitempty"`
+	Capabilities            []string            `json:"capabilities,omitempty"`
+	Attributes              map[string][]string `json:"attributes"` // Todos os atributos retornados, sem tratamento
+}
+
+// Scanner executa consultas anônimas ao rootDSE.
+type Scanner struct {
+	Timeout time.Duration // Timeout por conexão e por resposta
+	Threads int           // Quantidade de hosts consultados simultaneamente
+}
+
+// NewScanner é a factory que cria um Scanner com valores padrão.
+func NewScanner() *Scanner {
+	return &Scanner{
+		Timeout: 5 * time.Second,
+		Threads: 10,
+	}
+}
+
+// Query consulta o rootDSE em address:port com bind anônimo implícito.
+func (sc *Scanner) Query(ctx context.Context, address string, port int) (*RootDSE, error) {
+	dialer := &net.Dialer{Timeout: sc.Timeout}
+	target := net.JoinHostPort(address, strconv.Itoa(port))
+	var conn net.Conn
+	var err error
+	if port == 636 || port == 3269 {
+		if err := util.LimiterFrom(ctx).Wait(ctx); err != nil {
+			return nil, err
+		}
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{InsecureSkipVerify: true}}).DialContext(ctx, "tcp", target)
+	} else {
+		conn, err = util.DialContext(ctx, dialer, "tcp", target)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(sc.Timeout))
+
+	if _, err := conn.Write(searchRootDSE(1)); err != nil {
+		return nil, fmt.Errorf("failed to send rootDSE search to %s: %w", target, err)
+	}
+
+	attrs := make(map[string][]string)
+	reader := bufio.NewReader(conn)
+	for {
+		msg, err := berRead(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read LDAP response from %s: %w", target, err)
+		}
+		parts, err := berChildren(msg.Content)
+		if err != nil || len(parts) < 2 {
+			return nil, fmt.Errorf("invalid LDAP message from %s", target)
+		}
+		op := parts[1]
+		if op.Tag == tagSearchDone {
+			if fields, err := berChildren(op.Content); err == nil && len(fields) > 0 {
+				if code := berToInt(fields[0].Content); code != 0 {
+					return nil, fmt.Errorf("rootDSE search on %s failed with result code %d", target, code)
+				}
+			}
+			break
+		}
+		if op.Tag != tagSearchEntry {
+			continue
+		}
+		if err := parseSearchEntry(op.Content, attrs); err != nil {
+			return nil, fmt.Errorf("invalid search entry from %s: %w", target, err)
+		}
+	}
+	if len(attrs) == 0 {
+		return nil, errors.New("empty rootDSE returned by " + target)
+	}
+	return newRootDSE(address, port, attrs), nil
+}
+
+// searchRootDSE monta um SearchRequest base "" com filtro (objectClass=*).
+func searchRootDSE(messageID int) []byte {
+	var attrList []byte
+	for _, a := range rootDSEAttributes {
+		attrList = append(attrList, berEncode(tagOctetString, []byte(a))...)
+	}
+	request := berEncode(tagSearchRequest, berConcat(
+		berEncode(tagOctetString, nil), // baseObject ""
+		berInt(tagEnumerated, 0),       // scope: baseObject
+		berInt(tagEnumerated, 0),       // derefAliases: never
+		berInt(tagInteger, 0),          // sizeLimit
+		berInt(tagInteger, 0),          // timeLimit
+		berEncode(tagBoolean, []byte{0}),
+		berEncode(tagFilterPresent, []byte("objectClass")),
+		berEncode(tagSequence, attrList),
+	))
+	return berEncode(tagSequence, berConcat(berInt(tagInteger, messageID), request))
+}
+
+// parseSearchEntry extrai os atributos de um SearchResultEntry.
+func parseSearchEntry(content []byte, attrs map[string][]string) error {
+	fields, err := berChildren(content)
+	if err != nil || len(fields) < 2 {
+		return errors.New("malformed entry")
+	}
+	list, err := berChildren(fields[1].Content)
+	if err != nil {
+		return err
+	}
+	for _, item := range list {
+		pair, err := berChildren(item.Content)
+		if err != nil || len(pair) < 2 {
+			return errors.New("malformed attribute")
+		}
+		values, err := berChildren(pair[1].Content)
+		if err != nil {
+			return err
+		}
+		name := string(pair[0].Content)
+		for _, v := range values {
+			attrs[name] = append(attrs[name], string(v.Content))
+		}
+	}
+	return nil
+}
+
+// newRootDSE interpreta os atributos retornados e identifica controladores de domínio.
+func newRootDSE(address string, port int, attrs map[string][]string) *RootDSE {
+	first := func(name string) string {
+		for k, v := range attrs {
+			if strings.EqualFold(k, name) && len(v) > 0 {
+				return v[0]
+			}
+		}
+		return ""
+	}
+	all := func(name string) []string {
+		for k, v := range attrs {
+			if strings.EqualFold(k, name) {
+				return v
+			}
+		}
+		return nil
+	}
+
+	dse := &RootDSE{
+		Address:                 address,
+		Port:                    port,
+		DefaultNamingContext:    first("defaultNamingContext"),
+		RootDomainNamingContext: first("rootDomainNamingContext"),
+		DNSHostName:             first("dnsHostName"),
+		DomainFunctionality:     functionalLevel(first("domainFunctionality")),
+		ForestFunctionality:     functionalLevel(first("forestFunctionality")),
+		DCFunctionality:         functionalLevel(first("domainControllerFunctionality")),
+		GlobalCatalog:           strings.EqualFold(first("isGlobalCatalogReady"), "TRUE"),
+		SASLMechanisms:          all("supportedSASLMechanisms"),
+		Controls:                all("supportedControl"),
+		Capabilities:            all("supportedCapabilities"),
+		Attributes:              attrs,
+	}
+
+	// Um DC anuncia a capacidade de Active Directory (e não de AD LDS) e possui um naming context de domínio.
+	isAD, isLDS := false, false
+	for _, c := range dse.Capabilities {
+		switch c {
+		case oidActiveDirectory:
+			isAD = true
+		case oidActiveDirectoryLDS:
+			isLDS = true
+		}
+	}
+	dse.DomainController = isAD && !isLDS && dse.DefaultNamingContext != ""
+	return dse
+}
+
+// functionalLevel traduz os níveis funcionais do AD (msDS-Behavior-Version).
+func functionalLevel(value string) string {
+	if value == "" {
+		return ""
+	}
+	levels := map[string]string{
+		"0":  "Windows 2000",
+		"1":  "Windows Server 2003 interim",
+		"2":  "Windows Server 2003",
+		"3":  "Windows Server 2008",
+		"4":  "Windows Server 2008 R2",
+		"5":  "Windows Server 2012",
+		"6":  "Windows Server 2012 R2",
+		"7":  "Windows Server 2016",
+		"10": "Windows Server 2025",
+	}
+	if name, ok := levels[value]; ok {
+		return fmt.Sprintf("%s (%s)", name, value)
+	}
+	return value
+}
+
+// Run consulta os hosts com portas LDAP abertas, anexa o rootDSE ao registro do host
+// e marca os controladores de domínio com TagDomainController.
+func (sc *Scanner) Run(ctx context.Context, hosts []results.Host) []RootDSE {
+	threads := sc.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	batch := util.NewProbeBatch(ctx, ModuleName, threads)
+	for i := range hosts {
+		for _, p := range Ports {
+			if hosts[i].HasPort("tcp", p) {
+				batch.Add(hosts[i].Address, "tcp", p)
+			}
+		}
+	}
+	batch.Start()
+	defer batch.Finish()
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found []RootDSE
+		sem   = make(chan struct{}, threads)
+	)
+	for i := range hosts {
+		var ports []int
+		for _, p := range Ports {
+			if hosts[i].HasPort("tcp", p) {
+				ports = append(ports, p)
+			}
+		}
+		if len(ports) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		util.WaitWindow(ctx)
+		go func(host *results.Host, ports []int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			// Usa a primeira porta que responder ao rootDSE.
+			for _, port := range ports {
+				dse, err := sc.Query(ctx, host.Address, port)
+				if err != nil {
+					util.ModuleLogger(ModuleName).Debug().Err(err).Msg("rootDSE query failed")
+					continue
+				}
+
+				mu.Lock()
+				host.SetEnrichment(ModuleName, dse)
+				if dse.DomainController {
+					host.AddTag(TagDomainController)
+				}
+				found = append(found, *dse)
+				mu.Unlock()
+				return
+			}
+		}(&hosts[i], ports)
+	}
+	wg.Wait()
+	return found
+}
+
+// Findings converte os rootDSE obtidos em achados informativos.
+func Findings(found []RootDSE) []findings.Finding {
+	var list []findings.Finding
+	for _, dse := range found {
+		id, title := "ldap-anonymous-rootdse", "Anonymous LDAP rootDSE query allowed"
+		if dse.DomainController {
+			id, title = "ldap-domain-controller", "Domain controller identified"
+		}
+		list = append(list, findings.New(ModuleName, id, title, findings.SeverityInfo, dse.Address, dse.Port, "tcp").
+			WithEvidence(dse.DefaultNamingContext).
+			WithDetails("dns_hostname", dse.DNSHostName, "domain_functional_level", dse.DomainFunctionality))
+	}
+	return list
+}
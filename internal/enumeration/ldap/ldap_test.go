@@ -0,0 +1,238 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+func TestBerRead(t *testing.T) {
+	big := bytes.Repeat([]byte{0x41}, maxBERMessageBytes)
+	for name, tc := range map[string]struct {
+		data    []byte
+		content []byte
+		err     bool
+	}{
+		"short form":       {[]byte{0x04, 0x03, 'a', 'b', 'c', 0xff}, []byte("abc"), false},
+		"empty":            {[]byte{0x04, 0x00}, []byte{}, false},
+		"long form 1":      {append([]byte{0x04, 0x81, 0x80}, bytes.Repeat([]byte{1}, 0x80)...), bytes.Repeat([]byte{1}, 0x80), false},
+		"long form 2":      {append([]byte{0x04, 0x82, 0x01, 0x00}, bytes.Repeat([]byte{2}, 0x100)...), bytes.Repeat([]byte{2}, 0x100), false},
+		"long form 4":      {[]byte{0x04, 0x84, 0x00, 0x00, 0x00, 0x01, 'x'}, []byte("x"), false},
+		"4 MiB":            {append([]byte{0x04, 0x84, 0x00, 0x40, 0x00, 0x00}, big...), big, false},
+		"over 4 MiB":       {[]byte{0x04, 0x84, 0x00, 0x40, 0x00, 0x01}, nil, true},
+		"huge length":      {[]byte{0x04, 0x84, 0xff, 0xff, 0xff, 0xff}, nil, true},
+		"indefinite":       {[]byte{0x30, 0x80, 0x00, 0x00}, nil, true},
+		"5 length bytes":   {[]byte{0x04, 0x85, 0, 0, 0, 0, 1, 'x'}, nil, true},
+		"truncated length": {[]byte{0x04, 0x82, 0x01}, nil, true},
+		"truncated value":  {[]byte{0x04, 0x05, 'a', 'b'}, nil, true},
+		"no length":        {[]byte{0x04}, nil, true},
+	} {
+		el, err := berRead(bufio.NewReader(bytes.NewReader(tc.data)))
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: accepted %d bytes", name, len(el.Content))
+			}
+			continue
+		}
+		if err != nil || el.Tag != tc.data[0] || !bytes.Equal(el.Content, tc.content) {
+			t.Errorf("%s: berRead = %v, %v", name, el, err)
+		}
+	}
+	if _, err := berRead(bufio.NewReader(bytes.NewReader([]byte{0x04, 0x05, 'a'}))); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated value error: %v", err)
+	}
+}
+
+func TestBerChildren(t *testing.T) {
+	long := bytes.Repeat([]byte{'v'}, 300)
+	content := berConcat(berInt(tagInteger, 7), berEncode(tagOctetString, long), berEncode(tagSequence, nil))
+	children, err := berChildren(content)
+	if err != nil || len(children) != 3 {
+		t.Fatalf("berChildren = %+v, %v", children, err)
+	}
+	if children[0].Tag != tagInteger || berToInt(children[0].Content) != 7 || !bytes.Equal(children[1].Content, long) || len(children[2].Content) != 0 {
+		t.Errorf("children: %+v", children)
+	}
+
+	for name, data := range map[string][]byte{
+		"truncated header":  {0x04},
+		"truncated value":   {0x04, 0x05, 'a'},
+		"truncated length":  {0x04, 0x82, 0x01},
+		"indefinite":        {0x30, 0x80},
+		"5 length bytes":    {0x04, 0x85, 0, 0, 0, 0, 1, 'x'},
+		"length past end":   {0x04, 0x84, 0x7f, 0xff, 0xff, 0xff, 'x'},
+		"trailing garbage":  append(berInt(tagInteger, 1), 0x02),
+		"second truncated":  append(berInt(tagInteger, 1), 0x04, 0x02, 'a'),
+		"long form too big": {0x04, 0x81, 0x02, 'a'},
+	} {
+		if children, err := berChildren(data); err == nil {
+			t.Errorf("%s: accepted %+v", name, children)
+		}
+	}
+}
+
+func TestBerInt(t *testing.T) {
+	for _, v := range []int{0, 1, 127, 128, 255, 256, 65535, 1 << 20} {
+		encoded := berInt(tagInteger, v)
+		el, err := berRead(bufio.NewReader(bytes.NewReader(encoded)))
+		if err != nil || berToInt(el.Content) != v || el.Content[0]&0x80 != 0 {
+			t.Errorf("berInt(%d) = %x", v, encoded)
+		}
+	}
+}
+
+// entry monta um SearchResultEntry com os atributos informados (nome seguido dos valores).
+func entry(messageID int, attrs ...[]string) []byte {
+	var list []byte
+	for _, attr := range attrs {
+		var values []byte
+		for _, v := range attr[1:] {
+			values = append(values, berEncode(tagOctetString, []byte(v))...)
+		}
+		list = append(list, berEncode(tagSequence, berConcat(berEncode(tagOctetString, []byte(attr[0])), berEncode(tagSet, values)))...)
+	}
+	op := berEncode(tagSearchEntry, berConcat(berEncode(tagOctetString, nil), berEncode(tagSequence, list)))
+	return berEncode(tagSequence, berConcat(berInt(tagInteger, messageID), op))
+}
+
+// done monta o SearchResultDone com o código de resultado informado.
+func done(messageID, code int) []byte {
+	op := berEncode(tagSearchDone, berConcat(berInt(tagEnumerated, code), berEncode(tagOctetString, nil), berEncode(tagOctetString, nil)))
+	return berEncode(tagSequence, berConcat(berInt(tagInteger, messageID), op))
+}
+
+// dcAttributes é o rootDSE de um controlador de domínio Windows Server 2025 em um domínio de nível 2016.
+var dcAttributes = [][]string{
+	{"defaultNamingContext", "DC=corp,DC=local"},
+	{"rootDomainNamingContext", "DC=corp,DC=local"},
+	{"dnsHostName", "dc01.corp.local"},
+	{"domainFunctionality", "7"},
+	{"forestFunctionality", "7"},
+	{"domainControllerFunctionality", "10"},
+	{"isGlobalCatalogReady", "TRUE"},
+	{"supportedSASLMechanisms", "GSSAPI", "GSS-SPNEGO", "EXTERNAL", "DIGEST-MD5"},
+	{"supportedCapabilities", oidActiveDirectory, "1.2.840.113556.1.4.1670", "1.2.840.113556.1.4.1791"},
+}
+
+// fakeLDAP é um servidor em 127.0.0.1 que responde à busca do rootDSE com response (mensagens
+// já codificadas), depois de conferir a requisição. Retorna a porta.
+func fakeLDAP(t *testing.T, response ...[]byte) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+				msg, err := berRead(bufio.NewReader(conn))
+				if err != nil {
+					t.Errorf("reading request: %v", err)
+					return
+				}
+				parts, err := berChildren(msg.Content)
+				if err != nil || len(parts) != 2 || parts[1].Tag != tagSearchRequest {
+					t.Errorf("unexpected request: %x", msg.Content)
+					return
+				}
+				if fields, err := berChildren(parts[1].Content); err != nil || len(fields) != 8 || len(fields[0].Content) != 0 {
+					t.Errorf("search request is not a rootDSE search: %+v", fields)
+				}
+				for _, r := range response {
+					_, _ = conn.Write(r)
+				}
+			}()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestQueryDomainController(t *testing.T) {
+	// Os atributos podem vir em mais de uma entrada.
+	port := fakeLDAP(t, entry(1, dcAttributes[:4]...), entry(1, dcAttributes[4:]...), done(1, 0))
+	dse, err := NewScanner().Query(context.Background(), "127.0.0.1", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dse.DomainController || !dse.GlobalCatalog || dse.DefaultNamingContext != "DC=corp,DC=local" || dse.DNSHostName != "dc01.corp.local" {
+		t.Errorf("rootDSE: %+v", dse)
+	}
+	if dse.DomainFunctionality != "Windows Server 2016 (7)" || dse.DCFunctionality != "Windows Server 2025 (10)" {
+		t.Errorf("functional levels: %q, %q", dse.DomainFunctionality, dse.DCFunctionality)
+	}
+	if !reflect.DeepEqual(dse.SASLMechanisms, []string{"GSSAPI", "GSS-SPNEGO", "EXTERNAL", "DIGEST-MD5"}) || len(dse.Attributes) != len(dcAttributes) {
+		t.Errorf("mechanisms %v, attributes %v", dse.SASLMechanisms, dse.Attributes)
+	}
+}
+
+func TestQueryNotDomainController(t *testing.T) {
+	for name, attrs := range map[string][][]string{
+		"ad lds":            {{"defaultNamingContext", "CN=app"}, {"supportedCapabilities", oidActiveDirectory, oidActiveDirectoryLDS}},
+		"no naming context": {{"supportedCapabilities", oidActiveDirectory}},
+		"openldap":          {{"namingContexts", "dc=example,dc=org"}, {"supportedLDAPVersion", "3"}},
+	} {
+		port := fakeLDAP(t, entry(1, attrs...), done(1, 0))
+		dse, err := NewScanner().Query(context.Background(), "127.0.0.1", port)
+		if err != nil || dse.DomainController {
+			t.Errorf("%s: %+v, %v", name, dse, err)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	sc := NewScanner()
+	sc.Timeout = time.Second
+	for name, response := range map[string][][]byte{
+		"result code": {done(1, 49)},
+		"empty":       {done(1, 0)},
+		"no reply":    nil,
+		"malformed":   {berEncode(tagSequence, []byte{0x02, 0x05, 0x01})},
+		"bad entry":   {berEncode(tagSequence, berConcat(berInt(tagInteger, 1), berEncode(tagSearchEntry, []byte{0x04}))), done(1, 0)},
+		"too large":   {{0x30, 0x84, 0x10, 0x00, 0x00, 0x00}},
+		"truncated":   {entry(1, dcAttributes...)[:40]},
+	} {
+		port := fakeLDAP(t, response...)
+		if dse, err := sc.Query(context.Background(), "127.0.0.1", port); err == nil {
+			t.Errorf("%s: accepted %+v", name, dse)
+		}
+	}
+}
+
+func TestRunTagsDomainController(t *testing.T) {
+	dc := fakeLDAP(t, entry(1, dcAttributes...), done(1, 0))
+	saved := Ports
+	Ports = []int{dc}
+	t.Cleanup(func() { Ports = saved })
+
+	hosts := []results.Host{
+		{Address: "127.0.0.1", Services: []results.Service{{Protocol: "tcp", Port: dc, Name: "ldap"}}},
+		{Address: "127.0.0.2", Services: []results.Service{{Protocol: "tcp", Port: 22, Name: "ssh"}}},
+	}
+	found := NewScanner().Run(context.Background(), hosts)
+	if len(found) != 1 || len(hosts[0].Tags) != 1 || hosts[0].Tags[0] != TagDomainController || len(hosts[1].Tags) != 0 {
+		t.Fatalf("found %+v, tags %v / %v", found, hosts[0].Tags, hosts[1].Tags)
+	}
+	var dse RootDSE
+	if !hosts[0].Enrichment(ModuleName, &dse) || dse.DNSHostName != "dc01.corp.local" {
+		t.Errorf("enrichment: %+v", dse)
+	}
+	list := Findings(found)
+	if len(list) != 1 || list[0].ID != "ldap-domain-controller" || list[0].Evidence != "DC=corp,DC=local" || list[0].Port != dc {
+		t.Errorf("findings: %+v", list)
+	}
+}
@@ -0,0 +1,46 @@
+package ldap
+
+import (
+	"context"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+)
+
+// Module expõe o Scanner como enumeration.Enumerator.
+type Module struct {
+	Scanner *Scanner
+}
+
+// NewModule é a factory que cria o módulo a partir de um Scanner.
+func NewModule(sc *Scanner) *Module {
+	return &Module{Scanner: sc}
+}
+
+// Name retorna o nome do módulo.
+func (m *Module) Name() string { return ModuleName }
+
+// Selector seleciona as portas LDAP.
+func (m *Module) Selector() enumeration.Selector {
+	return enumeration.Selector{Ports: Ports}
+}
+
+// Enumerate consulta o rootDSE na primeira porta LDAP do host que responder.
+func (m *Module) Enumerate(ctx context.Context, target enumeration.Target) (*enumeration.Result, error) {
+	var lastErr error
+	for _, port := range Ports {
+		if !target.Host.HasPort("tcp", port) {
+			continue
+		}
+		dse, err := m.Scanner.Query(ctx, target.Host.Address, port)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		result := &enumeration.Result{Enrichment: dse, Findings: Findings([]RootDSE{*dse})}
+		if dse.DomainController {
+			result.Tags = append(result.Tags, TagDomainController)
+		}
+		return result, nil
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,55 @@
+package modules
+
+import (
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/banner"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/database"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/dns"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/ftp"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/ldap"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/smb"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/snmp"
+	"github.com/Arthx-x/arthxrecon/internal/enumeration/ssh"
+)
+
+// Builtin retorna os módulos de enumeração nativos com valores padrão, usando timeout
+// como timeout de conexão. O paralelismo entre hosts fica a cargo do Registry.
+func Builtin(timeout time.Duration) []enumeration.Enumerator {
+	grabber := banner.NewGrabber()
+	grabber.Timeout = timeout
+	smbScanner := smb.NewScanner()
+	smbScanner.Timeout = timeout
+	ldapScanner := ldap.NewScanner()
+	ldapScanner.Timeout = timeout
+	dnsScanner := dns.NewScanner()
+	dnsScanner.Timeout = timeout
+	snmpScanner := snmp.NewScanner()
+	snmpScanner.Timeout = timeout
+	auditor := ssh.NewAuditor()
+	auditor.Timeout = timeout
+	ftpScanner := ftp.NewScanner()
+	ftpScanner.Timeout = timeout
+	dbScanner := database.NewScanner()
+	dbScanner.Timeout = timeout
+
+	return []enumeration.Enumerator{
+		banner.NewModule(grabber),
+		smb.NewModule(smbScanner),
+		ldap.NewModule(ldapScanner),
+		dns.NewModule(dnsScanner),
+		snmp.NewModule(snmpScanner),
+		ssh.NewModule(auditor),
+		ftp.NewModule(ftpScanner),
+		database.NewModule(dbScanner),
+	}
+}
+
+// NewRegistry cria um Registry com todos os módulos nativos registrados.
+func NewRegistry(timeout time.Duration) *enumeration.Registry {
+	registry := enumeration.NewRegistry()
+	// Os nomes dos módulos nativos são únicos; Register só falharia com nomes duplicados.
+	_ = registry.Register(Builtin(timeout)...)
+	return registry
+}
@@ -0,0 +1,276 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/BurntSushi/toml"
+)
+
+// ManifestName é o nome do manifesto de um plugin instalado em um subdiretório.
+const ManifestName = "plugin.toml"
+
+// maxOutput limita a saída lida de um plugin.
+const maxOutput = 16 << 20
+
+// Match descreve os serviços aos quais o plugin se aplica, com os mesmos critérios de enumeration.Selector.
+type Match struct {
+	Ports        []int    `toml:"ports"`
+	UDPPorts     []int    `toml:"udp_ports"`
+	Services     []string `toml:"services"`
+	Categories   []string `toml:"categories"`
+	Protocols    []string `toml:"protocols"`
+	Unidentified bool     `toml:"unidentified"`
+	Host         bool     `toml:"host"`
+}
+
+// Manifest é a declaração de um plugin, lida de <dir>/<nome>.toml ou de <dir>/<nome>/plugin.toml.
+type Manifest struct {
+	Name        string   `toml:"name"`
+	Description string   `toml:"description"`
+	Command     string   `toml:"command"` // Executável; caminhos relativos partem do diretório do manifesto
+	Args        []string `toml:"args"`
+	Timeout     string   `toml:"timeout"` // Duração no formato do Go (ex.: "30s"); vazio usa o timeout padrão
+	Match       Match    `toml:"match"`
+}
+
+// Plugin é um executável externo exposto como enumeration.Enumerator.
+//
+// Para cada host selecionado o plugin recebe no stdin um JSON com o host e os serviços
+// selecionados ({"plugin", "host", "services"}) e deve escrever no stdout um JSON no formato de
+// enumeration.Result ({"enrichment", "services", "hostnames", "tags", "findings"}). O "id" de um
+// achado é o seu ID canônico (ex.: ftp-anonymous); sem ele, o título normalizado é usado. A origem dos
+// achados é sempre o nome do plugin. Saída vazia significa "nada encontrado"; código de saída diferente
+// de zero e stdout acima de maxOutput são tratados como falha.
+type Plugin struct {
+	Manifest Manifest
+	Path     string        // Caminho do manifesto
+	Dir      string        // Diretório do manifesto, usado como diretório de trabalho
+	timeout  time.Duration // Timeout declarado no manifesto (0 = padrão do Registry)
+}
+
+// request é o JSON enviado ao plugin.
+type request struct {
+	Plugin   string            `json:"plugin"`
+	Host     results.Host      `json:"host"`
+	Services []results.Service `json:"services,omitempty"`
+}
+
+// Load lê os manifestos do diretório de plugins. Um diretório inexistente não é erro.
+func Load(dir string) ([]*Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			if _, err := os.Stat(filepath.Join(path, ManifestName)); err == nil {
+				paths = append(paths, filepath.Join(path, ManifestName))
+			}
+			continue
+		}
+		if strings.EqualFold(filepath.Ext(entry.Name()), ".toml") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var loaded []*Plugin
+	seen := make(map[string]string)
+	for _, path := range paths {
+		p, err := LoadManifest(path)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := seen[strings.ToLower(p.Manifest.Name)]; ok {
+			return nil, fmt.Errorf("plugin %q declared twice: %s and %s", p.Manifest.Name, other, path)
+		}
+		seen[strings.ToLower(p.Manifest.Name)] = path
+		loaded = append(loaded, p)
+	}
+	return loaded, nil
+}
+
+// LoadManifest lê e valida o manifesto de um plugin.
+func LoadManifest(path string) (*Plugin, error) {
+	p := &Plugin{Path: path, Dir: filepath.Dir(path)}
+	meta, err := toml.DecodeFile(path, &p.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plugin manifest %s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("invalid plugin manifest %s: unknown key %q", path, undecoded[0].String())
+	}
+	if p.Manifest.Name == "" {
+		// Sem nome, usa o do arquivo (<nome>.toml) ou o do diretório (<nome>/plugin.toml).
+		p.Manifest.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if filepath.Base(path) == ManifestName {
+			p.Manifest.Name = filepath.Base(p.Dir)
+		}
+	}
+	if p.Manifest.Command == "" {
+		return nil, fmt.Errorf("invalid plugin manifest %s: command is required", path)
+	}
+	if p.Manifest.Timeout != "" {
+		if p.timeout, err = time.ParseDuration(p.Manifest.Timeout); err != nil || p.timeout <= 0 {
+			return nil, fmt.Errorf("invalid plugin manifest %s: invalid timeout %q", path, p.Manifest.Timeout)
+		}
+	}
+	m := p.Manifest.Match
+	if len(m.Ports) == 0 && len(m.UDPPorts) == 0 && len(m.Services) == 0 && len(m.Categories) == 0 && len(m.Protocols) == 0 && !m.Host {
+		return nil, fmt.Errorf("invalid plugin manifest %s: [match] selects no services", path)
+	}
+	return p, nil
+}
+
+// Name retorna o nome do plugin.
+func (p *Plugin) Name() string { return p.Manifest.Name }
+
+// Timeout retorna o timeout declarado no manifesto, ou 0 se não houver.
+func (p *Plugin) Timeout() time.Duration { return p.timeout }
+
+// Selector converte a seção [match] do manifesto.
+func (p *Plugin) Selector() enumeration.Selector {
+	return enumeration.Selector{
+		Ports:        p.Manifest.Match.Ports,
+		UDPPorts:     p.Manifest.Match.UDPPorts,
+		Services:     p.Manifest.Match.Services,
+		Categories:   p.Manifest.Match.Categories,
+		Protocols:    p.Manifest.Match.Protocols,
+		Unidentified: p.Manifest.Match.Unidentified,
+		Host:         p.Manifest.Match.Host,
+	}
+}
+
+// command resolve o executável: caminhos com separador são relativos ao diretório do manifesto;
+// nomes simples são procurados no PATH.
+func (p *Plugin) command() string {
+	if filepath.IsAbs(p.Manifest.Command) || !strings.ContainsRune(p.Manifest.Command, filepath.Separator) {
+		return p.Manifest.Command
+	}
+	path := filepath.Join(p.Dir, p.Manifest.Command)
+	// Com cmd.Dir definido, um caminho relativo seria resolvido a partir do próprio Dir.
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// Enumerate executa o plugin para o host e decodifica o resultado. Se ctx terminar antes do início
+// (timeout do Registry, janela de scan fechada), o processo não é iniciado.
+func (p *Plugin) Enumerate(ctx context.Context, target enumeration.Target) (*enumeration.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	input, err := json.Marshal(request{Plugin: p.Name(), Host: target.Host, Services: target.Services})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin input: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	output := &limitedWriter{w: &stdout, n: maxOutput}
+	cmd := exec.CommandContext(ctx, p.command(), p.Manifest.Args...)
+	cmd.Dir = p.Dir
+	cmd.Env = append(os.Environ(), "ARTHXRECON_PLUGIN="+p.Name())
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = output
+	cmd.Stderr = &limitedWriter{w: &stderr, n: maxOutput}
+
+	logger := util.ModuleLogger(p.Name()).With().Str("host", target.Host.Address).Logger()
+	err = util.RunCommand(ctx, &logger, cmd)
+	for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+		if line != "" {
+			logger.Debug().Msg(line)
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s failed: %w", p.Name(), err)
+	}
+	if output.truncated {
+		return nil, fmt.Errorf("plugin %s output exceeds %d bytes and was truncated", p.Name(), maxOutput)
+	}
+	return p.decode(stdout.Bytes(), target.Host.Address)
+}
+
+// decode interpreta o stdout do plugin e completa os achados com a origem (sempre o plugin) e o host.
+func (p *Plugin) decode(output []byte, address string) (*enumeration.Result, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+	var result enumeration.Result
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("plugin %s returned invalid JSON: %w", p.Name(), err)
+	}
+	for i := range result.Services {
+		if result.Services[i].Protocol == "" {
+			result.Services[i].Protocol = "tcp"
+		}
+	}
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		// Um plugin não pode se passar por outro módulo (ex.: "nse") na origem do achado.
+		f.Source = p.Name()
+		if f.Host == "" {
+			f.Host = address
+		}
+		if !findings.ValidSeverity(f.Severity) {
+			f.Severity = findings.SeverityInfo
+		}
+		if f.Title == "" {
+			return nil, fmt.Errorf("plugin %s returned a finding without title", p.Name())
+		}
+		if f.ID == "" {
+			f.ID = findings.Slug(f.Title)
+		} else {
+			f.ID = findings.Slug(f.ID)
+		}
+	}
+	return &result, nil
+}
+
+// limitedWriter descarta o que passar de n bytes, para que um plugin não esgote a memória.
+type limitedWriter struct {
+	w         io.Writer
+	n         int
+	truncated bool // Parte da saída foi descartada
+}
+
+func (l *limitedWriter) Write(b []byte) (int, error) {
+	size := len(b)
+	if l.n <= 0 {
+		l.truncated = l.truncated || size > 0
+		return size, nil
+	}
+	if len(b) > l.n {
+		b = b[:l.n]
+		l.truncated = true
+	}
+	l.n -= len(b)
+	if _, err := l.w.Write(b); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
@@ -0,0 +1,228 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// fixtures são os plugins de testdata/plugins: echo (echo.toml) e slow (slow/plugin.toml).
+func fixtures(t *testing.T) map[string]*Plugin {
+	t.Helper()
+	loaded, err := Load(filepath.Join("testdata", "plugins"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*Plugin)
+	for _, p := range loaded {
+		byName[p.Name()] = p
+	}
+	return byName
+}
+
+func TestLoad(t *testing.T) {
+	loaded := fixtures(t)
+	if len(loaded) != 2 || loaded["echo"] == nil || loaded["slow"] == nil {
+		t.Fatalf("loaded plugins: %v", loaded)
+	}
+	if p := loaded["slow"]; p.Timeout() != 200*time.Millisecond || filepath.Base(p.Dir) != "slow" {
+		t.Errorf("slow: timeout %s, dir %s", p.Timeout(), p.Dir)
+	}
+	if p := loaded["echo"]; p.Timeout() != 0 || !filepath.IsAbs(p.command()) || filepath.Base(p.command()) != "echo.sh" {
+		t.Errorf("echo: timeout %s, command %s", p.Timeout(), p.command())
+	}
+
+	if loaded, err := Load(filepath.Join(t.TempDir(), "missing")); err != nil || loaded != nil {
+		t.Errorf("missing directory: %v, %v", loaded, err)
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.toml", "command = \"a\"\n[match]\nhost = true\n")
+	write("b/plugin.toml", "name = \"A\"\ncommand = \"b\"\n[match]\nhost = true\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "declared twice") {
+		t.Errorf("duplicate name: %v", err)
+	}
+}
+
+func TestLoadManifestInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"no command":   "[match]\nhost = true\n",
+		"no match":     "command = \"x\"\n",
+		"bad timeout":  "command = \"x\"\ntimeout = \"soon\"\n[match]\nhost = true\n",
+		"zero timeout": "command = \"x\"\ntimeout = \"0s\"\n[match]\nhost = true\n",
+		"unknown key":  "command = \"x\"\n[match]\nhost = true\nport = 80\n",
+		"syntax":       "command = \n",
+	} {
+		path := filepath.Join(t.TempDir(), "plugin.toml")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadManifest(path); err == nil {
+			t.Errorf("%s: manifest accepted", name)
+		}
+	}
+}
+
+func TestSelector(t *testing.T) {
+	host := results.Host{
+		Address: "10.0.0.1",
+		Services: []results.Service{
+			{Protocol: "tcp", Port: 22, Name: "ssh"},
+			{Protocol: "tcp", Port: 80, Name: "http"},
+			{Protocol: "tcp", Port: 8080, Name: "unknown"},
+		},
+		UDPServices: []results.Service{{Protocol: "udp", Port: 8080, Name: "unknown"}},
+	}
+	matched := fixtures(t)["echo"].Selector().Matches(host)
+	if len(matched) != 2 || matched[0].Port != 80 || matched[1].Port != 8080 || matched[1].Protocol != "tcp" {
+		t.Errorf("echo matched %+v", matched)
+	}
+	if s := fixtures(t)["slow"].Selector(); !s.Host || len(s.Matches(host)) != 0 {
+		t.Errorf("slow selector %+v", s)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	p := fixtures(t)["echo"]
+	target := enumeration.Target{
+		Host:     results.Host{Address: "10.0.0.1"},
+		Services: []results.Service{{Protocol: "tcp", Port: 80, Name: "http"}},
+	}
+	result, err := p.Enumerate(context.Background(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// O enrichment é a entrada recebida no stdin.
+	input, _ := result.Enrichment.(map[string]interface{})
+	services, _ := input["services"].([]interface{})
+	if input["plugin"] != "echo" || input["host"].(map[string]interface{})["address"] != "10.0.0.1" || len(services) != 1 {
+		t.Errorf("plugin input: %v", result.Enrichment)
+	}
+	if len(result.Tags) != 1 || result.Tags[0] != "echo" {
+		t.Errorf("tags (ARTHXRECON_PLUGIN): %v", result.Tags)
+	}
+	if len(result.Services) != 1 || result.Services[0].Protocol != "tcp" || result.Services[0].Banner != "TestServer" {
+		t.Errorf("services: %+v", result.Services)
+	}
+	if len(result.Findings) != 2 {
+		t.Fatalf("findings: %+v", result.Findings)
+	}
+	f := result.Findings[0]
+	if f.ID != "test-finding" || f.Source != "echo" || f.Host != "10.0.0.1" || f.Severity != findings.SeverityHigh {
+		t.Errorf("finding without id: %+v", f)
+	}
+	if f := result.Findings[1]; f.ID != "custom-id" || f.Severity != findings.SeverityInfo {
+		t.Errorf("finding with id and unknown severity: %+v", f)
+	}
+}
+
+func TestEnumerateFailures(t *testing.T) {
+	base := fixtures(t)["echo"]
+	target := enumeration.Target{Host: results.Host{Address: "10.0.0.1"}}
+	with := func(args ...string) *Plugin {
+		p := *base
+		p.Manifest.Args = args
+		return &p
+	}
+
+	if result, err := with("empty").Enumerate(context.Background(), target); err != nil || result != nil {
+		t.Errorf("empty output: %+v, %v", result, err)
+	}
+	if _, err := with("fail").Enumerate(context.Background(), target); err == nil || !strings.Contains(err.Error(), "plugin echo failed") {
+		t.Errorf("exit code 3: %v", err)
+	}
+
+	// Saída cortada em maxOutput é reportada como tal, e não como JSON inválido.
+	if _, err := with("flood").Enumerate(context.Background(), target); err == nil || err.Error() != "plugin echo output exceeds 16777216 bytes and was truncated" {
+		t.Errorf("output over the limit: %v", err)
+	}
+
+	slow := fixtures(t)["slow"]
+	ctx, cancel := context.WithTimeout(context.Background(), slow.Timeout())
+	defer cancel()
+	start := time.Now()
+	if _, err := slow.Enumerate(ctx, target); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the plugin was not killed at the timeout: %s", elapsed)
+	}
+
+	// Com ctx já encerrado, o processo nem é iniciado.
+	cancel()
+	if _, err := with("fail").Enumerate(ctx, target); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("done context: %v", err)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	p := &Plugin{Manifest: Manifest{Name: "test"}}
+	if _, err := p.decode([]byte("not json"), "10.0.0.1"); err == nil {
+		t.Error("invalid JSON accepted")
+	}
+	if _, err := p.decode([]byte(`{"findings":[{"severity":"high"}]}`), "10.0.0.1"); err == nil {
+		t.Error("finding without title accepted")
+	}
+	if result, err := p.decode([]byte(" \n"), "10.0.0.1"); result != nil || err != nil {
+		t.Errorf("blank output: %+v, %v", result, err)
+	}
+}
+
+func TestDecodeFindings(t *testing.T) {
+	p := &Plugin{Manifest: Manifest{Name: "test"}}
+	result, err := p.decode([]byte(`{"findings":[
+		{"title":"SMBv1 enabled","severity":"high","source":"nse"},
+		{"id":"Weak Cipher","title":"Weak cipher","host":"10.0.0.9","port":443,"protocol":"tcp","source":"test"}
+	]}`), "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct {
+		f        findings.Finding
+		id, host string
+	}{
+		"origem de outro módulo": {result.Findings[0], "smbv1-enabled", "10.0.0.1"},
+		"host informado":         {result.Findings[1], "weak-cipher", "10.0.0.9"},
+	} {
+		if tc.f.Source != "test" || tc.f.ID != tc.id || tc.f.Host != tc.host {
+			t.Errorf("%s: %+v", name, tc.f)
+		}
+	}
+}
+
+func TestLimitedWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &limitedWriter{w: &buf, n: 10}
+	for _, chunk := range []string{"0123", "456789ab", "cdef"} {
+		if n, err := w.Write([]byte(chunk)); err != nil || n != len(chunk) {
+			t.Errorf("Write(%q) = %d, %v", chunk, n, err)
+		}
+	}
+	if buf.String() != "0123456789" || !w.truncated {
+		t.Errorf("kept %q (truncated %v), want the first 10 bytes", buf.String(), w.truncated)
+	}
+	exact := &limitedWriter{w: &buf, n: 10}
+	exact.Write([]byte("0123456789"))
+	if exact.truncated {
+		t.Error("output at the limit reported as truncated")
+	}
+}
@@ -0,0 +1,13 @@
+#!/bin/sh
+# Plugin de teste: devolve a entrada recebida no enrichment e um achado. O primeiro argumento
+# muda o comportamento: empty (sem saída), fail (código 3), sleep (não termina a tempo),
+# flood (stdout acima do limite).
+case "$1" in
+empty) cat >/dev/null; exit 0 ;;
+fail) echo "plugin broke" >&2; exit 3 ;;
+sleep) exec sleep 10 ;;
+flood) cat >/dev/null; printf '{"tags":["'; head -c 17000000 /dev/zero | tr '\0' a; printf '"]}\n'; exit 0 ;;
+esac
+input=$(cat)
+echo "processing" >&2
+printf '{"enrichment":%s,"tags":["%s"],"services":[{"port":80,"banner":"TestServer"}],"findings":[{"title":"Test Finding","severity":"high"},{"id":"Custom ID","title":"Other","severity":"bogus"}]}\n' "$input" "$ARTHXRECON_PLUGIN"
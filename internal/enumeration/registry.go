@@ -0,0 +1,304 @@
+package enumeration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ModuleReport resume a execução de um módulo.
+type ModuleReport struct {
+	Name     string        `json:"name"`
+	Targets  int           `json:"targets"`  // Execuções agendadas (um host e seus serviços selecionados)
+	Results  int           `json:"results"`  // Execuções que retornaram resultado
+	Errors   int           `json:"errors"`   // Execuções que falharam
+	Timeouts int           `json:"timeouts"` // Execuções interrompidas pelo timeout do módulo
+	Elapsed  time.Duration `json:"elapsed"`  // Soma do tempo das execuções
+}
+
+// Report consolida a execução de todos os módulos habilitados.
+type Report struct {
+	Modules  []ModuleReport     `json:"modules"`
+	Findings []findings.Finding `json:"findings,omitempty"`
+}
+
+// Registry guarda os módulos de enumeração e executa os que se aplicam a cada host.
+type Registry struct {
+	Timeout time.Duration // Tempo máximo de cada execução de módulo contra um host
+	Threads int           // Quantidade de execuções simultâneas
+
+	modules  []Enumerator
+	disabled map[string]bool
+	timeouts map[string]time.Duration
+}
+
+// NewRegistry é a factory que cria um Registry vazio com valores padrão.
+func NewRegistry() *Registry {
+	return &Registry{
+		Timeout:  2 * time.Minute,
+		Threads:  10,
+		disabled: make(map[string]bool),
+		timeouts: make(map[string]time.Duration),
+	}
+}
+
+// Register adiciona módulos ao registro. Os nomes devem ser únicos.
+func (r *Registry) Register(modules ...Enumerator) error {
+	for _, m := range modules {
+		name := m.Name()
+		if name == "" {
+			return errors.New("enumeration module without name")
+		}
+		if _, ok := r.Lookup(name); ok {
+			return fmt.Errorf("enumeration module %q already registered", name)
+		}
+		r.modules = append(r.modules, m)
+	}
+	return nil
+}
+
+// Modules retorna os módulos registrados, na ordem de registro.
+func (r *Registry) Modules() []Enumerator {
+	return append([]Enumerator(nil), r.modules...)
+}
+
+// Lookup retorna o módulo com o nome informado.
+func (r *Registry) Lookup(name string) (Enumerator, bool) {
+	for _, m := range r.modules {
+		if strings.EqualFold(m.Name(), name) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// Enabled indica se o módulo está habilitado.
+func (r *Registry) Enabled(name string) bool {
+	return !r.disabled[strings.ToLower(name)]
+}
+
+// Disable desabilita os módulos informados.
+func (r *Registry) Disable(names ...string) error {
+	for _, name := range names {
+		m, ok := r.Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown enumeration module: %s", name)
+		}
+		r.disabled[strings.ToLower(m.Name())] = true
+	}
+	return nil
+}
+
+// Only habilita apenas os módulos informados e desabilita os demais.
+func (r *Registry) Only(names ...string) error {
+	keep := make(map[string]bool)
+	for _, name := range names {
+		m, ok := r.Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown enumeration module: %s", name)
+		}
+		keep[strings.ToLower(m.Name())] = true
+	}
+	for _, m := range r.modules {
+		r.disabled[strings.ToLower(m.Name())] = !keep[strings.ToLower(m.Name())]
+	}
+	return nil
+}
+
+// SetTimeout define o timeout de execução de um módulo, substituindo o padrão do Registry.
+func (r *Registry) SetTimeout(name string, timeout time.Duration) error {
+	m, ok := r.Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown enumeration module: %s", name)
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("invalid timeout for module %s: %s", name, timeout)
+	}
+	r.timeouts[strings.ToLower(m.Name())] = timeout
+	return nil
+}
+
+// TimeoutFor retorna o timeout de execução do módulo.
+func (r *Registry) TimeoutFor(name string) time.Duration {
+	if t, ok := r.timeouts[strings.ToLower(name)]; ok {
+		return t
+	}
+	return r.Timeout
+}
+
+// job é a execução de um módulo contra um host.
+type job struct {
+	module Enumerator
+	host   int
+	target Target
+	result *Result
+	err    error
+	took   time.Duration
+}
+
+// Run executa os módulos habilitados contra os hosts, estágio por estágio, aplica os resultados
+// em hosts e, por fim, executa as etapas globais dos módulos que implementam Finalizer.
+func (r *Registry) Run(ctx context.Context, hosts []results.Host) *Report {
+	reports := make(map[string]*ModuleReport)
+	report := &Report{}
+	var enabled []Enumerator
+	for _, m := range r.modules {
+		if !r.Enabled(m.Name()) {
+			continue
+		}
+		enabled = append(enabled, m)
+		reports[m.Name()] = &ModuleReport{Name: m.Name()}
+	}
+
+	for _, stage := range []int{StageIdentify, StageEnumerate} {
+		var jobs []*job
+		for _, m := range enabled {
+			if stageOf(m) != stage {
+				continue
+			}
+			selector := m.Selector()
+			for i := range hosts {
+				services := selector.Matches(hosts[i])
+				if len(services) == 0 && !selector.Host {
+					continue
+				}
+				jobs = append(jobs, &job{module: m, host: i, target: Target{Host: hosts[i], Services: services}})
+			}
+		}
+		batches := r.probeBatches(ctx, enabled, jobs)
+		for _, b := range batches {
+			b.Start()
+		}
+		r.runJobs(ctx, jobs)
+		for _, b := range batches {
+			b.Finish()
+		}
+
+		// Os resultados são aplicados em ordem, depois do estágio, para não alterar os hosts durante as execuções.
+		for _, j := range jobs {
+			mr := reports[j.module.Name()]
+			mr.Targets++
+			mr.Elapsed += j.took
+			switch {
+			case errors.Is(j.err, context.DeadlineExceeded):
+				mr.Timeouts++
+				util.ModuleLogger(j.module.Name()).Debug().Msgf("Module timed out on %s", hosts[j.host].Address)
+			case j.err != nil:
+				mr.Errors++
+				util.ModuleLogger(j.module.Name()).Debug().Err(j.err).Msgf("Module failed on %s", hosts[j.host].Address)
+			case j.result != nil:
+				mr.Results++
+				j.result.apply(j.module.Name(), &hosts[j.host])
+				report.Findings = append(report.Findings, j.result.Findings...)
+			}
+		}
+	}
+
+	for _, m := range enabled {
+		f, ok := m.(Finalizer)
+		if !ok {
+			continue
+		}
+		fctx, cancel := context.WithTimeout(ctx, r.TimeoutFor(m.Name()))
+		list, err := f.Finalize(fctx, hosts)
+		cancel()
+		if err != nil {
+			reports[m.Name()].Errors++
+			util.ModuleLogger(m.Name()).Debug().Err(err).Msg("Module finalization failed")
+		}
+		report.Findings = append(report.Findings, list...)
+	}
+
+	for _, m := range enabled {
+		report.Modules = append(report.Modules, *reports[m.Name()])
+	}
+	findings.Sort(report.Findings)
+	return report
+}
+
+// probeBatches agrupa as execuções por módulo, na ordem dos módulos, para o log de auditoria:
+// cada lote traz os hosts e as portas que o módulo vai sondar.
+func (r *Registry) probeBatches(ctx context.Context, modules []Enumerator, jobs []*job) []*util.ProbeBatch {
+	byModule := make(map[string]*util.ProbeBatch)
+	for _, j := range jobs {
+		b, ok := byModule[j.module.Name()]
+		if !ok {
+			b = util.NewProbeBatch(ctx, j.module.Name(), r.Threads)
+			byModule[j.module.Name()] = b
+		}
+		b.Add(j.target.Host.Address, "", 0)
+		for _, svc := range j.target.Services {
+			b.Add(j.target.Host.Address, svc.Protocol, svc.Port)
+		}
+	}
+	var batches []*util.ProbeBatch
+	for _, m := range modules {
+		if b, ok := byModule[m.Name()]; ok {
+			batches = append(batches, b)
+		}
+	}
+	return batches
+}
+
+// runJobs executa as execuções concorrentemente, limitadas por Threads e pelo timeout de cada módulo.
+// Um módulo que não respeita o contexto é abandonado ao fim do timeout e o resultado dele é descartado,
+// mas continua ocupando a sua vaga até retornar, para que nunca haja mais de Threads execuções.
+// Fora da janela de scan, as execuções seguintes aguardam a abertura dela.
+func (r *Registry) runJobs(ctx context.Context, jobs []*job) {
+	threads := r.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, threads)
+	)
+	for _, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		util.WaitWindow(ctx)
+		go func(j *job) {
+			defer wg.Done()
+
+			jctx, cancel := context.WithTimeout(ctx, r.TimeoutFor(j.module.Name()))
+			defer cancel()
+			type outcome struct {
+				result *Result
+				err    error
+			}
+			done := make(chan outcome, 1)
+			start := time.Now()
+			go func() {
+				defer func() { <-sem }()
+				result, err := j.module.Enumerate(jctx, j.target)
+				done <- outcome{result, err}
+			}()
+			select {
+			case o := <-done:
+				j.result, j.err = o.result, o.err
+			case <-jctx.Done():
+				j.err = jctx.Err()
+			}
+			j.took = time.Since(start)
+		}(j)
+	}
+	wg.Wait()
+}
+
+// Names retorna os nomes dos módulos registrados, em ordem alfabética.
+func (r *Registry) Names() []string {
+	var names []string
+	for _, m := range r.modules {
+		names = append(names, m.Name())
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,234 @@
+package enumeration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// fakeModule é um módulo de teste: run produz o resultado de cada host (nil = sem resultado).
+type fakeModule struct {
+	name     string
+	selector Selector
+	stage    int
+	run      func(ctx context.Context, target Target) (*Result, error)
+
+	mu    sync.Mutex
+	calls []Target
+}
+
+func (m *fakeModule) Name() string       { return m.name }
+func (m *fakeModule) Selector() Selector { return m.selector }
+func (m *fakeModule) Stage() int         { return m.stage }
+
+func (m *fakeModule) Enumerate(ctx context.Context, target Target) (*Result, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, target)
+	m.mu.Unlock()
+	if m.run == nil {
+		return nil, nil
+	}
+	return m.run(ctx, target)
+}
+
+// finalizerModule é um fakeModule que também implementa Finalizer.
+type finalizerModule struct {
+	*fakeModule
+	finalize func(ctx context.Context, hosts []results.Host) ([]findings.Finding, error)
+}
+
+func (m finalizerModule) Finalize(ctx context.Context, hosts []results.Host) ([]findings.Finding, error) {
+	return m.finalize(ctx, hosts)
+}
+
+func testHost() results.Host {
+	return results.Host{
+		Address: "10.0.0.1",
+		Services: []results.Service{
+			{Protocol: "tcp", Port: 21, Name: "ftp", Product: "vsftpd"},
+			{Protocol: "tcp", Port: 3306, Name: "mysql"},
+			{Protocol: "tcp", Port: 4444, Name: "unknown"},
+		},
+		UDPServices: []results.Service{{Protocol: "udp", Port: 161, Name: "snmp"}},
+	}
+}
+
+func ports(services []results.Service) []int {
+	var list []int
+	for _, svc := range services {
+		list = append(list, svc.Port)
+	}
+	return list
+}
+
+func TestSelectorMatches(t *testing.T) {
+	host := testHost()
+	for name, tc := range map[string]struct {
+		selector Selector
+		want     []int
+	}{
+		"tcp port":          {Selector{Ports: []int{4444, 161}}, []int{4444}},
+		"udp port":          {Selector{UDPPorts: []int{161, 4444}}, []int{161}},
+		"service name":      {Selector{Services: []string{"FTP", "snmp"}}, []int{21, 161}},
+		"category":          {Selector{Categories: []string{"database"}}, []int{3306}},
+		"unknown category":  {Selector{Categories: []string{"nope"}}, nil},
+		"protocol":          {Selector{Protocols: []string{"udp"}}, []int{161}},
+		"unidentified only": {Selector{Protocols: []string{"tcp"}, Unidentified: true}, []int{3306, 4444}},
+		"any criterion":     {Selector{Ports: []int{21}, Services: []string{"mysql"}}, []int{21, 3306}},
+		"host only":         {Selector{Host: true}, nil},
+	} {
+		got := ports(tc.selector.Matches(host))
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: matched %v, want %v", name, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: matched %v, want %v", name, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRegistryStages(t *testing.T) {
+	// O módulo de identificação nomeia o serviço 4444; o de enumeração o seleciona pelo nome.
+	identify := &fakeModule{name: "identify", stage: StageIdentify, selector: Selector{Unidentified: true, Protocols: []string{"tcp"}},
+		run: func(ctx context.Context, target Target) (*Result, error) {
+			return &Result{Services: []ServiceResult{{Protocol: "tcp", Port: 4444, Name: "irc", Product: "UnrealIRCd"}}}, nil
+		}}
+	enumerate := &fakeModule{name: "irc", stage: StageEnumerate, selector: Selector{Services: []string{"irc"}},
+		run: func(ctx context.Context, target Target) (*Result, error) {
+			return &Result{Tags: []string{"irc"}, Findings: []findings.Finding{{ID: "irc-open", Title: "IRC", Severity: findings.SeverityLow}}}, nil
+		}}
+	r := NewRegistry()
+	if err := r.Register(enumerate, identify); err != nil {
+		t.Fatal(err)
+	}
+	hosts := []results.Host{testHost()}
+	report := r.Run(context.Background(), hosts)
+
+	if len(enumerate.calls) != 1 || len(enumerate.calls[0].Services) != 1 || enumerate.calls[0].Services[0].Port != 4444 {
+		t.Fatalf("enumerate stage calls: %+v", enumerate.calls)
+	}
+	if svc := hosts[0].Service("tcp", 4444); svc.Name != "irc" || svc.Method != "identify" {
+		t.Errorf("identified service: %+v", svc)
+	}
+	if len(hosts[0].Tags) != 1 || len(report.Findings) != 1 || report.Findings[0].ID != "irc-open" {
+		t.Errorf("host tags %v, findings %+v", hosts[0].Tags, report.Findings)
+	}
+	if len(report.Modules) != 2 || report.Modules[0].Name != "irc" || report.Modules[0].Results != 1 || report.Modules[1].Targets != 1 {
+		t.Errorf("module reports: %+v", report.Modules)
+	}
+}
+
+func TestRegistryEnableDisable(t *testing.T) {
+	a := &fakeModule{name: "a", selector: Selector{Host: true}}
+	b := &fakeModule{name: "b", selector: Selector{Host: true}}
+	c := &fakeModule{name: "c", selector: Selector{Host: true}}
+	r := NewRegistry()
+	if err := r.Register(a, b, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(&fakeModule{name: "A"}); err == nil {
+		t.Error("duplicate module name accepted")
+	}
+	if err := r.Disable("nope"); err == nil {
+		t.Error("unknown module disabled")
+	}
+	if err := r.Only("A", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Disable("C"); err != nil {
+		t.Fatal(err)
+	}
+	report := r.Run(context.Background(), []results.Host{testHost()})
+	if len(a.calls) != 1 || len(b.calls) != 0 || len(c.calls) != 0 {
+		t.Errorf("calls: a=%d b=%d c=%d", len(a.calls), len(b.calls), len(c.calls))
+	}
+	if len(report.Modules) != 1 || report.Modules[0].Name != "a" {
+		t.Errorf("module reports: %+v", report.Modules)
+	}
+
+	if err := r.SetTimeout("b", time.Second); err != nil || r.TimeoutFor("B") != time.Second || r.TimeoutFor("a") != r.Timeout {
+		t.Errorf("SetTimeout: %v, b=%s a=%s", err, r.TimeoutFor("b"), r.TimeoutFor("a"))
+	}
+	if err := r.SetTimeout("b", 0); err == nil {
+		t.Error("zero timeout accepted")
+	}
+}
+
+func TestRegistryFinalizer(t *testing.T) {
+	var seen []results.Host
+	tagger := &fakeModule{name: "tagger", selector: Selector{Host: true},
+		run: func(ctx context.Context, target Target) (*Result, error) { return &Result{Tags: []string{"seen"}}, nil }}
+	final := finalizerModule{
+		fakeModule: &fakeModule{name: "final", selector: Selector{Ports: []int{9}}},
+		finalize: func(ctx context.Context, hosts []results.Host) ([]findings.Finding, error) {
+			seen = hosts
+			return []findings.Finding{{ID: "global", Title: "Global", Severity: findings.SeverityMedium}}, errors.New("partial")
+		},
+	}
+	r := NewRegistry()
+	if err := r.Register(final, tagger); err != nil {
+		t.Fatal(err)
+	}
+	report := r.Run(context.Background(), []results.Host{testHost()})
+	// O finalizador roda mesmo sem serviços selecionados e vê os hosts já enriquecidos.
+	if len(seen) != 1 || len(seen[0].Tags) != 1 {
+		t.Fatalf("finalizer hosts: %+v", seen)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].ID != "global" || report.Modules[0].Errors != 1 {
+		t.Errorf("findings %+v, reports %+v", report.Findings, report.Modules)
+	}
+}
+
+func TestRegistryTimeoutHoldsSlot(t *testing.T) {
+	var running, peak atomic.Int32
+	release := make(chan struct{})
+	// O módulo ignora ctx e só retorna quando release é fechado.
+	stuck := &fakeModule{name: "stuck", selector: Selector{Host: true},
+		run: func(ctx context.Context, target Target) (*Result, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				if p := peak.Load(); n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			<-release
+			return &Result{Tags: []string{"late"}}, nil
+		}}
+	r := NewRegistry()
+	r.Threads = 2
+	r.Timeout = 20 * time.Millisecond
+	if err := r.Register(stuck); err != nil {
+		t.Fatal(err)
+	}
+	hosts := make([]results.Host, 4)
+	for i := range hosts {
+		hosts[i] = testHost()
+	}
+	time.AfterFunc(200*time.Millisecond, func() { close(release) })
+	report := r.Run(context.Background(), hosts)
+
+	if peak.Load() > 2 {
+		t.Errorf("%d executions at once with Threads=2", peak.Load())
+	}
+	// As duas primeiras execuções são abandonadas no timeout; as outras só começam quando elas
+	// retornam e liberam as vagas, e então terminam a tempo.
+	if report.Modules[0].Timeouts != 2 || report.Modules[0].Results != 2 {
+		t.Errorf("report: %+v", report.Modules[0])
+	}
+	for i, host := range hosts {
+		if timedOut := i < 2; timedOut == (len(host.Tags) != 0) {
+			t.Errorf("host %d: tags %v", i, host.Tags)
+		}
+	}
+}
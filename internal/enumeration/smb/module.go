@@ -0,0 +1,42 @@
+package smb
+
+import (
+	"context"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+)
+
+// Module expõe o Scanner como enumeration.Enumerator.
+type Module struct {
+	Scanner *Scanner
+}
+
+// NewModule é a factory que cria o módulo a partir de um Scanner.
+func NewModule(sc *Scanner) *Module {
+	return &Module{Scanner: sc}
+}
+
+// Name retorna o nome do módulo.
+func (m *Module) Name() string { return ModuleName }
+
+// Selector seleciona 445/tcp e 139/tcp.
+func (m *Module) Selector() enumeration.Selector {
+	return enumeration.Selector{Ports: []int{445, 139}}
+}
+
+// Enumerate enumera o SMB do host, preferindo 445 a 139.
+func (m *Module) Enumerate(ctx context.Context, target enumeration.Target) (*enumeration.Result, error) {
+	port := 139
+	if target.Host.HasPort("tcp", 445) {
+		port = 445
+	}
+	info, err := m.Scanner.Scan(ctx, target.Host.Address, port)
+	if err != nil {
+		return nil, err
+	}
+	result := &enumeration.Result{Enrichment: info, Findings: Findings([]Info{*info})}
+	if info.SigningNotRequired() {
+		result.Tags = append(result.Tags, TagSigningNotRequired)
+	}
+	return result, nil
+}
@@ -0,0 +1,221 @@
+package smb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Flags NTLMSSP usadas nas mensagens NEGOTIATE e AUTHENTICATE.
+const (
+	ntlmNegotiateUnicode         uint32 = 0x00000001
+	ntlmRequestTarget            uint32 = 0x00000004
+	ntlmNegotiateNTLM            uint32 = 0x00000200
+	ntlmNegotiateAnonymous       uint32 = 0x00000800
+	ntlmNegotiateAlwaysSign      uint32 = 0x00008000
+	ntlmNegotiateExtendedSession uint32 = 0x00080000
+	ntlmNegotiateTargetInfo      uint32 = 0x00800000
+	ntlmNegotiateVersion         uint32 = 0x02000000
+	ntlmNegotiate128             uint32 = 0x20000000
+	ntlmNegotiate56              uint32 = 0x80000000
+
+	ntlmDefaultFlags = ntlmNegotiateUnicode | ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign |
+		ntlmNegotiateExtendedSession | ntlmNegotiateTargetInfo | ntlmNegotiateVersion | ntlmNegotiate128 | ntlmNegotiate56
+)
+
+// IDs dos pares AV_PAIR presentes no TargetInfo do CHALLENGE.
+const (
+	avEOL             uint16 = 0x0000
+	avNbComputerName  uint16 = 0x0001
+	avNbDomainName    uint16 = 0x0002
+	avDNSComputerName uint16 = 0x0003
+	avDNSDomainName   uint16 = 0x0004
+	avDNSTreeName     uint16 = 0x0005
+)
+
+var (
+	ntlmSignature = []byte("NTLMSSP\x00")
+	spnegoOID     = []byte{0x06, 0x06, 0x2b, 0x06, 0x01, 0x05, 0x05, 0x02}
+	ntlmsspOID    = []byte{0x06, 0x0a, 0x2b, 0x06, 0x01, 0x04, 0x01, 0x82, 0x37, 0x02, 0x02, 0x0a}
+)
+
+// ntlmChallenge contém as informações do host extraídas da mensagem CHALLENGE.
+type ntlmChallenge struct {
+	TargetName      string
+	NetBIOSComputer string
+	NetBIOSDomain   string
+	DNSComputer     string
+	DNSDomain       string
+	DNSTree         string
+	VersionMajor    uint8
+	VersionMinor    uint8
+	VersionBuild    uint16
+	HasVersion      bool
+}
+
+// ntlmNegotiateMessage monta a mensagem NTLMSSP NEGOTIATE (tipo 1).
+func ntlmNegotiateMessage() []byte {
+	msg := make([]byte, 40)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmDefaultFlags)
+	// DomainNameFields e WorkstationFields vazios; Version 6.1.7601 / NTLM revision 15.
+	copy(msg[32:], []byte{6, 1, 0xb1, 0x1d, 0, 0, 0, 0x0f})
+	return msg
+}
+
+// ntlmAnonymousAuthenticate monta a mensagem NTLMSSP AUTHENTICATE (tipo 3) de uma sessão nula:
+// usuário, domínio e resposta NT vazios e LmChallengeResponse = Z(1).
+func ntlmAnonymousAuthenticate() []byte {
+	const headerLen = 64
+	msg := make([]byte, headerLen+1)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+	field := func(off int, length int, dataOff int) {
+		binary.LittleEndian.PutUint16(msg[off:], uint16(length))
+		binary.LittleEndian.PutUint16(msg[off+2:], uint16(length))
+		binary.LittleEndian.PutUint32(msg[off+4:], uint32(dataOff))
+	}
+	field(12, 1, headerLen)   // LmChallengeResponse
+	field(20, 0, headerLen+1) // NtChallengeResponse
+	field(28, 0, headerLen+1) // DomainName
+	field(36, 0, headerLen+1) // UserName
+	field(44, 0, headerLen+1) // Workstation
+	field(52, 0, headerLen+1) // EncryptedRandomSessionKey
+	flags := (ntlmDefaultFlags | ntlmNegotiateAnonymous) &^ ntlmNegotiateVersion
+	binary.LittleEndian.PutUint32(msg[60:], flags)
+	return msg
+}
+
+// parseNTLMChallenge localiza a mensagem CHALLENGE dentro do token SPNEGO e extrai nome, domínio e versão.
+func parseNTLMChallenge(token []byte) (*ntlmChallenge, error) {
+	idx := bytes.Index(token, ntlmSignature)
+	if idx < 0 {
+		return nil, errors.New("NTLMSSP challenge not found in security blob")
+	}
+	msg := token[idx:]
+	if len(msg) < 48 || binary.LittleEndian.Uint32(msg[8:]) != 2 {
+		return nil, errors.New("invalid NTLMSSP challenge message")
+	}
+
+	challenge := &ntlmChallenge{}
+	if name, ok := ntlmField(msg, 12); ok {
+		challenge.TargetName = decodeUTF16(name)
+	}
+	flags := binary.LittleEndian.Uint32(msg[20:])
+	if flags&ntlmNegotiateVersion != 0 && len(msg) >= 56 {
+		challenge.VersionMajor = msg[48]
+		challenge.VersionMinor = msg[49]
+		challenge.VersionBuild = binary.LittleEndian.Uint16(msg[50:])
+		challenge.HasVersion = true
+	}
+
+	info, ok := ntlmField(msg, 40)
+	if !ok {
+		return challenge, nil
+	}
+	for len(info) >= 4 {
+		id := binary.LittleEndian.Uint16(info[0:])
+		length := int(binary.LittleEndian.Uint16(info[2:]))
+		if id == avEOL || 4+length > len(info) {
+			break
+		}
+		value := decodeUTF16(info[4 : 4+length])
+		switch id {
+		case avNbComputerName:
+			challenge.NetBIOSComputer = value
+		case avNbDomainName:
+			challenge.NetBIOSDomain = value
+		case avDNSComputerName:
+			challenge.DNSComputer = value
+		case avDNSDomainName:
+			challenge.DNSDomain = value
+		case avDNSTreeName:
+			challenge.DNSTree = value
+		}
+		info = info[4+length:]
+	}
+	return challenge, nil
+}
+
+// ntlmField lê um campo (Len, MaxLen, Offset) de uma mensagem NTLMSSP.
+func ntlmField(msg []byte, off int) ([]byte, bool) {
+	length := int(binary.LittleEndian.Uint16(msg[off:]))
+	offset := int(binary.LittleEndian.Uint32(msg[off+4:]))
+	if length == 0 || offset+length > len(msg) {
+		return nil, false
+	}
+	return msg[offset : offset+length], true
+}
+
+// OSVersion retorna a versão do Windows anunciada no CHALLENGE (ex.: "10.0.17763").
+func (c *ntlmChallenge) OSVersion() string {
+	if !c.HasVersion {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d.%d", c.VersionMajor, c.VersionMinor, c.VersionBuild)
+}
+
+// OSName traduz a versão anunciada para o nome do sistema operacional mais provável.
+func (c *ntlmChallenge) OSName() string {
+	if !c.HasVersion {
+		return ""
+	}
+	switch {
+	case c.VersionMajor == 10 && c.VersionBuild >= 26100:
+		return "Windows 11 24H2 / Server 2025"
+	case c.VersionMajor == 10 && c.VersionBuild >= 22000:
+		return "Windows 11"
+	case c.VersionMajor == 10 && c.VersionBuild == 20348:
+		return "Windows Server 2022"
+	case c.VersionMajor == 10 && c.VersionBuild == 17763:
+		return "Windows 10 1809 / Server 2019"
+	case c.VersionMajor == 10 && c.VersionBuild == 14393:
+		return "Windows 10 1607 / Server 2016"
+	case c.VersionMajor == 10:
+		return "Windows 10 / Server 2016+"
+	case c.VersionMajor == 6 && c.VersionMinor == 3:
+		return "Windows 8.1 / Server 2012 R2"
+	case c.VersionMajor == 6 && c.VersionMinor == 2:
+		return "Windows 8 / Server 2012"
+	case c.VersionMajor == 6 && c.VersionMinor == 1:
+		return "Windows 7 / Server 2008 R2"
+	case c.VersionMajor == 6 && c.VersionMinor == 0:
+		return "Windows Vista / Server 2008"
+	case c.VersionMajor == 5 && c.VersionMinor == 2:
+		return "Windows XP x64 / Server 2003"
+	case c.VersionMajor == 5 && c.VersionMinor == 1:
+		return "Windows XP"
+	}
+	return "Windows " + c.OSVersion()
+}
+
+// spnegoInit encapsula um token NTLMSSP em um NegTokenInit (GSS-API/SPNEGO).
+func spnegoInit(ntlmToken []byte) []byte {
+	mechTypes := derWrap(0xa0, derWrap(0x30, ntlmsspOID))
+	mechToken := derWrap(0xa2, derWrap(0x04, ntlmToken))
+	negTokenInit := derWrap(0xa0, derWrap(0x30, append(mechTypes, mechToken...)))
+	return derWrap(0x60, append(append([]byte{}, spnegoOID...), negTokenInit...))
+}
+
+// spnegoResponse encapsula um token NTLMSSP em um NegTokenResp.
+func spnegoResponse(ntlmToken []byte) []byte {
+	responseToken := derWrap(0xa2, derWrap(0x04, ntlmToken))
+	return derWrap(0xa1, derWrap(0x30, responseToken))
+}
+
+// derWrap codifica um elemento DER com a tag e o conteúdo informados.
+func derWrap(tag byte, content []byte) []byte {
+	out := []byte{tag}
+	n := len(content)
+	switch {
+	case n < 0x80:
+		out = append(out, byte(n))
+	case n < 0x100:
+		out = append(out, 0x81, byte(n))
+	default:
+		out = append(out, 0x82, byte(n>>8), byte(n))
+	}
+	return append(out, content...)
+}
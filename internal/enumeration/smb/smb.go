@@ -0,0 +1,276 @@
+package smb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ModuleName é o nome do módulo usado em logs e nos resultados.
+const ModuleName = "smb"
+
+// TagSigningNotRequired marca hosts cujo servidor SMB não exige assinatura (alvos de relay NTLM).
+const TagSigningNotRequired = "smb-signing-not-required"
+
+// Info contém o resultado da enumeração SMB de um host.
+type Info struct {
+	Address         string   `json:"address"`
+	Port            int      `json:"port"`
+	SMBv1           bool     `json:"smbv1"`
+	Dialects        []string `json:"dialects"`
+	SigningEnabled  bool     `json:"signing_enabled"`
+	SigningRequired bool     `json:"signing_required"`
+	SigningUnknown  bool     `json:"signing_unknown,omitempty"` // A resposta não informou o modo de segurança
+	OS              string   `json:"os,omitempty"`
+	OSVersion       string   `json:"os_version,omitempty"`
+	NetBIOSName     string   `json:"netbios_name,omitempty"`
+	NetBIOSDomain   string   `json:"netbios_domain,omitempty"`
+	DNSHostname     string   `json:"dns_hostname,omitempty"`
+	DNSDomain       string   `json:"dns_domain,omitempty"`
+	DNSForest       string   `json:"dns_forest,omitempty"`
+	NullSession     bool     `json:"null_session"`
+	Shares          []Share  `json:"shares,omitempty"`
+	ShareError      string   `json:"share_error,omitempty"` // Motivo da falha na listagem, se houver
+}
+
+// SigningNotRequired indica se o servidor comprovadamente não exige assinatura. Sem o modo de
+// segurança na resposta, a assinatura é desconhecida e o host não é marcado.
+func (i Info) SigningNotRequired() bool {
+	return !i.SigningUnknown && !i.SigningRequired
+}
+
+// Scanner executa a enumeração SMB (dialetos, assinatura, NTLM e sessão nula).
+type Scanner struct {
+	Timeout time.Duration // Timeout por conexão e por resposta
+	Threads int           // Quantidade de hosts enumerados simultaneamente
+}
+
+// NewScanner é a factory que cria um Scanner com valores padrão.
+func NewScanner() *Scanner {
+	return &Scanner{
+		Timeout: 5 * time.Second,
+		Threads: 10,
+	}
+}
+
+// Scan enumera o serviço SMB em address:port (445 direto ou 139 via sessão NetBIOS).
+func (sc *Scanner) Scan(ctx context.Context, address string, port int) (*Info, error) {
+	info := &Info{Address: address, Port: port}
+
+	// SMBv1: conexão dedicada, pois um servidor que não o suporta encerra a conexão.
+	var smb1 smb1Negotiate
+	if s, err := dial(ctx, address, port, sc.Timeout); err == nil {
+		smb1 = s.smb1Negotiate()
+		info.SMBv1 = smb1.Supported
+		s.Close()
+	}
+
+	// Dialetos SMB2/3: um NEGOTIATE por dialeto, cada um em sua própria conexão.
+	var supported []uint16
+	for _, d := range dialectOrder {
+		s, err := dial(ctx, address, port, sc.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		if neg, err := s.negotiate([]uint16{d}); err == nil && neg.Dialect == d {
+			supported = append(supported, d)
+			info.Dialects = append(info.Dialects, dialectNames[d])
+		}
+		s.Close()
+	}
+	if len(supported) == 0 {
+		if info.SMBv1 {
+			// Só SMBv1: a assinatura vem do SecurityMode do NEGOTIATE SMB1.
+			info.Dialects = append(info.Dialects, "NT LM 0.12")
+			info.SigningUnknown = !smb1.ModeKnown
+			info.SigningEnabled = smb1.SecurityMode&smb1SecuritySignaturesEnabled != 0
+			info.SigningRequired = smb1.SecurityMode&smb1SecuritySignaturesRequired != 0
+			sc.smb1Challenge(ctx, info)
+			return info, nil
+		}
+		return nil, fmt.Errorf("no SMB dialect accepted by %s:%d", address, port)
+	}
+
+	s, err := dial(ctx, address, port, sc.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	// Negocia o maior dialeto até 3.0.2; o 3.1.1 só é usado quando for o único disponível.
+	dialects := []uint16{0x0202, 0x0210, 0x0300, 0x0302}
+	if len(supported) == 1 && supported[0] == smb2Dialect311 {
+		dialects = supported
+	}
+	neg, err := s.negotiate(dialects)
+	if err != nil {
+		return nil, fmt.Errorf("smb negotiate failed: %w", err)
+	}
+	info.SigningEnabled = neg.SecurityMode&smb2NegotiateSigningEnabled != 0
+	info.SigningRequired = neg.SecurityMode&smb2NegotiateSigningRequired != 0
+
+	// NTLM: o CHALLENGE revela nomes, domínio e versão do sistema operacional.
+	token, _, err := s.sessionSetup(spnegoInit(ntlmNegotiateMessage()))
+	if err != nil {
+		return info, nil
+	}
+	if challenge, err := parseNTLMChallenge(token); err == nil {
+		info.setChallenge(challenge)
+	}
+
+	// Sessão nula: AUTHENTICATE anônimo seguido da listagem de compartilhamentos via srvsvc.
+	if _, _, err := s.sessionSetup(spnegoResponse(ntlmAnonymousAuthenticate())); err != nil {
+		var se *statusError
+		if errors.As(err, &se) && (se.Status == statusAccessDenied || se.Status == statusLogonFailure) {
+			info.ShareError = "null session denied"
+		} else {
+			info.ShareError = err.Error()
+		}
+		return info, nil
+	}
+	info.NullSession = true
+
+	if err := s.treeConnect(address, "IPC$"); err != nil {
+		info.ShareError = err.Error()
+		return info, nil
+	}
+	fileID, err := s.openPipe("srvsvc")
+	if err != nil {
+		info.ShareError = err.Error()
+		return info, nil
+	}
+	defer s.closeFile(fileID)
+	shares, err := s.listShares(fileID, address)
+	if err != nil {
+		info.ShareError = err.Error()
+	}
+	info.Shares = shares
+	return info, nil
+}
+
+// smb1Challenge obtém o CHALLENGE NTLM de um host que só aceita SMBv1, via SESSION_SETUP_ANDX com
+// segurança estendida. Sem versão no CHALLENGE (XP/2003), o sistema vem do NativeOS da resposta.
+func (sc *Scanner) smb1Challenge(ctx context.Context, info *Info) {
+	s, err := dial(ctx, info.Address, info.Port, sc.Timeout)
+	if err != nil {
+		return
+	}
+	defer s.Close()
+	if !s.smb1Negotiate().Supported {
+		return
+	}
+	token, nativeOS, err := s.smb1SessionSetup(spnegoInit(ntlmNegotiateMessage()))
+	if err != nil {
+		return
+	}
+	if challenge, err := parseNTLMChallenge(token); err == nil {
+		info.setChallenge(challenge)
+	}
+	if info.OS == "" {
+		info.OS = nativeOS
+	}
+}
+
+// setChallenge preenche nomes, domínio e sistema operacional a partir do CHALLENGE NTLM.
+func (i *Info) setChallenge(challenge *ntlmChallenge) {
+	i.OS = challenge.OSName()
+	i.OSVersion = challenge.OSVersion()
+	i.NetBIOSName = challenge.NetBIOSComputer
+	i.NetBIOSDomain = challenge.NetBIOSDomain
+	i.DNSHostname = challenge.DNSComputer
+	i.DNSDomain = challenge.DNSDomain
+	i.DNSForest = challenge.DNSTree
+}
+
+// Run enumera os hosts com 445/tcp ou 139/tcp abertas e anexa o resultado ao registro de cada host.
+func (sc *Scanner) Run(ctx context.Context, hosts []results.Host) []Info {
+	threads := sc.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	batch := util.NewProbeBatch(ctx, ModuleName, threads)
+	for i := range hosts {
+		for _, p := range []int{445, 139} {
+			if hosts[i].HasPort("tcp", p) {
+				batch.Add(hosts[i].Address, "tcp", p)
+				break
+			}
+		}
+	}
+	batch.Start()
+	defer batch.Finish()
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found []Info
+		sem   = make(chan struct{}, threads)
+	)
+	for i := range hosts {
+		port := 0
+		switch {
+		case hosts[i].HasPort("tcp", 445):
+			port = 445
+		case hosts[i].HasPort("tcp", 139):
+			port = 139
+		default:
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		util.WaitWindow(ctx)
+		go func(host *results.Host, port int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			info, err := sc.Scan(ctx, host.Address, port)
+			if err != nil {
+				util.ModuleLogger(ModuleName).Debug().Err(err).Msg("SMB enumeration failed")
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			host.SetEnrichment(ModuleName, info)
+			if info.SigningNotRequired() {
+				host.AddTag(TagSigningNotRequired)
+			}
+			found = append(found, *info)
+		}(&hosts[i], port)
+	}
+	wg.Wait()
+	return found
+}
+
+// Findings converte o resultado da enumeração SMB em achados.
+func Findings(infos []Info) []findings.Finding {
+	var list []findings.Finding
+	for _, info := range infos {
+		newFinding := func(id, title, severity, evidence string) findings.Finding {
+			return findings.New(ModuleName, id, title, severity, info.Address, info.Port, "tcp").
+				WithEvidence(evidence).
+				WithDetails("os", info.OS, "netbios_name", info.NetBIOSName, "domain", info.DNSDomain)
+		}
+		if info.SigningNotRequired() {
+			list = append(list, newFinding(findings.IDSMBSigningNotRequired, "SMB signing not required", findings.SeverityMedium, "dialects: "+strings.Join(info.Dialects, ", ")))
+		}
+		if info.SMBv1 {
+			list = append(list, newFinding(findings.IDSMBv1Enabled, "SMBv1 enabled", findings.SeverityMedium, "SMB1 negotiate accepted"))
+		}
+		if info.NullSession {
+			var shares []string
+			for _, share := range info.Shares {
+				shares = append(shares, share.Name)
+			}
+			list = append(list, newFinding(findings.IDSMBNullSession, "SMB null session allowed", findings.SeverityMedium, "shares: "+strings.Join(shares, ", ")))
+		}
+	}
+	return list
+}
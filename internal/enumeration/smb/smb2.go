@@ -0,0 +1,544 @@
+package smb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+	"unicode/utf16"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// Comandos SMB2 utilizados pelo módulo.
+const (
+	cmdNegotiate    uint16 = 0x0000
+	cmdSessionSetup uint16 = 0x0001
+	cmdTreeConnect  uint16 = 0x0003
+	cmdCreate       uint16 = 0x0005
+	cmdClose        uint16 = 0x0006
+	cmdRead         uint16 = 0x0008
+	cmdIoctl        uint16 = 0x000b
+)
+
+// Status NT relevantes.
+const (
+	statusSuccess                 uint32 = 0x00000000
+	statusPending                 uint32 = 0x00000103
+	statusBufferOverflow          uint32 = 0x80000005
+	statusMoreProcessingRequired  uint32 = 0xc0000016
+	statusAccessDenied            uint32 = 0xc0000022
+	statusLogonFailure            uint32 = 0xc000006d
+	smb2HeaderSize                       = 64
+	smb2FlagAsync                 uint32 = 0x00000002
+	smb2NegotiateSigningEnabled   uint16 = 0x0001
+	smb2NegotiateSigningRequired  uint16 = 0x0002
+	smb2SessionFlagIsGuest        uint16 = 0x0001
+	smb2SessionFlagIsNull         uint16 = 0x0002
+	fsctlPipeTransceive           uint32 = 0x0011c017
+	smb2MaxIoctlOutput            uint32 = 0xffff
+	smb2Dialect311                uint16 = 0x0311
+	smb2PreauthIntegrityContextID uint16 = 0x0001
+)
+
+// dialectNames mapeia as revisões de dialeto SMB2/3 para seus nomes.
+var dialectNames = map[uint16]string{
+	0x0202: "2.0.2",
+	0x0210: "2.1",
+	0x0300: "3.0",
+	0x0302: "3.0.2",
+	0x0311: "3.1.1",
+}
+
+// dialectOrder define a ordem em que os dialetos são testados e reportados.
+var dialectOrder = []uint16{0x0202, 0x0210, 0x0300, 0x0302, 0x0311}
+
+// statusError representa uma resposta SMB2 com status NT diferente de sucesso.
+type statusError struct {
+	Command uint16
+	Status  uint32
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("smb2 command 0x%02x failed with status 0x%08x", e.Command, e.Status)
+}
+
+// negotiateResult contém os campos relevantes da resposta ao NEGOTIATE.
+type negotiateResult struct {
+	Dialect      uint16
+	SecurityMode uint16
+	ServerGUID   [16]byte
+	SystemTime   time.Time
+}
+
+// session é uma conexão SMB2 com o estado mínimo necessário para as requisições do módulo.
+type session struct {
+	conn      net.Conn
+	timeout   time.Duration
+	messageID uint64
+	sessionID uint64
+	treeID    uint32
+	dialect   uint16
+}
+
+// dial abre a conexão TCP. Na porta 139 é necessário negociar antes uma sessão NetBIOS.
+func dial(ctx context.Context, address string, port int, timeout time.Duration) (*session, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := util.DialContext(ctx, &dialer, "tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s:%d: %w", address, port, err)
+	}
+	s := &session{conn: conn, timeout: timeout}
+	if port == 139 {
+		if err := s.netbiosSessionRequest(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Close encerra a conexão.
+func (s *session) Close() error {
+	return s.conn.Close()
+}
+
+// netbiosSessionRequest envia um SESSION REQUEST (RFC 1002) para o nome genérico *SMBSERVER.
+func (s *session) netbiosSessionRequest() error {
+	called := netbiosEncode("*SMBSERVER")
+	calling := netbiosEncode("ARTHXRECON")
+	payload := append(called, calling...)
+	packet := []byte{0x81, 0x00, byte(len(payload) >> 8), byte(len(payload))}
+	packet = append(packet, payload...)
+
+	_ = s.conn.SetDeadline(time.Now().Add(s.timeout))
+	if _, err := s.conn.Write(packet); err != nil {
+		return fmt.Errorf("failed to send NetBIOS session request: %w", err)
+	}
+	resp := make([]byte, 4)
+	if _, err := io.ReadFull(s.conn, resp); err != nil {
+		return fmt.Errorf("failed to read NetBIOS session response: %w", err)
+	}
+	if resp[0] != 0x82 {
+		return fmt.Errorf("NetBIOS session rejected (type 0x%02x)", resp[0])
+	}
+	return nil
+}
+
+// netbiosEncode aplica a codificação de primeiro nível (RFC 1001) a um nome NetBIOS.
+func netbiosEncode(name string) []byte {
+	padded := []byte(fmt.Sprintf("%-16s", name))[:16] // Sufixo 0x20: serviço de arquivos
+	out := []byte{0x20}
+	for _, b := range padded {
+		out = append(out, 'A'+(b>>4), 'A'+(b&0x0f))
+	}
+	return append(out, 0x00)
+}
+
+// writeFrame envia um pacote com o cabeçalho de sessão NetBIOS/Direct TCP.
+func (s *session) writeFrame(packet []byte) error {
+	frame := make([]byte, 4, 4+len(packet))
+	binary.BigEndian.PutUint32(frame, uint32(len(packet)))
+	frame = append(frame, packet...)
+	_ = s.conn.SetDeadline(time.Now().Add(s.timeout))
+	_, err := s.conn.Write(frame)
+	return err
+}
+
+// readFrame lê um pacote completo, descartando o cabeçalho de sessão.
+func (s *session) readFrame() ([]byte, error) {
+	_ = s.conn.SetDeadline(time.Now().Add(s.timeout))
+	hdr := make([]byte, 4)
+	if _, err := io.ReadFull(s.conn, hdr); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(hdr) & 0x00ffffff
+	packet := make([]byte, length)
+	if _, err := io.ReadFull(s.conn, packet); err != nil {
+		return nil, err
+	}
+	return packet, nil
+}
+
+// request envia um comando SMB2 e retorna a resposta completa (cabeçalho + corpo).
+// Respostas intermediárias STATUS_PENDING são ignoradas. Status de erro retornam *statusError junto com o pacote.
+func (s *session) request(command uint16, body []byte) ([]byte, error) {
+	hdr := make([]byte, smb2HeaderSize)
+	copy(hdr, "\xfeSMB")
+	binary.LittleEndian.PutUint16(hdr[4:], smb2HeaderSize)
+	if s.dialect != 0 && s.dialect != 0x0202 {
+		binary.LittleEndian.PutUint16(hdr[6:], 1) // CreditCharge
+	}
+	binary.LittleEndian.PutUint16(hdr[12:], command)
+	binary.LittleEndian.PutUint16(hdr[14:], 31) // CreditRequest
+	binary.LittleEndian.PutUint64(hdr[24:], s.messageID)
+	binary.LittleEndian.PutUint32(hdr[32:], 0xfeff) // ProcessId
+	binary.LittleEndian.PutUint32(hdr[36:], s.treeID)
+	binary.LittleEndian.PutUint64(hdr[40:], s.sessionID)
+	s.messageID++
+
+	if err := s.writeFrame(append(hdr, body...)); err != nil {
+		return nil, fmt.Errorf("failed to send smb2 command 0x%02x: %w", command, err)
+	}
+	for {
+		packet, err := s.readFrame()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read smb2 response: %w", err)
+		}
+		if len(packet) < smb2HeaderSize || !bytes.HasPrefix(packet, []byte("\xfeSMB")) {
+			return nil, errors.New("invalid smb2 response")
+		}
+		status := binary.LittleEndian.Uint32(packet[8:])
+		flags := binary.LittleEndian.Uint32(packet[16:])
+		if status == statusPending && flags&smb2FlagAsync != 0 {
+			continue
+		}
+		if status != statusSuccess && status != statusMoreProcessingRequired && status != statusBufferOverflow {
+			return packet, &statusError{Command: command, Status: status}
+		}
+		return packet, nil
+	}
+}
+
+// negotiate envia um NEGOTIATE com os dialetos informados.
+func (s *session) negotiate(dialects []uint16) (*negotiateResult, error) {
+	body := make([]byte, 36)
+	binary.LittleEndian.PutUint16(body[0:], 36)
+	binary.LittleEndian.PutUint16(body[2:], uint16(len(dialects)))
+	binary.LittleEndian.PutUint16(body[4:], smb2NegotiateSigningEnabled)
+	copy(body[12:28], "arthxrecon-smb2\x00") // ClientGuid
+	for _, d := range dialects {
+		body = binary.LittleEndian.AppendUint16(body, d)
+	}
+
+	// O dialeto 3.1.1 exige ao menos o contexto de integridade de pré-autenticação.
+	for _, d := range dialects {
+		if d != smb2Dialect311 {
+			continue
+		}
+		for (smb2HeaderSize+len(body))%8 != 0 {
+			body = append(body, 0)
+		}
+		binary.LittleEndian.PutUint32(body[28:], uint32(smb2HeaderSize+len(body))) // NegotiateContextOffset
+		binary.LittleEndian.PutUint16(body[32:], 1)                                // NegotiateContextCount
+		ctxData := make([]byte, 6+32)
+		binary.LittleEndian.PutUint16(ctxData[0:], 1)      // HashAlgorithmCount
+		binary.LittleEndian.PutUint16(ctxData[2:], 32)     // SaltLength
+		binary.LittleEndian.PutUint16(ctxData[4:], 0x0001) // SHA-512
+		ctxHdr := make([]byte, 8)
+		binary.LittleEndian.PutUint16(ctxHdr[0:], smb2PreauthIntegrityContextID)
+		binary.LittleEndian.PutUint16(ctxHdr[2:], uint16(len(ctxData)))
+		body = append(body, ctxHdr...)
+		body = append(body, ctxData...)
+		break
+	}
+
+	packet, err := s.request(cmdNegotiate, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(packet) < smb2HeaderSize+64 {
+		return nil, errors.New("short smb2 negotiate response")
+	}
+	resp := packet[smb2HeaderSize:]
+	result := &negotiateResult{
+		SecurityMode: binary.LittleEndian.Uint16(resp[2:]),
+		Dialect:      binary.LittleEndian.Uint16(resp[4:]),
+		SystemTime:   filetimeToTime(binary.LittleEndian.Uint64(resp[40:])),
+	}
+	copy(result.ServerGUID[:], resp[8:24])
+	s.dialect = result.Dialect
+	return result, nil
+}
+
+// sessionSetup envia um SESSION_SETUP com o token de segurança informado.
+// Retorna o token devolvido pelo servidor e os SessionFlags.
+func (s *session) sessionSetup(token []byte) ([]byte, uint16, error) {
+	body := make([]byte, 24)
+	binary.LittleEndian.PutUint16(body[0:], 25)
+	body[3] = byte(smb2NegotiateSigningEnabled)
+	binary.LittleEndian.PutUint16(body[12:], smb2HeaderSize+24) // SecurityBufferOffset
+	binary.LittleEndian.PutUint16(body[14:], uint16(len(token)))
+	body = append(body, token...)
+
+	packet, err := s.request(cmdSessionSetup, body)
+	if packet != nil && len(packet) >= smb2HeaderSize {
+		s.sessionID = binary.LittleEndian.Uint64(packet[40:])
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(packet) < smb2HeaderSize+8 {
+		return nil, 0, errors.New("short smb2 session setup response")
+	}
+	resp := packet[smb2HeaderSize:]
+	flags := binary.LittleEndian.Uint16(resp[2:])
+	offset := int(binary.LittleEndian.Uint16(resp[4:]))
+	length := int(binary.LittleEndian.Uint16(resp[6:]))
+	if offset+length > len(packet) {
+		return nil, flags, errors.New("invalid smb2 security buffer")
+	}
+	return packet[offset : offset+length], flags, nil
+}
+
+// treeConnect conecta ao compartilhamento \\address\share e guarda o TreeId.
+func (s *session) treeConnect(address, share string) error {
+	path := encodeUTF16(`\\` + address + `\` + share)
+	body := make([]byte, 8)
+	binary.LittleEndian.PutUint16(body[0:], 9)
+	binary.LittleEndian.PutUint16(body[4:], smb2HeaderSize+8)
+	binary.LittleEndian.PutUint16(body[6:], uint16(len(path)))
+	body = append(body, path...)
+
+	packet, err := s.request(cmdTreeConnect, body)
+	if err != nil {
+		return err
+	}
+	s.treeID = binary.LittleEndian.Uint32(packet[36:])
+	return nil
+}
+
+// openPipe abre um named pipe no IPC$ e retorna seu FileId.
+func (s *session) openPipe(name string) ([16]byte, error) {
+	var fileID [16]byte
+	nameBytes := encodeUTF16(name)
+	body := make([]byte, 56)
+	binary.LittleEndian.PutUint16(body[0:], 57)
+	binary.LittleEndian.PutUint32(body[4:], 2)           // ImpersonationLevel: Impersonation
+	binary.LittleEndian.PutUint32(body[24:], 0x0012019f) // DesiredAccess
+	binary.LittleEndian.PutUint32(body[32:], 0x00000007) // ShareAccess: read/write/delete
+	binary.LittleEndian.PutUint32(body[36:], 1)          // CreateDisposition: FILE_OPEN
+	binary.LittleEndian.PutUint16(body[44:], smb2HeaderSize+56)
+	binary.LittleEndian.PutUint16(body[46:], uint16(len(nameBytes)))
+	body = append(body, nameBytes...)
+
+	packet, err := s.request(cmdCreate, body)
+	if err != nil {
+		return fileID, err
+	}
+	if len(packet) < smb2HeaderSize+80 {
+		return fileID, errors.New("short smb2 create response")
+	}
+	copy(fileID[:], packet[smb2HeaderSize+64:smb2HeaderSize+80])
+	return fileID, nil
+}
+
+// closeFile fecha um FileId aberto.
+func (s *session) closeFile(fileID [16]byte) {
+	body := make([]byte, 24)
+	binary.LittleEndian.PutUint16(body[0:], 24)
+	copy(body[8:], fileID[:])
+	_, _ = s.request(cmdClose, body)
+}
+
+// transceive envia dados para o pipe e lê a resposta via FSCTL_PIPE_TRANSCEIVE.
+func (s *session) transceive(fileID [16]byte, input []byte) ([]byte, error) {
+	body := make([]byte, 56)
+	binary.LittleEndian.PutUint16(body[0:], 57)
+	binary.LittleEndian.PutUint32(body[4:], fsctlPipeTransceive)
+	copy(body[8:24], fileID[:])
+	binary.LittleEndian.PutUint32(body[24:], smb2HeaderSize+56) // InputOffset
+	binary.LittleEndian.PutUint32(body[28:], uint32(len(input)))
+	binary.LittleEndian.PutUint32(body[44:], smb2MaxIoctlOutput) // MaxOutputResponse
+	binary.LittleEndian.PutUint32(body[48:], 1)                  // SMB2_0_IOCTL_IS_FSCTL
+	body = append(body, input...)
+
+	packet, err := s.request(cmdIoctl, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(packet) < smb2HeaderSize+48 {
+		return nil, errors.New("short smb2 ioctl response")
+	}
+	resp := packet[smb2HeaderSize:]
+	offset := int(binary.LittleEndian.Uint32(resp[32:]))
+	length := int(binary.LittleEndian.Uint32(resp[36:]))
+	if offset+length > len(packet) {
+		return nil, errors.New("invalid smb2 ioctl output")
+	}
+	return packet[offset : offset+length], nil
+}
+
+// read lê dados pendentes do pipe (usado para fragmentos DCE/RPC adicionais).
+func (s *session) read(fileID [16]byte, length uint32) ([]byte, error) {
+	body := make([]byte, 49)
+	binary.LittleEndian.PutUint16(body[0:], 49)
+	binary.LittleEndian.PutUint32(body[4:], length)
+	copy(body[16:32], fileID[:])
+
+	packet, err := s.request(cmdRead, body)
+	if err != nil {
+		return nil, err
+	}
+	if len(packet) < smb2HeaderSize+16 {
+		return nil, errors.New("short smb2 read response")
+	}
+	resp := packet[smb2HeaderSize:]
+	offset := int(resp[2])
+	dataLen := int(binary.LittleEndian.Uint32(resp[4:]))
+	if offset+dataLen > len(packet) {
+		return nil, errors.New("invalid smb2 read data")
+	}
+	return packet[offset : offset+dataLen], nil
+}
+
+// Comandos, bits do SecurityMode e capacidades SMB1 utilizados pelo módulo.
+const (
+	smb1ComNegotiate               byte   = 0x72
+	smb1ComSessionSetupAndX        byte   = 0x73
+	smb1SecuritySignaturesEnabled  byte   = 0x04
+	smb1SecuritySignaturesRequired byte   = 0x08
+	smb1CapUnicode                 uint32 = 0x00000004
+	smb1CapNTSMBs                  uint32 = 0x00000010
+	smb1CapStatus32                uint32 = 0x00000040
+	smb1CapExtendedSecurity        uint32 = 0x80000000
+)
+
+// smb1Negotiate é o resultado do NEGOTIATE SMB1: se o dialeto foi aceito e o SecurityMode anunciado.
+type smb1Negotiate struct {
+	Supported    bool
+	SecurityMode byte
+	ModeKnown    bool // A resposta trouxe os parâmetros do NT LM 0.12 (WordCount 17)
+}
+
+// smb1Header monta o cabeçalho SMB1 de 32 bytes do comando informado.
+func smb1Header(command byte) []byte {
+	packet := []byte("\xffSMB")
+	packet = append(packet, command)
+	packet = append(packet, 0, 0, 0, 0)             // Status
+	packet = append(packet, 0x18)                   // Flags
+	packet = append(packet, 0x01, 0xc8)             // Flags2: long names, extended security, NT status, unicode
+	packet = append(packet, make([]byte, 12)...)    // PIDHigh, SecuritySignature, Reserved
+	packet = append(packet, 0xff, 0xff, 0xff, 0xfe) // TID, PIDLow
+	return append(packet, 0, 0, 0, 0)               // UID, MID
+}
+
+// smb1Negotiate verifica se o servidor aceita o dialeto SMBv1 "NT LM 0.12" e lê o SecurityMode.
+func (s *session) smb1Negotiate() smb1Negotiate {
+	packet := smb1Header(smb1ComNegotiate)
+	dialect := append([]byte{0x02}, []byte("NT LM 0.12\x00")...)
+	packet = append(packet, 0) // WordCount
+	packet = binary.LittleEndian.AppendUint16(packet, uint16(len(dialect)))
+	packet = append(packet, dialect...)
+
+	if err := s.writeFrame(packet); err != nil {
+		return smb1Negotiate{}
+	}
+	resp, err := s.readFrame()
+	if err != nil {
+		return smb1Negotiate{}
+	}
+	return parseSMB1Negotiate(resp)
+}
+
+// parseSMB1Negotiate interpreta a resposta ao NEGOTIATE SMB1: cabeçalho de 32 bytes, WordCount,
+// DialectIndex e, no NT LM 0.12, o SecurityMode logo em seguida.
+func parseSMB1Negotiate(resp []byte) smb1Negotiate {
+	if len(resp) < 35 || !bytes.HasPrefix(resp, []byte("\xffSMB")) {
+		return smb1Negotiate{}
+	}
+	status := binary.LittleEndian.Uint32(resp[5:])
+	wordCount := resp[32]
+	dialectIndex := binary.LittleEndian.Uint16(resp[33:])
+	result := smb1Negotiate{Supported: status == 0 && wordCount > 0 && dialectIndex != 0xffff}
+	if result.Supported && wordCount >= 17 && len(resp) >= 36 {
+		result.SecurityMode = resp[35]
+		result.ModeKnown = true
+	}
+	return result
+}
+
+// smb1SessionSetup envia um SESSION_SETUP_ANDX com segurança estendida e o token informado.
+// Retorna o token devolvido pelo servidor e o NativeOS anunciado na resposta.
+func (s *session) smb1SessionSetup(token []byte) ([]byte, string, error) {
+	packet := smb1Header(smb1ComSessionSetupAndX)
+	packet = append(packet, 12)               // WordCount
+	packet = append(packet, 0xff, 0, 0, 0)    // AndXCommand (nenhum), AndXReserved, AndXOffset
+	packet = append(packet, 0xff, 0xff, 2, 0) // MaxBufferSize, MaxMpxCount
+	packet = append(packet, 1, 0, 0, 0, 0, 0) // VcNumber, SessionKey
+	packet = binary.LittleEndian.AppendUint16(packet, uint16(len(token)))
+	packet = append(packet, 0, 0, 0, 0) // Reserved
+	packet = binary.LittleEndian.AppendUint32(packet, smb1CapUnicode|smb1CapNTSMBs|smb1CapStatus32|smb1CapExtendedSecurity)
+	data := append([]byte{}, token...)
+	if (len(packet)+2+len(data))%2 != 0 {
+		data = append(data, 0) // Alinhamento das strings Unicode
+	}
+	data = append(data, 0, 0, 0, 0) // NativeOS e NativeLanMan vazios
+	packet = binary.LittleEndian.AppendUint16(packet, uint16(len(data)))
+	packet = append(packet, data...)
+
+	if err := s.writeFrame(packet); err != nil {
+		return nil, "", fmt.Errorf("failed to send smb1 session setup: %w", err)
+	}
+	resp, err := s.readFrame()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read smb1 session setup response: %w", err)
+	}
+	return parseSMB1SessionSetup(resp)
+}
+
+// parseSMB1SessionSetup interpreta a resposta ao SESSION_SETUP_ANDX com segurança estendida:
+// cabeçalho de 32 bytes, WordCount 4 (AndX, Action, SecurityBlobLength), ByteCount, o token e as
+// strings NativeOS e NativeLanMan em Unicode.
+func parseSMB1SessionSetup(resp []byte) ([]byte, string, error) {
+	if len(resp) < 33 || !bytes.HasPrefix(resp, []byte("\xffSMB")) || resp[4] != smb1ComSessionSetupAndX {
+		return nil, "", errors.New("invalid smb1 session setup response")
+	}
+	if status := binary.LittleEndian.Uint32(resp[5:]); status != statusSuccess && status != statusMoreProcessingRequired {
+		return nil, "", fmt.Errorf("smb1 session setup failed with status 0x%08x", status)
+	}
+	if resp[32] != 4 || len(resp) < 43 {
+		return nil, "", errors.New("smb1 session setup response without security blob")
+	}
+	blobLen := int(binary.LittleEndian.Uint16(resp[39:]))
+	end := 43 + int(binary.LittleEndian.Uint16(resp[41:]))
+	if end > len(resp) || 43+blobLen > end {
+		return nil, "", errors.New("invalid smb1 security blob")
+	}
+	offset := 43 + blobLen
+	if offset%2 != 0 {
+		offset++
+	}
+	var nativeOS string
+	if offset < end {
+		nativeOS = decodeUTF16(resp[offset:end])
+	}
+	return resp[43 : 43+blobLen], nativeOS, nil
+}
+
+// encodeUTF16 codifica uma string em UTF-16LE, sem terminador.
+func encodeUTF16(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(out[2*i:], u)
+	}
+	return out
+}
+
+// decodeUTF16 decodifica uma string UTF-16LE, removendo terminadores nulos.
+func decodeUTF16(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u := binary.LittleEndian.Uint16(b[i:])
+		if u == 0 {
+			break
+		}
+		units = append(units, u)
+	}
+	return string(utf16.Decode(units))
+}
+
+// filetimeToTime converte um FILETIME (intervalos de 100ns desde 1601) em time.Time.
+func filetimeToTime(ft uint64) time.Time {
+	if ft == 0 {
+		return time.Time{}
+	}
+	const epochDiff = 116444736000000000
+	return time.Unix(0, int64(ft-epochDiff)*100).UTC()
+}
@@ -0,0 +1,440 @@
+package smb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeServer é um servidor SMB mínimo em 127.0.0.1 que responde ao NEGOTIATE SMB1, ao NEGOTIATE
+// SMB2 e ao SESSION_SETUP do módulo.
+type fakeServer struct {
+	SMB1         bool     // Aceita o NEGOTIATE SMB1
+	SMB1Words    byte     // WordCount da resposta SMB1 (17 no NT LM 0.12)
+	SMB1Mode     byte     // SecurityMode da resposta SMB1
+	Dialects     []uint16 // Dialetos SMB2/3 aceitos (vazio: encerra a conexão)
+	SecurityMode uint16   // SecurityMode da resposta SMB2
+	Challenge    []byte   // Token NTLM devolvido no primeiro SESSION_SETUP (SMB1 ou SMB2)
+	NativeOS     string   // NativeOS da resposta ao SESSION_SETUP_ANDX
+}
+
+// start escuta em uma porta livre e retorna a porta.
+func (f *fakeServer) start(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func (f *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	setups := 0
+	for {
+		hdr := make([]byte, 4)
+		if _, err := io.ReadFull(conn, hdr); err != nil {
+			return
+		}
+		packet := make([]byte, binary.BigEndian.Uint32(hdr)&0x00ffffff)
+		if _, err := io.ReadFull(conn, packet); err != nil {
+			return
+		}
+
+		var resp []byte
+		switch {
+		case bytes.HasPrefix(packet, []byte("\xffSMB")):
+			if !f.SMB1 || len(packet) < 32 {
+				return
+			}
+			if packet[4] == smb1ComSessionSetupAndX {
+				resp = f.smb1SessionSetupResponse(packet)
+				break
+			}
+			resp = f.smb1Response()
+		case bytes.HasPrefix(packet, []byte("\xfeSMB")) && len(packet) >= smb2HeaderSize:
+			switch binary.LittleEndian.Uint16(packet[12:]) {
+			case cmdNegotiate:
+				dialect, ok := f.pick(packet[smb2HeaderSize:])
+				if !ok {
+					return
+				}
+				body := make([]byte, 64)
+				binary.LittleEndian.PutUint16(body[0:], 65)
+				binary.LittleEndian.PutUint16(body[2:], f.SecurityMode)
+				binary.LittleEndian.PutUint16(body[4:], dialect)
+				resp = smb2Response(packet, statusSuccess, body)
+			case cmdSessionSetup:
+				setups++
+				if setups > 1 {
+					resp = smb2Response(packet, statusAccessDenied, make([]byte, 8))
+					break
+				}
+				body := make([]byte, 8)
+				binary.LittleEndian.PutUint16(body[0:], 9)
+				binary.LittleEndian.PutUint16(body[4:], smb2HeaderSize+8)
+				binary.LittleEndian.PutUint16(body[6:], uint16(len(f.Challenge)))
+				resp = smb2Response(packet, statusMoreProcessingRequired, append(body, f.Challenge...))
+			default:
+				return
+			}
+		default:
+			return
+		}
+
+		frame := binary.BigEndian.AppendUint32(nil, uint32(len(resp)))
+		if _, err := conn.Write(append(frame, resp...)); err != nil {
+			return
+		}
+	}
+}
+
+// pick escolhe o maior dialeto pedido no NEGOTIATE que o servidor aceita.
+func (f *fakeServer) pick(body []byte) (uint16, bool) {
+	if len(body) < 36 {
+		return 0, false
+	}
+	count := int(binary.LittleEndian.Uint16(body[2:]))
+	var chosen uint16
+	for i := 0; i < count && 36+2*i+2 <= len(body); i++ {
+		requested := binary.LittleEndian.Uint16(body[36+2*i:])
+		for _, d := range f.Dialects {
+			if d == requested && d > chosen {
+				chosen = d
+			}
+		}
+	}
+	return chosen, chosen != 0
+}
+
+// smb1Response monta a resposta ao NEGOTIATE SMB1 com o WordCount e o SecurityMode configurados.
+func (f *fakeServer) smb1Response() []byte {
+	resp := make([]byte, 32)
+	copy(resp, "\xffSMB")
+	resp[4] = 0x72
+	resp = append(resp, f.SMB1Words)
+	words := make([]byte, 2*int(f.SMB1Words))
+	if len(words) >= 3 {
+		words[2] = f.SMB1Mode // DialectIndex 0 e SecurityMode
+	}
+	resp = append(resp, words...)
+	return append(resp, 0, 0) // ByteCount
+}
+
+// smb1SessionSetupResponse responde ao SESSION_SETUP_ANDX com o CHALLENGE configurado, depois de
+// conferir a segurança estendida; sem CHALLENGE, recusa a sessão.
+func (f *fakeServer) smb1SessionSetupResponse(request []byte) []byte {
+	resp := make([]byte, 32)
+	copy(resp, "\xffSMB")
+	resp[4] = smb1ComSessionSetupAndX
+	flags2 := binary.LittleEndian.Uint16(request[10:])
+	if f.Challenge == nil || len(request) < 33+24 || request[32] != 12 || flags2&0x0800 == 0 ||
+		binary.LittleEndian.Uint32(request[33+20:])&smb1CapExtendedSecurity == 0 {
+		binary.LittleEndian.PutUint32(resp[5:], statusAccessDenied)
+		return append(resp, 0, 0, 0)
+	}
+	binary.LittleEndian.PutUint32(resp[5:], statusMoreProcessingRequired)
+	resp = append(resp, 4, 0xff, 0, 0, 0, 0, 0) // WordCount, AndX, Action
+	resp = binary.LittleEndian.AppendUint16(resp, uint16(len(f.Challenge)))
+	data := append([]byte{}, f.Challenge...)
+	if (len(resp)+2+len(data))%2 != 0 {
+		data = append(data, 0)
+	}
+	data = append(data, encodeUTF16(f.NativeOS+"\x00Windows Server 2003 5.2\x00")...)
+	resp = binary.LittleEndian.AppendUint16(resp, uint16(len(data)))
+	return append(resp, data...)
+}
+
+// smb2Response monta uma resposta SMB2 ao pedido request.
+func smb2Response(request []byte, status uint32, body []byte) []byte {
+	hdr := make([]byte, smb2HeaderSize)
+	copy(hdr, request[:smb2HeaderSize])
+	binary.LittleEndian.PutUint32(hdr[8:], status)
+	binary.LittleEndian.PutUint32(hdr[16:], 0x00000001) // SERVER_TO_REDIR
+	binary.LittleEndian.PutUint64(hdr[40:], 0x1122)     // SessionId
+	return append(hdr, body...)
+}
+
+// challengeMessage monta um CHALLENGE NTLM com versão e os nomes do host no TargetInfo.
+func challengeMessage(major, minor uint8, build uint16, pairs map[uint16]string) []byte {
+	const headerLen = 56
+	var info []byte
+	for _, id := range []uint16{avNbComputerName, avNbDomainName, avDNSComputerName, avDNSDomainName, avDNSTreeName} {
+		if value, ok := pairs[id]; ok {
+			encoded := encodeUTF16(value)
+			info = binary.LittleEndian.AppendUint16(info, id)
+			info = binary.LittleEndian.AppendUint16(info, uint16(len(encoded)))
+			info = append(info, encoded...)
+		}
+	}
+	info = append(info, 0, 0, 0, 0) // MsvAvEOL
+
+	msg := make([]byte, headerLen)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 2)
+	binary.LittleEndian.PutUint32(msg[20:], ntlmNegotiateVersion|ntlmNegotiateTargetInfo|ntlmNegotiateUnicode)
+	binary.LittleEndian.PutUint16(msg[40:], uint16(len(info)))
+	binary.LittleEndian.PutUint16(msg[42:], uint16(len(info)))
+	binary.LittleEndian.PutUint32(msg[44:], headerLen)
+	msg[48], msg[49] = major, minor
+	binary.LittleEndian.PutUint16(msg[50:], build)
+	return append(msg, info...)
+}
+
+func TestScanSMB1Only(t *testing.T) {
+	tests := []struct {
+		name         string
+		words        byte
+		mode         byte
+		wantUnknown  bool
+		wantRequired bool
+		wantFinding  bool
+	}{
+		{name: "signing required", words: 17, mode: 0x0f, wantRequired: true},
+		{name: "signing enabled", words: 17, mode: 0x07, wantFinding: true},
+		{name: "signing disabled", words: 17, mode: 0x03, wantFinding: true},
+		{name: "no security mode", words: 1, wantUnknown: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := (&fakeServer{SMB1: true, SMB1Words: tt.words, SMB1Mode: tt.mode}).start(t)
+			sc := NewScanner()
+			sc.Timeout = 2 * time.Second
+
+			info, err := sc.Scan(context.Background(), "127.0.0.1", port)
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if !info.SMBv1 || len(info.Dialects) != 1 || info.Dialects[0] != "NT LM 0.12" {
+				t.Fatalf("SMBv1 = %v, Dialects = %v", info.SMBv1, info.Dialects)
+			}
+			if info.SigningUnknown != tt.wantUnknown || info.SigningRequired != tt.wantRequired {
+				t.Errorf("SigningUnknown = %v, SigningRequired = %v", info.SigningUnknown, info.SigningRequired)
+			}
+			if info.SigningNotRequired() != tt.wantFinding {
+				t.Errorf("SigningNotRequired() = %v, want %v", info.SigningNotRequired(), tt.wantFinding)
+			}
+
+			signing := false
+			for _, f := range Findings([]Info{*info}) {
+				if f.Title == "SMB signing not required" {
+					signing = true
+				}
+			}
+			if signing != tt.wantFinding {
+				t.Errorf("signing finding = %v, want %v", signing, tt.wantFinding)
+			}
+		})
+	}
+}
+
+func TestScanSMB1Challenge(t *testing.T) {
+	names := map[uint16]string{avNbComputerName: "FS01", avNbDomainName: "CORP", avDNSDomainName: "corp.local"}
+	// O Windows Server 2003 não envia a Version no CHALLENGE.
+	noVersion := challengeMessage(0, 0, 0, names)
+	binary.LittleEndian.PutUint32(noVersion[20:], binary.LittleEndian.Uint32(noVersion[20:])&^ntlmNegotiateVersion)
+	tests := []struct {
+		name      string
+		challenge []byte
+		nativeOS  string
+		wantOS    string
+		wantVer   string
+	}{
+		{name: "version in challenge", challenge: challengeMessage(6, 1, 7601, names), nativeOS: "Windows 7 Professional 7601 Service Pack 1", wantOS: "Windows 7 / Server 2008 R2", wantVer: "6.1.7601"},
+		{name: "native os", challenge: spnegoInit(noVersion), nativeOS: "Windows Server 2003 3790 Service Pack 2", wantOS: "Windows Server 2003 3790 Service Pack 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &fakeServer{SMB1: true, SMB1Words: 17, SMB1Mode: 0x03, Challenge: tt.challenge, NativeOS: tt.nativeOS}
+			sc := NewScanner()
+			sc.Timeout = 2 * time.Second
+
+			info, err := sc.Scan(context.Background(), "127.0.0.1", server.start(t))
+			if err != nil {
+				t.Fatalf("Scan: %v", err)
+			}
+			if !info.SMBv1 || !info.SigningNotRequired() {
+				t.Errorf("SMBv1 = %v, SigningNotRequired() = %v", info.SMBv1, info.SigningNotRequired())
+			}
+			if info.OS != tt.wantOS || info.OSVersion != tt.wantVer {
+				t.Errorf("OS = %q, OSVersion = %q", info.OS, info.OSVersion)
+			}
+			if info.NetBIOSName != "FS01" || info.NetBIOSDomain != "CORP" || info.DNSDomain != "corp.local" {
+				t.Errorf("NTLM names = %q %q %q", info.NetBIOSName, info.NetBIOSDomain, info.DNSDomain)
+			}
+		})
+	}
+}
+
+func TestParseSMB1SessionSetup(t *testing.T) {
+	server := &fakeServer{Challenge: []byte("NTLMSSP\x00blob"), NativeOS: "Windows 5.1"}
+	request := append(smb1Header(smb1ComSessionSetupAndX), 12)
+	request = append(request, make([]byte, 24)...)
+	binary.LittleEndian.PutUint32(request[33+20:], smb1CapExtendedSecurity)
+	valid := server.smb1SessionSetupResponse(request)
+
+	token, nativeOS, err := parseSMB1SessionSetup(valid)
+	if err != nil || string(token) != "NTLMSSP\x00blob" || nativeOS != "Windows 5.1" {
+		t.Fatalf("got %q, %q, %v", token, nativeOS, err)
+	}
+
+	badLength := append([]byte{}, valid...)
+	binary.LittleEndian.PutUint16(badLength[39:], 0xffff)
+	inputs := map[string][]byte{
+		"empty":          nil,
+		"truncated":      valid[:40],
+		"blob too long":  badLength,
+		"access denied":  (&fakeServer{}).smb1SessionSetupResponse(request),
+		"negotiate":      (&fakeServer{SMB1Words: 17}).smb1Response(),
+		"truncated data": valid[:len(valid)-10],
+	}
+	for name, resp := range inputs {
+		t.Run(name, func(t *testing.T) {
+			if token, _, err := parseSMB1SessionSetup(resp); err == nil {
+				t.Errorf("accepted token %q", token)
+			}
+		})
+	}
+}
+
+func TestScanSMB2(t *testing.T) {
+	server := &fakeServer{
+		Dialects:     []uint16{0x0202, 0x0210, 0x0300},
+		SecurityMode: smb2NegotiateSigningEnabled,
+		Challenge: challengeMessage(10, 0, 17763, map[uint16]string{
+			avNbComputerName:  "DC01",
+			avNbDomainName:    "CORP",
+			avDNSComputerName: "dc01.corp.local",
+			avDNSDomainName:   "corp.local",
+			avDNSTreeName:     "corp.local",
+		}),
+	}
+	port := server.start(t)
+	sc := NewScanner()
+	sc.Timeout = 2 * time.Second
+
+	info, err := sc.Scan(context.Background(), "127.0.0.1", port)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if info.SMBv1 {
+		t.Error("SMBv1 reported for a server without SMB1")
+	}
+	if got := info.Dialects; len(got) != 3 || got[0] != "2.0.2" || got[2] != "3.0" {
+		t.Errorf("Dialects = %v", got)
+	}
+	if !info.SigningEnabled || info.SigningRequired || info.SigningUnknown || !info.SigningNotRequired() {
+		t.Errorf("signing: enabled %v, required %v, unknown %v", info.SigningEnabled, info.SigningRequired, info.SigningUnknown)
+	}
+	if info.NetBIOSName != "DC01" || info.NetBIOSDomain != "CORP" || info.DNSHostname != "dc01.corp.local" || info.DNSForest != "corp.local" {
+		t.Errorf("NTLM names = %q %q %q %q", info.NetBIOSName, info.NetBIOSDomain, info.DNSHostname, info.DNSForest)
+	}
+	if info.OSVersion != "10.0.17763" || info.OS == "" {
+		t.Errorf("OS = %q, OSVersion = %q", info.OS, info.OSVersion)
+	}
+	if info.NullSession || info.ShareError != "null session denied" {
+		t.Errorf("NullSession = %v, ShareError = %q", info.NullSession, info.ShareError)
+	}
+}
+
+func TestScanSMB2SigningRequired(t *testing.T) {
+	server := &fakeServer{
+		SMB1:         true,
+		SMB1Words:    17,
+		Dialects:     []uint16{0x0210},
+		SecurityMode: smb2NegotiateSigningEnabled | smb2NegotiateSigningRequired,
+	}
+	port := server.start(t)
+	sc := NewScanner()
+	sc.Timeout = 2 * time.Second
+
+	info, err := sc.Scan(context.Background(), "127.0.0.1", port)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	// O SecurityMode do SMB1 (sem assinatura) não se sobrepõe ao do dialeto SMB2 negociado.
+	if !info.SMBv1 || !info.SigningRequired || info.SigningNotRequired() {
+		t.Errorf("SMBv1 = %v, SigningRequired = %v", info.SMBv1, info.SigningRequired)
+	}
+}
+
+func TestParseSMB1Negotiate(t *testing.T) {
+	valid := (&fakeServer{SMB1Words: 17, SMB1Mode: 0x0f}).smb1Response()
+	rejected := append([]byte{}, valid...)
+	binary.LittleEndian.PutUint16(rejected[33:], 0xffff)
+
+	tests := []struct {
+		name      string
+		resp      []byte
+		supported bool
+		known     bool
+	}{
+		{name: "nt lm 0.12", resp: valid, supported: true, known: true},
+		{name: "dialect rejected", resp: rejected},
+		{name: "core dialect", resp: (&fakeServer{SMB1Words: 1}).smb1Response(), supported: true},
+		{name: "truncated header", resp: valid[:20]},
+		{name: "truncated words", resp: valid[:35], supported: true},
+		{name: "smb2 header", resp: append([]byte("\xfeSMB"), make([]byte, 60)...)},
+		{name: "empty", resp: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSMB1Negotiate(tt.resp)
+			if got.Supported != tt.supported || got.ModeKnown != tt.known {
+				t.Errorf("got %+v, want supported %v, known %v", got, tt.supported, tt.known)
+			}
+			if tt.known && got.SecurityMode != 0x0f {
+				t.Errorf("SecurityMode = %#x", got.SecurityMode)
+			}
+		})
+	}
+}
+
+func TestParseNTLMChallengeMalformed(t *testing.T) {
+	valid := challengeMessage(6, 1, 7601, map[uint16]string{avNbComputerName: "HOST"})
+	badOffset := append([]byte{}, valid...)
+	binary.LittleEndian.PutUint32(badOffset[44:], 0xfffffff0)
+	badPair := append([]byte{}, valid[:56]...)
+	badPair = append(badPair, 0x01, 0x00, 0xff, 0x00, 'H', 0)
+	binary.LittleEndian.PutUint16(badPair[40:], 6)
+
+	inputs := map[string][]byte{
+		"empty":             nil,
+		"signature only":    ntlmSignature,
+		"negotiate message": ntlmNegotiateMessage(),
+		"truncated":         valid[:47],
+		"truncated version": valid[:52],
+		"target info out":   badOffset,
+		"av pair too long":  badPair,
+	}
+	for name, token := range inputs {
+		t.Run(name, func(t *testing.T) {
+			challenge, err := parseNTLMChallenge(token)
+			if err == nil && challenge.NetBIOSComputer != "" {
+				t.Errorf("NetBIOSComputer = %q from malformed input", challenge.NetBIOSComputer)
+			}
+		})
+	}
+
+	challenge, err := parseNTLMChallenge(append([]byte{0x60, 0x10}, valid...))
+	if err != nil {
+		t.Fatalf("parseNTLMChallenge: %v", err)
+	}
+	if challenge.NetBIOSComputer != "HOST" || challenge.OSVersion() != "6.1.7601" {
+		t.Errorf("got %q %q", challenge.NetBIOSComputer, challenge.OSVersion())
+	}
+}
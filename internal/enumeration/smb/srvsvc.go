@@ -0,0 +1,249 @@
+package smb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Tipos de PDU DCE/RPC utilizados.
+const (
+	rpcPtypeRequest   byte = 0
+	rpcPtypeResponse  byte = 2
+	rpcPtypeFault     byte = 3
+	rpcPtypeBind      byte = 11
+	rpcPtypeBindAck   byte = 12
+	rpcFlagLastFrag   byte = 0x02
+	rpcMaxFrag             = 4280
+	opNetShareEnumAll      = 15
+)
+
+var (
+	// srvsvcSyntax: 4b324fc8-1670-01d3-1278-5a47bf6ee188 v3.0
+	srvsvcSyntax = []byte{
+		0xc8, 0x4f, 0x32, 0x4b, 0x70, 0x16, 0xd3, 0x01, 0x12, 0x78, 0x5a, 0x47, 0xbf, 0x6e, 0xe1, 0x88,
+		0x03, 0x00, 0x00, 0x00,
+	}
+	// ndrSyntax: 8a885d04-1ceb-11c9-9fe8-08002b104860 v2
+	ndrSyntax = []byte{
+		0x04, 0x5d, 0x88, 0x8a, 0xeb, 0x1c, 0xc9, 0x11, 0x9f, 0xe8, 0x08, 0x00, 0x2b, 0x10, 0x48, 0x60,
+		0x02, 0x00, 0x00, 0x00,
+	}
+)
+
+// Share representa um compartilhamento retornado por NetShareEnumAll.
+type Share struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Remark string `json:"remark,omitempty"`
+}
+
+// rpcHeader monta o cabeçalho comum de 16 bytes de um PDU DCE/RPC.
+func rpcHeader(ptype byte, fragLen int, callID uint32) []byte {
+	hdr := []byte{5, 0, ptype, 0x03, 0x10, 0, 0, 0}
+	hdr = binary.LittleEndian.AppendUint16(hdr, uint16(fragLen))
+	hdr = binary.LittleEndian.AppendUint16(hdr, 0)
+	return binary.LittleEndian.AppendUint32(hdr, callID)
+}
+
+// rpcCall envia um PDU pelo pipe e remonta a resposta, lendo fragmentos adicionais quando necessário.
+func (s *session) rpcCall(fileID [16]byte, pdu []byte) ([]byte, byte, error) {
+	data, err := s.transceive(fileID, pdu)
+	if err != nil {
+		return nil, 0, err
+	}
+	var stub []byte
+	var ptype byte
+	for {
+		if len(data) < 16 {
+			return nil, 0, errors.New("short DCE/RPC response")
+		}
+		ptype = data[2]
+		flags := data[3]
+		fragLen := int(binary.LittleEndian.Uint16(data[8:]))
+		for len(data) < fragLen {
+			more, err := s.read(fileID, uint32(fragLen-len(data)))
+			if err != nil {
+				return nil, 0, err
+			}
+			data = append(data, more...)
+		}
+		switch ptype {
+		case rpcPtypeResponse:
+			if fragLen < 24 {
+				return nil, 0, errors.New("short DCE/RPC response fragment")
+			}
+			stub = append(stub, data[24:fragLen]...)
+		case rpcPtypeFault:
+			if fragLen >= 28 {
+				return nil, ptype, fmt.Errorf("DCE/RPC fault 0x%08x", binary.LittleEndian.Uint32(data[24:]))
+			}
+			return nil, ptype, errors.New("DCE/RPC fault")
+		default:
+			return data[:fragLen], ptype, nil
+		}
+		if flags&rpcFlagLastFrag != 0 {
+			return stub, ptype, nil
+		}
+		data, err = s.read(fileID, rpcMaxFrag)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+}
+
+// listShares executa NetShareEnumAll (nível 1) pelo pipe srvsvc, já aberto em fileID.
+func (s *session) listShares(fileID [16]byte, address string) ([]Share, error) {
+	// Bind na interface srvsvc.
+	bind := binary.LittleEndian.AppendUint16(nil, rpcMaxFrag)
+	bind = binary.LittleEndian.AppendUint16(bind, rpcMaxFrag)
+	bind = binary.LittleEndian.AppendUint32(bind, 0) // assoc_group
+	bind = append(bind, 1, 0, 0, 0)                  // n_context_elem + padding
+	bind = append(bind, 0, 0, 1, 0)                  // context id 0, 1 transfer syntax
+	bind = append(bind, srvsvcSyntax...)
+	bind = append(bind, ndrSyntax...)
+	pdu := append(rpcHeader(rpcPtypeBind, 16+len(bind), 1), bind...)
+	if _, ptype, err := s.rpcCall(fileID, pdu); err != nil {
+		return nil, fmt.Errorf("srvsvc bind failed: %w", err)
+	} else if ptype != rpcPtypeBindAck {
+		return nil, fmt.Errorf("srvsvc bind rejected (ptype %d)", ptype)
+	}
+
+	// NetShareEnumAll(ServerName, InfoStruct{Level 1, container vazio}, PreferedMaximumLength, ResumeHandle).
+	server := append(encodeUTF16(`\\`+address), 0, 0)
+	chars := uint32(len(server) / 2)
+	stub := binary.LittleEndian.AppendUint32(nil, 0x00020000) // referent ServerName
+	stub = binary.LittleEndian.AppendUint32(stub, chars)      // max count
+	stub = binary.LittleEndian.AppendUint32(stub, 0)          // offset
+	stub = binary.LittleEndian.AppendUint32(stub, chars)      // actual count
+	stub = append(stub, server...)
+	for len(stub)%4 != 0 {
+		stub = append(stub, 0)
+	}
+	stub = binary.LittleEndian.AppendUint32(stub, 1)          // Level
+	stub = binary.LittleEndian.AppendUint32(stub, 1)          // union switch
+	stub = binary.LittleEndian.AppendUint32(stub, 0x00020004) // referent container
+	stub = binary.LittleEndian.AppendUint32(stub, 0)          // EntriesRead
+	stub = binary.LittleEndian.AppendUint32(stub, 0)          // Buffer (NULL)
+	stub = binary.LittleEndian.AppendUint32(stub, 0xffffffff) // PreferedMaximumLength
+	stub = binary.LittleEndian.AppendUint32(stub, 0x00020008) // referent ResumeHandle
+	stub = binary.LittleEndian.AppendUint32(stub, 0)          // ResumeHandle
+
+	req := binary.LittleEndian.AppendUint32(nil, uint32(len(stub))) // alloc_hint
+	req = binary.LittleEndian.AppendUint16(req, 0)                  // context id
+	req = binary.LittleEndian.AppendUint16(req, opNetShareEnumAll)
+	req = append(req, stub...)
+	pdu = append(rpcHeader(rpcPtypeRequest, 16+len(req), 2), req...)
+	resp, _, err := s.rpcCall(fileID, pdu)
+	if err != nil {
+		return nil, fmt.Errorf("NetShareEnumAll failed: %w", err)
+	}
+	return parseShareEnum(resp)
+}
+
+// ndrReader lê tipos NDR alinhados em little endian.
+type ndrReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *ndrReader) uint32() uint32 {
+	r.align(4)
+	if r.err != nil || r.pos+4 > len(r.data) {
+		r.err = errors.New("truncated NDR data")
+		return 0
+	}
+	v := binary.LittleEndian.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v
+}
+
+func (r *ndrReader) align(n int) {
+	for r.pos%n != 0 {
+		r.pos++
+	}
+}
+
+// wstring lê uma string conformant varying (max, offset, actual + UTF-16LE).
+func (r *ndrReader) wstring() string {
+	r.uint32() // max count
+	r.uint32() // offset
+	actual := int(r.uint32())
+	if r.err != nil || r.pos+2*actual > len(r.data) {
+		r.err = errors.New("truncated NDR string")
+		return ""
+	}
+	s := decodeUTF16(r.data[r.pos : r.pos+2*actual])
+	r.pos += 2 * actual
+	return s
+}
+
+// parseShareEnum decodifica a resposta de NetShareEnumAll nível 1 (SHARE_INFO_1_CONTAINER).
+func parseShareEnum(stub []byte) ([]Share, error) {
+	r := &ndrReader{data: stub}
+	r.uint32() // Level
+	r.uint32() // union switch
+	if r.uint32() == 0 {
+		return nil, errors.New("empty share container")
+	}
+	count := int(r.uint32())
+	if r.uint32() == 0 || count == 0 {
+		return nil, r.err
+	}
+	if max := int(r.uint32()); max < count {
+		return nil, errors.New("invalid share array")
+	}
+
+	type entry struct {
+		namePtr, remarkPtr uint32
+		shareType          uint32
+	}
+	entries := make([]entry, count)
+	for i := range entries {
+		entries[i].namePtr = r.uint32()
+		entries[i].shareType = r.uint32()
+		entries[i].remarkPtr = r.uint32()
+	}
+	shares := make([]Share, 0, count)
+	for _, e := range entries {
+		share := Share{Type: shareTypeName(e.shareType)}
+		if e.namePtr != 0 {
+			share.Name = r.wstring()
+		}
+		if e.remarkPtr != 0 {
+			share.Remark = r.wstring()
+		}
+		if r.err != nil {
+			return shares, r.err
+		}
+		shares = append(shares, share)
+	}
+
+	// Ao final da resposta vem o WERROR da chamada.
+	if len(stub) >= 4 {
+		if werr := binary.LittleEndian.Uint32(stub[len(stub)-4:]); werr != 0 {
+			return shares, fmt.Errorf("NetShareEnumAll returned error 0x%08x", werr)
+		}
+	}
+	return shares, nil
+}
+
+// shareTypeName traduz o campo shi1_type.
+func shareTypeName(t uint32) string {
+	name := "unknown"
+	switch t & 0x0fffffff {
+	case 0:
+		name = "disk"
+	case 1:
+		name = "printer"
+	case 2:
+		name = "device"
+	case 3:
+		name = "ipc"
+	}
+	if t&0x80000000 != 0 {
+		name += " (special)"
+	}
+	return name
+}
@@ -0,0 +1,34 @@
+package snmp
+
+import (
+	"context"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+)
+
+// Module expõe o Scanner como enumeration.Enumerator.
+type Module struct {
+	Scanner *Scanner
+}
+
+// NewModule é a factory que cria o módulo a partir de um Scanner.
+func NewModule(sc *Scanner) *Module {
+	return &Module{Scanner: sc}
+}
+
+// Name retorna o nome do módulo.
+func (m *Module) Name() string { return ModuleName }
+
+// Selector seleciona a porta UDP do agente SNMP (ou todos os hosts, com AllHosts).
+func (m *Module) Selector() enumeration.Selector {
+	return enumeration.Selector{UDPPorts: []int{int(m.Scanner.Port)}, Host: m.Scanner.AllHosts}
+}
+
+// Enumerate testa as community strings e percorre as tabelas MIB-II do host.
+func (m *Module) Enumerate(ctx context.Context, target enumeration.Target) (*enumeration.Result, error) {
+	info, err := m.Scanner.Enumerate(ctx, target.Host.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &enumeration.Result{Enrichment: info, Findings: m.Scanner.Findings([]Info{*info})}, nil
+}
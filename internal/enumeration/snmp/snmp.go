@@ -0,0 +1,484 @@
+package snmp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/gosnmp/gosnmp"
+)
+
+// ModuleName é o nome do módulo usado em logs e nos resultados.
+const ModuleName = "snmp"
+
+// DefaultCommunities são as community strings testadas quando nenhuma lista é informada.
+var DefaultCommunities = []string{"public", "private"}
+
+// OIDs consultados (MIB-II).
+const (
+	oidSysDescr      = ".1.3.6.1.2.1.1.1.0"
+	oidSystem        = ".1.3.6.1.2.1.1"
+	oidIfTable       = ".1.3.6.1.2.1.2.2.1"
+	oidIPAddrTable   = ".1.3.6.1.2.1.4.20.1"
+	oidIPRouteTable  = ".1.3.6.1.2.1.4.21.1"
+	oidIPNetToMedia  = ".1.3.6.1.2.1.4.22.1"
+	maxRowsPerWalk   = 5000
+	defaultSNMPPort  = 161
+	defaultSNMPRetry = 1
+)
+
+// System contém o grupo system da MIB-II.
+type System struct {
+	Descr    string `json:"descr,omitempty"`
+	ObjectID string `json:"object_id,omitempty"`
+	UpTime   string `json:"uptime,omitempty"`
+	Contact  string `json:"contact,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Location string `json:"location,omitempty"`
+}
+
+// Interface é uma linha da ifTable, com os endereços IP associados.
+type Interface struct {
+	Index     int      `json:"index"`
+	Descr     string   `json:"descr,omitempty"`
+	Type      int      `json:"type,omitempty"`
+	MTU       int      `json:"mtu,omitempty"`
+	Speed     uint64   `json:"speed,omitempty"`
+	MAC       string   `json:"mac,omitempty"`
+	AdminUp   bool     `json:"admin_up"`
+	OperUp    bool     `json:"oper_up"`
+	Addresses []string `json:"addresses,omitempty"` // Endereços em notação CIDR
+}
+
+// ARPEntry é uma linha da ipNetToMediaTable.
+type ARPEntry struct {
+	IfIndex int    `json:"if_index"`
+	Address string `json:"address"`
+	MAC     string `json:"mac"`
+}
+
+// Route é uma linha da ipRouteTable.
+type Route struct {
+	Destination string `json:"destination"` // Rede de destino em notação CIDR
+	NextHop     string `json:"next_hop"`
+	IfIndex     int    `json:"if_index"`
+}
+
+// Info contém o resultado da enumeração SNMP de um host.
+type Info struct {
+	Address    string      `json:"address"`
+	Community  string      `json:"community"`
+	Version    string      `json:"version"`
+	System     System      `json:"system"`
+	Interfaces []Interface `json:"interfaces,omitempty"`
+	ARP        []ARPEntry  `json:"arp,omitempty"`
+	Routes     []Route     `json:"routes,omitempty"`
+	Subnets    []string    `json:"subnets,omitempty"` // Redes aprendidas nas interfaces e rotas
+}
+
+// Suggestion é uma rede aprendida via SNMP sugerida como alvo adicional.
+type Suggestion struct {
+	Subnet  string `json:"subnet"`
+	Source  string `json:"source"` // Host que revelou a rede
+	InScope bool   `json:"in_scope"`
+}
+
+// Scanner testa community strings e percorre as tabelas MIB-II dos hosts que responderem.
+type Scanner struct {
+	Communities []string      // Community strings testadas, em ordem
+	Port        uint16        // Porta UDP do agente SNMP
+	Timeout     time.Duration // Timeout por requisição
+	Retries     int           // Retransmissões por requisição
+	Threads     int           // Quantidade de hosts consultados simultaneamente
+	AllHosts    bool          // Testa todos os hosts, não apenas os com 161/udp aberta ou open|filtered
+	Scope       []*net.IPNet  // Escopo autorizado para as redes sugeridas
+}
+
+// NewScanner é a factory que cria um Scanner com valores padrão.
+func NewScanner() *Scanner {
+	return &Scanner{
+		Communities: DefaultCommunities,
+		Port:        defaultSNMPPort,
+		Timeout:     2 * time.Second,
+		Retries:     defaultSNMPRetry,
+		Threads:     10,
+	}
+}
+
+// client cria um cliente gosnmp para o host, community e versão informados.
+func (sc *Scanner) client(ctx context.Context, address, community string, version gosnmp.SnmpVersion) *gosnmp.GoSNMP {
+	limiter := util.LimiterFrom(ctx)
+	return &gosnmp.GoSNMP{
+		Context:            ctx,
+		Target:             address,
+		Port:               sc.Port,
+		Transport:          "udp",
+		Community:          community,
+		Version:            version,
+		Timeout:            sc.Timeout,
+		Retries:            sc.Retries,
+		MaxOids:            gosnmp.MaxOids,
+		MaxRepetitions:     25,
+		ExponentialTimeout: false,
+		// Cada requisição respeita o limite de pacotes por segundo. Se ctx terminar durante a
+		// espera, o deadline vencido faz a escrita falhar e o gosnmp encerra pelo Context cancelado,
+		// sem enviar o pacote.
+		PreSend: func(g *gosnmp.GoSNMP) {
+			if err := limiter.Wait(ctx); err != nil {
+				g.Conn.SetDeadline(time.Now())
+			}
+		},
+	}
+}
+
+// Login testa as community strings (v2c e depois v1) e retorna o primeiro cliente aceito.
+func (sc *Scanner) Login(ctx context.Context, address string) (*gosnmp.GoSNMP, error) {
+	for _, community := range sc.Communities {
+		for _, version := range []gosnmp.SnmpVersion{gosnmp.Version2c, gosnmp.Version1} {
+			client := sc.client(ctx, address, community, version)
+			if err := client.Connect(); err != nil {
+				return nil, fmt.Errorf("failed to open UDP socket to %s: %w", address, err)
+			}
+			packet, err := client.Get([]string{oidSysDescr})
+			if err == nil && packet.Error == gosnmp.NoError && len(packet.Variables) > 0 &&
+				packet.Variables[0].Type != gosnmp.NoSuchObject && packet.Variables[0].Type != gosnmp.NoSuchInstance {
+				return client, nil
+			}
+			client.Conn.Close()
+		}
+	}
+	return nil, fmt.Errorf("no valid community string for %s", address)
+}
+
+// Enumerate autentica com uma community válida e percorre system, interfaces, ARP e rotas.
+func (sc *Scanner) Enumerate(ctx context.Context, address string) (*Info, error) {
+	client, err := sc.Login(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Conn.Close()
+
+	info := &Info{Address: address, Community: client.Community, Version: client.Version.String()}
+	walk := func(root string) []gosnmp.SnmpPDU {
+		var pdus []gosnmp.SnmpPDU
+		var err error
+		if client.Version == gosnmp.Version1 {
+			pdus, err = client.WalkAll(root)
+		} else {
+			pdus, err = client.BulkWalkAll(root)
+		}
+		if err != nil {
+			util.ModuleLogger(ModuleName).Debug().Err(err).Msgf("SNMP walk %s failed on %s", root, address)
+		}
+		if len(pdus) > maxRowsPerWalk {
+			pdus = pdus[:maxRowsPerWalk]
+		}
+		return pdus
+	}
+
+	for _, pdu := range walk(oidSystem) {
+		switch strings.TrimPrefix(pdu.Name, oidSystem) {
+		case ".1.0":
+			info.System.Descr = pduString(pdu)
+		case ".2.0":
+			info.System.ObjectID = pduString(pdu)
+		case ".3.0":
+			info.System.UpTime = (time.Duration(gosnmp.ToBigInt(pdu.Value).Int64()) * 10 * time.Millisecond).String()
+		case ".4.0":
+			info.System.Contact = pduString(pdu)
+		case ".5.0":
+			info.System.Name = pduString(pdu)
+		case ".6.0":
+			info.System.Location = pduString(pdu)
+		}
+	}
+
+	// ifTable: coluna.índice
+	ifaces := make(map[int]*Interface)
+	for _, pdu := range walk(oidIfTable) {
+		column, index, ok := splitColumn(pdu.Name, oidIfTable)
+		if !ok {
+			continue
+		}
+		idx, _ := strconv.Atoi(index)
+		iface, exists := ifaces[idx]
+		if !exists {
+			iface = &Interface{Index: idx}
+			ifaces[idx] = iface
+		}
+		switch column {
+		case 2:
+			iface.Descr = pduString(pdu)
+		case 3:
+			iface.Type = int(gosnmp.ToBigInt(pdu.Value).Int64())
+		case 4:
+			iface.MTU = int(gosnmp.ToBigInt(pdu.Value).Int64())
+		case 5:
+			iface.Speed = gosnmp.ToBigInt(pdu.Value).Uint64()
+		case 6:
+			iface.MAC = pduMAC(pdu)
+		case 7:
+			iface.AdminUp = gosnmp.ToBigInt(pdu.Value).Int64() == 1
+		case 8:
+			iface.OperUp = gosnmp.ToBigInt(pdu.Value).Int64() == 1
+		}
+	}
+
+	// ipAddrTable: coluna.endereço
+	subnets := make(map[string]bool)
+	addrIf := make(map[string]int)
+	addrMask := make(map[string]string)
+	for _, pdu := range walk(oidIPAddrTable) {
+		column, addr, ok := splitColumn(pdu.Name, oidIPAddrTable)
+		if !ok {
+			continue
+		}
+		switch column {
+		case 2:
+			addrIf[addr] = int(gosnmp.ToBigInt(pdu.Value).Int64())
+		case 3:
+			addrMask[addr] = pduString(pdu)
+		}
+	}
+	for addr, mask := range addrMask {
+		cidr, network := toCIDR(addr, mask)
+		if cidr == "" {
+			continue
+		}
+		if iface, ok := ifaces[addrIf[addr]]; ok {
+			iface.Addresses = append(iface.Addresses, cidr)
+		}
+		if !strings.HasPrefix(addr, "127.") {
+			subnets[network] = true
+		}
+	}
+
+	// ipNetToMediaTable: coluna.ifIndex.endereço
+	arp := make(map[string]*ARPEntry)
+	for _, pdu := range walk(oidIPNetToMedia) {
+		column, index, ok := splitColumn(pdu.Name, oidIPNetToMedia)
+		if !ok {
+			continue
+		}
+		entry, exists := arp[index]
+		if !exists {
+			entry = &ARPEntry{}
+			arp[index] = entry
+		}
+		switch column {
+		case 1:
+			entry.IfIndex = int(gosnmp.ToBigInt(pdu.Value).Int64())
+		case 2:
+			entry.MAC = pduMAC(pdu)
+		case 3:
+			entry.Address = pduString(pdu)
+		}
+	}
+
+	// ipRouteTable: coluna.destino
+	routes := make(map[string]*Route)
+	routeMask := make(map[string]string)
+	for _, pdu := range walk(oidIPRouteTable) {
+		column, dest, ok := splitColumn(pdu.Name, oidIPRouteTable)
+		if !ok {
+			continue
+		}
+		route, exists := routes[dest]
+		if !exists {
+			route = &Route{}
+			routes[dest] = route
+		}
+		switch column {
+		case 2:
+			route.IfIndex = int(gosnmp.ToBigInt(pdu.Value).Int64())
+		case 7:
+			route.NextHop = pduString(pdu)
+		case 11:
+			routeMask[dest] = pduString(pdu)
+		}
+	}
+	for dest, route := range routes {
+		_, network := toCIDR(dest, routeMask[dest])
+		route.Destination = network
+		// Rotas padrão, de host e de loopback não indicam redes novas.
+		if network != "" && !strings.HasSuffix(network, "/0") && !strings.HasSuffix(network, "/32") && !strings.HasPrefix(network, "127.") {
+			subnets[network] = true
+		}
+	}
+
+	for _, idx := range sortedKeys(ifaces) {
+		info.Interfaces = append(info.Interfaces, *ifaces[idx])
+	}
+	for _, entry := range arp {
+		if entry.Address != "" {
+			info.ARP = append(info.ARP, *entry)
+		}
+	}
+	sort.Slice(info.ARP, func(a, b int) bool { return info.ARP[a].Address < info.ARP[b].Address })
+	for _, route := range routes {
+		if route.Destination != "" {
+			info.Routes = append(info.Routes, *route)
+		}
+	}
+	sort.Slice(info.Routes, func(a, b int) bool { return info.Routes[a].Destination < info.Routes[b].Destination })
+	for subnet := range subnets {
+		info.Subnets = append(info.Subnets, subnet)
+	}
+	sort.Strings(info.Subnets)
+	return info, nil
+}
+
+// Run enumera os hosts com 161/udp aberta (ou open|filtered), anexa os resultados e retorna
+// as redes aprendidas que ainda não fazem parte dos alvos, marcadas conforme o escopo.
+func (sc *Scanner) Run(ctx context.Context, hosts []results.Host) ([]Info, []Suggestion) {
+	threads := sc.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	batch := util.NewProbeBatch(ctx, ModuleName, threads)
+	for i := range hosts {
+		if sc.AllHosts || hosts[i].HasPort("udp", int(sc.Port)) {
+			batch.Add(hosts[i].Address, "udp", int(sc.Port))
+		}
+	}
+	batch.Start()
+	defer batch.Finish()
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found []Info
+		sem   = make(chan struct{}, threads)
+	)
+	for i := range hosts {
+		if !sc.AllHosts && !hosts[i].HasPort("udp", int(sc.Port)) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		util.WaitWindow(ctx)
+		go func(host *results.Host) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			info, err := sc.Enumerate(ctx, host.Address)
+			if err != nil {
+				util.ModuleLogger(ModuleName).Debug().Err(err).Msg("SNMP enumeration failed")
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			host.SetEnrichment(ModuleName, info)
+			found = append(found, *info)
+		}(&hosts[i])
+	}
+	wg.Wait()
+
+	// Redes que já contêm algum host conhecido não são novidade.
+	seen := make(map[string]bool)
+	var suggestions []Suggestion
+	for _, info := range found {
+		for _, subnet := range info.Subnets {
+			if seen[subnet] || subnetHasHost(subnet, hosts) {
+				continue
+			}
+			seen[subnet] = true
+			suggestions = append(suggestions, Suggestion{
+				Subnet:  subnet,
+				Source:  info.Address,
+				InScope: util.SubnetInScope(subnet, sc.Scope),
+			})
+		}
+	}
+	return found, suggestions
+}
+
+// splitColumn separa "<root>.<coluna>.<índice>" em coluna e índice.
+func splitColumn(name, root string) (int, string, bool) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(name, root), ".")
+	if rest == name {
+		return 0, "", false
+	}
+	parts := strings.SplitN(rest, ".", 2)
+	if len(parts) != 2 {
+		return 0, "", false
+	}
+	column, err := strconv.Atoi(parts[0])
+	return column, parts[1], err == nil
+}
+
+// toCIDR converte endereço e máscara em "endereço/bits" e "rede/bits".
+func toCIDR(addr, mask string) (string, string) {
+	ip := net.ParseIP(addr).To4()
+	m := net.ParseIP(mask).To4()
+	if ip == nil || m == nil {
+		return "", ""
+	}
+	ipMask := net.IPMask(m)
+	bits, _ := ipMask.Size()
+	return fmt.Sprintf("%s/%d", ip, bits), fmt.Sprintf("%s/%d", ip.Mask(ipMask), bits)
+}
+
+// subnetHasHost indica se algum host conhecido pertence à rede.
+func subnetHasHost(subnet string, hosts []results.Host) bool {
+	_, network, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return false
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h.Address); ip != nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// pduString converte o valor de um PDU em texto.
+func pduString(pdu gosnmp.SnmpPDU) string {
+	switch v := pdu.Value.(type) {
+	case []byte:
+		return strings.TrimRight(string(v), "\x00")
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(pdu.Value)
+}
+
+// pduMAC formata um OCTET STRING de 6 bytes como endereço MAC.
+func pduMAC(pdu gosnmp.SnmpPDU) string {
+	if b, ok := pdu.Value.([]byte); ok && len(b) == 6 {
+		return net.HardwareAddr(b).String()
+	}
+	return ""
+}
+
+// sortedKeys retorna os índices de interface em ordem.
+func sortedKeys(m map[int]*Interface) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// Findings converte as community strings aceitas em achados.
+func (sc *Scanner) Findings(infos []Info) []findings.Finding {
+	var list []findings.Finding
+	for _, info := range infos {
+		list = append(list, findings.New(ModuleName, findings.Slug("snmp-community", info.Community), "SNMP community string accepted: "+info.Community, findings.SeverityHigh, info.Address, int(sc.Port), "udp").
+			WithEvidence(info.System.Descr).
+			WithDetails("version", info.Version, "sys_name", info.System.Name))
+	}
+	return list
+}
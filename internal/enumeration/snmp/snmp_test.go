@@ -0,0 +1,162 @@
+package snmp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/gosnmp/gosnmp"
+)
+
+func TestSplitColumn(t *testing.T) {
+	const ifEntry, arpEntry = ".1.3.6.1.2.1.2.2.1", ".1.3.6.1.2.1.4.22.1"
+	tests := []struct {
+		name, root string
+		column     int
+		index      string
+		ok         bool
+	}{
+		{ifEntry + ".2.3", ifEntry, 2, "3", true},
+		{arpEntry + ".2.5.10.0.0.1", arpEntry, 2, "5.10.0.0.1", true},
+		{ifEntry + ".7", ifEntry, 0, "", false},
+		{ifEntry + ".x.3", ifEntry, 0, "3", false},
+	}
+	for _, tt := range tests {
+		column, index, ok := splitColumn(tt.name, tt.root)
+		if column != tt.column || index != tt.index || ok != tt.ok {
+			t.Errorf("splitColumn(%q) = %d, %q, %v", tt.name, column, index, ok)
+		}
+	}
+}
+
+func TestToCIDR(t *testing.T) {
+	tests := []struct {
+		addr, mask, cidr, network string
+	}{
+		{"10.0.5.20", "255.255.255.0", "10.0.5.20/24", "10.0.5.0/24"},
+		{"172.16.3.1", "255.255.240.0", "172.16.3.1/20", "172.16.0.0/20"},
+		{"10.0.5.20", "", "", ""},
+		{"fe80::1", "255.255.255.0", "", ""},
+	}
+	for _, tt := range tests {
+		if cidr, network := toCIDR(tt.addr, tt.mask); cidr != tt.cidr || network != tt.network {
+			t.Errorf("toCIDR(%q, %q) = %q, %q", tt.addr, tt.mask, cidr, network)
+		}
+	}
+}
+
+func TestSubnetHasHost(t *testing.T) {
+	hosts := []results.Host{{Address: "10.0.0.5"}, {Address: "192.168.1.10"}}
+	for subnet, want := range map[string]bool{
+		"10.0.0.0/24":    true,
+		"192.168.0.0/16": true,
+		"172.16.0.0/12":  false,
+		"invalid":        false,
+	} {
+		if got := subnetHasHost(subnet, hosts); got != want {
+			t.Errorf("subnetHasHost(%q) = %v, want %v", subnet, got, want)
+		}
+	}
+}
+
+func TestPDUValues(t *testing.T) {
+	for _, tt := range []struct {
+		value interface{}
+		want  string
+	}{
+		{[]byte("Linux router 5.10\x00\x00"), "Linux router 5.10"},
+		{"core-sw01", "core-sw01"},
+		{uint32(42), "42"},
+		{nil, ""},
+	} {
+		if got := pduString(gosnmp.SnmpPDU{Value: tt.value}); got != tt.want {
+			t.Errorf("pduString(%#v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+	if got := pduMAC(gosnmp.SnmpPDU{Value: []byte{0x00, 0x50, 0x56, 0xab, 0xcd, 0xef}}); got != "00:50:56:ab:cd:ef" {
+		t.Errorf("pduMAC = %q", got)
+	}
+	if got := pduMAC(gosnmp.SnmpPDU{Value: []byte{0x00, 0x50}}); got != "" {
+		t.Errorf("pduMAC of a short value = %q", got)
+	}
+}
+
+// countingAgent conta os pacotes UDP recebidos em 127.0.0.1, sem responder.
+func countingAgent(t *testing.T) (int, *atomic.Int32) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	var received atomic.Int32
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			if _, _, err := conn.ReadFrom(buf); err != nil {
+				return
+			}
+			received.Add(1)
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port, &received
+}
+
+func TestClientAbortsWhileRateLimited(t *testing.T) {
+	port, received := countingAgent(t)
+	limiter := util.NewRateLimiter(1)
+	// Cancelamento sem deadline: o gosnmp não limita a escrita pelo prazo de ctx.
+	ctx, cancel := context.WithCancel(util.WithLimiter(context.Background(), limiter))
+	defer cancel()
+	time.AfterFunc(100*time.Millisecond, cancel)
+	// Consome a vaga do segundo atual: a próxima requisição espera ~1s, além do cancelamento.
+	limiter.Wait(ctx)
+
+	sc := NewScanner()
+	sc.Port = uint16(port)
+	client := sc.client(ctx, "127.0.0.1", "public", gosnmp.Version2c)
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Conn.Close()
+
+	start := time.Now()
+	_, err := client.Get([]string{oidSysDescr})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Get = %v, want the context cancellation", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Get took %s after ctx ended", elapsed)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := received.Load(); n != 0 {
+		t.Errorf("%d packets sent after the limiter wait failed", n)
+	}
+}
+
+func TestClientUsesContextLimiter(t *testing.T) {
+	port, received := countingAgent(t)
+	sc := NewScanner()
+	sc.Port = uint16(port)
+	sc.Timeout = 50 * time.Millisecond
+	sc.Retries = 0
+
+	ctx := util.WithLimiter(context.Background(), util.NewRateLimiter(0))
+	client := sc.client(ctx, "127.0.0.1", "public", gosnmp.Version2c)
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Conn.Close()
+	if _, err := client.Get([]string{oidSysDescr}); err == nil {
+		t.Error("Get succeeded without an agent")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := received.Load(); n != 1 {
+		t.Errorf("%d packets received, want 1", n)
+	}
+}
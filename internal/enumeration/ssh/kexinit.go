@@ -0,0 +1,109 @@
+package ssh
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	msgKexInit        = 20
+	maxBannerLines    = 32
+	maxKexInitPayload = 64 * 1024
+)
+
+// clientBanner é a identificação enviada ao servidor antes do KEXINIT.
+const clientBanner = "SSH-2.0-arthxrecon\r\n"
+
+// kexInit contém as listas de algoritmos anunciadas pelo servidor no SSH_MSG_KEXINIT.
+type kexInit struct {
+	Kex         []string
+	HostKey     []string
+	CiphersC2S  []string
+	CiphersS2C  []string
+	MACsC2S     []string
+	MACsS2C     []string
+	Compression []string
+}
+
+// readServerHello lê o banner do servidor e o primeiro pacote binário (KEXINIT), ainda sem criptografia.
+func readServerHello(conn net.Conn, timeout time.Duration) (string, *kexInit, error) {
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	reader := bufio.NewReader(conn)
+
+	// O servidor pode enviar linhas antes do banner (RFC 4253, seção 4.2).
+	banner := ""
+	for i := 0; i < maxBannerLines; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read SSH banner: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "SSH-") {
+			banner = line
+			break
+		}
+	}
+	if banner == "" {
+		return "", nil, errors.New("SSH banner not found")
+	}
+	if strings.HasPrefix(banner, "SSH-1.") && !strings.HasPrefix(banner, "SSH-1.99") {
+		// Servidores somente SSHv1 não enviam KEXINIT.
+		return banner, nil, nil
+	}
+
+	if _, err := conn.Write([]byte(clientBanner)); err != nil {
+		return banner, nil, fmt.Errorf("failed to send SSH banner: %w", err)
+	}
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return banner, nil, fmt.Errorf("failed to read KEXINIT: %w", err)
+	}
+	packetLen := int(binary.BigEndian.Uint32(header))
+	paddingLen := int(header[4])
+	if packetLen < paddingLen+2 || packetLen > maxKexInitPayload {
+		return banner, nil, errors.New("invalid KEXINIT packet length")
+	}
+	rest := make([]byte, packetLen-1)
+	if _, err := io.ReadFull(reader, rest); err != nil {
+		return banner, nil, fmt.Errorf("failed to read KEXINIT: %w", err)
+	}
+	payload := rest[:packetLen-1-paddingLen]
+	if len(payload) < 17 || payload[0] != msgKexInit {
+		return banner, nil, errors.New("first SSH packet is not KEXINIT")
+	}
+
+	// Após o tipo e o cookie (16 bytes) vêm as name-lists.
+	data := payload[17:]
+	var lists [][]string
+	for i := 0; i < 8; i++ {
+		if len(data) < 4 {
+			return banner, nil, errors.New("truncated KEXINIT name-list")
+		}
+		n := int(binary.BigEndian.Uint32(data))
+		if len(data) < 4+n {
+			return banner, nil, errors.New("truncated KEXINIT name-list")
+		}
+		var names []string
+		if n > 0 {
+			names = strings.Split(string(data[4:4+n]), ",")
+		}
+		lists = append(lists, names)
+		data = data[4+n:]
+	}
+	return banner, &kexInit{
+		Kex:         lists[0],
+		HostKey:     lists[1],
+		CiphersC2S:  lists[2],
+		CiphersS2C:  lists[3],
+		MACsC2S:     lists[4],
+		MACsS2C:     lists[5],
+		Compression: lists[6],
+	}, nil
+}
@@ -0,0 +1,123 @@
+package ssh
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedServer aceita uma conexão em 127.0.0.1, envia data, encerra a escrita e descarta o que o
+// cliente enviar.
+func scriptedServer(t *testing.T, data []byte) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write(data)
+		_ = conn.(*net.TCPConn).CloseWrite()
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+	return ln.Addr().String()
+}
+
+// kexInitPacket monta um pacote binário SSH (sem criptografia) com o KEXINIT das listas informadas.
+func kexInitPacket(lists ...string) []byte {
+	payload := append([]byte{msgKexInit}, make([]byte, 16)...)
+	for _, list := range lists {
+		payload = binary.BigEndian.AppendUint32(payload, uint32(len(list)))
+		payload = append(payload, list...)
+	}
+	payload = append(payload, 0, 0, 0, 0, 0) // first_kex_packet_follows e reservado
+	return packet(payload, 4)
+}
+
+// packet envolve payload em um pacote binário com padding bytes de preenchimento.
+func packet(payload []byte, padding int) []byte {
+	out := binary.BigEndian.AppendUint32(nil, uint32(1+len(payload)+padding))
+	out = append(out, byte(padding))
+	out = append(out, payload...)
+	return append(out, make([]byte, padding)...)
+}
+
+// hello lê o banner e o KEXINIT enviados pelo servidor de teste.
+func hello(t *testing.T, data []byte) (string, *kexInit, error) {
+	t.Helper()
+	conn, err := net.Dial("tcp", scriptedServer(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	return readServerHello(conn, 2*time.Second)
+}
+
+func TestReadServerHello(t *testing.T) {
+	lists := []string{
+		"curve25519-sha256,diffie-hellman-group1-sha1", "ssh-ed25519,ssh-rsa",
+		"aes128-ctr,3des-cbc", "aes128-ctr", "hmac-sha2-256", "hmac-sha2-256,hmac-md5",
+		"none,zlib@openssh.com", "none", "", "",
+	}
+	data := []byte("Welcome\r\nauthorized use only\r\nSSH-2.0-OpenSSH_8.9p1 Ubuntu-3\r\n")
+	data = append(data, kexInitPacket(lists...)...)
+
+	banner, kex, err := hello(t, data)
+	if err != nil {
+		t.Fatalf("readServerHello: %v", err)
+	}
+	if banner != "SSH-2.0-OpenSSH_8.9p1 Ubuntu-3" || software(banner) != "OpenSSH_8.9p1 Ubuntu-3" {
+		t.Errorf("banner = %q", banner)
+	}
+	got := [][]string{kex.Kex, kex.HostKey, kex.CiphersC2S, kex.CiphersS2C, kex.MACsC2S, kex.MACsS2C, kex.Compression}
+	for i, list := range got {
+		if strings.Join(list, ",") != lists[i] {
+			t.Errorf("list %d = %v, want %s", i, list, lists[i])
+		}
+	}
+}
+
+func TestReadServerHelloSSH1(t *testing.T) {
+	banner, kex, err := hello(t, []byte("SSH-1.5-OpenSSH_3.9p1\n"))
+	if err != nil || kex != nil || banner != "SSH-1.5-OpenSSH_3.9p1" {
+		t.Errorf("got %q, %v, %v", banner, kex, err)
+	}
+}
+
+func TestReadServerHelloMalformed(t *testing.T) {
+	banner := "SSH-2.0-test\r\n"
+	full := kexInitPacket("a", "b", "c", "d", "e", "f", "g", "h", "", "")
+	notKex := packet(append([]byte{21}, make([]byte, 40)...), 4)
+	shortList := packet(append(append([]byte{msgKexInit}, make([]byte, 16)...), 0, 0, 1, 0, 'x'), 4)
+	hugeLength := binary.BigEndian.AppendUint32(nil, maxKexInitPayload+1)
+	badPadding := []byte{0, 0, 0, 8, 200}
+
+	tests := map[string][]byte{
+		"no banner":          []byte(strings.Repeat("hello\n", maxBannerLines+1)),
+		"closed before ssh":  []byte("hello\n"),
+		"truncated header":   []byte(banner + "\x00\x00"),
+		"truncated packet":   append([]byte(banner), full[:len(full)-10]...),
+		"packet too large":   append([]byte(banner), append(hugeLength, 4)...),
+		"padding too large":  append([]byte(banner), badPadding...),
+		"not kexinit":        append([]byte(banner), notKex...),
+		"short payload":      append([]byte(banner), packet([]byte{msgKexInit, 1, 2}, 4)...),
+		"name-list overflow": append([]byte(banner), shortList...),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, kex, err := hello(t, data)
+			if err == nil || kex != nil {
+				t.Errorf("got %+v, %v; want an error", kex, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package ssh
+
+import (
+	"context"
+	"net"
+	"sort"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// Module expõe o Auditor como enumeration.Enumerator.
+type Module struct {
+	Auditor *Auditor
+}
+
+// NewModule é a factory que cria o módulo a partir de um Auditor.
+func NewModule(a *Auditor) *Module {
+	return &Module{Auditor: a}
+}
+
+// Name retorna o nome do módulo.
+func (m *Module) Name() string { return ModuleName }
+
+// Selector seleciona os serviços ssh e as portas SSH padrão.
+func (m *Module) Selector() enumeration.Selector {
+	return enumeration.Selector{Ports: DefaultPorts, Services: []string{"ssh"}}
+}
+
+// Enumerate audita cada serviço SSH selecionado do host.
+func (m *Module) Enumerate(ctx context.Context, target enumeration.Target) (*enumeration.Result, error) {
+	var (
+		infos   []Info
+		lastErr error
+	)
+	for _, svc := range target.Services {
+		if svc.Protocol != "tcp" {
+			continue
+		}
+		info, err := m.Auditor.Audit(ctx, target.Host.Address, svc.Port)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		infos = append(infos, *info)
+	}
+	if len(infos) == 0 {
+		return nil, lastErr
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Port < infos[j].Port })
+
+	result := &enumeration.Result{Enrichment: infos, Findings: Findings(infos, nil)}
+	for _, info := range infos {
+		if len(info.Weaknesses) > 0 {
+			result.Tags = append(result.Tags, TagWeakAlgorithms)
+			break
+		}
+	}
+	return result, nil
+}
+
+// Finalize procura chaves de host compartilhadas entre os hosts auditados e os marca com TagSharedHostKey.
+func (m *Module) Finalize(ctx context.Context, hosts []results.Host) ([]findings.Finding, error) {
+	var all []Info
+	for i := range hosts {
+		var infos []Info
+		if hosts[i].Enrichment(ModuleName, &infos) {
+			all = append(all, infos...)
+		}
+	}
+	shared := sharedKeys(all)
+	for _, sk := range shared {
+		for _, target := range sk.Hosts {
+			address, _, _ := net.SplitHostPort(target)
+			for i := range hosts {
+				if hosts[i].Address == address {
+					hosts[i].AddTag(TagSharedHostKey)
+				}
+			}
+		}
+	}
+	return Findings(nil, shared), nil
+}
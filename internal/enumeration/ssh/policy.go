@@ -0,0 +1,130 @@
+package ssh
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+)
+
+// Weakness é um algoritmo ou configuração reprovado pela política embutida.
+type Weakness struct {
+	Category  string `json:"category"` // protocol, kex, hostkey, cipher, mac
+	Algorithm string `json:"algorithm"`
+	Severity  string `json:"severity"`
+	Reason    string `json:"reason"`
+}
+
+// rule associa um algoritmo (nome exato ou prefixo terminado em "*") a uma severidade.
+type rule struct {
+	pattern  string
+	severity string
+	reason   string
+}
+
+// policy é a política embutida de algoritmos fracos, por categoria.
+var policy = map[string][]rule{
+	"kex": {
+		{"diffie-hellman-group1-sha1", findings.SeverityHigh, "1024-bit Oakley group with SHA-1"},
+		{"diffie-hellman-group-exchange-sha1", findings.SeverityMedium, "group exchange with SHA-1"},
+		{"diffie-hellman-group14-sha1", findings.SeverityLow, "SHA-1 based key exchange"},
+		{"gss-*-sha1-*", findings.SeverityMedium, "GSSAPI key exchange with SHA-1"},
+		{"rsa1024-sha1", findings.SeverityHigh, "1024-bit RSA key exchange"},
+	},
+	"hostkey": {
+		{"ssh-dss", findings.SeverityHigh, "DSA host keys are limited to 1024 bits"},
+		{"ssh-rsa", findings.SeverityLow, "RSA signatures with SHA-1"},
+		{"ssh-rsa-cert-v01@openssh.com", findings.SeverityLow, "RSA certificate signatures with SHA-1"},
+	},
+	"cipher": {
+		{"none", findings.SeverityHigh, "no encryption"},
+		{"arcfour*", findings.SeverityHigh, "RC4 stream cipher"},
+		{"des-cbc", findings.SeverityHigh, "single DES"},
+		{"3des-cbc", findings.SeverityMedium, "3DES (Sweet32) in CBC mode"},
+		{"blowfish-cbc", findings.SeverityMedium, "64-bit block cipher in CBC mode"},
+		{"cast128-cbc", findings.SeverityMedium, "64-bit block cipher in CBC mode"},
+		{"*-cbc", findings.SeverityLow, "CBC mode (plaintext recovery attacks)"},
+	},
+	"mac": {
+		{"none", findings.SeverityHigh, "no integrity protection"},
+		{"hmac-md5*", findings.SeverityMedium, "MD5 based MAC"},
+		{"hmac-sha1-96*", findings.SeverityMedium, "truncated SHA-1 MAC"},
+		{"umac-64*", findings.SeverityLow, "64-bit tag MAC"},
+		{"hmac-sha1", findings.SeverityLow, "SHA-1 based MAC"},
+		{"hmac-sha1-etm@openssh.com", findings.SeverityLow, "SHA-1 based MAC"},
+		{"hmac-ripemd160*", findings.SeverityLow, "RIPEMD-160 based MAC"},
+	},
+}
+
+// minRSABits é o tamanho mínimo aceito para chaves de host RSA.
+const minRSABits = 2048
+
+// matches verifica se o algoritmo casa com o padrão da regra ("*" casa com qualquer sequência).
+func (r rule) matches(algorithm string) bool {
+	parts := strings.Split(r.pattern, "*")
+	if len(parts) == 1 {
+		return r.pattern == algorithm
+	}
+	if !strings.HasPrefix(algorithm, parts[0]) {
+		return false
+	}
+	rest := algorithm[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return strings.HasSuffix(rest, parts[len(parts)-1])
+}
+
+// evaluate aplica a política sobre a lista de algoritmos de uma categoria.
+func evaluate(category string, algorithms []string) []Weakness {
+	var weaknesses []Weakness
+	for _, algorithm := range algorithms {
+		for _, r := range policy[category] {
+			if r.matches(algorithm) {
+				weaknesses = append(weaknesses, Weakness{Category: category, Algorithm: algorithm, Severity: r.severity, Reason: r.reason})
+				break
+			}
+		}
+	}
+	return weaknesses
+}
+
+// audit avalia banner, algoritmos e chaves de host de um servidor.
+func audit(info *Info) []Weakness {
+	var weaknesses []Weakness
+	if strings.HasPrefix(info.Banner, "SSH-1.") {
+		weaknesses = append(weaknesses, Weakness{Category: "protocol", Algorithm: info.Banner, Severity: findings.SeverityHigh, Reason: "SSH protocol version 1 supported"})
+	}
+	weaknesses = append(weaknesses, evaluate("kex", info.KexAlgorithms)...)
+	weaknesses = append(weaknesses, evaluate("hostkey", info.HostKeyAlgorithms)...)
+	weaknesses = append(weaknesses, evaluate("cipher", unique(info.Ciphers))...)
+	weaknesses = append(weaknesses, evaluate("mac", unique(info.MACs))...)
+	for _, key := range info.HostKeys {
+		if key.Type == "ssh-rsa" && key.Bits > 0 && key.Bits < minRSABits {
+			weaknesses = append(weaknesses, Weakness{
+				Category:  "hostkey",
+				Algorithm: key.Type,
+				Severity:  findings.SeverityMedium,
+				Reason:    fmt.Sprintf("%d-bit RSA host key", key.Bits),
+			})
+		}
+	}
+	return weaknesses
+}
+
+// unique remove duplicatas preservando a ordem.
+func unique(list []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+)
+
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		algorithm string
+		want      bool
+	}{
+		{"ssh-rsa", "ssh-rsa", true},
+		{"ssh-rsa", "ssh-rsa-cert-v01@openssh.com", false},
+		{"arcfour*", "arcfour", true},
+		{"arcfour*", "arcfour256", true},
+		{"*-cbc", "aes256-cbc", true},
+		{"*-cbc", "aes256-ctr", false},
+		{"gss-*-sha1-*", "gss-group14-sha1-toWM5Slw5Ew8Mqkay+al2g==", true},
+		{"gss-*-sha1-*", "gss-curve25519-sha256-toWM5Slw5Ew8Mqkay+al2g==", false},
+		{"hmac-md5*", "hmac-md5-96-etm@openssh.com", true},
+		{"a*a", "a", false},
+		{"none", "", false},
+	}
+	for _, tt := range tests {
+		if got := (rule{pattern: tt.pattern}).matches(tt.algorithm); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.algorithm, got, tt.want)
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	info := &Info{
+		Banner:            "SSH-1.99-Cisco-1.25",
+		KexAlgorithms:     []string{"curve25519-sha256", "diffie-hellman-group1-sha1"},
+		HostKeyAlgorithms: []string{"ssh-ed25519", "ssh-dss"},
+		Ciphers:           []string{"aes128-ctr", "3des-cbc", "aes256-cbc", "3des-cbc"},
+		MACs:              []string{"hmac-sha2-256", "hmac-sha1"},
+		HostKeys:          []HostKey{{Type: "ssh-rsa", Bits: 1024}, {Type: "ssh-rsa", Bits: 4096}, {Type: "ssh-ed25519"}},
+	}
+	want := []Weakness{
+		{Category: "protocol", Algorithm: info.Banner, Severity: findings.SeverityHigh},
+		{Category: "kex", Algorithm: "diffie-hellman-group1-sha1", Severity: findings.SeverityHigh},
+		{Category: "hostkey", Algorithm: "ssh-dss", Severity: findings.SeverityHigh},
+		{Category: "cipher", Algorithm: "3des-cbc", Severity: findings.SeverityMedium},
+		{Category: "cipher", Algorithm: "aes256-cbc", Severity: findings.SeverityLow},
+		{Category: "mac", Algorithm: "hmac-sha1", Severity: findings.SeverityLow},
+		{Category: "hostkey", Algorithm: "ssh-rsa", Severity: findings.SeverityMedium},
+	}
+	got := audit(info)
+	if len(got) != len(want) {
+		t.Fatalf("audit = %+v", got)
+	}
+	for i := range want {
+		if got[i].Category != want[i].Category || got[i].Algorithm != want[i].Algorithm || got[i].Severity != want[i].Severity {
+			t.Errorf("weakness %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if weak := audit(&Info{Banner: "SSH-2.0-OpenSSH_9.6", KexAlgorithms: []string{"curve25519-sha256"}, Ciphers: []string{"chacha20-poly1305@openssh.com"}}); len(weak) != 0 {
+		t.Errorf("modern server reported %+v", weak)
+	}
+}
@@ -0,0 +1,351 @@
+package ssh
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// ModuleName é o nome do módulo usado em logs e nos resultados.
+const ModuleName = "ssh"
+
+// TagSharedHostKey marca hosts cuja chave de host também é usada por outro host.
+const TagSharedHostKey = "ssh-shared-hostkey"
+
+// TagWeakAlgorithms marca hosts que anunciam algoritmos reprovados pela política.
+const TagWeakAlgorithms = "ssh-weak-algorithms"
+
+// DefaultPorts são as portas tratadas como SSH mesmo quando o Nmap não identifica o serviço.
+var DefaultPorts = []int{22, 2222}
+
+// HostKey é uma chave de host obtida durante o handshake.
+type HostKey struct {
+	Type   string `json:"type"`
+	Bits   int    `json:"bits,omitempty"`
+	SHA256 string `json:"sha256"`
+	MD5    string `json:"md5"`
+}
+
+// Info contém o resultado da auditoria de um serviço SSH.
+type Info struct {
+	Address           string     `json:"address"`
+	Port              int        `json:"port"`
+	Banner            string     `json:"banner"`
+	Software          string     `json:"software,omitempty"`
+	KexAlgorithms     []string   `json:"kex_algorithms,omitempty"`
+	HostKeyAlgorithms []string   `json:"host_key_algorithms,omitempty"`
+	Ciphers           []string   `json:"ciphers,omitempty"`
+	MACs              []string   `json:"macs,omitempty"`
+	Compression       []string   `json:"compression,omitempty"`
+	HostKeys          []HostKey  `json:"host_keys,omitempty"`
+	Weaknesses        []Weakness `json:"weaknesses,omitempty"`
+}
+
+// SharedKey é uma chave de host presente em mais de um endereço.
+type SharedKey struct {
+	Fingerprint string   `json:"fingerprint"`
+	Type        string   `json:"type"`
+	Hosts       []string `json:"hosts"` // endereço:porta
+}
+
+// Auditor coleta banner, algoritmos e chaves de host de servidores SSH.
+type Auditor struct {
+	Timeout time.Duration // Timeout por conexão e por handshake
+	Threads int           // Quantidade de serviços auditados simultaneamente
+}
+
+// NewAuditor é a factory que cria um Auditor com valores padrão.
+func NewAuditor() *Auditor {
+	return &Auditor{
+		Timeout: 5 * time.Second,
+		Threads: 10,
+	}
+}
+
+// Audit conecta em address:port, lê o banner e o KEXINIT do servidor, coleta uma chave
+// de host por algoritmo anunciado e avalia o resultado contra a política embutida.
+func (a *Auditor) Audit(ctx context.Context, address string, port int) (*Info, error) {
+	target := net.JoinHostPort(address, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: a.Timeout}
+	conn, err := util.DialContext(ctx, dialer, "tcp", target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+	banner, kex, err := readServerHello(conn, a.Timeout)
+	conn.Close()
+	if err != nil && banner == "" {
+		return nil, fmt.Errorf("%s: %w", target, err)
+	}
+
+	info := &Info{Address: address, Port: port, Banner: banner, Software: software(banner)}
+	if err != nil {
+		util.ModuleLogger(ModuleName).Debug().Err(err).Msgf("KEXINIT not received from %s", target)
+	}
+	if kex != nil {
+		info.KexAlgorithms = kex.Kex
+		info.HostKeyAlgorithms = kex.HostKey
+		info.Ciphers = unique(append(append([]string{}, kex.CiphersC2S...), kex.CiphersS2C...))
+		info.MACs = unique(append(append([]string{}, kex.MACsC2S...), kex.MACsS2C...))
+		info.Compression = kex.Compression
+
+		seen := make(map[string]bool)
+		for _, algorithm := range kex.HostKey {
+			key, err := a.fetchHostKey(ctx, target, algorithm)
+			if err != nil {
+				util.ModuleLogger(ModuleName).Debug().Err(err).Msgf("host key %s not collected from %s", algorithm, target)
+				continue
+			}
+			if !seen[key.SHA256] {
+				seen[key.SHA256] = true
+				info.HostKeys = append(info.HostKeys, *key)
+			}
+		}
+	}
+	info.Weaknesses = audit(info)
+	return info, nil
+}
+
+// fetchHostKey executa um handshake restrito a um algoritmo de chave de host e captura a chave
+// apresentada. A autenticação nunca é tentada: o callback interrompe a conexão após a troca de chaves.
+func (a *Auditor) fetchHostKey(ctx context.Context, target, algorithm string) (*HostKey, error) {
+	var captured gossh.PublicKey
+	config := &gossh.ClientConfig{
+		User:              "arthxrecon",
+		HostKeyAlgorithms: []string{algorithm},
+		Timeout:           a.Timeout,
+		HostKeyCallback: func(_ string, _ net.Addr, key gossh.PublicKey) error {
+			captured = key
+			return errHostKeyCaptured
+		},
+	}
+	config.SetDefaults()
+	config.KeyExchanges = append(config.KeyExchanges, legacyKex...)
+	config.Ciphers = append(config.Ciphers, legacyCiphers...)
+
+	dialer := &net.Dialer{Timeout: a.Timeout}
+	conn, err := util.DialContext(ctx, dialer, "tcp", target)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(a.Timeout))
+
+	client, _, _, err := gossh.NewClientConn(conn, target, config)
+	if client != nil {
+		client.Close()
+	}
+	if captured == nil {
+		return nil, err
+	}
+	return newHostKey(captured), nil
+}
+
+// errHostKeyCaptured interrompe o handshake após a chave de host ser capturada.
+var errHostKeyCaptured = errors.New("host key captured")
+
+// Algoritmos legados habilitados para conseguir negociar com servidores antigos.
+var (
+	legacyKex     = []string{"diffie-hellman-group14-sha1", "diffie-hellman-group1-sha1", "diffie-hellman-group-exchange-sha1"}
+	legacyCiphers = []string{"aes128-cbc", "3des-cbc", "arcfour256", "arcfour128", "arcfour"}
+)
+
+// newHostKey calcula as impressões digitais e o tamanho de uma chave pública.
+func newHostKey(key gossh.PublicKey) *HostKey {
+	hk := &HostKey{
+		Type:   key.Type(),
+		SHA256: gossh.FingerprintSHA256(key),
+		MD5:    gossh.FingerprintLegacyMD5(key),
+	}
+	if crypto, ok := key.(gossh.CryptoPublicKey); ok {
+		if rsaKey, ok := crypto.CryptoPublicKey().(*rsa.PublicKey); ok {
+			hk.Bits = rsaKey.N.BitLen()
+		}
+	}
+	return hk
+}
+
+// software extrai a parte "softwareversion" do banner (ex.: "OpenSSH_8.9p1 Ubuntu-3").
+func software(banner string) string {
+	parts := strings.SplitN(banner, "-", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[2]
+}
+
+// isSSHService indica se o serviço deve ser auditado.
+func isSSHService(svc results.Service) bool {
+	if svc.Name == "ssh" {
+		return true
+	}
+	for _, p := range DefaultPorts {
+		if svc.Port == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Run audita todos os serviços SSH dos hosts, anexa os resultados ao registro de cada host
+// e detecta chaves de host compartilhadas entre endereços diferentes.
+func (a *Auditor) Run(ctx context.Context, hosts []results.Host) ([]Info, []SharedKey) {
+	threads := a.Threads
+	if threads < 1 {
+		threads = 1
+	}
+	batch := util.NewProbeBatch(ctx, ModuleName, threads)
+	for i := range hosts {
+		for _, svc := range hosts[i].Services {
+			if isSSHService(svc) {
+				batch.Add(hosts[i].Address, "tcp", svc.Port)
+			}
+		}
+	}
+	batch.Start()
+	defer batch.Finish()
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		found   []Info
+		perHost = make(map[*results.Host][]Info)
+		sem     = make(chan struct{}, threads)
+	)
+	for i := range hosts {
+		for _, svc := range hosts[i].Services {
+			if !isSSHService(svc) {
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+			util.WaitWindow(ctx)
+			go func(host *results.Host, port int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				info, err := a.Audit(ctx, host.Address, port)
+				if err != nil {
+					util.ModuleLogger(ModuleName).Debug().Err(err).Msg("SSH audit failed")
+					return
+				}
+
+				mu.Lock()
+				perHost[host] = append(perHost[host], *info)
+				found = append(found, *info)
+				mu.Unlock()
+			}(&hosts[i], svc.Port)
+		}
+	}
+	wg.Wait()
+
+	for host, infos := range perHost {
+		sort.Slice(infos, func(i, j int) bool { return infos[i].Port < infos[j].Port })
+		host.SetEnrichment(ModuleName, infos)
+		for _, info := range infos {
+			if len(info.Weaknesses) > 0 {
+				host.AddTag(TagWeakAlgorithms)
+			}
+		}
+	}
+
+	shared := sharedKeys(found)
+	for _, sk := range shared {
+		for i := range hosts {
+			for _, target := range sk.Hosts {
+				if h, _, _ := net.SplitHostPort(target); h == hosts[i].Address {
+					hosts[i].AddTag(TagSharedHostKey)
+				}
+			}
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Address != found[j].Address {
+			return found[i].Address < found[j].Address
+		}
+		return found[i].Port < found[j].Port
+	})
+	return found, shared
+}
+
+// sharedKeys agrupa as chaves de host por impressão digital e retorna as que aparecem
+// em mais de um endereço. Portas diferentes do mesmo host não contam como compartilhamento.
+func sharedKeys(infos []Info) []SharedKey {
+	type group struct {
+		keyType   string
+		targets   map[string]bool
+		addresses map[string]bool
+	}
+	groups := make(map[string]*group)
+	for _, info := range infos {
+		target := net.JoinHostPort(info.Address, strconv.Itoa(info.Port))
+		for _, key := range info.HostKeys {
+			g, ok := groups[key.SHA256]
+			if !ok {
+				g = &group{keyType: key.Type, targets: make(map[string]bool), addresses: make(map[string]bool)}
+				groups[key.SHA256] = g
+			}
+			g.targets[target] = true
+			g.addresses[info.Address] = true
+		}
+	}
+
+	var shared []SharedKey
+	for fingerprint, g := range groups {
+		if len(g.addresses) < 2 {
+			continue
+		}
+		sk := SharedKey{Fingerprint: fingerprint, Type: g.keyType}
+		for target := range g.targets {
+			sk.Hosts = append(sk.Hosts, target)
+		}
+		sort.Strings(sk.Hosts)
+		shared = append(shared, sk)
+	}
+	sort.Slice(shared, func(i, j int) bool { return shared[i].Fingerprint < shared[j].Fingerprint })
+	return shared
+}
+
+// Findings converte as fraquezas e as chaves compartilhadas em achados.
+func Findings(infos []Info, shared []SharedKey) []findings.Finding {
+	var list []findings.Finding
+	for _, info := range infos {
+		for _, w := range info.Weaknesses {
+			id := findings.Slug("ssh-weak", w.Category, w.Algorithm)
+			title := fmt.Sprintf("Weak SSH %s algorithm: %s", w.Category, w.Algorithm)
+			switch {
+			case w.Category == "protocol":
+				id, title = "ssh-protocol-v1", "SSH "+w.Reason
+			case strings.HasSuffix(w.Reason, "host key"):
+				id, title = findings.IDSSHWeakRSAHostKey, "SSH "+w.Reason
+			case w.Category == "hostkey" && w.Algorithm == "ssh-dss":
+				id = findings.IDSSHDSAHostKey
+			}
+			list = append(list, findings.New(ModuleName, id, title, w.Severity, info.Address, info.Port, "tcp").
+				WithEvidence(w.Reason).
+				WithDetails("banner", info.Banner, "category", w.Category, "algorithm", w.Algorithm))
+		}
+	}
+	for _, sk := range shared {
+		for _, target := range sk.Hosts {
+			host, portStr, _ := net.SplitHostPort(target)
+			port, _ := strconv.Atoi(portStr)
+			list = append(list, findings.New(ModuleName, "ssh-shared-hostkey", "SSH host key shared with other hosts", findings.SeverityMedium, host, port, "tcp").
+				WithEvidence(sk.Fingerprint).
+				WithDetails("type", sk.Type, "hosts", strings.Join(sk.Hosts, ", ")))
+		}
+	}
+	return list
+}
@@ -0,0 +1,124 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// sshServer inicia um servidor SSH em 127.0.0.1 com a chave de host informada e retorna a porta.
+// Nenhuma autenticação é aceita.
+func sshServer(t *testing.T, signer gossh.Signer) int {
+	t.Helper()
+	config := &gossh.ServerConfig{
+		PasswordCallback: func(gossh.ConnMetadata, []byte) (*gossh.Permissions, error) {
+			return nil, errors.New("denied")
+		},
+	}
+	config.AddHostKey(signer)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+				_, _, _, _ = gossh.NewServerConn(conn, config)
+			}()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func newSigner(t *testing.T) gossh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func TestAuditLoopback(t *testing.T) {
+	signer := newSigner(t)
+	port := sshServer(t, signer)
+	auditor := NewAuditor()
+	auditor.Timeout = 3 * time.Second
+
+	info, err := auditor.Audit(context.Background(), "127.0.0.1", port)
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if info.Banner == "" || info.Software == "" || len(info.KexAlgorithms) == 0 || len(info.Ciphers) == 0 {
+		t.Errorf("incomplete info: %+v", info)
+	}
+	if len(info.HostKeyAlgorithms) != 1 || info.HostKeyAlgorithms[0] != gossh.KeyAlgoED25519 {
+		t.Errorf("HostKeyAlgorithms = %v", info.HostKeyAlgorithms)
+	}
+	fingerprint := gossh.FingerprintSHA256(signer.PublicKey())
+	if len(info.HostKeys) != 1 || info.HostKeys[0].SHA256 != fingerprint || info.HostKeys[0].Type != gossh.KeyAlgoED25519 {
+		t.Errorf("HostKeys = %+v, want %s", info.HostKeys, fingerprint)
+	}
+}
+
+func TestAuditNotSSH(t *testing.T) {
+	addr := scriptedServer(t, []byte("220 ftp ready\r\n"))
+	_, portStr, _ := net.SplitHostPort(addr)
+	port, _ := strconv.Atoi(portStr)
+	auditor := NewAuditor()
+	auditor.Timeout = 2 * time.Second
+	if info, err := auditor.Audit(context.Background(), "127.0.0.1", port); err == nil {
+		t.Errorf("Audit of a non-SSH service = %+v", info)
+	}
+}
+
+func TestSharedKeys(t *testing.T) {
+	key := HostKey{Type: "ssh-ed25519", SHA256: "SHA256:shared"}
+	infos := []Info{
+		{Address: "10.0.0.1", Port: 22, HostKeys: []HostKey{key, {Type: "ssh-rsa", SHA256: "SHA256:a"}}},
+		{Address: "10.0.0.1", Port: 2222, HostKeys: []HostKey{{Type: "ssh-rsa", SHA256: "SHA256:a"}}},
+		{Address: "10.0.0.2", Port: 22, HostKeys: []HostKey{key}},
+	}
+	shared := sharedKeys(infos)
+	if len(shared) != 1 || shared[0].Fingerprint != "SHA256:shared" {
+		t.Fatalf("sharedKeys = %+v", shared)
+	}
+	if hosts := shared[0].Hosts; len(hosts) != 2 || hosts[0] != "10.0.0.1:22" || hosts[1] != "10.0.0.2:22" {
+		t.Errorf("Hosts = %v", hosts)
+	}
+	if list := Findings(nil, shared); len(list) != 2 || list[1].Host != "10.0.0.2" || list[1].Port != 22 {
+		t.Errorf("Findings = %+v", list)
+	}
+}
+
+func TestSoftware(t *testing.T) {
+	tests := map[string]string{
+		"SSH-2.0-OpenSSH_8.9p1 Ubuntu-3": "OpenSSH_8.9p1 Ubuntu-3",
+		"SSH-2.0-dropbear_2022.83":       "dropbear_2022.83",
+		"SSH-2.0":                        "",
+		"":                               "",
+	}
+	for banner, want := range tests {
+		if got := software(banner); got != want {
+			t.Errorf("software(%q) = %q, want %q", banner, got, want)
+		}
+	}
+}
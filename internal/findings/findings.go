@@ -0,0 +1,353 @@
+package findings
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Severidades dos achados, da mais grave para a menos grave.
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// Severities lista as severidades em ordem decrescente de gravidade.
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo}
+
+// IDs canônicos dos problemas reportados por mais de uma origem (módulos nativos e NSE): cada
+// origem usa o mesmo ID e o registro une os relatos do mesmo host e porta em um único achado.
+const (
+	IDFTPAnonymous          = "ftp-anonymous"
+	IDFTPAnonymousWritable  = "ftp-anonymous-writable"
+	IDSMBSigningNotRequired = "smb-signing-not-required"
+	IDSMBv1Enabled          = "smbv1-enabled"
+	IDSMBNullSession        = "smb-null-session"
+	IDSSHDSAHostKey         = "ssh-dsa-hostkey"
+	IDSSHWeakRSAHostKey     = "ssh-weak-rsa-hostkey"
+)
+
+// Finding é um achado produzido por um módulo de enumeração, pelo NSE ou pela análise de CVEs.
+type Finding struct {
+	ID        string            `json:"id"` // ID canônico do problema (ex.: smb-signing-not-required, cve-2021-41773)
+	Title     string            `json:"title"`
+	Severity  string            `json:"severity"`
+	Host      string            `json:"host"`
+	Port      int               `json:"port,omitempty"` // 0 para achados de host
+	Protocol  string            `json:"protocol,omitempty"`
+	Evidence  string            `json:"evidence,omitempty"`
+	Source    string            `json:"source"`            // Módulo de origem (ex.: ftp, nse, vulnanalysis)
+	Sources   []string          `json:"sources,omitempty"` // Todas as origens que reportaram o achado (registro)
+	Details   map[string]string `json:"details,omitempty"`
+	FirstSeen time.Time         `json:"first_seen"`
+	LastSeen  time.Time         `json:"last_seen"`
+}
+
+// New cria o achado id para host:port/protocol (port 0 = achado de host).
+func New(source, id, title, severity, host string, port int, protocol string) Finding {
+	return Finding{ID: id, Source: source, Title: title, Severity: severity, Host: host, Port: port, Protocol: protocol}
+}
+
+// Slug monta um ID canônico a partir das partes informadas: letras minúsculas, dígitos e pontos,
+// com as partes e os demais caracteres separados por "-" (ex.: "ssh-weak", "kex", "rsa1024-sha1").
+func Slug(parts ...string) string {
+	var b strings.Builder
+	dash := false
+	for _, part := range parts {
+		for _, r := range strings.ToLower(part) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' {
+				if dash && b.Len() > 0 {
+					b.WriteByte('-')
+				}
+				b.WriteRune(r)
+				dash = false
+				continue
+			}
+			dash = true
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// WithEvidence define a evidência do achado.
+func (f Finding) WithEvidence(evidence string) Finding {
+	f.Evidence = evidence
+	return f
+}
+
+// WithDetails define os detalhes do achado, ignorando valores vazios (pares chave, valor).
+func (f Finding) WithDetails(pairs ...string) Finding {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			continue
+		}
+		if f.Details == nil {
+			f.Details = make(map[string]string)
+		}
+		f.Details[pairs[i]] = pairs[i+1]
+	}
+	return f
+}
+
+// Key identifica o achado de forma estável entre execuções e origens: mesmo ID canônico, host,
+// protocolo e porta. Sem ID, o título normalizado faz as vezes dele.
+func (f Finding) Key() string {
+	id := f.ID
+	if id == "" {
+		id = Slug(f.Title)
+	}
+	sum := sha1.Sum([]byte(strings.Join([]string{id, f.Host, f.Protocol, strconv.Itoa(f.Port)}, "|")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Merge une os achados repetidos da lista (mesma Key), preservando a ordem e a primeira ocorrência.
+func Merge(list []Finding) []Finding {
+	seen := make(map[string]bool, len(list))
+	var out []Finding
+	for _, f := range list {
+		if key := f.Key(); !seen[key] {
+			seen[key] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Target formata host:port/protocol para o console.
+func (f Finding) Target() string {
+	if f.Port == 0 {
+		return f.Host
+	}
+	protocol := f.Protocol
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	return fmt.Sprintf("%s:%d/%s", f.Host, f.Port, protocol)
+}
+
+// Rank retorna a posição da severidade (0 = critical); severidades desconhecidas ficam por último.
+func Rank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(Severities)
+}
+
+// ValidSeverity indica se a severidade é conhecida.
+func ValidSeverity(severity string) bool {
+	return Rank(severity) < len(Severities)
+}
+
+// SeverityFromCVSS converte o score CVSS na severidade qualitativa (escala do CVSS v3).
+func SeverityFromCVSS(score float64) string {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityInfo
+}
+
+// Sort ordena os achados por severidade, host, porta e título.
+func Sort(list []Finding) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if Rank(a.Severity) != Rank(b.Severity) {
+			return Rank(a.Severity) < Rank(b.Severity)
+		}
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.Title < b.Title
+	})
+}
+
+// Summary resume a quantidade de achados por severidade (ex.: "2 high, 5 info").
+func Summary(list []Finding) string {
+	counts := make(map[string]int)
+	for _, f := range list {
+		counts[f.Severity]++
+	}
+	var parts []string
+	for _, severity := range Severities {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Registry guarda os achados de todas as execuções e módulos, sem duplicatas, indexados por Key.
+type Registry struct {
+	mu       sync.Mutex
+	findings map[string]*Finding
+}
+
+// NewRegistry cria um registro vazio.
+func NewRegistry() *Registry {
+	return &Registry{findings: make(map[string]*Finding)}
+}
+
+// LoadRegistry carrega o registro persistido em path; se o arquivo não existir, retorna um registro vazio.
+func LoadRegistry(path string) (*Registry, error) {
+	registry := NewRegistry()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read findings: %w", err)
+	}
+	var list []Finding
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode findings %s: %w", path, err)
+	}
+	for i := range list {
+		f := list[i]
+		if len(f.Sources) == 0 {
+			// Registro anterior aos IDs canônicos: o ID era um hash que incluía a origem.
+			f.ID = Slug(f.Title)
+			f.Sources = []string{f.Source}
+		}
+		if existing, ok := registry.findings[f.Key()]; ok {
+			// Entradas antigas de origens diferentes colapsam na mesma chave.
+			existing.merge(f)
+			continue
+		}
+		registry.findings[f.Key()] = &f
+	}
+	return registry, nil
+}
+
+// merge combina em f outro registro do mesmo achado: mantém o FirstSeen mais antigo, une as
+// origens e fica com a maior severidade; título, evidência e detalhes vêm do visto por último.
+func (f *Finding) merge(other Finding) {
+	if !other.FirstSeen.IsZero() && (f.FirstSeen.IsZero() || other.FirstSeen.Before(f.FirstSeen)) {
+		f.FirstSeen = other.FirstSeen
+	}
+	if other.LastSeen.After(f.LastSeen) {
+		f.LastSeen = other.LastSeen
+		f.Title = other.Title
+		if other.Evidence != "" {
+			f.Evidence = other.Evidence
+		}
+		if other.Details != nil {
+			f.Details = other.Details
+		}
+	}
+	for _, source := range other.Sources {
+		if !slices.Contains(f.Sources, source) {
+			f.Sources = append(f.Sources, source)
+		}
+	}
+	sort.Strings(f.Sources)
+	if Rank(other.Severity) < Rank(f.Severity) {
+		f.Severity = other.Severity
+	}
+}
+
+// Add registra os achados. Achados já conhecidos, da mesma ou de outra origem, têm LastSeen,
+// evidência e detalhes atualizados e a origem acrescentada a Sources, preservando FirstSeen.
+// Título e severidade acompanham a última execução da mesma origem; outra origem só eleva a
+// severidade. Retorna quantos achados eram novos.
+func (r *Registry) Add(list ...Finding) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	added := 0
+	for _, f := range list {
+		if f.ID == "" {
+			f.ID = Slug(f.Title)
+		}
+		if f.LastSeen.IsZero() {
+			f.LastSeen = now
+		}
+		key := f.Key()
+		existing, ok := r.findings[key]
+		if !ok {
+			if f.FirstSeen.IsZero() {
+				f.FirstSeen = f.LastSeen
+			}
+			f.Sources = []string{f.Source}
+			r.findings[key] = &f
+			added++
+			continue
+		}
+		if f.LastSeen.After(existing.LastSeen) {
+			existing.LastSeen = f.LastSeen
+		}
+		if slices.Contains(existing.Sources, f.Source) {
+			existing.Title = f.Title
+			existing.Severity = f.Severity
+		} else {
+			existing.Sources = append(existing.Sources, f.Source)
+			sort.Strings(existing.Sources)
+			if Rank(f.Severity) < Rank(existing.Severity) {
+				existing.Severity = f.Severity
+			}
+		}
+		if f.Evidence != "" {
+			existing.Evidence = f.Evidence
+		}
+		if f.Details != nil {
+			existing.Details = f.Details
+		}
+	}
+	return added
+}
+
+// All retorna todos os achados ordenados por severidade.
+func (r *Registry) All() []Finding {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := make([]Finding, 0, len(r.findings))
+	for _, f := range r.findings {
+		list = append(list, *f)
+	}
+	Sort(list)
+	return list
+}
+
+// Save grava o registro em JSON, ordenado por severidade.
+func (r *Registry) Save(path string) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create findings directory: %w", err)
+		}
+	}
+	data, err := json.MarshalIndent(r.All(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal findings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write findings: %w", err)
+	}
+	return nil
+}
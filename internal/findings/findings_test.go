@@ -0,0 +1,153 @@
+package findings
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{[]string{"CVE-2021-41773"}, "cve-2021-41773"},
+		{[]string{"ssh-weak", "kex", "diffie-hellman-group1-sha1"}, "ssh-weak-kex-diffie-hellman-group1-sha1"},
+		{[]string{"dns-zone-transfer", "Corp.Local"}, "dns-zone-transfer-corp.local"},
+		{[]string{"SMB signing not required"}, "smb-signing-not-required"},
+		{[]string{"  EDB-ID:1234 "}, "edb-id-1234"},
+		{[]string{"", "x"}, "x"},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.parts...); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.parts, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryMergesSources(t *testing.T) {
+	registry := NewRegistry()
+	module := New("smb", IDSMBSigningNotRequired, "SMB signing not required", SeverityMedium, "10.0.0.1", 445, "tcp")
+	nse := New("nse", IDSMBSigningNotRequired, "SMB2 message signing not required", SeverityMedium, "10.0.0.1", 445, "tcp")
+	other := New("nse", IDSMBSigningNotRequired, "SMB2 message signing not required", SeverityMedium, "10.0.0.2", 445, "tcp")
+
+	if added := registry.Add(module, nse, other); added != 2 {
+		t.Fatalf("Add = %d new, want 2", added)
+	}
+	all := registry.All()
+	if len(all) != 2 {
+		t.Fatalf("%d findings: %+v", len(all), all)
+	}
+	for _, f := range all {
+		want := []string{"nse"}
+		if f.Host == "10.0.0.1" {
+			want = []string{"nse", "smb"}
+		}
+		if len(f.Sources) != len(want) || f.Sources[0] != want[0] || f.Sources[len(want)-1] != want[len(want)-1] {
+			t.Errorf("%s: Sources = %v, want %v", f.Host, f.Sources, want)
+		}
+	}
+
+	// Uma origem nova só eleva a severidade; uma origem já conhecida pode reduzi-la.
+	registry.Add(New("ldap", IDSMBSigningNotRequired, "x", SeverityInfo, "10.0.0.1", 445, "tcp"))
+	for _, f := range registry.All() {
+		if f.Host == "10.0.0.1" && (f.Severity != SeverityMedium || f.Title == "x") {
+			t.Errorf("a new source lowered the finding to %s %q", f.Severity, f.Title)
+		}
+	}
+	registry.Add(New("nse", IDSMBSigningNotRequired, "x", SeverityHigh, "10.0.0.1", 445, "tcp"))
+	registry.Add(New("smb", IDSMBSigningNotRequired, "SMB signing not required", SeverityLow, "10.0.0.1", 445, "tcp"))
+	for _, f := range registry.All() {
+		if f.Host == "10.0.0.1" && f.Severity != SeverityLow {
+			t.Errorf("Severity = %s, want low", f.Severity)
+		}
+	}
+}
+
+func TestKeyIgnoresTitleAndSource(t *testing.T) {
+	a := New("nse", "http-title", "HTTP title: Welcome", SeverityInfo, "10.0.0.1", 80, "tcp")
+	b := New("banner", "http-title", "HTTP title: Maintenance", SeverityInfo, "10.0.0.1", 80, "tcp")
+	if a.Key() != b.Key() {
+		t.Error("the key changes with the title or the source")
+	}
+	for _, c := range []Finding{
+		New("nse", "http-title", "x", SeverityInfo, "10.0.0.2", 80, "tcp"),
+		New("nse", "http-title", "x", SeverityInfo, "10.0.0.1", 8080, "tcp"),
+		New("nse", "http-title", "x", SeverityInfo, "10.0.0.1", 80, "udp"),
+		New("nse", "http-server-header", "x", SeverityInfo, "10.0.0.1", 80, "tcp"),
+	} {
+		if c.Key() == a.Key() {
+			t.Errorf("%+v shares the key of %+v", c, a)
+		}
+	}
+	if got := Merge([]Finding{a, b}); len(got) != 1 || got[0].Title != a.Title {
+		t.Errorf("Merge = %+v", got)
+	}
+}
+
+func TestLoadRegistryLegacy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "findings.json")
+	legacy := []Finding{{ID: "0123456789abcdef", Title: "SMB signing not required", Source: "smb", Severity: SeverityMedium, Host: "10.0.0.1", Port: 445, Protocol: "tcp"}}
+	data, _ := json.Marshal(legacy)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	registry, err := LoadRegistry(path)
+	if err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+	added := registry.Add(New("nse", IDSMBSigningNotRequired, "SMB2 message signing not required", SeverityMedium, "10.0.0.1", 445, "tcp"))
+	all := registry.All()
+	if added != 0 || len(all) != 1 || len(all[0].Sources) != 2 {
+		t.Fatalf("added %d, findings %+v", added, all)
+	}
+
+	if err := registry.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadRegistry(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.All(); len(got) != 1 || got[0].ID != IDSMBSigningNotRequired || len(got[0].Sources) != 2 {
+		t.Errorf("reloaded %+v", got)
+	}
+}
+
+func TestLoadRegistryLegacyCollapsed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "findings.json")
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	// O mesmo problema reportado por duas origens tinha IDs diferentes no formato antigo.
+	legacy := []Finding{
+		{ID: "0123456789abcdef", Title: "Anonymous FTP login", Source: "nse", Severity: SeverityMedium, Host: "10.0.0.1", Port: 21, Protocol: "tcp",
+			Evidence: "ftp-anon", FirstSeen: day(3), LastSeen: day(5)},
+		{ID: "fedcba9876543210", Title: "Anonymous FTP login", Source: "ftp", Severity: SeverityHigh, Host: "10.0.0.1", Port: 21, Protocol: "tcp",
+			Evidence: "230 Login successful", FirstSeen: day(1), LastSeen: day(4)},
+		{ID: "00112233aabbccdd", Title: "Anonymous FTP login", Source: "ftp", Severity: SeverityHigh, Host: "10.0.0.2", Port: 21, Protocol: "tcp",
+			FirstSeen: day(2), LastSeen: day(2)},
+	}
+	data, _ := json.Marshal(legacy)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	registry, err := LoadRegistry(path)
+	if err != nil {
+		t.Fatalf("LoadRegistry: %v", err)
+	}
+	all := registry.All()
+	if len(all) != 2 {
+		t.Fatalf("findings %+v", all)
+	}
+	f := all[0]
+	if f.Host != "10.0.0.1" {
+		f = all[1]
+	}
+	if !f.FirstSeen.Equal(day(1)) || !f.LastSeen.Equal(day(5)) || f.Severity != SeverityHigh || f.Evidence != "ftp-anon" {
+		t.Errorf("merged finding %+v", f)
+	}
+	if len(f.Sources) != 2 || f.Sources[0] != "ftp" || f.Sources[1] != "nse" {
+		t.Errorf("sources %v", f.Sources)
+	}
+}
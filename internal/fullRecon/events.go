@@ -0,0 +1,111 @@
+package fullrecon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// stateSnapshot guarda o que o State já continha antes de uma etapa, para notificar só o que ela acrescentou.
+type stateSnapshot struct {
+	hosts    map[string]bool // Endereços já conhecidos
+	ports    map[string]bool // host:porta/protocolo já conhecidos
+	findings int             // Quantidade de achados
+}
+
+// snapshot registra os hosts, portas e achados atuais do State.
+func snapshot(state *State) stateSnapshot {
+	s := stateSnapshot{hosts: make(map[string]bool), ports: make(map[string]bool), findings: len(state.Findings)}
+	for _, address := range state.Alive {
+		s.hosts[address] = true
+	}
+	for _, host := range state.Hosts {
+		s.hosts[host.Address] = true
+		eachService(host, func(svc results.Service) {
+			s.ports[portKey(host.Address, svc)] = true
+		})
+	}
+	return s
+}
+
+// stageEvents gera os eventos de uma etapa concluída: a conclusão, os hosts e portas novos e os achados.
+func stageEvents(stage string, before stateSnapshot, state *State) []notify.Event {
+	var (
+		events   []notify.Event
+		newHosts int
+		newPorts int
+		seen     = make(map[string]bool)
+	)
+	addHost := func(address string, ports string) {
+		if before.hosts[address] || seen[address] {
+			return
+		}
+		seen[address] = true
+		newHosts++
+		message := "New host " + address
+		if ports != "" {
+			message += " (" + ports + ")"
+		}
+		events = append(events, notify.Event{Type: notify.EventNewHost, Source: "fullrecon", Stage: stage, Host: address, New: ports, Message: message})
+	}
+	for _, address := range state.Alive {
+		addHost(address, "")
+	}
+	for _, host := range state.Hosts {
+		var ports []string
+		eachService(host, func(svc results.Service) {
+			ports = append(ports, fmt.Sprintf("%d/%s", svc.Port, svc.Protocol))
+		})
+		addHost(host.Address, strings.Join(ports, ", "))
+		eachService(host, func(svc results.Service) {
+			if before.ports[portKey(host.Address, svc)] {
+				return
+			}
+			newPorts++
+			label := strings.Join(strings.Fields(svc.Name+" "+svc.Product+" "+svc.Version), " ")
+			message := fmt.Sprintf("Open port %s:%d/%s", host.Address, svc.Port, svc.Protocol)
+			if label != "" {
+				message += " (" + label + ")"
+			}
+			events = append(events, notify.Event{Type: notify.EventPortOpened, Source: "fullrecon", Stage: stage,
+				Host: host.Address, Port: svc.Port, Protocol: svc.Protocol, New: label, Message: message})
+		})
+	}
+	// O mesmo problema reportado por outra origem (ex.: módulo SMB e NSE) é notificado uma só vez.
+	reported := make(map[string]bool)
+	for _, f := range state.Findings[:before.findings] {
+		reported[f.Key()] = true
+	}
+	newFindings := 0
+	for _, f := range state.Findings[before.findings:] {
+		if reported[f.Key()] {
+			continue
+		}
+		reported[f.Key()] = true
+		newFindings++
+		events = append(events, notify.Event{Type: notify.EventFinding, Source: "fullrecon", Stage: stage,
+			Host: f.Host, Port: f.Port, Protocol: f.Protocol, Severity: f.Severity, Title: f.Title,
+			Message: fmt.Sprintf("[%s] %s on %s", strings.ToUpper(f.Severity), f.Title, f.Target())})
+	}
+
+	completed := notify.Event{Type: notify.EventStageCompleted, Source: "fullrecon", Stage: stage,
+		Message: fmt.Sprintf("%s finished: %d new hosts, %d new open ports, %d findings", stage, newHosts, newPorts, newFindings)}
+	return append([]notify.Event{completed}, events...)
+}
+
+// eachService chama fn para cada serviço TCP e UDP do host.
+func eachService(host results.Host, fn func(svc results.Service)) {
+	for _, svc := range host.Services {
+		fn(svc)
+	}
+	for _, svc := range host.UDPServices {
+		fn(svc)
+	}
+}
+
+// portKey identifica uma porta de um host.
+func portKey(address string, svc results.Service) string {
+	return fmt.Sprintf("%s:%d/%s", address, svc.Port, svc.Protocol)
+}
@@ -0,0 +1,111 @@
+package fullrecon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// eventsOf retorna os eventos do tipo informado.
+func eventsOf(events []notify.Event, eventType string) []notify.Event {
+	var out []notify.Event
+	for _, e := range events {
+		if e.Type == eventType {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestStageEvents(t *testing.T) {
+	smbv1 := findings.Finding{ID: "smbv1-enabled", Title: "SMBv1 enabled", Severity: "high", Host: "10.0.0.5", Port: 445, Protocol: "tcp", Source: "smb"}
+	state := &State{
+		Alive:    []string{"10.0.0.5", "10.0.0.6"},
+		Hosts:    []results.Host{{Address: "10.0.0.5", Services: []results.Service{{Port: 22, Protocol: "tcp", Name: "ssh"}}}},
+		Findings: []findings.Finding{smbv1},
+	}
+	before := snapshot(state)
+
+	// A etapa acrescenta um host com portas, uma porta em host conhecido e achados.
+	state.Hosts[0].Services = append(state.Hosts[0].Services, results.Service{Port: 445, Protocol: "tcp", Name: "microsoft-ds", Product: "Samba", Version: "4.6"})
+	state.Hosts = append(state.Hosts, results.Host{Address: "10.0.0.7",
+		Services:    []results.Service{{Port: 80, Protocol: "tcp"}},
+		UDPServices: []results.Service{{Port: 161, Protocol: "udp", Name: "snmp"}},
+	})
+	anonymous := findings.Finding{ID: "ftp-anonymous", Title: "Anonymous FTP login", Severity: "medium", Host: "10.0.0.7", Port: 21, Protocol: "tcp", Source: "ftp"}
+	signing := findings.Finding{ID: "smb-signing-not-required", Title: "SMB signing not required", Severity: "medium", Host: "10.0.0.5", Port: 445, Protocol: "tcp", Source: "smb"}
+	smbv1NSE := smbv1
+	smbv1NSE.Source = "nse"
+	signingNSE := signing
+	signingNSE.Source, signingNSE.Title = "nse", "Message signing disabled"
+	state.Findings = append(state.Findings, anonymous, signing, signingNSE, smbv1NSE)
+
+	events := stageEvents("Enumeration", before, state)
+	if len(events) == 0 || events[0].Type != notify.EventStageCompleted ||
+		events[0].Message != "Enumeration finished: 1 new hosts, 3 new open ports, 2 findings" {
+		t.Fatalf("first event: %+v", events)
+	}
+	for _, e := range events {
+		if e.Source != "fullrecon" || e.Stage != "Enumeration" {
+			t.Errorf("event without source or stage: %+v", e)
+		}
+	}
+
+	hosts := eventsOf(events, notify.EventNewHost)
+	if len(hosts) != 1 || hosts[0].Host != "10.0.0.7" || hosts[0].New != "80/tcp, 161/udp" || hosts[0].Message != "New host 10.0.0.7 (80/tcp, 161/udp)" {
+		t.Errorf("new_host events: %+v", hosts)
+	}
+
+	var ports []string
+	for _, e := range eventsOf(events, notify.EventPortOpened) {
+		ports = append(ports, e.Message)
+	}
+	if want := []string{
+		"Open port 10.0.0.5:445/tcp (microsoft-ds Samba 4.6)",
+		"Open port 10.0.0.7:80/tcp",
+		"Open port 10.0.0.7:161/udp (snmp)",
+	}; !reflect.DeepEqual(ports, want) {
+		t.Errorf("port_opened events: %q, want %q", ports, want)
+	}
+
+	// O achado repetido por outra origem não gera evento, nem o que já existia antes da etapa.
+	found := eventsOf(events, notify.EventFinding)
+	if len(found) != 2 {
+		t.Fatalf("finding events: %+v", found)
+	}
+	if e := found[0]; e.Title != "Anonymous FTP login" || e.Severity != "medium" || e.Port != 21 || e.Message != "[MEDIUM] Anonymous FTP login on 10.0.0.7:21/tcp" {
+		t.Errorf("finding event: %+v", e)
+	}
+	if e := found[1]; e.Title != "SMB signing not required" || e.Host != "10.0.0.5" || e.Protocol != "tcp" {
+		t.Errorf("finding event: %+v", e)
+	}
+
+	// Sem mudanças, só a conclusão da etapa é notificada.
+	if events := stageEvents("Vuln Analysis", snapshot(state), state); len(events) != 1 ||
+		events[0].Message != "Vuln Analysis finished: 0 new hosts, 0 new open ports, 0 findings" {
+		t.Errorf("events without changes: %+v", events)
+	}
+}
+
+func TestStageEventsHostDiscovery(t *testing.T) {
+	state := &State{}
+	before := snapshot(state)
+	state.Alive = []string{"10.0.0.1", "10.0.0.2", "10.0.0.1"}
+
+	events := stageEvents("Host Discovery", before, state)
+	hosts := eventsOf(events, notify.EventNewHost)
+	if len(hosts) != 2 || hosts[0].Message != "New host 10.0.0.1" || hosts[1].Host != "10.0.0.2" || hosts[0].New != "" {
+		t.Errorf("new_host events: %+v", hosts)
+	}
+
+	// O port scan não repete como novo o host já encontrado pelo host discovery.
+	before = snapshot(state)
+	state.Hosts = []results.Host{{Address: "10.0.0.1", Services: []results.Service{{Port: 3389, Protocol: "tcp"}}}}
+	events = stageEvents("Port Scan", before, state)
+	if len(eventsOf(events, notify.EventNewHost)) != 0 || len(eventsOf(events, notify.EventPortOpened)) != 1 {
+		t.Errorf("port scan events: %+v", events)
+	}
+}
@@ -1,49 +1,64 @@
 package fullrecon
 
 import (
+	"context"
 	"fmt"
-	"log"
 
-	"github.com/Arthx-x/arthxrecon/internal/hostdiscovery"
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/results"
 	"github.com/Arthx-x/arthxrecon/util"
 )
 
-// FullReconOrchestrator coordena a execução dos módulos: host discovery, port scan, enumeration, etc.
-type FullReconOrchestrator struct {
-	Target string // Pode ser IP, range ou caminho para arquivo com alvos
-	Mode   string // Modo do scan (por exemplo, "normal", "aggressive", etc.)
+// State é o estado compartilhado entre as etapas do pipeline. Cada etapa lê o que as
+// anteriores produziram e acrescenta o seu resultado.
+type State struct {
+	Targets  []string           // Alvos informados (IPs, CIDRs ou o caminho de um arquivo)
+	FileMode bool               // Indica se Targets contém o caminho de um arquivo de alvos
+	Alive    []string           // Hosts ativos encontrados no host discovery
+	Hosts    []results.Host     // Hosts com serviços, enriquecidos pelas etapas seguintes
+	Findings []findings.Finding // Achados acumulados de todas as etapas
 }
 
-// Run executa as etapas de FullRecon em sequência.
-func (fr *FullReconOrchestrator) Run() error {
-	// Utilize a função comum para obter os targets (a partir de arquivo ou flag única)
-	targets := util.GetTargets("", fr.Target)
-	if len(targets) == 0 {
-		return fmt.Errorf("no valid targets provided")
-	}
-
-	// Configura os parâmetros para host discovery.
-	params := hostdiscovery.DiscoveryParams{
-		Target:     fr.Target,
-		OutputFile: "hostdiscovery_output", // Este nome pode ser ajustado ou derivado dinamicamente
-		Mode:       fr.Mode,
-		Options:    []string{}, // Adicione opções extras se necessário
-	}
+// Stage é uma etapa do pipeline (host discovery, port scan, enumeration, vulnanalysis...).
+type Stage interface {
+	// Name retorna o nome exibido da etapa.
+	Name() string
+	// Run executa a etapa sobre o estado atual.
+	Run(ctx context.Context, state *State) error
+}
 
-	// Cria a estratégia: por enquanto, usamos apenas Nmap.
-	strategy := hostdiscovery.NewNmapHostDiscovery()
+// FullReconOrchestrator coordena a execução das etapas: host discovery, port scan, enumeration, etc.
+type FullReconOrchestrator struct {
+	Stages   []Stage
+	Notifier *notify.Dispatcher // Recebe os eventos de cada etapa concluída (nil = sem notificações)
+}
 
-	// Cria o orquestrador de host discovery.
-	orchestrator := hostdiscovery.NewHostDiscoveryOrchestrator(strategy, params)
+// NewFullReconOrchestrator cria um novo orquestrador com as etapas informadas, executadas em ordem.
+func NewFullReconOrchestrator(stages ...Stage) *FullReconOrchestrator {
+	return &FullReconOrchestrator{Stages: stages}
+}
 
-	// Executa o host discovery.
-	discoveredHosts, err := orchestrator.Run()
-	if err != nil {
-		log.Printf("Error during host discovery: %v", err)
-		return err
+// Run executa as etapas de FullRecon em sequência, interrompendo na primeira falha.
+func (fr *FullReconOrchestrator) Run(ctx context.Context, state *State) error {
+	if len(state.Targets) == 0 {
+		return fmt.Errorf("no valid targets provided")
+	}
+	for _, stage := range fr.Stages {
+		// Fora da janela de scan, a próxima etapa só começa quando ela abrir.
+		util.WaitWindow(ctx)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fmt.Printf("\n%s %s", util.MarkerCyan, stage.Name())
+		fmt.Printf("\n%s %s Starting\n", util.MarkerCyan, util.GetFormattedTime())
+		fr.Notifier.Send(ctx, notify.Event{Type: notify.EventStageStarted, Source: "fullrecon", Stage: stage.Name(), Message: stage.Name() + " started"})
+		before := snapshot(state)
+		if err := stage.Run(ctx, state); err != nil {
+			return fmt.Errorf("%s: %w", stage.Name(), err)
+		}
+		fmt.Printf("%s %s %s Finished\n", util.MarkerCyan, util.GetFormattedTime(), stage.Name())
+		fr.Notifier.Send(ctx, stageEvents(stage.Name(), before, state)...)
 	}
-
-	fmt.Printf("Discovered hosts: %v\n", discoveredHosts)
-	// Aqui, você chamaria os outros módulos (portscan, enumeration, vulnanalysis) sequencialmente.
 	return nil
 }
@@ -0,0 +1,156 @@
+package fullrecon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Arthx-x/arthxrecon/internal/enumeration"
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/hostdiscovery"
+	"github.com/Arthx-x/arthxrecon/internal/nse"
+	"github.com/Arthx-x/arthxrecon/internal/portscan"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/internal/vulnanalysis"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ErrNoLiveHosts é retornado pelo host discovery quando nenhum alvo responde.
+var ErrNoLiveHosts = errors.New("no live hosts found")
+
+// DiscoveryStage executa o host discovery sobre os alvos informados.
+type DiscoveryStage struct {
+	Params   hostdiscovery.DiscoveryParams // Parâmetros do discovery; os alvos vêm do State
+	Strategy hostdiscovery.HostDiscoveryStrategy
+}
+
+// Name retorna o nome da etapa.
+func (s *DiscoveryStage) Name() string { return "Host Discovery" }
+
+// Run descobre os hosts ativos e os grava em state.Alive.
+func (s *DiscoveryStage) Run(ctx context.Context, state *State) error {
+	params := s.Params
+	params.Targets = state.Targets
+	params.FileMode = state.FileMode
+	alive, err := hostdiscovery.NewHostDiscoveryOrchestrator(s.Strategy, params).Run(ctx)
+	if err != nil {
+		return err
+	}
+	if len(alive) == 0 {
+		return ErrNoLiveHosts
+	}
+	state.Alive = alive
+	fmt.Printf("%s Discovered: %s %s\n", util.MarkerGreen, util.Green(strconv.Itoa(len(alive))), util.Green("Hosts"))
+	return nil
+}
+
+// PortScanStage executa o port scan sobre os hosts ativos.
+type PortScanStage struct {
+	Params   portscan.PortScanParams // Parâmetros do port scan; os alvos vêm do State
+	Strategy portscan.PortScanStrategy
+}
+
+// Name retorna o nome da etapa.
+func (s *PortScanStage) Name() string { return "Port Scan" }
+
+// Run varre os hosts ativos (ou os alvos informados, sem host discovery) e grava os serviços em state.Hosts.
+func (s *PortScanStage) Run(ctx context.Context, state *State) error {
+	params := s.Params
+	params.Targets, params.FileMode = state.Targets, state.FileMode
+	if len(state.Alive) > 0 {
+		params.Targets, params.FileMode = state.Alive, false
+	}
+	portscan.ShowConfiguration(params)
+	hosts, err := portscan.NewPortScanOrchestrator(s.Strategy, params).Run(ctx)
+	if err != nil {
+		return err
+	}
+	state.Hosts = hosts
+	state.Findings = append(state.Findings, nse.Collect(hosts)...)
+
+	tcpPorts, udpPorts := 0, 0
+	for _, host := range hosts {
+		tcpPorts += len(host.Services)
+		udpPorts += len(host.UDPServices)
+	}
+	fmt.Printf("%s Ports discovered: %s TCP, %s UDP\n", util.MarkerGreen, util.Green(strconv.Itoa(tcpPorts)), util.Green(strconv.Itoa(udpPorts)))
+	return nil
+}
+
+// EnumerationStage executa os módulos de enumeração habilitados no Registry.
+type EnumerationStage struct {
+	Registry   *enumeration.Registry
+	OutputFile string            // Nome base do JSON de resultados em util.EnumerationName
+	Limiter    *util.RateLimiter // Limite de taxa dos módulos nesta execução (nil = util.Limiter)
+}
+
+// Name retorna o nome da etapa.
+func (s *EnumerationStage) Name() string { return "Enumeration" }
+
+// Run executa os módulos sobre state.Hosts, acumula os achados e grava os hosts enriquecidos.
+func (s *EnumerationStage) Run(ctx context.Context, state *State) error {
+	if s.Limiter != nil {
+		ctx = util.WithLimiter(ctx, s.Limiter)
+	}
+	report := s.Registry.Run(ctx, state.Hosts)
+	for _, m := range report.Modules {
+		if m.Targets == 0 {
+			continue
+		}
+		fmt.Printf("%s %-10s %s/%d results, %d errors, %d timeouts\n", util.MarkerGreen, m.Name,
+			util.Green(strconv.Itoa(m.Results)), m.Targets, m.Errors, m.Timeouts)
+	}
+	state.Findings = append(state.Findings, report.Findings...)
+
+	if err := util.EnsureDir(util.EnumerationName); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", util.EnumerationName, err)
+	}
+	path := filepath.Join(util.EnumerationName, s.OutputFile+".json")
+	if err := results.SaveJSON(path, state.Hosts); err != nil {
+		return err
+	}
+	fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(path))
+	return nil
+}
+
+// VulnAnalysisStage cruza os serviços com a base local de CVEs. Sem base, a etapa é ignorada.
+type VulnAnalysisStage struct {
+	FeedPath   string  // Caminho da base local de vulnerabilidades
+	MinCVSS    float64 // CVSS mínimo para reportar
+	OutputFile string  // Nome base do JSON de resultados em util.VulnAnalysisName
+}
+
+// Name retorna o nome da etapa.
+func (s *VulnAnalysisStage) Name() string { return "Vulnerability Analysis" }
+
+// Run associa vulnerabilidades aos serviços de state.Hosts, acumula os achados e grava os hosts.
+func (s *VulnAnalysisStage) Run(ctx context.Context, state *State) error {
+	if _, err := os.Stat(s.FeedPath); errors.Is(err, os.ErrNotExist) {
+		util.StageLogger(vulnanalysis.ModuleName).Warn().Msgf("Vulnerability feed %s not found, skipping (run \"vulnanalysis update\" first)", s.FeedPath)
+		return nil
+	}
+	feed, err := vulnanalysis.LoadFeed(s.FeedPath)
+	if err != nil {
+		return err
+	}
+	matches := vulnanalysis.NewIndex(feed).Run(state.Hosts, s.MinCVSS)
+	list := make([]findings.Finding, 0, len(matches))
+	for _, m := range matches {
+		list = append(list, m.Finding())
+	}
+	state.Findings = append(state.Findings, list...)
+	fmt.Printf("%s Vulnerabilities: %s\n", util.MarkerGreen, util.Red(strconv.Itoa(len(matches))))
+
+	if err := util.EnsureDir(util.VulnAnalysisName); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", util.VulnAnalysisName, err)
+	}
+	path := filepath.Join(util.VulnAnalysisName, s.OutputFile+".json")
+	if err := results.SaveJSON(path, state.Hosts); err != nil {
+		return err
+	}
+	fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(path))
+	return nil
+}
@@ -1,7 +1,12 @@
 package hostdiscovery
 
 import (
+	"context"
 	"fmt"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/util"
+	"github.com/rs/zerolog"
 )
 
 // DiscoveryParams centraliza os parâmetros para a descoberta de hosts.
@@ -11,14 +16,31 @@ type DiscoveryParams struct {
 	Mode       string   // Modo do scan (aggressive, normal, passive)
 	Options    []string // Outras opções, se houver
 	FileMode   bool     // Indica se os targets vieram de um arquivo (modo arquivo)
+	MaxRate    int      // Máximo de pacotes por segundo (--max-rate no Nmap, --rate no Masscan); 0 = sem limite
+}
+
+// logger retorna o sub-logger da etapa de host discovery.
+func logger() *zerolog.Logger {
+	return util.StageLogger("hostdiscovery")
+}
+
+// NewStrategy é a factory que cria a estratégia de descoberta do engine informado (nmap ou masscan).
+func NewStrategy(engine string) (HostDiscoveryStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(engine)) {
+	case "", "nmap":
+		return NewNmapHostDiscovery(), nil
+	case "masscan":
+		return NewMasscanHostDiscovery(), nil
+	}
+	return nil, fmt.Errorf("unknown host discovery engine %q (available: %s)", engine, strings.Join(util.Engines, ", "))
 }
 
 // HostDiscoveryStrategy define a interface para uma estratégia de descoberta.
 type HostDiscoveryStrategy interface {
 	// Configure configura a estratégia com os parâmetros.
 	Configure(params DiscoveryParams) error
-	// Execute executa a descoberta de hosts e retorna a saída bruta.
-	Execute() (string, error)
+	// Execute executa a descoberta de hosts e retorna a saída bruta; o processo é encerrado se ctx terminar.
+	Execute(ctx context.Context) (string, error)
 	// Parse processa a saída bruta e retorna os hosts descobertos.
 	Parse(rawOutput string) ([]string, error)
 }
@@ -37,13 +59,13 @@ func NewHostDiscoveryOrchestrator(strategy HostDiscoveryStrategy, params Discove
 	}
 }
 
-// Run executa o fluxo completo: configura, executa e parseia a saída.
-func (orchestrator *HostDiscoveryOrchestrator) Run() ([]string, error) {
+// Run executa o fluxo completo: configura, executa e parseia a saída. Cancelar ctx interrompe o scan.
+func (orchestrator *HostDiscoveryOrchestrator) Run(ctx context.Context) ([]string, error) {
 	if err := orchestrator.Strategy.Configure(orchestrator.Params); err != nil {
 		return nil, fmt.Errorf("failed to configure host discovery: %w", err)
 	}
 
-	rawOutput, err := orchestrator.Strategy.Execute()
+	rawOutput, err := orchestrator.Strategy.Execute(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute host discovery: %w", err)
 	}
@@ -1,13 +1,14 @@
 package hostdiscovery
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
-	"github.com/rs/zerolog/log"
+	"github.com/Arthx-x/arthxrecon/util"
 	"github.com/tomsteele/go-nmap"
 )
 
@@ -19,6 +20,7 @@ type MasscanHostDiscovery struct {
 	Mode       string   // Modo do scan (por exemplo, "aggressive", "normal", "passive").
 	Options    []string // Outras opções de linha de comando para o Masscan.
 	FileMode   bool     // Indica se os targets foram informados via arquivo.
+	MaxRate    int      // Máximo de pacotes por segundo (--rate).
 }
 
 // NewMasscanHostDiscovery cria uma instância de MasscanHostDiscovery.
@@ -34,6 +36,7 @@ func (m *MasscanHostDiscovery) Configure(params DiscoveryParams) error {
 	m.Mode = params.Mode
 	m.Options = params.Options
 	m.FileMode = params.FileMode
+	m.MaxRate = params.MaxRate
 	return nil
 }
 
@@ -46,6 +49,9 @@ func (m *MasscanHostDiscovery) buildCommand() (string, []string) {
 	if len(m.Options) > 0 {
 		args = append(args, m.Options...)
 	}
+	if m.MaxRate > 0 {
+		args = append(args, "--rate", strconv.Itoa(m.MaxRate))
+	}
 	if m.FileMode {
 		// Se for modo arquivo, o primeiro elemento de Targets é o caminho para o arquivo.
 		args = append(args, "-iL", m.Targets[0])
@@ -61,15 +67,15 @@ func (m *MasscanHostDiscovery) buildCommand() (string, []string) {
 }
 
 // Execute executa o comando masscan e, após sua conclusão, lê o arquivo XML gerado e retorna seu conteúdo.
-func (m *MasscanHostDiscovery) Execute() (string, error) {
+// O processo é encerrado se ctx terminar.
+func (m *MasscanHostDiscovery) Execute(ctx context.Context) (string, error) {
 	commandStr, args := m.buildCommand()
-	log.Info().Msgf("Executing host discovery with masscan: %s", commandStr)
-	cmd := exec.Command("masscan", args...)
-	var outputBuffer bytes.Buffer
-	cmd.Stdout = &outputBuffer
-	cmd.Stderr = &outputBuffer
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("masscan execution failed: %w", err)
+	logger().Info().Msgf("Executing host discovery with masscan: %s", commandStr)
+	cmd := exec.CommandContext(ctx, "masscan", args...)
+	output := util.NewTailBuffer(0)
+	cmd.Stdout, cmd.Stderr = output, output
+	if err := util.RunCommand(ctx, logger(), cmd); err != nil {
+		return "", util.CommandError("masscan", err, output)
 	}
 	xmlFilePath := m.OutputFile + ".xml"
 	data, err := os.ReadFile(xmlFilePath)
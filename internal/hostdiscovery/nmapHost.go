@@ -1,15 +1,15 @@
 package hostdiscovery
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/Arthx-x/arthxrecon/util"
-	"github.com/rs/zerolog/log"
 	"github.com/tomsteele/go-nmap"
 )
 
@@ -20,6 +20,7 @@ type NmapHostDiscovery struct {
 	Mode       string   // Modo do scan (aggressive, normal, passive)
 	Options    []string // Outras opções, se houver
 	FileMode   bool     // Indica se os targets vieram de um arquivo (modo arquivo)
+	MaxRate    int      // Máximo de pacotes por segundo (--max-rate)
 }
 
 // NewNmapHostDiscovery é a factory que cria uma instância de NmapHostDiscovery.
@@ -34,6 +35,10 @@ func (nmapHD *NmapHostDiscovery) Configure(params DiscoveryParams) error {
 	nmapHD.Mode = params.Mode
 	nmapHD.Options = params.Options
 	nmapHD.FileMode = params.FileMode
+	nmapHD.MaxRate = params.MaxRate
+	if _, err := util.AppConfig.TimingOptions(nmapHD.Mode); err != nil {
+		return err
+	}
 
 	// fmt.Printf("\n┌──────────────────────────────────────────────┐\n  %s Target \t: %s \n  %s Output \t: %s \n  %s Mode \t: %s \n  %s Options \t: %s \n└──────────────────────────────────────────────┘\n\n",
 	// 	util.MarkerGreen,
@@ -69,30 +74,23 @@ func (nmapHD *NmapHostDiscovery) buildCommand() (string, []string) {
 
 	outputDir := util.HostDiscoveryName
 	if err := util.EnsureDir(outputDir); err != nil {
-		log.Fatal().Msgf("Error creating directory %s: %v", outputDir, err)
+		logger().Fatal().Msgf("Error creating directory %s: %v", outputDir, err)
 	}
 
-	args := []string{"-sn", util.HostDiscoveryFlagNmap}
+	args := []string{"-sn"}
+	if util.HostDiscoveryFlagNmap != "" {
+		args = append(args, util.HostDiscoveryFlagNmap)
+	}
 	if len(nmapHD.Options) > 0 {
 		args = append(args, nmapHD.Options...)
 	}
 
-	// Adiciona o flag de modo.
-	modeFlag := ""
-	lowerMode := strings.ToLower(strings.TrimSpace(nmapHD.Mode))
-	switch lowerMode {
-	case "aggressive", "3":
-		modeFlag = "-T4"
-	case "stealth", "1":
-		modeFlag = "-T2"
-		// Para stealth, pode-se adicionar opções extras se necessário.
-	case "normal", "2":
-		// Sem flag adicional.
-	default:
-		// Valor padrão.
-	}
-	if modeFlag != "" {
-		args = append(args, modeFlag)
+	// Adiciona as opções de timing do modo, definidas em [modes] no config.toml.
+	// O modo já foi validado em Configure.
+	timing, _ := util.AppConfig.TimingOptions(nmapHD.Mode)
+	args = append(args, timing...)
+	if nmapHD.MaxRate > 0 {
+		args = append(args, "--max-rate", strconv.Itoa(nmapHD.MaxRate))
 	}
 
 	// Se for fileMode, utiliza o primeiro (único) target como caminho para o arquivo.
@@ -108,20 +106,19 @@ func (nmapHD *NmapHostDiscovery) buildCommand() (string, []string) {
 	return commandStr, args
 }
 
-// Execute executa o comando nmap e retorna a saída bruta.
-func (nmapHD *NmapHostDiscovery) Execute() (string, error) {
+// Execute executa o comando nmap e retorna a saída bruta. O processo é encerrado se ctx terminar.
+func (nmapHD *NmapHostDiscovery) Execute(ctx context.Context) (string, error) {
 	commandStr, args := nmapHD.buildCommand()
 	//fmt.Printf("%s Running: ", util.MarkerGreen+util.Red(commandStr))
 	fmt.Printf("%s Running: %s\n", util.MarkerGreen, util.Green(commandStr))
 	//log.Info().Msgf("Executing host discovery: %s", commandStr)
 
-	cmd := exec.Command("nmap", args...)
-	var outputBuffer bytes.Buffer
-	cmd.Stdout = &outputBuffer
-	cmd.Stderr = &outputBuffer
-	err := cmd.Run()
+	cmd := exec.CommandContext(ctx, "nmap", args...)
+	output := util.NewTailBuffer(0)
+	cmd.Stdout, cmd.Stderr = output, output
+	err := util.RunCommand(ctx, logger(), cmd)
 	if err != nil {
-		return "", fmt.Errorf("nmap execution failed: %w", err)
+		return "", util.CommandError("nmap", err, output)
 	}
 
 	// Após a execução, lemos o arquivo XML gerado.
@@ -153,7 +150,7 @@ func (nmapHD *NmapHostDiscovery) Parse(rawOutput string) ([]string, error) {
 	// Cria ou sobrescreve o arquivo targets.txt na pasta "hostDiscovery".
 	targetsFile := filepath.Join(util.HostDiscoveryName, "targets.txt")
 	if err := util.WriteTargetsToFile(targetsFile, hosts); err != nil {
-		log.Error().Err(err).Msg("Failed to write targets.txt")
+		logger().Error().Err(err).Msg("Failed to write targets.txt")
 	} else {
 		fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(targetsFile))
 		//log.Info().Msgf("Targets extracted successfully to: %s", targetsFile)
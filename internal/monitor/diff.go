@@ -0,0 +1,125 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// Change é uma diferença entre duas execuções. Type é um dos tipos de evento de notify.
+type Change struct {
+	Type     string `json:"type"`
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Old      string `json:"old,omitempty"` // Serviço anterior (service_changed)
+	New      string `json:"new,omitempty"` // Serviço atual (service_changed, port_opened)
+}
+
+// Diff compara os hosts de duas execuções: hosts novos e ausentes, portas abertas e fechadas
+// em hosts presentes nas duas, e serviços cuja identificação (nome, produto ou versão) mudou.
+func Diff(prev, curr []results.Host) []Change {
+	before := indexHosts(prev)
+	var changes []Change
+	for _, host := range curr {
+		old, ok := before[host.Address]
+		if !ok {
+			changes = append(changes, Change{Type: notify.EventNewHost, Host: host.Address, New: portSummary(host)})
+			continue
+		}
+		delete(before, host.Address)
+		changes = append(changes, diffServices(host.Address, old.Services, host.Services)...)
+		changes = append(changes, diffServices(host.Address, old.UDPServices, host.UDPServices)...)
+	}
+	for _, host := range prev {
+		if _, gone := before[host.Address]; gone {
+			changes = append(changes, Change{Type: notify.EventHostGone, Host: host.Address, Old: portSummary(host)})
+		}
+	}
+	return changes
+}
+
+// indexHosts indexa os hosts pelo endereço.
+func indexHosts(hosts []results.Host) map[string]results.Host {
+	index := make(map[string]results.Host, len(hosts))
+	for _, host := range hosts {
+		index[host.Address] = host
+	}
+	return index
+}
+
+// diffServices compara os serviços de um mesmo protocolo de um host.
+func diffServices(address string, prev, curr []results.Service) []Change {
+	before := make(map[int]results.Service, len(prev))
+	for _, svc := range prev {
+		before[svc.Port] = svc
+	}
+	var changes []Change
+	for _, svc := range curr {
+		old, ok := before[svc.Port]
+		if !ok {
+			changes = append(changes, Change{Type: notify.EventPortOpened, Host: address, Port: svc.Port, Protocol: svc.Protocol, New: serviceLabel(svc)})
+			continue
+		}
+		delete(before, svc.Port)
+		// Sem identificação em uma das execuções (ex.: scan simples), não há o que comparar.
+		if o, n := serviceLabel(old), serviceLabel(svc); o != "" && n != "" && o != n {
+			changes = append(changes, Change{Type: notify.EventServiceChanged, Host: address, Port: svc.Port, Protocol: svc.Protocol, Old: o, New: n})
+		}
+	}
+	var closed []int
+	for port := range before {
+		closed = append(closed, port)
+	}
+	sort.Ints(closed)
+	for _, port := range closed {
+		old := before[port]
+		changes = append(changes, Change{Type: notify.EventPortClosed, Host: address, Port: port, Protocol: old.Protocol, Old: serviceLabel(old)})
+	}
+	return changes
+}
+
+// serviceLabel descreve a identificação do serviço (ex.: "ssh OpenSSH 8.9p1").
+func serviceLabel(svc results.Service) string {
+	return strings.Join(strings.Fields(svc.Name+" "+svc.Product+" "+svc.Version), " ")
+}
+
+// portSummary lista as portas abertas do host (ex.: "22/tcp, 80/tcp, 161/udp").
+func portSummary(host results.Host) string {
+	var ports []string
+	for _, svc := range host.Services {
+		ports = append(ports, fmt.Sprintf("%d/tcp", svc.Port))
+	}
+	for _, svc := range host.UDPServices {
+		ports = append(ports, fmt.Sprintf("%d/udp", svc.Port))
+	}
+	return strings.Join(ports, ", ")
+}
+
+// Event converte a mudança no evento enviado aos notificadores.
+func (c Change) Event() notify.Event {
+	event := notify.Event{Type: c.Type, Source: "monitor", Host: c.Host, Port: c.Port, Protocol: c.Protocol, Old: c.Old, New: c.New}
+	target := fmt.Sprintf("%s:%d/%s", c.Host, c.Port, c.Protocol)
+	switch c.Type {
+	case notify.EventNewHost:
+		event.Message = fmt.Sprintf("New host %s", c.Host)
+		if c.New != "" {
+			event.Message += " (" + c.New + ")"
+		}
+	case notify.EventHostGone:
+		event.Message = fmt.Sprintf("Host %s is no longer reachable", c.Host)
+	case notify.EventPortOpened:
+		event.Message = fmt.Sprintf("Port opened on %s", target)
+		if c.New != "" {
+			event.Message += " (" + c.New + ")"
+		}
+	case notify.EventPortClosed:
+		event.Message = fmt.Sprintf("Port closed on %s", target)
+	case notify.EventServiceChanged:
+		event.Message = fmt.Sprintf("Service changed on %s: %s -> %s", target, c.Old, c.New)
+	}
+	return event
+}
@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+func TestDiff(t *testing.T) {
+	ssh := results.Service{Protocol: "tcp", Port: 22, Name: "ssh", Product: "OpenSSH", Version: "8.9p1"}
+	http := results.Service{Protocol: "tcp", Port: 80, Name: "http"}
+	snmp := results.Service{Protocol: "udp", Port: 161, Name: "snmp"}
+	host := func(address string, services []results.Service, udp ...results.Service) results.Host {
+		return results.Host{Address: address, Services: services, UDPServices: udp}
+	}
+
+	for name, tc := range map[string]struct {
+		prev, curr []results.Host
+		want       []Change
+	}{
+		"unchanged": {
+			prev: []results.Host{host("10.0.0.1", []results.Service{ssh}, snmp)},
+			curr: []results.Host{host("10.0.0.1", []results.Service{ssh}, snmp)},
+		},
+		"new host": {
+			prev: []results.Host{host("10.0.0.1", nil)},
+			curr: []results.Host{host("10.0.0.1", nil), host("10.0.0.2", []results.Service{ssh, http}, snmp)},
+			want: []Change{{Type: notify.EventNewHost, Host: "10.0.0.2", New: "22/tcp, 80/tcp, 161/udp"}},
+		},
+		"gone host": {
+			prev: []results.Host{host("10.0.0.1", []results.Service{ssh}), host("10.0.0.2", nil)},
+			curr: []results.Host{host("10.0.0.2", nil)},
+			want: []Change{{Type: notify.EventHostGone, Host: "10.0.0.1", Old: "22/tcp"}},
+		},
+		"all hosts gone": {
+			prev: []results.Host{host("10.0.0.1", nil), host("10.0.0.2", nil)},
+			want: []Change{{Type: notify.EventHostGone, Host: "10.0.0.1"}, {Type: notify.EventHostGone, Host: "10.0.0.2"}},
+		},
+		"opened and closed ports": {
+			prev: []results.Host{host("10.0.0.1", []results.Service{ssh})},
+			curr: []results.Host{host("10.0.0.1", []results.Service{http}, snmp)},
+			want: []Change{
+				{Type: notify.EventPortOpened, Host: "10.0.0.1", Port: 80, Protocol: "tcp", New: "http"},
+				{Type: notify.EventPortClosed, Host: "10.0.0.1", Port: 22, Protocol: "tcp", Old: "ssh OpenSSH 8.9p1"},
+				{Type: notify.EventPortOpened, Host: "10.0.0.1", Port: 161, Protocol: "udp", New: "snmp"},
+			},
+		},
+		"version change": {
+			prev: []results.Host{host("10.0.0.1", []results.Service{ssh})},
+			curr: []results.Host{host("10.0.0.1", []results.Service{{Protocol: "tcp", Port: 22, Name: "ssh", Product: "OpenSSH", Version: "9.6p1"}})},
+			want: []Change{{Type: notify.EventServiceChanged, Host: "10.0.0.1", Port: 22, Protocol: "tcp", Old: "ssh OpenSSH 8.9p1", New: "ssh OpenSSH 9.6p1"}},
+		},
+		"unidentified service": {
+			// Um scan sem detecção de versão não gera service_changed.
+			prev: []results.Host{host("10.0.0.1", []results.Service{ssh})},
+			curr: []results.Host{host("10.0.0.1", []results.Service{{Protocol: "tcp", Port: 22}})},
+		},
+	} {
+		if got := Diff(tc.prev, tc.curr); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: changes %+v, want %+v", name, got, tc.want)
+		}
+	}
+}
+
+func TestChangeEvent(t *testing.T) {
+	event := Change{Type: notify.EventServiceChanged, Host: "10.0.0.1", Port: 22, Protocol: "tcp", Old: "ssh OpenSSH 8.9p1", New: "ssh OpenSSH 9.6p1"}.Event()
+	if event.Source != ModuleName || event.Message != "Service changed on 10.0.0.1:22/tcp: ssh OpenSSH 8.9p1 -> ssh OpenSSH 9.6p1" {
+		t.Errorf("event: %+v", event)
+	}
+	if event := (Change{Type: notify.EventHostGone, Host: "10.0.0.1"}).Event(); event.Message != "Host 10.0.0.1 is no longer reachable" {
+		t.Errorf("host gone message: %q", event.Message)
+	}
+}
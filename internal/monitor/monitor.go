@@ -0,0 +1,166 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ModuleName é o nome do estágio nos logs.
+const ModuleName = "monitor"
+
+// MonitorParams reúne os parâmetros do monitoramento.
+type MonitorParams struct {
+	Targets  []string          // Alvos (IPs, CIDRs ou o caminho de um arquivo)
+	FileMode bool              // Indica se Targets contém o caminho de um arquivo de alvos
+	Stages   []fullrecon.Stage // Etapas de cada execução (host discovery e port scan)
+	Interval time.Duration     // Intervalo entre o início de duas execuções
+	Keep     int               // Execuções mantidas (0 mantém todas)
+	Once     bool              // Executa uma única vez e retorna
+}
+
+// Monitor repete as etapas a cada intervalo, grava cada execução e notifica as mudanças.
+type Monitor struct {
+	Params   MonitorParams
+	Store    *Store
+	Notifier *notify.Dispatcher
+}
+
+// NewMonitor é a factory que cria o Monitor.
+func NewMonitor(params MonitorParams, store *Store, notifier *notify.Dispatcher) *Monitor {
+	return &Monitor{Params: params, Store: store, Notifier: notifier}
+}
+
+// Run executa o monitoramento até ctx ser cancelado (ou uma única vez, com Once).
+// A falha de uma execução é gravada e notificada, mas não interrompe o monitoramento.
+func (m *Monitor) Run(ctx context.Context) error {
+	baseline, err := m.Store.Latest()
+	if err != nil {
+		return fmt.Errorf("error reading the previous runs in %s: %w", m.Store.Dir, err)
+	}
+	if baseline != nil {
+		fmt.Printf("%s Baseline: run %s (%s hosts)\n", util.MarkerCyan, util.Cyan(baseline.ID), util.Cyan(strconv.Itoa(len(baseline.Hosts))))
+	}
+	for {
+		run := m.runOnce(ctx, baseline)
+		if ctx.Err() != nil {
+			// Execução interrompida: não é gravada nem comparada.
+			return nil
+		}
+		m.finish(ctx, run, baseline)
+		if !run.Failed() {
+			baseline = run
+		}
+		if m.Params.Once {
+			return nil
+		}
+
+		next := run.Started.Add(m.Params.Interval)
+		if now := time.Now(); next.Before(now) {
+			next = now
+		}
+		fmt.Printf("\n%s Next run at %s\n", util.MarkerCyan, util.Cyan(next.Format("2006-01-02 15:04:05")))
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// runOnce executa as etapas uma vez e compara o resultado com a execução base.
+func (m *Monitor) runOnce(ctx context.Context, baseline *Run) (run *Run) {
+	run = &Run{Started: time.Now(), Targets: m.Params.Targets}
+	run.ID = run.Started.Format(runIDFormat)
+	fmt.Printf("\n%s %s Monitor run %s\n", util.MarkerCyan, util.GetFormattedTime(), util.Cyan(run.ID))
+
+	defer func() {
+		// Uma falha inesperada em uma execução não derruba o monitoramento.
+		if r := recover(); r != nil {
+			run.Error = fmt.Sprintf("panic: %v", r)
+		}
+		run.Finished = time.Now()
+	}()
+
+	state := &fullrecon.State{Targets: m.Params.Targets, FileMode: m.Params.FileMode}
+	err := fullrecon.NewFullReconOrchestrator(m.Params.Stages...).Run(ctx, state)
+	switch {
+	case errors.Is(err, fullrecon.ErrNoLiveHosts):
+		// Nenhum host ativo não é uma falha: a execução vazia gera host_gone para os hosts da base.
+	case err != nil:
+		run.Error = err.Error()
+		return run
+	}
+	run.Hosts = liveHosts(state)
+	if baseline != nil {
+		run.Changes = Diff(baseline.Hosts, run.Hosts)
+	}
+	return run
+}
+
+// liveHosts retorna os hosts do port scan e, sem portas abertas, os demais hosts ativos do discovery.
+func liveHosts(state *fullrecon.State) []results.Host {
+	hosts := state.Hosts
+	seen := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		seen[host.Address] = true
+	}
+	for _, address := range state.Alive {
+		if !seen[address] {
+			hosts = append(hosts, results.Host{Address: address, Services: []results.Service{}})
+			seen[address] = true
+		}
+	}
+	return hosts
+}
+
+// finish grava a execução, notifica as mudanças (ou a falha) e remove as execuções antigas.
+func (m *Monitor) finish(ctx context.Context, run *Run, baseline *Run) {
+	logger := util.StageLogger(ModuleName)
+	if path, err := m.Store.Save(run); err != nil {
+		logger.Error().Err(err).Str("run", run.ID).Msg("Failed to save the monitor run")
+	} else {
+		fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(path))
+	}
+
+	switch {
+	case run.Failed():
+		fmt.Printf("%s Run %s failed: %s\n", util.MarkerRed, run.ID, run.Error)
+		logger.Error().Str("run", run.ID).Str("error", run.Error).Msg("Monitor run failed")
+		m.Notifier.Send(ctx, notify.Event{
+			Type:    notify.EventRunFailed,
+			Time:    run.Finished,
+			Source:  ModuleName,
+			Message: fmt.Sprintf("Monitor run %s failed: %s", run.ID, run.Error),
+		})
+	case baseline == nil:
+		fmt.Printf("%s Baseline recorded: %s hosts\n", util.MarkerGreen, util.Green(strconv.Itoa(len(run.Hosts))))
+		logger.Info().Str("run", run.ID).Int("hosts", len(run.Hosts)).Msg("Monitor baseline recorded")
+	default:
+		fmt.Printf("%s Changes since %s: %s\n", util.MarkerGreen, baseline.ID, util.Green(strconv.Itoa(len(run.Changes))))
+		logger.Info().Str("run", run.ID).Str("baseline", baseline.ID).Int("changes", len(run.Changes)).Msg("Monitor run finished")
+		events := make([]notify.Event, 0, len(run.Changes))
+		for _, change := range run.Changes {
+			event := change.Event()
+			event.Time = run.Finished
+			events = append(events, event)
+		}
+		m.Notifier.Send(ctx, events...)
+	}
+
+	removed, err := m.Store.Prune(m.Params.Keep)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to remove old monitor runs")
+	} else if removed > 0 {
+		logger.Info().Int("removed", removed).Int("keep", m.Params.Keep).Msg("Old monitor runs removed")
+	}
+}
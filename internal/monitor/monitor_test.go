@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// stage é uma etapa de teste que executa run sobre o estado.
+type stage struct {
+	run func(state *fullrecon.State) error
+}
+
+func (s stage) Name() string { return "Test" }
+
+func (s stage) Run(ctx context.Context, state *fullrecon.State) error { return s.run(state) }
+
+// recorder guarda os eventos recebidos.
+type recorder struct {
+	mu     sync.Mutex
+	events []notify.Event
+}
+
+func (r *recorder) Name() string { return "recorder" }
+
+func (r *recorder) Notify(ctx context.Context, event notify.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// runMonitor executa uma vez o monitoramento com a etapa informada e retorna os eventos enviados.
+func runMonitor(t *testing.T, store *Store, s stage) []notify.Event {
+	t.Helper()
+	rec := &recorder{}
+	params := MonitorParams{Targets: []string{"10.0.0.0/24"}, Stages: []fullrecon.Stage{s}, Interval: time.Hour, Once: true}
+	if err := NewMonitor(params, store, (*notify.Dispatcher)(nil).With(rec)).Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var events []notify.Event
+	for _, event := range rec.events {
+		if event.Source == ModuleName {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestMonitorNoLiveHosts(t *testing.T) {
+	store := NewStore(t.TempDir())
+	baseline := &Run{ID: "20240101T000000", Hosts: []results.Host{{Address: "10.0.0.1"}, {Address: "10.0.0.2"}}}
+	if _, err := store.Save(baseline); err != nil {
+		t.Fatal(err)
+	}
+
+	dark := stage{run: func(state *fullrecon.State) error { return fullrecon.ErrNoLiveHosts }}
+	events := runMonitor(t, store, dark)
+	if len(events) != 2 || events[0].Type != notify.EventHostGone || events[1].Host != "10.0.0.2" {
+		t.Fatalf("events after the scope went dark: %+v", events)
+	}
+	latest, err := store.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Failed() || len(latest.Hosts) != 0 || len(latest.Changes) != 2 {
+		t.Errorf("empty run: %+v", latest)
+	}
+}
+
+func TestMonitorFailedRun(t *testing.T) {
+	store := NewStore(t.TempDir())
+	failing := stage{run: func(state *fullrecon.State) error { return errors.New("nmap failed") }}
+	events := runMonitor(t, store, failing)
+	if len(events) != 1 || events[0].Type != notify.EventRunFailed {
+		t.Fatalf("events: %+v", events)
+	}
+	if latest, err := store.Latest(); latest != nil || err != nil {
+		t.Errorf("failed run used as baseline: %+v, %v", latest, err)
+	}
+	if ids, _ := store.List(); len(ids) != 1 {
+		t.Errorf("failed run not saved: %v", ids)
+	}
+}
@@ -0,0 +1,143 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// runIDFormat é o formato do ID de cada execução, usado também como nome do arquivo.
+const runIDFormat = "20060102T150405"
+
+// Run é uma execução do monitoramento: os hosts encontrados e as mudanças em relação à anterior.
+type Run struct {
+	ID       string         `json:"id"`
+	Started  time.Time      `json:"started"`
+	Finished time.Time      `json:"finished"`
+	Targets  []string       `json:"targets"`
+	Error    string         `json:"error,omitempty"` // Motivo da falha; execuções com falha não servem de base
+	Hosts    []results.Host `json:"hosts"`
+	Changes  []Change       `json:"changes,omitempty"`
+}
+
+// Failed indica se a execução falhou.
+func (r *Run) Failed() bool {
+	return r.Error != ""
+}
+
+// Store guarda as execuções, um JSON por execução, em <Dir>/runs.
+type Store struct {
+	Dir string
+}
+
+// NewStore cria o Store das execuções em dir.
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// runsDir retorna o diretório dos arquivos das execuções.
+func (s *Store) runsDir() string {
+	return filepath.Join(s.Dir, "runs")
+}
+
+// Save grava a execução em <Dir>/runs/<ID>.json e retorna o caminho do arquivo.
+func (s *Store) Save(run *Run) (string, error) {
+	if err := util.EnsureDir(s.runsDir()); err != nil {
+		return "", fmt.Errorf("error creating directory %s: %w", s.runsDir(), err)
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.runsDir(), run.ID+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// List retorna os IDs das execuções gravadas, da mais antiga para a mais recente.
+func (s *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(s.runsDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, entry := range entries {
+		if id, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Load lê a execução com o ID informado.
+func (s *Store) Load(id string) (*Run, error) {
+	path := filepath.Join(s.runsDir(), id+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("invalid run %s: %w", path, err)
+	}
+	return &run, nil
+}
+
+// Latest retorna a execução bem-sucedida mais recente, ou nil quando não há nenhuma.
+func (s *Store) Latest() (*Run, error) {
+	ids, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(ids) - 1; i >= 0; i-- {
+		run, err := s.Load(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		if !run.Failed() {
+			return run, nil
+		}
+	}
+	return nil, nil
+}
+
+// Prune mantém apenas as keep execuções mais recentes (0 mantém todas) e retorna quantas removeu.
+// A execução bem-sucedida mais recente é sempre mantida, pois é a base da próxima comparação.
+func (s *Store) Prune(keep int) (int, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+	ids, err := s.List()
+	if err != nil || len(ids) <= keep {
+		return 0, err
+	}
+	baseline, err := s.Latest()
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, id := range ids[:len(ids)-keep] {
+		if baseline != nil && id == baseline.ID {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.runsDir(), id+".json")); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
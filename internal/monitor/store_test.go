@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// saveRuns grava uma execução por ID; os IDs em failed são gravados como falhas.
+func saveRuns(t *testing.T, store *Store, ids []string, failed ...string) {
+	t.Helper()
+	for _, id := range ids {
+		run := &Run{ID: id, Hosts: []results.Host{{Address: "10.0.0.1"}}}
+		for _, f := range failed {
+			if f == id {
+				run.Error = "port scan: nmap failed"
+			}
+		}
+		if _, err := store.Save(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStoreLatest(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if run, err := store.Latest(); run != nil || err != nil {
+		t.Fatalf("empty store: %+v, %v", run, err)
+	}
+
+	saveRuns(t, store, []string{"20240101T000000", "20240102T000000", "20240103T000000"}, "20240102T000000", "20240103T000000")
+	run, err := store.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run == nil || run.ID != "20240101T000000" || len(run.Hosts) != 1 {
+		t.Errorf("latest successful run: %+v", run)
+	}
+
+	// Arquivos que não são execuções são ignorados.
+	if err := os.WriteFile(filepath.Join(store.runsDir(), "notes.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ids, _ := store.List(); len(ids) != 3 {
+		t.Errorf("runs: %v", ids)
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	ids := []string{"20240101T000000", "20240102T000000", "20240103T000000", "20240104T000000", "20240105T000000"}
+	for name, tc := range map[string]struct {
+		keep    int
+		failed  []string
+		removed int
+		left    []string
+	}{
+		"keep all":       {keep: 0, removed: 0, left: ids},
+		"keep more":      {keep: 10, removed: 0, left: ids},
+		"keep latest":    {keep: 2, removed: 3, left: ids[3:]},
+		"keep baseline":  {keep: 2, failed: ids[2:], removed: 2, left: []string{ids[1], ids[3], ids[4]}},
+		"all failed":     {keep: 1, failed: ids, removed: 4, left: ids[4:]},
+		"latest is base": {keep: 1, failed: ids[:4], removed: 4, left: ids[4:]},
+	} {
+		store := NewStore(t.TempDir())
+		saveRuns(t, store, ids, tc.failed...)
+		removed, err := store.Prune(tc.keep)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		left, _ := store.List()
+		if removed != tc.removed || !reflect.DeepEqual(left, tc.left) {
+			t.Errorf("%s: removed %d, left %v; want %d, %v", name, removed, left, tc.removed, tc.left)
+		}
+	}
+}
+
+func TestStoreSaveLoad(t *testing.T) {
+	store := NewStore(t.TempDir())
+	run := &Run{ID: "20240101T000000", Started: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Targets: []string{"10.0.0.0/24"},
+		Hosts: []results.Host{{Address: "10.0.0.1", Services: []results.Service{{Protocol: "tcp", Port: 22, Name: "ssh"}}}}}
+	path, err := store.Save(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(store.Dir, "runs", run.ID+".json"); path != want {
+		t.Errorf("path %s, want %s", path, want)
+	}
+	loaded, err := store.Load(run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, run) {
+		t.Errorf("loaded %+v, want %+v", loaded, run)
+	}
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// logNotifier registra os eventos no log da aplicação e os exibe no console.
+type logNotifier struct{}
+
+// newLogNotifier cria o notificador do tipo "log".
+func newLogNotifier(cfg util.NotifierConfig) (Notifier, error) {
+	return logNotifier{}, nil
+}
+
+// Name retorna o nome do notificador.
+func (logNotifier) Name() string { return "log" }
+
+// Notify exibe o evento e o registra no log com os campos estruturados.
+func (logNotifier) Notify(ctx context.Context, event Event) error {
+	marker := util.MarkerYellow
+	if event.Type == EventRunFailed {
+		marker = util.MarkerRed
+	}
+	fmt.Printf("%s %s\n", marker, event.Message)
+	entry := util.StageLogger("notify").Info().Str("event", event.Type)
+	if event.Host != "" {
+		entry = entry.Str("host", event.Host)
+	}
+	if event.Port != 0 {
+		entry = entry.Int("port", event.Port).Str("protocol", event.Protocol)
+	}
+	if event.Old != "" {
+		entry = entry.Str("old", event.Old)
+	}
+	if event.New != "" {
+		entry = entry.Str("new", event.New)
+	}
+	entry.Msg(event.Message)
+	return nil
+}
+
+// fileNotifier acrescenta os eventos, um JSON por linha, a um arquivo.
+type fileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// newFileNotifier cria o notificador do tipo "file"; path é obrigatório.
+func newFileNotifier(cfg util.NotifierConfig) (Notifier, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("path is required")
+	}
+	return &fileNotifier{path: cfg.Path}, nil
+}
+
+// Name retorna o nome do notificador.
+func (n *fileNotifier) Name() string { return "file " + n.path }
+
+// Notify acrescenta o evento ao arquivo.
+func (n *fileNotifier) Notify(ctx context.Context, event Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if dir := filepath.Dir(n.path); dir != "." {
+		if err := util.EnsureDir(dir); err != nil {
+			return err
+		}
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(n.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
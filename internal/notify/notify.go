@@ -0,0 +1,206 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// Tipos de evento enviados aos notificadores.
+const (
+	EventNewHost        = "new_host"        // Host que não existia no run anterior
+	EventHostGone       = "host_gone"       // Host do run anterior que não respondeu
+	EventPortOpened     = "port_opened"     // Porta aberta em um host já conhecido
+	EventPortClosed     = "port_closed"     // Porta que estava aberta no run anterior
+	EventServiceChanged = "service_changed" // Serviço, produto ou versão diferente na mesma porta
+	EventRunFailed      = "run_failed"      // Execução do monitoramento que falhou
+	EventStageStarted   = "stage_started"   // Etapa do pipeline iniciada
+	EventStageCompleted = "stage_completed" // Etapa do pipeline concluída
+	EventFinding        = "finding"         // Achado com severidade a partir de min_severity
+)
+
+// EventTypes lista os tipos de evento aceitos no filtro "events" dos notificadores.
+var EventTypes = []string{EventNewHost, EventHostGone, EventPortOpened, EventPortClosed, EventServiceChanged,
+	EventRunFailed, EventStageStarted, EventStageCompleted, EventFinding}
+
+// Event é uma notificação: o que mudou, onde e uma mensagem pronta para exibição.
+type Event struct {
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	Source   string    `json:"source"`          // Quem gerou o evento (ex.: monitor, fullrecon)
+	Stage    string    `json:"stage,omitempty"` // Etapa do pipeline (stage_completed)
+	Host     string    `json:"host,omitempty"`
+	Port     int       `json:"port,omitempty"`
+	Protocol string    `json:"protocol,omitempty"`
+	Old      string    `json:"old,omitempty"`      // Valor anterior (ex.: serviço antes da mudança)
+	New      string    `json:"new,omitempty"`      // Valor atual
+	Severity string    `json:"severity,omitempty"` // Severidade do achado (finding)
+	Title    string    `json:"title,omitempty"`    // Título do achado (finding)
+	Message  string    `json:"message"`
+}
+
+// Notifier entrega eventos a um destino (log, arquivo...).
+type Notifier interface {
+	// Name retorna o nome do notificador, usado nos logs.
+	Name() string
+	// Notify entrega um evento.
+	Notify(ctx context.Context, event Event) error
+}
+
+// factories cria os notificadores de cada tipo configurado em [[notifiers]].
+var factories = map[string]func(cfg util.NotifierConfig) (Notifier, error){
+	"log":     newLogNotifier,
+	"file":    newFileNotifier,
+	"webhook": newWebhookNotifier,
+}
+
+// queuedTypes são os tipos de notificador entregues em segundo plano, por uma fila (ver queued).
+var queuedTypes = map[string]bool{"webhook": true}
+
+// Types lista os tipos de notificador disponíveis.
+func Types() []string {
+	var types []string
+	for name := range factories {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// route é um notificador, os eventos que ele recebe e como a mensagem é montada.
+type route struct {
+	notifier    Notifier
+	events      map[string]bool    // Tipos de evento aceitos (nil = todos)
+	minSeverity string             // Severidade mínima dos eventos finding
+	template    *template.Template // Modelo da mensagem (nil = mensagem padrão)
+}
+
+// Dispatcher envia cada evento aos notificadores que o aceitam.
+type Dispatcher struct {
+	routes []route
+}
+
+// New é a factory que cria o Dispatcher dos notificadores configurados.
+func New(configs []util.NotifierConfig) (*Dispatcher, error) {
+	d := &Dispatcher{}
+	for i, cfg := range configs {
+		factory, ok := factories[cfg.Type]
+		if !ok {
+			return nil, fmt.Errorf("notifiers[%d]: unknown type %q (available: %s)", i, cfg.Type, strings.Join(Types(), ", "))
+		}
+		n, err := factory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("notifiers[%d] (%s): %w", i, cfg.Type, err)
+		}
+		if queuedTypes[cfg.Type] {
+			n = newQueued(n, queueSize)
+		}
+		r := route{notifier: n, minSeverity: findings.SeverityHigh}
+		for _, event := range cfg.Events {
+			if !validEvent(event) {
+				return nil, fmt.Errorf("notifiers[%d] (%s): unknown event %q (available: %s)", i, cfg.Type, event, strings.Join(EventTypes, ", "))
+			}
+			if r.events == nil {
+				r.events = make(map[string]bool)
+			}
+			r.events[event] = true
+		}
+		if cfg.MinSeverity != "" {
+			if !findings.ValidSeverity(cfg.MinSeverity) {
+				return nil, fmt.Errorf("notifiers[%d] (%s): unknown severity %q (available: %s)", i, cfg.Type, cfg.MinSeverity, strings.Join(findings.Severities, ", "))
+			}
+			r.minSeverity = cfg.MinSeverity
+		}
+		if cfg.Template != "" {
+			if r.template, err = template.New(cfg.Type).Option("missingkey=error").Parse(cfg.Template); err != nil {
+				return nil, fmt.Errorf("notifiers[%d] (%s): invalid template: %w", i, cfg.Type, err)
+			}
+		}
+		d.routes = append(d.routes, r)
+	}
+	return d, nil
+}
+
+// With retorna uma cópia do Dispatcher que também entrega todos os eventos a n.
+func (d *Dispatcher) With(n Notifier) *Dispatcher {
+	c := &Dispatcher{}
+	if d != nil {
+		c.routes = append(c.routes, d.routes...)
+	}
+	c.routes = append(c.routes, route{notifier: n, minSeverity: findings.SeverityInfo})
+	return c
+}
+
+// Send entrega os eventos aos notificadores. Os notificadores remotos só enfileiram o evento;
+// a entrega termina em segundo plano (ver Close). Falhas de um notificador são registradas no
+// log e não impedem a entrega aos demais.
+func (d *Dispatcher) Send(ctx context.Context, events ...Event) {
+	if d == nil {
+		return
+	}
+	logger := util.StageLogger("notify")
+	for _, event := range events {
+		if event.Time.IsZero() {
+			event.Time = time.Now()
+		}
+		for _, r := range d.routes {
+			if !r.accepts(event) {
+				continue
+			}
+			delivered := event
+			if r.template != nil {
+				var message strings.Builder
+				if err := r.template.Execute(&message, event); err != nil {
+					logger.Warn().Err(err).Str("notifier", r.notifier.Name()).Msg("Failed to render the notification template, using the default message")
+				} else {
+					delivered.Message = message.String()
+				}
+			}
+			if err := r.notifier.Notify(ctx, delivered); err != nil {
+				logger.Error().Err(err).Str("notifier", r.notifier.Name()).Str("event", event.Type).Msg("Notification failed")
+			}
+		}
+	}
+}
+
+// Close espera a entrega dos eventos enfileirados para os notificadores remotos, até ctx
+// terminar. Deve ser chamado uma vez, no Dispatcher criado por New, antes de encerrar o
+// processo; as cópias de With compartilham as mesmas filas. Eventos enviados depois são descartados.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	if d == nil {
+		return nil
+	}
+	var errs []error
+	for _, r := range d.routes {
+		if q, ok := r.notifier.(*queued); ok {
+			errs = append(errs, q.close(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// accepts indica se o notificador recebe o evento: o tipo está no filtro e, para achados,
+// a severidade atinge a mínima.
+func (r route) accepts(event Event) bool {
+	if r.events != nil && !r.events[event.Type] {
+		return false
+	}
+	return event.Type != EventFinding || findings.Rank(event.Severity) <= findings.Rank(r.minSeverity)
+}
+
+// validEvent indica se o tipo de evento existe.
+func validEvent(event string) bool {
+	for _, e := range EventTypes {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// queueSize é o número de eventos que aguardam a entrega de um notificador remoto.
+const queueSize = 1024
+
+// errQueueClosed indica um evento enviado depois de Dispatcher.Close.
+var errQueueClosed = errors.New("notifier closed, event dropped")
+
+// queued entrega os eventos de um notificador remoto (webhook) em segundo plano, por uma fila
+// limitada, para que as novas tentativas de entrega não atrasem o scan. Com a fila cheia, o
+// evento é descartado.
+type queued struct {
+	Notifier
+
+	mu     sync.Mutex
+	closed bool
+	events chan Event
+	done   chan struct{}
+
+	ctx     context.Context // Contexto das entregas, cancelado quando Close desiste de esperar
+	cancel  context.CancelFunc
+	dropped int // Eventos não entregues porque Close desistiu de esperar
+}
+
+// newQueued cria a fila de n e inicia o worker que a esvazia.
+func newQueued(n Notifier, size int) *queued {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &queued{Notifier: n, events: make(chan Event, size), done: make(chan struct{}), ctx: ctx, cancel: cancel}
+	go q.run()
+	return q
+}
+
+// Notify enfileira o evento sem esperar a entrega. O ctx de quem envia não é usado: a entrega
+// continua depois que a etapa ou o job que gerou o evento termina.
+func (q *queued) Notify(ctx context.Context, event Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return errQueueClosed
+	}
+	select {
+	case q.events <- event:
+		return nil
+	default:
+		return fmt.Errorf("queue full (%d events), event dropped", cap(q.events))
+	}
+}
+
+// run entrega os eventos em ordem até a fila ser fechada.
+func (q *queued) run() {
+	defer close(q.done)
+	logger := util.StageLogger("notify")
+	for event := range q.events {
+		if q.ctx.Err() != nil {
+			q.dropped++
+			continue
+		}
+		if err := q.Notifier.Notify(q.ctx, event); err != nil {
+			if q.ctx.Err() != nil {
+				q.dropped++
+				continue
+			}
+			logger.Error().Err(err).Str("notifier", q.Name()).Str("event", event.Type).Msg("Notification failed")
+		}
+	}
+}
+
+// close fecha a fila e espera a entrega dos eventos pendentes. Se ctx terminar antes, cancela
+// as entregas restantes e informa quantos eventos foram perdidos.
+func (q *queued) close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.events)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-q.done
+		return fmt.Errorf("%s: %d notifications not delivered: %w", q.Name(), q.dropped, ctx.Err())
+	}
+}
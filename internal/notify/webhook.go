@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// Valores padrão do notificador webhook.
+const (
+	defaultWebhookTimeout = 10 * time.Second
+	defaultWebhookRetries = 3
+	defaultWebhookBackoff = time.Second
+	maxWebhookBackoff     = time.Minute
+	discordContentLimit   = 2000 // Tamanho máximo do campo content do Discord
+)
+
+// Webhook envia cada evento em um POST JSON. O formato do corpo segue o destino: o próprio
+// evento (generic), {"text": ...} (slack) ou {"content": ...} (discord).
+type Webhook struct {
+	URL     string
+	Format  string            // generic, slack ou discord
+	Headers map[string]string // Cabeçalhos extras (ex.: Authorization)
+	Retries int               // Novas tentativas após uma falha
+	Backoff time.Duration     // Espera antes da primeira nova tentativa; dobra a cada falha
+	Client  *http.Client
+}
+
+// NewWebhook é a factory que cria o notificador webhook a partir da configuração.
+func NewWebhook(cfg util.NotifierConfig) (*Webhook, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid url %q (expected http or https)", cfg.URL)
+	}
+	w := &Webhook{
+		URL:     cfg.URL,
+		Format:  cfg.Format,
+		Headers: cfg.Headers,
+		Retries: defaultWebhookRetries,
+		Backoff: defaultWebhookBackoff,
+		Client:  &http.Client{Timeout: defaultWebhookTimeout},
+	}
+	if w.Format == "" {
+		w.Format = "generic"
+	}
+	if cfg.Retries != nil {
+		w.Retries = *cfg.Retries
+	}
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q", cfg.Timeout)
+		}
+		w.Client.Timeout = timeout
+	}
+	if _, err := w.Payload(Event{}); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// newWebhookNotifier cria o notificador do tipo "webhook".
+func newWebhookNotifier(cfg util.NotifierConfig) (Notifier, error) {
+	return NewWebhook(cfg)
+}
+
+// Name retorna o nome do notificador, sem o caminho da URL (que costuma conter o token).
+func (w *Webhook) Name() string {
+	if u, err := url.Parse(w.URL); err == nil {
+		return "webhook " + u.Scheme + "://" + u.Host
+	}
+	return "webhook"
+}
+
+// Payload monta o corpo JSON do evento no formato do destino.
+func (w *Webhook) Payload(event Event) ([]byte, error) {
+	switch w.Format {
+	case "generic":
+		return json.Marshal(event)
+	case "slack":
+		return json.Marshal(map[string]string{"text": event.Message})
+	case "discord":
+		content := []rune(event.Message)
+		if len(content) > discordContentLimit {
+			content = append(content[:discordContentLimit-1], '…')
+		}
+		return json.Marshal(map[string]string{"content": string(content), "username": "arthxrecon"})
+	}
+	return nil, fmt.Errorf("unknown format %q (available: generic, slack, discord)", w.Format)
+}
+
+// Notify envia o evento, repetindo o POST com backoff exponencial em falhas de rede, 429 e 5xx.
+// Um Retry-After na resposta substitui o backoff calculado.
+func (w *Webhook) Notify(ctx context.Context, event Event) error {
+	body, err := w.Payload(event)
+	if err != nil {
+		return err
+	}
+	backoff := w.Backoff
+	for attempt := 0; ; attempt++ {
+		wait, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if wait < 0 || attempt >= w.Retries {
+			return err
+		}
+		if wait == 0 {
+			wait = backoff
+			backoff = min(2*backoff, maxWebhookBackoff)
+		}
+		util.StageLogger("notify").Debug().Err(err).Str("notifier", w.Name()).Int("attempt", attempt+1).Dur("retry_in", wait).Msg("Webhook delivery failed, retrying")
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// post faz uma tentativa de entrega. Em caso de erro, wait indica a espera pedida pelo servidor
+// (Retry-After), 0 para usar o backoff ou -1 quando não adianta repetir (ex.: 400, 401, 404).
+func (w *Webhook) post(ctx context.Context, body []byte) (wait time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "arthxrecon")
+	for name, value := range w.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+	err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return -1, err
+	}
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+		return min(time.Duration(seconds)*time.Second, maxWebhookBackoff), err
+	}
+	return 0, err
+}
@@ -0,0 +1,270 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// webhookServer responde cada POST com o próximo status de statuses (o último se repete) e
+// guarda os corpos e cabeçalhos recebidos.
+type webhookServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	header   http.Header // Cabeçalhos extras da resposta
+	bodies   [][]byte
+	requests []*http.Request
+}
+
+func newWebhookServer(t *testing.T, statuses ...int) *webhookServer {
+	t.Helper()
+	s := &webhookServer{statuses: statuses, header: http.Header{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		status := s.statuses[min(len(s.bodies), len(s.statuses)-1)]
+		s.bodies = append(s.bodies, body)
+		s.requests = append(s.requests, r)
+		for name, values := range s.header {
+			w.Header()[name] = values
+		}
+		s.mu.Unlock()
+		w.WriteHeader(status)
+		io.WriteString(w, http.StatusText(status))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// attempts retorna o número de POSTs recebidos.
+func (s *webhookServer) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+// newTestWebhook cria um webhook para s com backoff curto.
+func newTestWebhook(t *testing.T, s *webhookServer, format string, retries int) *Webhook {
+	t.Helper()
+	w, err := NewWebhook(util.NotifierConfig{Type: "webhook", URL: s.URL + "/hook/secret", Format: format, Retries: &retries,
+		Headers: map[string]string{"Authorization": "Bearer token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Backoff = time.Millisecond
+	return w
+}
+
+func TestWebhookPayloads(t *testing.T) {
+	event := Event{Type: EventFinding, Source: "fullrecon", Host: "10.0.0.5", Port: 445, Protocol: "tcp",
+		Severity: "high", Title: "SMBv1 enabled", Message: "High: SMBv1 enabled on 10.0.0.5:445"}
+	long := event
+	long.Message = strings.Repeat("é", discordContentLimit+10)
+
+	tests := []struct {
+		format string
+		event  Event
+		check  func(t *testing.T, body map[string]any)
+	}{
+		{"generic", event, func(t *testing.T, body map[string]any) {
+			if body["type"] != EventFinding || body["host"] != "10.0.0.5" || body["port"] != float64(445) || body["title"] != "SMBv1 enabled" {
+				t.Errorf("generic payload = %v", body)
+			}
+		}},
+		{"slack", event, func(t *testing.T, body map[string]any) {
+			if len(body) != 1 || body["text"] != event.Message {
+				t.Errorf("slack payload = %v", body)
+			}
+		}},
+		{"discord", event, func(t *testing.T, body map[string]any) {
+			if body["content"] != event.Message || body["username"] != "arthxrecon" {
+				t.Errorf("discord payload = %v", body)
+			}
+		}},
+		{"discord", long, func(t *testing.T, body map[string]any) {
+			content := []rune(body["content"].(string))
+			if len(content) != discordContentLimit || content[len(content)-1] != '…' {
+				t.Errorf("discord content has %d runes", len(content))
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			server := newWebhookServer(t, http.StatusOK)
+			if err := newTestWebhook(t, server, tt.format, 0).Notify(context.Background(), tt.event); err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+			if server.attempts() != 1 {
+				t.Fatalf("%d requests", server.attempts())
+			}
+			r := server.requests[0]
+			if r.Method != http.MethodPost || r.URL.Path != "/hook/secret" || r.Header.Get("Content-Type") != "application/json" ||
+				r.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("request = %s %s %v", r.Method, r.URL.Path, r.Header)
+			}
+			var body map[string]any
+			if err := json.Unmarshal(server.bodies[0], &body); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, body)
+		})
+	}
+
+	if _, err := NewWebhook(util.NotifierConfig{URL: "https://hooks.example", Format: "teams"}); err == nil {
+		t.Error("NewWebhook accepted an unknown format")
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		retries  int
+		attempts int
+		ok       bool
+	}{
+		{"5xx then success", []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}, 3, 3, true},
+		{"429 then success", []int{http.StatusTooManyRequests, http.StatusOK}, 3, 2, true},
+		{"gives up", []int{http.StatusInternalServerError}, 2, 3, false},
+		{"no retries", []int{http.StatusInternalServerError}, 0, 1, false},
+		{"client error", []int{http.StatusNotFound}, 3, 1, false},
+		{"unauthorized", []int{http.StatusUnauthorized}, 3, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWebhookServer(t, tt.statuses...)
+			err := newTestWebhook(t, server, "slack", tt.retries).Notify(context.Background(), Event{Message: "x"})
+			if (err == nil) != tt.ok {
+				t.Errorf("Notify = %v", err)
+			}
+			if got := server.attempts(); got != tt.attempts {
+				t.Errorf("%d attempts, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestWebhookRetryAfter(t *testing.T) {
+	server := newWebhookServer(t, http.StatusTooManyRequests, http.StatusOK)
+	server.header.Set("Retry-After", "1")
+	start := time.Now()
+	if err := newTestWebhook(t, server, "generic", 1).Notify(context.Background(), Event{Message: "x"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, before Retry-After", elapsed)
+	}
+	if server.attempts() != 2 {
+		t.Errorf("%d attempts", server.attempts())
+	}
+}
+
+func TestWebhookCanceled(t *testing.T) {
+	server := newWebhookServer(t, http.StatusServiceUnavailable)
+	w := newTestWebhook(t, server, "generic", 5)
+	w.Backoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	if err := w.Notify(ctx, Event{Message: "x"}); err == nil {
+		t.Error("Notify succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second || server.attempts() != 1 {
+		t.Errorf("%d attempts in %s", server.attempts(), elapsed)
+	}
+}
+
+// blockingNotifier só conclui as entregas depois que release é fechado.
+type blockingNotifier struct {
+	release   chan struct{}
+	mu        sync.Mutex
+	delivered []string
+}
+
+func (n *blockingNotifier) Name() string { return "blocking" }
+
+func (n *blockingNotifier) Notify(ctx context.Context, event Event) error {
+	select {
+	case <-n.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	n.mu.Lock()
+	n.delivered = append(n.delivered, event.Message)
+	n.mu.Unlock()
+	return nil
+}
+
+func TestDispatcherQueue(t *testing.T) {
+	slow := &blockingNotifier{release: make(chan struct{})}
+	q := newQueued(slow, 2)
+	d := &Dispatcher{routes: []route{{notifier: q, minSeverity: "info"}}}
+
+	// Send não espera a entrega; com a fila cheia, o evento é descartado.
+	start := time.Now()
+	d.Send(context.Background(), Event{Message: "a"})
+	for len(q.events) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	d.Send(context.Background(), Event{Message: "b"}, Event{Message: "c"}, Event{Message: "d"})
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Send blocked for %s", elapsed)
+	}
+
+	close(slow.release)
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// "a" já estava com o worker; "b" e "c" esperavam na fila; "d" foi descartado.
+	if got := strings.Join(slow.delivered, ""); got != "abc" {
+		t.Errorf("delivered %q, want abc", got)
+	}
+	d.Send(context.Background(), Event{Message: "e"})
+	if len(slow.delivered) != 3 {
+		t.Error("an event sent after Close was delivered")
+	}
+}
+
+func TestDispatcherCloseTimeout(t *testing.T) {
+	slow := &blockingNotifier{release: make(chan struct{})}
+	d := &Dispatcher{routes: []route{{notifier: newQueued(slow, 8), minSeverity: "info"}}}
+	d.Send(context.Background(), Event{Message: "a"}, Event{Message: "b"}, Event{Message: "c"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := d.Close(ctx)
+	if err == nil || !strings.Contains(err.Error(), "3 notifications not delivered") {
+		t.Errorf("Close = %v", err)
+	}
+}
+
+func TestNewQueuesWebhooks(t *testing.T) {
+	server := newWebhookServer(t, http.StatusOK)
+	d, err := New([]util.NotifierConfig{{Type: "log"}, {Type: "webhook", URL: server.URL, Events: []string{EventStageCompleted}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.routes[0].notifier.(*queued); ok {
+		t.Error("the log notifier is queued")
+	}
+	if _, ok := d.routes[1].notifier.(*queued); !ok {
+		t.Fatal("the webhook notifier is not queued")
+	}
+	d.Send(context.Background(), Event{Type: EventStageCompleted, Message: "Port Scan completed"}, Event{Type: EventStageStarted})
+	if err := d.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if server.attempts() != 1 || !strings.Contains(string(server.bodies[0]), "Port Scan completed") {
+		t.Errorf("%d deliveries: %q", server.attempts(), server.bodies)
+	}
+}
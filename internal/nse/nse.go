@@ -0,0 +1,150 @@
+package nse
+
+import (
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// ModuleName é o nome usado nos resultados anexados aos hosts e serviços.
+const ModuleName = "nse"
+
+// normalizer converte o resultado de um script em achados (sem endereço e porta).
+type normalizer func(script results.Script) []findings.Finding
+
+// normalizers contém os scripts conhecidos, indexados pelo id do NSE.
+var normalizers = map[string]normalizer{
+	"smb-security-mode":  smbSecurityMode,
+	"smb2-security-mode": smb2SecurityMode,
+	"ssl-cert":           sslCert,
+	"http-title":         httpTitle,
+	"http-server-header": httpServerHeader,
+	"ftp-anon":           ftpAnon,
+	"ssh-hostkey":        sshHostKey,
+	"vulners":            vulners,
+}
+
+// Known indica se o script possui normalização própria.
+func Known(id string) bool {
+	_, ok := normalizers[id]
+	return ok
+}
+
+// Normalize converte um script em achados. Scripts que usam a biblioteca vulns do NSE
+// (smb-vuln-*, http-vuln-*, ...) são reconhecidos pela estrutura; os demais não geram
+// achados e permanecem apenas como árvore bruta em results.Script.
+func Normalize(script results.Script) []findings.Finding {
+	if fn, ok := normalizers[script.ID]; ok {
+		return fn(script)
+	}
+	return vulnsLibrary(script)
+}
+
+// Analyze normaliza os scripts de host e de porta de todos os hosts, anexa os achados
+// aos registros correspondentes e retorna a lista ordenada por severidade.
+func Analyze(hosts []results.Host) []findings.Finding {
+	var all []findings.Finding
+	for i := range hosts {
+		host := &hosts[i]
+		var hostResults []findings.Finding
+		for _, script := range host.Scripts {
+			for _, r := range Normalize(script) {
+				r.Host = host.Address
+				hostResults = append(hostResults, r)
+			}
+		}
+		if len(hostResults) > 0 {
+			host.SetEnrichment(ModuleName, hostResults)
+			all = append(all, hostResults...)
+		}
+
+		for _, services := range [][]results.Service{host.Services, host.UDPServices} {
+			for j := range services {
+				svc := &services[j]
+				var svcResults []findings.Finding
+				for _, script := range svc.Scripts {
+					for _, r := range Normalize(script) {
+						r.Host = host.Address
+						r.Port = svc.Port
+						r.Protocol = svc.Protocol
+						svcResults = append(svcResults, r)
+					}
+				}
+				if len(svcResults) > 0 {
+					svc.SetEnrichment(ModuleName, svcResults)
+					all = append(all, svcResults...)
+				}
+			}
+		}
+	}
+	findings.Sort(all)
+	return all
+}
+
+// str lê um valor textual da árvore do script.
+func str(tree map[string]interface{}, keys ...string) string {
+	var current interface{} = tree
+	for _, key := range keys {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = m[key]
+	}
+	if s, ok := current.(string); ok {
+		return s
+	}
+	return ""
+}
+
+// list retorna os itens de uma lista da árvore (ou os itens sem chave de um mapa).
+func list(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		if items, ok := v["_items"].([]interface{}); ok {
+			return items
+		}
+	}
+	return nil
+}
+
+// firstLine retorna a primeira linha não vazia da saída textual.
+func firstLine(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// result cria o achado id do script; endereço e porta são preenchidos em Analyze. Problemas também
+// reportados pelos módulos nativos usam os IDs canônicos de findings.
+func result(script results.Script, id, title, severity, evidence string, pairs ...string) findings.Finding {
+	return findings.Finding{ID: id, Source: ModuleName, Title: title, Severity: severity, Evidence: evidence}.
+		WithDetails(append([]string{"script", script.ID}, pairs...)...)
+}
+
+// Collect reúne os achados do NSE já anexados aos hosts e serviços (por Analyze ou carregados de um JSON).
+func Collect(hosts []results.Host) []findings.Finding {
+	var all []findings.Finding
+	for i := range hosts {
+		var list []findings.Finding
+		if hosts[i].Enrichment(ModuleName, &list) {
+			all = append(all, list...)
+		}
+		for _, services := range [][]results.Service{hosts[i].Services, hosts[i].UDPServices} {
+			for _, svc := range services {
+				var list []findings.Finding
+				if svc.Enrichment(ModuleName, &list) {
+					all = append(all, list...)
+				}
+			}
+		}
+	}
+	findings.Sort(all)
+	return all
+}
@@ -0,0 +1,189 @@
+package nse
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// loadFixture lê o XML do Nmap de testdata com scripts de host e de porta.
+func loadFixture(t *testing.T) []results.Host {
+	t.Helper()
+	data, err := os.ReadFile("testdata/scripts.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := results.FromNmapXML(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 1 {
+		t.Fatalf("%d hosts in the fixture", len(hosts))
+	}
+	return hosts
+}
+
+// script retorna o script id dos scripts informados.
+func script(t *testing.T, scripts []results.Script, id string) results.Script {
+	t.Helper()
+	for _, s := range scripts {
+		if s.ID == id {
+			return s
+		}
+	}
+	t.Fatalf("script %s not found", id)
+	return results.Script{}
+}
+
+func TestScriptTree(t *testing.T) {
+	host := loadFixture(t)[0]
+	if len(host.Scripts) != 4 || len(host.Services) != 5 || len(host.UDPServices) != 1 {
+		t.Fatalf("host scripts %d, services %d, udp %d", len(host.Scripts), len(host.Services), len(host.UDPServices))
+	}
+
+	for name, tc := range map[string]struct {
+		script results.Script
+		want   interface{}
+	}{
+		"keyed elements": {script(t, host.Scripts, "smb-security-mode"), map[string]interface{}{
+			"account_used": "guest", "authentication_level": "user", "challenge_response": "supported", "message_signing": "disabled",
+		}},
+		"keyed table of items": {script(t, host.Scripts, "smb2-security-mode"), map[string]interface{}{
+			"3:1:1": []interface{}{"Message signing enabled but not required"},
+		}},
+		"nested tables": {script(t, host.Scripts, "smb-vuln-ms17-010"), map[string]interface{}{
+			"CVE-2017-0143": map[string]interface{}{
+				"title":      "Remote Code Execution vulnerability in Microsoft SMBv1 servers (ms17-010)",
+				"state":      "VULNERABLE",
+				"ids":        []interface{}{"CVE:CVE-2017-0143"},
+				"disclosure": "2017-03-14",
+			},
+		}},
+		"unkeyed tables": {script(t, host.Service("tcp", 22).Scripts, "ssh-hostkey"), []interface{}{
+			map[string]interface{}{"type": "ssh-dss", "bits": "1024", "fingerprint": "2b9f7ed4110a5c613b94206af81c0e93", "key": "AAAAB3NzaC1kc3MAAACBAK"},
+			map[string]interface{}{"type": "ssh-rsa", "bits": "1024", "fingerprint": "9c4e1a2d77e035620baa134f9ecc081d", "key": "AAAAB3NzaC1yc2EAAAADAQAB"},
+			map[string]interface{}{"type": "ssh-ed25519", "bits": "256", "fingerprint": "4ad89b33e1075cc2408f216e351b77a9", "key": "AAAAC3NzaC1lZDI1NTE5AAAAI"},
+		}},
+		"mixed keyed and unkeyed": {script(t, host.UDPServices[0].Scripts, "snmp-info"), map[string]interface{}{
+			"enterprise": "net-snmp", "engineIDFormat": "unknown", "_items": []interface{}{"uptime unavailable"},
+		}},
+		"output only": {script(t, host.Service("tcp", 21).Scripts, "ftp-anon"), nil},
+	} {
+		if !reflect.DeepEqual(tc.script.Data, tc.want) {
+			t.Errorf("%s: tree %#v, want %#v", name, tc.script.Data, tc.want)
+		}
+	}
+	if svc := host.Service("tcp", 8080); svc != nil {
+		t.Errorf("closed port kept: %+v", svc)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	hosts := loadFixture(t)
+	all := Analyze(hosts)
+
+	// Achados por alvo, como "id/severidade", em ordem alfabética.
+	got := make(map[int][]string)
+	for _, f := range all {
+		if f.Host != "10.0.0.5" || f.Source != ModuleName {
+			t.Errorf("finding without target or source: %+v", f)
+		}
+		got[f.Port] = append(got[f.Port], f.ID+"/"+f.Severity)
+	}
+	for port := range got {
+		sort.Strings(got[port])
+	}
+	want := map[int][]string{
+		0:   {"cve-2017-0143/high", "smb-guest-account/medium", "smb-signing-not-required/medium", "smb-signing-not-required/medium"},
+		21:  {"ftp-anonymous-writable/high", "ftp-anonymous/medium"},
+		22:  {"ssh-dsa-hostkey/medium", "ssh-hostkey-ssh-dss/info", "ssh-hostkey-ssh-ed25519/info", "ssh-hostkey-ssh-rsa/info", "ssh-weak-rsa-hostkey/medium"},
+		80:  {"cve-2021-42013/critical", "edb-id-50383/high", "http-title/info"},
+		443: {"tls-certificate/info", "tls-expired/low", "tls-self-signed/low", "tls-weak-rsa-key/medium", "tls-weak-signature-hash/low"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings %v, want %v", got, want)
+	}
+	if all[0].Severity != findings.SeverityCritical {
+		t.Errorf("findings not sorted by severity: first %+v", all[0])
+	}
+
+	// Os achados ficam anexados ao host e aos serviços e são recuperados por Collect.
+	if collected := Collect(hosts); len(collected) != len(all) {
+		t.Errorf("Collect: %d findings, want %d", len(collected), len(all))
+	}
+	var onSSH []findings.Finding
+	if !hosts[0].Service("tcp", 22).Enrichment(ModuleName, &onSSH) || len(onSSH) != 5 || onSSH[0].Port != 22 || onSSH[0].Protocol != "tcp" {
+		t.Errorf("ssh enrichment: %+v", onSSH)
+	}
+	if hosts[0].UDPServices[0].Enrichment(ModuleName, new([]findings.Finding)) {
+		t.Error("enrichment attached to a service without findings")
+	}
+}
+
+func TestNormalizeDetails(t *testing.T) {
+	host := loadFixture(t)[0]
+	details := func(list []findings.Finding, id string) map[string]string {
+		for _, f := range list {
+			if f.ID == id {
+				return f.Details
+			}
+		}
+		t.Fatalf("finding %s not found in %+v", id, list)
+		return nil
+	}
+
+	smb2 := Normalize(script(t, host.Scripts, "smb2-security-mode"))
+	if d := details(smb2, findings.IDSMBSigningNotRequired); d["dialect"] != "3:1:1" || d["script"] != "smb2-security-mode" {
+		t.Errorf("smb2-security-mode details: %v", d)
+	}
+	ms17 := Normalize(script(t, host.Scripts, "smb-vuln-ms17-010"))
+	if d := details(ms17, "cve-2017-0143"); d["ids"] != "CVE:CVE-2017-0143" || d["state"] != "VULNERABLE" || d["disclosure"] != "2017-03-14" {
+		t.Errorf("vulns library details: %v", d)
+	}
+	ftp := Normalize(script(t, host.Service("tcp", 21).Scripts, "ftp-anon"))
+	if d := details(ftp, findings.IDFTPAnonymous); d["entries"] != "2" {
+		t.Errorf("ftp-anon details: %v", d)
+	}
+	cert := Normalize(script(t, host.Service("tcp", 443).Scripts, "ssl-cert"))
+	if d := details(cert, "tls-certificate"); d["subject"] != "files.corp.local" || d["pubkey_bits"] != "1024" || d["not_after"] != "2020-01-01T00:00:00" {
+		t.Errorf("ssl-cert details: %v", d)
+	}
+	vulns := Normalize(script(t, host.Service("tcp", 80).Scripts, "vulners"))
+	if len(vulns) != 2 || vulns[1].Title != "EDB-ID:50383 (exploit available)" || vulns[0].Evidence != "cpe:/a:apache:http_server:2.4.49" {
+		t.Errorf("vulners findings: %+v", vulns)
+	}
+}
+
+func TestNormalizeUnknownScript(t *testing.T) {
+	host := loadFixture(t)[0]
+	// Scripts sem normalização própria ficam apenas como árvore bruta.
+	for _, s := range []results.Script{
+		script(t, host.Service("tcp", 80).Scripts, "http-methods"),
+		script(t, host.UDPServices[0].Scripts, "snmp-info"),
+		script(t, host.Scripts, "smb-vuln-ms10-054"),
+		{ID: "banner", Output: "SSH-2.0-OpenSSH_7.4"},
+	} {
+		if Known(s.ID) {
+			t.Errorf("%s: known script", s.ID)
+		}
+		if list := Normalize(s); len(list) != 0 {
+			t.Errorf("%s: findings %+v", s.ID, list)
+		}
+	}
+	if !Known("ssl-cert") || !Known("vulners") {
+		t.Error("typed normalizers not known")
+	}
+
+	// Sem a saída estruturada, o http-title usa a primeira linha da saída textual.
+	title := Normalize(results.Script{ID: "http-title", Output: "\n  Site doesn't have a title (text/html).\n"})
+	if len(title) != 1 || title[0].Evidence != "Site doesn't have a title (text/html)." {
+		t.Errorf("http-title fallback: %+v", title)
+	}
+	if list := Normalize(results.Script{ID: "ftp-anon", Output: "Anonymous FTP login not allowed"}); len(list) != 0 {
+		t.Errorf("ftp-anon without anonymous login: %+v", list)
+	}
+}
@@ -0,0 +1,253 @@
+package nse
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// smbSecurityMode trata a saída do smb-security-mode (SMBv1).
+func smbSecurityMode(script results.Script) []findings.Finding {
+	tree := script.Tree()
+	signing := str(tree, "message_signing")
+	d := []string{
+		"account_used", str(tree, "account_used"),
+		"authentication_level", str(tree, "authentication_level"),
+		"challenge_response", str(tree, "challenge_response"),
+		"message_signing", signing,
+	}
+	var out []findings.Finding
+	switch signing {
+	case "disabled":
+		out = append(out, result(script, findings.IDSMBSigningNotRequired, "SMB message signing disabled", findings.SeverityMedium, "message_signing: disabled", d...))
+	case "supported":
+		out = append(out, result(script, findings.IDSMBSigningNotRequired, "SMB message signing not required", findings.SeverityMedium, "message_signing: supported", d...))
+	}
+	if account := str(tree, "account_used"); account == "guest" {
+		out = append(out, result(script, "smb-guest-account", "SMB guest account accepted", findings.SeverityMedium, "account_used: guest", d...))
+	}
+	if strings.Contains(str(tree, "challenge_response"), "dangerous") {
+		out = append(out, result(script, "smb-plaintext-auth", "SMB plaintext or LM authentication allowed", findings.SeverityHigh, "challenge_response: "+str(tree, "challenge_response"), d...))
+	}
+	return out
+}
+
+// smb2SecurityMode trata a saída do smb2-security-mode: uma tabela por dialeto com a política de assinatura.
+func smb2SecurityMode(script results.Script) []findings.Finding {
+	tree := script.Tree()
+	var dialects []string
+	for dialect := range tree {
+		dialects = append(dialects, dialect)
+	}
+	sort.Strings(dialects)
+	for _, dialect := range dialects {
+		for _, item := range list(tree[dialect]) {
+			text, _ := item.(string)
+			if strings.Contains(text, "not required") || strings.Contains(text, "disabled") {
+				return []findings.Finding{result(script, findings.IDSMBSigningNotRequired, "SMB2 message signing not required", findings.SeverityMedium, text, "dialect", dialect)}
+			}
+		}
+	}
+	return nil
+}
+
+// sslCertTimeLayouts são os formatos de data usados pelo ssl-cert.
+var sslCertTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04:05Z07:00", "2006-01-02T15:04:05+00:00"}
+
+// sslCert trata a saída do ssl-cert: titular, emissor, validade e chave pública.
+func sslCert(script results.Script) []findings.Finding {
+	tree := script.Tree()
+	subject := str(tree, "subject", "commonName")
+	issuer := str(tree, "issuer", "commonName")
+	notAfter := str(tree, "validity", "notAfter")
+	keyType := str(tree, "pubkey", "type")
+	bits := str(tree, "pubkey", "bits")
+	d := []string{
+		"subject", subject,
+		"issuer", issuer,
+		"organization", str(tree, "subject", "organizationName"),
+		"not_before", str(tree, "validity", "notBefore"),
+		"not_after", notAfter,
+		"sig_algo", str(tree, "sig_algo"),
+		"pubkey_type", keyType,
+		"pubkey_bits", bits,
+		"sha1", str(tree, "sha1"),
+	}
+
+	out := []findings.Finding{result(script, "tls-certificate", "TLS certificate for "+subject, findings.SeverityInfo, "issuer: "+issuer, d...)}
+	if subject != "" && subject == issuer && str(tree, "subject", "organizationName") == str(tree, "issuer", "organizationName") {
+		out = append(out, result(script, "tls-self-signed", "Self-signed TLS certificate", findings.SeverityLow, "subject equals issuer: "+subject, d...))
+	}
+	for _, layout := range sslCertTimeLayouts {
+		if expiry, err := time.Parse(layout, notAfter); err == nil {
+			if time.Now().After(expiry) {
+				out = append(out, result(script, "tls-expired", "Expired TLS certificate", findings.SeverityLow, "notAfter: "+notAfter, d...))
+			}
+			break
+		}
+	}
+	if n, err := strconv.Atoi(bits); err == nil && keyType == "rsa" && n < 2048 {
+		out = append(out, result(script, "tls-weak-rsa-key", fmt.Sprintf("Weak %d-bit RSA certificate key", n), findings.SeverityMedium, "pubkey bits: "+bits, d...))
+	}
+	if algo := strings.ToLower(str(tree, "sig_algo")); strings.Contains(algo, "md5") || strings.Contains(algo, "sha1") {
+		out = append(out, result(script, "tls-weak-signature-hash", "TLS certificate signed with a weak hash", findings.SeverityLow, "sig_algo: "+str(tree, "sig_algo"), d...))
+	}
+	return out
+}
+
+// httpTitle trata a saída do http-title.
+func httpTitle(script results.Script) []findings.Finding {
+	tree := script.Tree()
+	title := str(tree, "title")
+	if title == "" {
+		title = firstLine(script.Output)
+	}
+	return []findings.Finding{result(script, "http-title", "HTTP title: "+title, findings.SeverityInfo, title, "title", title, "redirect_url", str(tree, "redirect_url"))}
+}
+
+// httpServerHeader trata a saída do http-server-header.
+func httpServerHeader(script results.Script) []findings.Finding {
+	header := firstLine(script.Output)
+	if items := list(script.Data); len(items) > 0 {
+		if s, ok := items[0].(string); ok {
+			header = s
+		}
+	}
+	if header == "" {
+		return nil
+	}
+	return []findings.Finding{result(script, "http-server-header", "HTTP Server header disclosed", findings.SeverityInfo, header, "server", header)}
+}
+
+// ftpAnon trata a saída do ftp-anon, que não possui saída estruturada.
+func ftpAnon(script results.Script) []findings.Finding {
+	if !strings.Contains(script.Output, "Anonymous FTP login allowed") {
+		return nil
+	}
+	var entries []string
+	writable := false
+	for _, line := range strings.Split(script.Output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "Anonymous FTP login allowed") {
+			continue
+		}
+		entries = append(entries, line)
+		if strings.Contains(line, "[NSE: writeable]") {
+			writable = true
+		}
+	}
+	d := []string{"entries", strconv.Itoa(len(entries))}
+	out := []findings.Finding{result(script, findings.IDFTPAnonymous, "Anonymous FTP login allowed", findings.SeverityMedium, firstLine(script.Output), d...)}
+	if writable {
+		out = append(out, result(script, findings.IDFTPAnonymousWritable, "Anonymous FTP writable directory", findings.SeverityHigh, "[NSE: writeable]", d...))
+	}
+	return out
+}
+
+// sshHostKey trata a saída do ssh-hostkey: uma tabela sem chave por chave de host.
+func sshHostKey(script results.Script) []findings.Finding {
+	var out []findings.Finding
+	for _, item := range list(script.Data) {
+		key, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		keyType := str(key, "type")
+		bits := str(key, "bits")
+		fingerprint := str(key, "fingerprint")
+		d := []string{"type", keyType, "bits", bits, "fingerprint", fingerprint}
+		out = append(out, result(script, findings.Slug("ssh-hostkey", keyType), "SSH host key "+keyType, findings.SeverityInfo, fingerprint, d...))
+		n, _ := strconv.Atoi(bits)
+		switch {
+		case keyType == "ssh-dss":
+			out = append(out, result(script, findings.IDSSHDSAHostKey, "SSH DSA host key", findings.SeverityMedium, fingerprint, d...))
+		case keyType == "ssh-rsa" && n > 0 && n < 2048:
+			out = append(out, result(script, findings.IDSSHWeakRSAHostKey, fmt.Sprintf("Weak %d-bit SSH RSA host key", n), findings.SeverityMedium, fingerprint, d...))
+		}
+	}
+	return out
+}
+
+// vulners trata a saída do vulners: uma tabela por CPE, com uma tabela sem chave por vulnerabilidade.
+func vulners(script results.Script) []findings.Finding {
+	var out []findings.Finding
+	tree := script.Tree()
+	var cpes []string
+	for cpe := range tree {
+		cpes = append(cpes, cpe)
+	}
+	sort.Strings(cpes)
+	for _, cpe := range cpes {
+		for _, item := range list(tree[cpe]) {
+			vuln, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id := str(vuln, "id")
+			cvss := str(vuln, "cvss")
+			score, _ := strconv.ParseFloat(cvss, 64)
+			d := []string{"cpe", cpe, "id", id, "cvss", cvss, "type", str(vuln, "type"), "is_exploit", str(vuln, "is_exploit")}
+			title := id
+			if str(vuln, "is_exploit") == "true" {
+				title += " (exploit available)"
+			}
+			out = append(out, result(script, findings.Slug(id), title, findings.SeverityFromCVSS(score), cpe, d...))
+		}
+	}
+	return out
+}
+
+// vulnsLibrary reconhece a saída padrão da biblioteca vulns do NSE: uma tabela por vulnerabilidade
+// (chave = id) com os campos title e state. Apenas estados VULNERABLE geram achados.
+func vulnsLibrary(script results.Script) []findings.Finding {
+	var out []findings.Finding
+	tree := script.Tree()
+	var keys []string
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		vuln, ok := tree[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		state := str(vuln, "state")
+		title := str(vuln, "title")
+		if title == "" || !strings.Contains(state, "VULNERABLE") || strings.HasPrefix(state, "NOT VULNERABLE") {
+			continue
+		}
+		severity := findings.SeverityHigh
+		switch strings.ToLower(str(vuln, "risk_factor")) {
+		case "low":
+			severity = findings.SeverityLow
+		case "medium":
+			severity = findings.SeverityMedium
+		}
+		if strings.HasPrefix(state, "LIKELY") && severity == findings.SeverityHigh {
+			severity = findings.SeverityMedium
+		}
+		// A tabela ids usa chaves no formato "CVE:CVE-2017-0143".
+		var ids []string
+		if table, ok := vuln["ids"].(map[string]interface{}); ok {
+			for _, id := range table {
+				if s, ok := id.(string); ok {
+					ids = append(ids, s)
+				}
+			}
+		}
+		for _, id := range list(vuln["ids"]) {
+			if s, ok := id.(string); ok {
+				ids = append(ids, s)
+			}
+		}
+		sort.Strings(ids)
+		out = append(out, result(script, findings.Slug(key), title, severity, state, "id", key, "ids", strings.Join(ids, ","), "state", state, "disclosure", str(vuln, "disclosure")))
+	}
+	return out
+}
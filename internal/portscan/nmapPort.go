@@ -1,15 +1,26 @@
 package portscan
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/nse"
+	"github.com/Arthx-x/arthxrecon/internal/results"
 	"github.com/Arthx-x/arthxrecon/util"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
 )
 
+// logger retorna o sub-logger da etapa de port scan.
+func logger() *zerolog.Logger {
+	return util.StageLogger("portscan")
+}
+
 // PortScanParams centraliza os parâmetros para o port scan.
 type PortScanParams struct {
 	Targets    []string // Lista de alvos (IPs ou CIDRs)
@@ -17,10 +28,13 @@ type PortScanParams struct {
 	Mode       string   // Modo do scan: aggressive, normal ou passive
 	Options    []string // Outras opções extras para o scan
 	PortList   string   // Lista ou range de portas (ex.: "1-1024")
+	UDPPorts   string   // Lista de portas UDP (ex.: "161,162"); se definida, adiciona -sU
 	Category   string   // Categoria de portas (ex.: "top12", "database", etc.)
 	AllPorts   bool     // Se verdadeiro, varre todas as portas (-p-)
 	SimpleScan bool     // Se verdadeiro, usa -sS; caso contrário, usa -sV -sC
 	FileMode   bool     // Se os alvos foram passados via arquivo
+	Scripts    string   // Seleção de scripts NSE, global ou por categoria (ex.: "web=http-title;default")
+	MaxRate    int      // Máximo de pacotes por segundo (--max-rate); 0 = sem limite
 }
 
 // NmapPortScanner implementa a interface PortScanStrategy usando Nmap.
@@ -30,10 +44,13 @@ type NmapPortScanner struct {
 	Mode       string
 	Options    []string
 	PortList   string
+	UDPPorts   string
 	Category   string
 	AllPorts   bool
 	SimpleScan bool
 	FileMode   bool
+	Scripts    []scriptGroup
+	MaxRate    int
 }
 
 // NewNmapPortScanner é a factory que cria uma instância de NmapPortScanner.
@@ -41,16 +58,6 @@ func NewNmapPortScanner() *NmapPortScanner {
 	return &NmapPortScanner{}
 }
 
-var portCategories = map[string]string{
-	"top12":    "21,22,2222,23,53,80,135,139,443,445,3389,8080",
-	"database": "3306,5432,1433,1521,27017,6379,9042,9160,50000,8086,5984,7474,7687,11211,3050,9092,1527,2638,8529,28015,2424,26257,9200",
-	"web":      "80,443,8080,8443,8000,3000,5000,4200,8888,8081,8001,3001,9000,9090,1313,8008,8880",
-	"network":  "10000,20000,902,903,8006,10050,10051,23560,17778,3000,55000,9090,5666,5665,19999,443,8000,8089,6557,8980,9100,9000,8443",
-	"firewall": "4444,4433,4443,443,8443",
-	"windows":  "88,389,636,593,5985,5986",
-	"vpn":      "22,2222,3389,1194,1701,500,4500,1723,5900,5901,5985,5986,443,4443,8443,5938,992,8080,6000,5902",
-}
-
 // PortListOrDefault retorna o valor de nmapPS.PortList se não estiver vazio; caso contrário, retorna "".
 func (nmapPS *NmapPortScanner) PortListOrDefault() string {
 	if nmapPS.PortList != "" {
@@ -67,18 +74,48 @@ func (nmapPS *NmapPortScanner) Configure(params PortScanParams) error {
 	nmapPS.Mode = params.Mode
 	nmapPS.Options = params.Options
 	nmapPS.PortList = params.PortList
+	nmapPS.UDPPorts = params.UDPPorts
 	nmapPS.Category = params.Category
 	nmapPS.AllPorts = params.AllPorts
 	nmapPS.SimpleScan = params.SimpleScan
 	nmapPS.FileMode = params.FileMode
+	nmapPS.MaxRate = params.MaxRate
+	if _, err := util.AppConfig.TimingOptions(nmapPS.Mode); err != nil {
+		return err
+	}
 
-	// Se uma categoria for especificada, mescle-a com a PortList
+	if params.Scripts != "" {
+		groups, err := parseScriptSpec(params.Scripts)
+		if err != nil {
+			return err
+		}
+		nmapPS.Scripts = groups
+	}
 
-	if nmapPS.Category != "" {
-		nmapPS.PortList = combinePortLists(nmapPS.PortListOrDefault(), nmapPS.Category)
+	// As listas de portas chegam ao Nmap como informadas quando não há categoria; são validadas antes.
+	if nmapPS.PortList != "" {
+		if err := util.ValidatePortSpec(nmapPS.PortList); err != nil {
+			return fmt.Errorf("invalid --ports %q: %w", nmapPS.PortList, err)
+		}
+	}
+	if nmapPS.UDPPorts != "" {
+		if err := util.ValidatePortSpec(nmapPS.UDPPorts); err != nil {
+			return fmt.Errorf("invalid UDP ports %q: %w", nmapPS.UDPPorts, err)
+		}
+	}
 
-	} else {
-		log.Warn().Msgf("Categoria de porta desconhecida: %s", nmapPS.Category)
+	// Se uma categoria for especificada, mescle-a com a PortList
+
+	if nmapPS.Category != "" || strings.Contains(nmapPS.PortList, ":") {
+		tcpPorts, udpPorts, err := combinePortLists(nmapPS.PortListOrDefault(), nmapPS.Category)
+		if err != nil {
+			return err
+		}
+		nmapPS.PortList = tcpPorts
+		// As portas UDP das categorias somam-se às da flag --udp.
+		if _, nmapPS.UDPPorts, err = combinePortLists("U:"+nmapPS.UDPPorts+","+udpPorts, ""); err != nil {
+			return err
+		}
 	}
 
 	// Se AllPorts estiver definido, força o uso de "-p-"
@@ -117,17 +154,33 @@ func (nmapPS *NmapPortScanner) Configure(params PortScanParams) error {
 func (nmapPS *NmapPortScanner) buildCommand() (string, []string) {
 	outputDir := util.PortScanName
 	if err := util.EnsureDir(outputDir); err != nil {
-		log.Fatal().Msgf("Error creating directory %s: %v", outputDir, err)
+		logger().Fatal().Msgf("Error creating directory %s: %v", outputDir, err)
 	}
 
 	args := []string{}
 
 	// Escolha de scan: simples (-sS) ou detalhado (-sV -sC)
-	if nmapPS.SimpleScan {
+	// Com --scripts, a seleção informada substitui os scripts padrão do -sC.
+	switch {
+	case nmapPS.SimpleScan:
 		args = append(args, "-sS")
-	} else {
+	case len(nmapPS.Scripts) > 0:
+		args = append(args, "-sV")
+	default:
 		args = append(args, "-sV", "-sC")
 	}
+	if len(nmapPS.Scripts) > 0 {
+		args = append(args, "--script", scriptArgument(nmapPS.Scripts))
+	}
+
+	// Portas UDP exigem -sU; o TCP explícito (-sS) mantém o scan TCP junto do UDP.
+	// Sem portas TCP definidas, apenas as portas UDP são varridas.
+	if nmapPS.UDPPorts != "" {
+		if !nmapPS.SimpleScan && (nmapPS.AllPorts || nmapPS.PortList != "") {
+			args = append(args, "-sS")
+		}
+		args = append(args, "-sU")
+	}
 
 	// Adiciona opções extras, se houver.
 	if len(nmapPS.Options) > 0 {
@@ -135,25 +188,29 @@ func (nmapPS *NmapPortScanner) buildCommand() (string, []string) {
 	}
 
 	// Adiciona a lista de portas, a menos que AllPorts esteja definido. <=============================== OLHAR
-	if !nmapPS.AllPorts {
-		if nmapPS.PortList != "" {
-			args = append(args, "-p", nmapPS.PortList)
-		}
-	} else {
+	// Com UDP, as portas são prefixadas por protocolo (T:/U:), pois -p- também varreria todo o UDP.
+	switch {
+	case nmapPS.UDPPorts != "" && nmapPS.AllPorts:
+		args = append(args, "-p", "T:1-65535,U:"+nmapPS.UDPPorts)
+	case nmapPS.UDPPorts != "" && nmapPS.PortList != "":
+		args = append(args, "-p", "T:"+nmapPS.PortList+",U:"+nmapPS.UDPPorts)
+	case nmapPS.UDPPorts != "":
+		args = append(args, "-p", "U:"+nmapPS.UDPPorts)
+	case nmapPS.AllPorts:
 		args = append(args, "-p-")
+	case nmapPS.PortList != "":
+		args = append(args, "-p", nmapPS.PortList)
 	}
 
 	// Adiciona flag para mostrar somente portas abertas.
 	args = append(args, "--open")
 
-	// Adiciona o flag de modo de timing.
-	lowerMode := strings.ToLower(strings.TrimSpace(nmapPS.Mode))
-	switch lowerMode {
-	case "aggressive", "3":
-		args = append(args, "-T4")
-	case "stealth", "1":
-		args = append(args, "-T2")
-		// "normal" (2) ou default não adiciona flag adicional.
+	// Adiciona as opções de timing do modo, definidas em [modes] no config.toml.
+	// O modo já foi validado em Configure.
+	timing, _ := util.AppConfig.TimingOptions(nmapPS.Mode)
+	args = append(args, timing...)
+	if nmapPS.MaxRate > 0 {
+		args = append(args, "--max-rate", strconv.Itoa(nmapPS.MaxRate))
 	}
 
 	// Adiciona os alvos.
@@ -171,20 +228,18 @@ func (nmapPS *NmapPortScanner) buildCommand() (string, []string) {
 	return commandStr, args
 }
 
-// Execute executa o comando Nmap e retorna a saída bruta.
-func (nmapPS *NmapPortScanner) Execute() (string, error) {
+// Execute executa o comando Nmap e retorna a saída bruta. O processo é encerrado se ctx terminar.
+func (nmapPS *NmapPortScanner) Execute(ctx context.Context) (string, error) {
 	commandStr, args := nmapPS.buildCommand()
 	fmt.Printf("%s Running: %s\n", util.MarkerGreen, util.Green(commandStr))
 
-	fmt.Println(args)
-	// cmd := exec.Command("nmap", args...)
-	// var outputBuffer bytes.Buffer
-	// cmd.Stdout = &outputBuffer
-	// cmd.Stderr = &outputBuffer
+	cmd := exec.CommandContext(ctx, "nmap", args...)
+	output := util.NewTailBuffer(0)
+	cmd.Stdout, cmd.Stderr = output, output
 
-	// if err := cmd.Run(); err != nil {
-	// 	return "", fmt.Errorf("nmap execution failed: %w", err)
-	// }
+	if err := util.RunCommand(ctx, logger(), cmd); err != nil {
+		return "", util.CommandError("nmap", err, output)
+	}
 
 	// Lê o arquivo XML gerado pelo Nmap.
 	xmlFilePath := nmapPS.OutputFile + ".xml"
@@ -195,9 +250,23 @@ func (nmapPS *NmapPortScanner) Execute() (string, error) {
 	return string(data), nil
 }
 
-// Parse processa a saída bruta e extrai informações relevantes (exemplo: lista de portas abertas).
-func (nmapPS *NmapPortScanner) Parse(rawOutput string) ([]string, error) {
-	// Aqui você pode integrar um parser real (por exemplo, com a biblioteca go-nmap).
-	// Este é um exemplo simplificado:
-	return []string{"192.168.1.1:80", "192.168.1.2:443"}, nil
+// Parse converte o XML do Nmap em hosts estruturados, com os serviços TCP e UDP separados,
+// normaliza os resultados dos scripts NSE e grava o resultado em JSON ao lado dos arquivos -oA.
+func (nmapPS *NmapPortScanner) Parse(rawOutput string) ([]results.Host, error) {
+	hosts, err := results.FromNmapXML([]byte(rawOutput))
+	if err != nil {
+		return nil, err
+	}
+	filterScripts(hosts, nmapPS.Scripts)
+	if list := nse.Analyze(hosts); len(list) > 0 {
+		fmt.Printf("%s NSE findings: %s\n", util.MarkerGreen, util.Green(findings.Summary(list)))
+	}
+
+	jsonFile := nmapPS.OutputFile + ".json"
+	if err := results.SaveJSON(jsonFile, hosts); err != nil {
+		logger().Error().Err(err).Msgf("Failed to write %s", jsonFile)
+	} else {
+		fmt.Printf("%s Creating: %s\n", util.MarkerGreen, util.Green(jsonFile))
+	}
+	return hosts, nil
 }
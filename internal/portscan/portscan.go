@@ -1,12 +1,13 @@
 package portscan
 
 import (
+	"context"
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 
-	"github.com/rs/zerolog/log"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
 )
 
 // PortScanOrchestrator coordena a execução do port scan usando uma estratégia.
@@ -18,8 +19,8 @@ type PortScanOrchestrator struct {
 // PortScanStrategy define a interface para uma estratégia de varredura de portas.
 type PortScanStrategy interface {
 	Configure(params PortScanParams) error
-	Execute() (string, error)
-	Parse(rawOutput string) ([]string, error)
+	Execute(ctx context.Context) (string, error) // O processo é encerrado se ctx terminar
+	Parse(rawOutput string) ([]results.Host, error)
 }
 
 // NewPortScanOrchestrator cria um novo orquestrador com a estratégia escolhida.
@@ -30,131 +31,142 @@ func NewPortScanOrchestrator(strategy PortScanStrategy, params PortScanParams) *
 	}
 }
 
-// Run executa o fluxo completo do port scan: configuração, execução e parsing.
-func (orchestrator *PortScanOrchestrator) Run() ([]string, error) {
+// Run executa o fluxo completo do port scan: configuração, execução e parsing. Cancelar ctx interrompe o scan.
+func (orchestrator *PortScanOrchestrator) Run(ctx context.Context) ([]results.Host, error) {
 
 	if err := orchestrator.Strategy.Configure(orchestrator.Params); err != nil {
 		return nil, fmt.Errorf("failed to configure port scan: %w", err)
 	}
 
-	rawOutput, err := orchestrator.Strategy.Execute()
+	rawOutput, err := orchestrator.Strategy.Execute(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute port scan: %w", err)
 	}
 
-	ports, err := orchestrator.Strategy.Parse(rawOutput)
+	hosts, err := orchestrator.Strategy.Parse(rawOutput)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse port scan output: %w", err)
 	}
 
-	return ports, nil
+	return hosts, nil
 }
 
 // ===========================================================
 
-// combinePortLists combina a flag --ports com as portas provenientes da flag --category.
-// Retorna a união (sem duplicatas) das portas como string, ou "" se nenhum for informado.
-func combinePortLists(portList, category string) string {
-	set := make(map[int]bool)
-	// Processa a flag --ports.
-	if portList != "" {
-		for _, token := range strings.Split(portList, ",") {
-			token = strings.TrimSpace(token)
-			if token == "" {
-				continue
-			}
-			if strings.Contains(token, "-") {
-				parts := strings.Split(token, "-")
-				if len(parts) == 2 {
-					start, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
-					end, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
-					if err1 == nil && err2 == nil {
-						for i := start; i <= end; i++ {
-							set[i] = true
-						}
-					}
-				}
-			} else {
-				if num, err := strconv.Atoi(token); err == nil {
-					set[num] = true
-				}
-			}
+// portSet guarda as portas TCP e UDP selecionadas, sem duplicatas.
+type portSet struct {
+	tcp map[int]bool
+	udp map[int]bool
+}
+
+func newPortSet() *portSet {
+	return &portSet{tcp: make(map[int]bool), udp: make(map[int]bool)}
+}
+
+// addSpec adiciona uma especificação de portas no formato do Nmap (ex.: "22,80-90,U:53,161").
+// Como no Nmap, os prefixos T: e U: valem para as portas seguintes até o próximo prefixo.
+// Itens vazios são ignorados; uma porta inválida ou fora de 1-65535 é erro.
+func (ps *portSet) addSpec(spec string) error {
+	target := ps.tcp
+	for _, token := range strings.Split(spec, ",") {
+		token = strings.TrimSpace(token)
+		switch {
+		case strings.HasPrefix(strings.ToUpper(token), "U:"):
+			target = ps.udp
+			token = token[2:]
+		case strings.HasPrefix(strings.ToUpper(token), "T:"):
+			target = ps.tcp
+			token = token[2:]
 		}
-	}
-	// Processa a flag --category.
-	if category != "" {
-		catPorts := mergeCategoryPorts(category)
-		for _, token := range strings.Split(catPorts, ",") {
-			token = strings.TrimSpace(token)
-			if token == "" {
-				continue
-			}
-			if num, err := strconv.Atoi(token); err == nil {
-				set[num] = true
-			}
+		if token == "" {
+			continue
+		}
+		start, end, err := util.ParsePortRange(token)
+		if err != nil {
+			return err
+		}
+		for i := start; i <= end; i++ {
+			target[i] = true
 		}
 	}
+	return nil
+}
+
+// lists retorna as portas TCP e UDP ordenadas, separadas por vírgula.
+func (ps *portSet) lists() (string, string) {
+	return joinPorts(ps.tcp), joinPorts(ps.udp)
+}
+
+// sortedPorts retorna as portas do conjunto em ordem crescente.
+func sortedPorts(set map[int]bool) []int {
 	var ports []int
 	for p := range set {
 		ports = append(ports, p)
 	}
 	sort.Ints(ports)
+	return ports
+}
+
+// joinPorts ordena as portas do conjunto e as junta com vírgulas.
+func joinPorts(set map[int]bool) string {
 	var portStrs []string
-	for _, p := range ports {
+	for _, p := range sortedPorts(set) {
 		portStrs = append(portStrs, fmt.Sprintf("%d", p))
 	}
 	return strings.Join(portStrs, ",")
 }
 
-// mergeCategoryPorts lê as categorias (separadas por vírgula) e retorna uma string com a união de todas as portas,
-// sem duplicatas e ordenadas. Se "all" for especificado, inclui todas as portas de todas as categorias.
-func mergeCategoryPorts(catStr string) string {
-	cats := strings.Split(strings.ToLower(catStr), ",")
-	portSet := make(map[int]bool)
-	useAll := false
-	for _, cat := range cats {
-		cat = strings.TrimSpace(cat)
-		if cat == "all" {
-			useAll = true
-			break
+// CategoryPorts retorna as portas TCP e UDP de uma categoria de portas, ou ok=false se ela não existir.
+func CategoryPorts(category string) (tcp, udp []int, ok bool) {
+	tcp, udp, err := util.AppConfig.ExpandCategory(category)
+	if err != nil {
+		return nil, nil, false
+	}
+	return tcp, udp, true
+}
+
+// combinePortLists combina a flag --ports com as portas provenientes da flag --category.
+// Retorna a união (sem duplicatas) das portas TCP e UDP como strings, ou "" se nenhuma for informada.
+func combinePortLists(portList, category string) (string, string, error) {
+	set := newPortSet()
+	// Processa a flag --ports.
+	if portList != "" {
+		if err := set.addSpec(portList); err != nil {
+			return "", "", err
+		}
+	}
+	// Processa a flag --category.
+	if category != "" {
+		if err := set.addCategories(category); err != nil {
+			return "", "", err
 		}
 	}
-	if useAll {
-		for _, ports := range portCategories {
-			for _, port := range strings.Split(ports, ",") {
-				p := strings.TrimSpace(port)
-				if p != "" {
-					if num, err := strconv.Atoi(p); err == nil {
-						portSet[num] = true
-					}
-				}
-			}
+	tcp, udp := set.lists()
+	return tcp, udp, nil
+}
+
+// addCategories adiciona as portas das categorias informadas (separadas por vírgula), expandidas
+// pela configuração. "all" inclui todas as categorias configuradas; uma categoria desconhecida é erro.
+func (ps *portSet) addCategories(catStr string) error {
+	for _, cat := range strings.Split(strings.ToLower(catStr), ",") {
+		if cat = strings.TrimSpace(cat); cat == "" {
+			continue
 		}
-	} else {
-		for _, cat := range cats {
-			cat = strings.TrimSpace(cat)
-			if ports, ok := portCategories[cat]; ok {
-				for _, port := range strings.Split(ports, ",") {
-					p := strings.TrimSpace(port)
-					if p != "" {
-						if num, err := strconv.Atoi(p); err == nil {
-							portSet[num] = true
-						}
-					}
-				}
-			} else {
-				log.Warn().Msgf("Unknown Category: %s", cat)
-			}
+		tcp, udp, err := util.AppConfig.ExpandCategory(cat)
+		if err != nil {
+			return err
 		}
+		ps.addPorts(tcp, udp)
 	}
-	var portSlice []int
-	for p := range portSet {
-		portSlice = append(portSlice, p)
+	return nil
+}
+
+// addPorts adiciona portas TCP e UDP já expandidas.
+func (ps *portSet) addPorts(tcp, udp []int) {
+	for _, p := range tcp {
+		ps.tcp[p] = true
 	}
-	sort.Ints(portSlice)
-	var portStrs []string
-	for _, p := range portSlice {
-		portStrs = append(portStrs, fmt.Sprintf("%d", p))
+	for _, p := range udp {
+		ps.udp[p] = true
 	}
-	return strings.Join(portStrs, ",")
 }
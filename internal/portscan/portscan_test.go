@@ -0,0 +1,50 @@
+package portscan
+
+import "testing"
+
+func TestCombinePortLists(t *testing.T) {
+	for _, tc := range []struct {
+		ports, category string
+		tcp, udp        string
+	}{
+		{"22,80-82", "", "22,80,81,82", ""},
+		{"22,U:53,161,T:443", "", "22,443", "53,161"},
+		{"u:53,,t:22,", "", "22", "53"},
+		{"22", "firewall", "22,443,4433,4443,4444,8443", ""},
+	} {
+		tcp, udp, err := combinePortLists(tc.ports, tc.category)
+		if err != nil || tcp != tc.tcp || udp != tc.udp {
+			t.Errorf("combinePortLists(%q, %q) = %q, %q, %v; want %q, %q", tc.ports, tc.category, tcp, udp, err, tc.tcp, tc.udp)
+		}
+	}
+
+	for _, spec := range []string{"0-70000", "65536", "0", "80-22", "http", "22,abc", "1-2-3", "U:-5"} {
+		if tcp, udp, err := combinePortLists(spec, ""); err == nil {
+			t.Errorf("combinePortLists(%q) accepted: %q, %q", spec, tcp, udp)
+		}
+	}
+	if _, _, err := combinePortLists("22", "nope"); err == nil {
+		t.Error("unknown category accepted")
+	}
+}
+
+func TestConfigureInvalidPorts(t *testing.T) {
+	for _, params := range []PortScanParams{
+		{PortList: "1-70000"},
+		{PortList: "22,foo", Category: "firewall"},
+		{UDPPorts: "161,0"},
+		{UDPPorts: "99999", Category: "firewall"},
+	} {
+		params.Mode = "normal"
+		if err := NewNmapPortScanner().Configure(params); err == nil {
+			t.Errorf("Configure(ports %q, udp %q, category %q) accepted", params.PortList, params.UDPPorts, params.Category)
+		}
+	}
+	s := NewNmapPortScanner()
+	if err := s.Configure(PortScanParams{Mode: "normal", PortList: "22", UDPPorts: "161", Category: "firewall"}); err != nil {
+		t.Fatal(err)
+	}
+	if s.PortList != "22,443,4433,4443,4444,8443" || s.UDPPorts != "161" {
+		t.Errorf("ports %q, udp %q", s.PortList, s.UDPPorts)
+	}
+}
@@ -0,0 +1,145 @@
+package portscan
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// nseCategories são as categorias de scripts do NSE. Uma seleção por categoria pode casar
+// com qualquer script, por isso não restringe o escopo dos resultados.
+var nseCategories = map[string]bool{
+	"auth": true, "broadcast": true, "brute": true, "default": true, "discovery": true,
+	"dos": true, "exploit": true, "external": true, "fuzzer": true, "intrusive": true,
+	"malware": true, "safe": true, "version": true, "vuln": true,
+}
+
+// scriptGroup é a seleção de scripts NSE de uma categoria de portas ("" = todas as portas).
+type scriptGroup struct {
+	category string
+	scripts  []string
+	ports    *portSet // nil para o grupo global
+}
+
+// parseScriptSpec interpreta a flag --scripts. Grupos são separados por ";" e cada grupo é
+// "categoria=scripts" ou apenas "scripts" (vale para todas as portas).
+// Ex.: "default,vuln" ou "web=http-title,http-headers;windows=smb-security-mode,smb2-security-mode".
+func parseScriptSpec(spec string) ([]scriptGroup, error) {
+	var groups []scriptGroup
+	for _, raw := range strings.Split(spec, ";") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		group := scriptGroup{}
+		list := raw
+		if category, scripts, ok := strings.Cut(raw, "="); ok {
+			group.category = strings.ToLower(strings.TrimSpace(category))
+			list = scripts
+			if group.category != "all" {
+				tcp, udp, err := util.AppConfig.ExpandCategory(group.category)
+				if err != nil {
+					return nil, fmt.Errorf("invalid port category in --scripts: %w", err)
+				}
+				group.ports = newPortSet()
+				group.ports.addPorts(tcp, udp)
+			} else {
+				group.category = ""
+			}
+		}
+		for _, s := range strings.Split(list, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				group.scripts = append(group.scripts, s)
+			}
+		}
+		if len(group.scripts) == 0 {
+			return nil, fmt.Errorf("empty script list in --scripts: %q", raw)
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// scriptArgument junta os scripts de todos os grupos para o argumento --script do Nmap.
+// O Nmap não seleciona scripts por porta: a união é executada e os resultados são
+// restringidos às portas de cada categoria em filterScripts.
+func scriptArgument(groups []scriptGroup) string {
+	seen := make(map[string]bool)
+	var all []string
+	for _, g := range groups {
+		for _, s := range g.scripts {
+			if !seen[s] {
+				seen[s] = true
+				all = append(all, s)
+			}
+		}
+	}
+	sort.Strings(all)
+	return strings.Join(all, ",")
+}
+
+// selects indica se o item da seleção (nome, glob ou categoria NSE) pode ter gerado o script.
+func selects(item, scriptID string) bool {
+	if nseCategories[item] || item == "all" {
+		return true
+	}
+	item = strings.TrimSuffix(item, ".nse")
+	if ok, _ := path.Match(item, scriptID); ok {
+		return true
+	}
+	return item == scriptID
+}
+
+// scriptAllowed indica se o resultado do script na porta pertence a algum grupo que o selecionou.
+func scriptAllowed(groups []scriptGroup, scriptID, protocol string, port int) bool {
+	for _, g := range groups {
+		matched := false
+		for _, item := range g.scripts {
+			if selects(item, scriptID) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if g.ports == nil {
+			return true
+		}
+		if protocol == "udp" && g.ports.udp[port] || protocol != "udp" && g.ports.tcp[port] {
+			return true
+		}
+	}
+	return false
+}
+
+// filterScripts remove os resultados de scripts executados em portas fora da categoria que os selecionou.
+// Scripts de host não são filtrados.
+func filterScripts(hosts []results.Host, groups []scriptGroup) {
+	scoped := false
+	for _, g := range groups {
+		if g.ports != nil {
+			scoped = true
+		}
+	}
+	if !scoped {
+		return
+	}
+	for i := range hosts {
+		for _, services := range [][]results.Service{hosts[i].Services, hosts[i].UDPServices} {
+			for j := range services {
+				var kept []results.Script
+				for _, s := range services[j].Scripts {
+					if scriptAllowed(groups, s.ID, services[j].Protocol, services[j].Port) {
+						kept = append(kept, s)
+					}
+				}
+				services[j].Scripts = kept
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package portscan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+func TestParseScriptSpec(t *testing.T) {
+	groups, err := parseScriptSpec("default, vuln; Web=http-title,http-*.nse ;windows=smb2-security-mode;all=ssl-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 4 {
+		t.Fatalf("groups: %+v", groups)
+	}
+	global, web, windows, all := groups[0], groups[1], groups[2], groups[3]
+	if global.category != "" || global.ports != nil || !reflect.DeepEqual(global.scripts, []string{"default", "vuln"}) {
+		t.Errorf("global group: %+v", global)
+	}
+	if web.category != "web" || !web.ports.tcp[8080] || web.ports.tcp[445] || !reflect.DeepEqual(web.scripts, []string{"http-title", "http-*.nse"}) {
+		t.Errorf("web group: %+v", web)
+	}
+	if !windows.ports.tcp[389] || !windows.ports.udp[137] || windows.ports.tcp[137] {
+		t.Errorf("windows group ports: tcp %v, udp %v", windows.ports.tcp, windows.ports.udp)
+	}
+	if all.category != "" || all.ports != nil {
+		t.Errorf("all group: %+v", all)
+	}
+	if got := scriptArgument(groups); got != "default,http-*.nse,http-title,smb2-security-mode,ssl-cert,vuln" {
+		t.Errorf("scriptArgument = %q", got)
+	}
+
+	for _, spec := range []string{"nope=http-title", "web=", "web= , ;default"} {
+		if _, err := parseScriptSpec(spec); err == nil {
+			t.Errorf("parseScriptSpec(%q) accepted", spec)
+		}
+	}
+}
+
+func TestFilterScripts(t *testing.T) {
+	groups, err := parseScriptSpec("web=http-*;windows=smb2-security-mode,ldap-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scripts := func(ids ...string) []results.Script {
+		var list []results.Script
+		for _, id := range ids {
+			list = append(list, results.Script{ID: id})
+		}
+		return list
+	}
+	hosts := []results.Host{{
+		Address: "10.0.0.5",
+		Scripts: scripts("smb2-security-mode", "http-title"),
+		Services: []results.Service{
+			{Protocol: "tcp", Port: 80, Scripts: scripts("http-title", "smb2-security-mode", "ssl-cert")},
+			{Protocol: "tcp", Port: 389, Scripts: scripts("http-title", "ldap-rootdse", "smb2-security-mode")},
+		},
+		UDPServices: []results.Service{{Protocol: "udp", Port: 137, Scripts: scripts("nbstat", "http-title")}},
+	}}
+	filterScripts(hosts, groups)
+
+	ids := func(list []results.Script) []string {
+		var out []string
+		for _, s := range list {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+	for name, tc := range map[string]struct {
+		got, want []string
+	}{
+		// Scripts de host não são filtrados; nas portas, cada script vale só para a sua categoria.
+		"host":    {ids(hosts[0].Scripts), []string{"smb2-security-mode", "http-title"}},
+		"web":     {ids(hosts[0].Services[0].Scripts), []string{"http-title"}},
+		"windows": {ids(hosts[0].Services[1].Scripts), []string{"ldap-rootdse", "smb2-security-mode"}},
+		"udp":     {ids(hosts[0].UDPServices[0].Scripts), nil},
+	} {
+		if !reflect.DeepEqual(tc.got, tc.want) {
+			t.Errorf("%s: scripts %v, want %v", name, tc.got, tc.want)
+		}
+	}
+
+	// Uma categoria do NSE pode ter gerado qualquer script, mas só nas portas do grupo.
+	byCategory, _ := parseScriptSpec("udp=vuln")
+	hosts = []results.Host{{Address: "10.0.0.5",
+		Services:    []results.Service{{Protocol: "tcp", Port: 137, Scripts: scripts("smb-vuln-ms17-010")}},
+		UDPServices: []results.Service{{Protocol: "udp", Port: 137, Scripts: scripts("nbstat", "smb-vuln-ms17-010")}},
+	}}
+	filterScripts(hosts, byCategory)
+	if len(hosts[0].Services[0].Scripts) != 0 || len(hosts[0].UDPServices[0].Scripts) != 2 {
+		t.Errorf("vuln category: tcp %v, udp %v", ids(hosts[0].Services[0].Scripts), ids(hosts[0].UDPServices[0].Scripts))
+	}
+
+	// Sem categorias, nada é filtrado.
+	global, _ := parseScriptSpec("http-title")
+	hosts = []results.Host{{Address: "10.0.0.5", Services: []results.Service{{Protocol: "tcp", Port: 22, Scripts: scripts("ssh-hostkey")}}}}
+	filterScripts(hosts, global)
+	if len(hosts[0].Services[0].Scripts) != 1 {
+		t.Errorf("global selection filtered: %+v", hosts[0].Services[0].Scripts)
+	}
+}
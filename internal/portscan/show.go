@@ -13,10 +13,15 @@ func ShowConfiguration(params PortScanParams) {
 	fmt.Printf("  %s: %s\n", util.Green("Target"), strings.Join(params.Targets, ", "))
 	fmt.Printf("  %s: %s\n", util.Green("Output"), params.OutputFile)
 	fmt.Printf("  %s: %s\n", util.Green("Port Range"), params.PortList)
+	fmt.Printf("  %s: %s\n", util.Green("UDP Ports"), params.UDPPorts)
 	fmt.Printf("  %s: %s\n", util.Green("Options"), strings.Join(params.Options, ", "))
 	fmt.Printf("  %s: %t\n", util.Green("All Ports"), params.AllPorts)
 	fmt.Printf("  %s: %t\n", util.Green("Simple Scan"), params.SimpleScan)
 	fmt.Printf("  %s: %s\n", util.Green("Mode"), params.Mode)
 	fmt.Printf("  %s: %s\n", util.Green("Category"), params.Category)
+	fmt.Printf("  %s: %s\n", util.Green("Scripts"), params.Scripts)
+	if params.MaxRate > 0 {
+		fmt.Printf("  %s: %d pps\n", util.Green("Max Rate"), params.MaxRate)
+	}
 	fmt.Println("└──────────────────────────────────────────────┘")
 }
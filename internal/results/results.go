@@ -0,0 +1,241 @@
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tomsteele/go-nmap"
+)
+
+// Service representa uma porta aberta e o serviço identificado nela.
+type Service struct {
+	Port      int      `json:"port"`
+	Protocol  string   `json:"protocol"`             // tcp ou udp
+	State     string   `json:"state"`                // open, open|filtered, ...
+	Name      string   `json:"name,omitempty"`       // Nome do serviço (ex.: ssh, http)
+	Product   string   `json:"product,omitempty"`    // Produto identificado (ex.: OpenSSH)
+	Version   string   `json:"version,omitempty"`    // Versão do produto
+	ExtraInfo string   `json:"extra_info,omitempty"` // Informação extra reportada pelo Nmap
+	CPEs      []string `json:"cpes,omitempty"`       // CPEs reportados pelo -sV
+	Banner    string   `json:"banner,omitempty"`     // Primeiros bytes retornados pelo serviço
+	Method    string   `json:"method,omitempty"`     // Origem da identificação: probed, table, banner...
+	Scripts   []Script `json:"scripts,omitempty"`    // Resultados dos scripts NSE da porta
+
+	Enrichments map[string]interface{} `json:"enrichments,omitempty"` // Resultados dos módulos específicos desta porta
+}
+
+// SetEnrichment anexa o resultado de um módulo ao serviço.
+func (s *Service) SetEnrichment(module string, value interface{}) {
+	if s.Enrichments == nil {
+		s.Enrichments = make(map[string]interface{})
+	}
+	s.Enrichments[module] = value
+}
+
+// Enrichment decodifica o resultado de um módulo anexado ao serviço em out.
+func (s Service) Enrichment(module string, out interface{}) bool {
+	return decodeEnrichment(s.Enrichments, module, out)
+}
+
+// Identified indica se o serviço já possui produto ou versão conhecidos.
+func (s Service) Identified() bool {
+	return s.Product != "" || s.Version != ""
+}
+
+// Identify completa nome, produto e versão identificados por um módulo quando o Nmap não os identificou.
+// O nome só é substituído se estiver vazio, for "unknown" ou tiver vindo apenas da tabela de portas.
+func (s *Service) Identify(method, name, product, version string) {
+	if s.Identified() || (name == "" && product == "" && version == "") {
+		return
+	}
+	if name != "" && (s.Name == "" || s.Name == "unknown" || s.Method == "table") {
+		s.Name = name
+	}
+	s.Product = product
+	s.Version = version
+	s.Method = method
+}
+
+// Host representa um host e tudo o que foi coletado sobre ele durante o recon.
+type Host struct {
+	Address     string                 `json:"address"`
+	Hostnames   []string               `json:"hostnames,omitempty"`
+	Services    []Service              `json:"services"`               // Serviços TCP
+	UDPServices []Service              `json:"udp_services,omitempty"` // Serviços UDP (open e open|filtered)
+	Scripts     []Script               `json:"scripts,omitempty"`      // Resultados dos scripts NSE de host (<hostscript>)
+	Tags        []string               `json:"tags,omitempty"`         // Marcações livres (ex.: domain-controller)
+	Enrichments map[string]interface{} `json:"enrichments,omitempty"`  // Resultados dos módulos, indexados pelo nome do módulo
+}
+
+// SetEnrichment anexa o resultado de um módulo ao host.
+func (h *Host) SetEnrichment(module string, value interface{}) {
+	if h.Enrichments == nil {
+		h.Enrichments = make(map[string]interface{})
+	}
+	h.Enrichments[module] = value
+}
+
+// AddTag adiciona uma marcação ao host, sem duplicatas.
+func (h *Host) AddTag(tag string) {
+	for _, t := range h.Tags {
+		if t == tag {
+			return
+		}
+	}
+	h.Tags = append(h.Tags, tag)
+}
+
+// HasTag indica se o host possui a marcação informada.
+func (h *Host) HasTag(tag string) bool {
+	for _, t := range h.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Service retorna o serviço na porta e protocolo informados, ou nil se a porta não estiver aberta.
+func (h *Host) Service(protocol string, port int) *Service {
+	services := h.Services
+	if protocol == "udp" {
+		services = h.UDPServices
+	}
+	for i := range services {
+		if services[i].Port == port && services[i].Protocol == protocol {
+			return &services[i]
+		}
+	}
+	return nil
+}
+
+// HasPort indica se o host possui a porta aberta no protocolo informado.
+func (h *Host) HasPort(protocol string, port int) bool {
+	services := h.Services
+	if protocol == "udp" {
+		services = h.UDPServices
+	}
+	for _, s := range services {
+		if s.Port == port && s.Protocol == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// FromNmapXML converte o XML gerado pelo Nmap (-oX/-oA) em uma lista de hosts.
+// Apenas portas abertas (open ou open|filtered) são consideradas.
+func FromNmapXML(data []byte) ([]Host, error) {
+	run, err := nmap.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse nmap XML: %w", err)
+	}
+	return FromNmapRun(run), nil
+}
+
+// FromNmapRun converte uma execução já parseada do Nmap em uma lista de hosts.
+func FromNmapRun(run *nmap.NmapRun) []Host {
+	var hosts []Host
+	for _, nh := range run.Hosts {
+		host := Host{}
+		for _, addr := range nh.Addresses {
+			if addr.AddrType == "ipv4" || addr.AddrType == "ipv6" {
+				host.Address = addr.Addr
+				break
+			}
+		}
+		if host.Address == "" {
+			continue
+		}
+		for _, hn := range nh.Hostnames {
+			host.Hostnames = append(host.Hostnames, hn.Name)
+		}
+		host.Scripts = FromNmapScripts(nh.HostScripts)
+		for _, p := range nh.Ports {
+			if !strings.HasPrefix(p.State.State, "open") {
+				continue
+			}
+			svc := Service{
+				Port:      p.PortId,
+				Protocol:  p.Protocol,
+				State:     p.State.State,
+				Name:      p.Service.Name,
+				Product:   p.Service.Product,
+				Version:   p.Service.Version,
+				ExtraInfo: p.Service.ExtraInfo,
+				Method:    p.Service.Method,
+				Scripts:   FromNmapScripts(p.Scripts),
+			}
+			for _, cpe := range p.Service.CPEs {
+				svc.CPEs = append(svc.CPEs, string(cpe))
+			}
+			if svc.Protocol == "udp" {
+				host.UDPServices = append(host.UDPServices, svc)
+			} else {
+				host.Services = append(host.Services, svc)
+			}
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// LoadFile carrega hosts de um arquivo XML do Nmap ou de um JSON gerado por SaveJSON.
+func LoadFile(path string) ([]Host, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results file: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var hosts []Host
+		if err := json.Unmarshal(data, &hosts); err != nil {
+			return nil, fmt.Errorf("failed to decode results JSON: %w", err)
+		}
+		return hosts, nil
+	}
+	return FromNmapXML(data)
+}
+
+// SaveJSON grava os hosts em formato JSON indentado.
+func SaveJSON(path string, hosts []Host) error {
+	data, err := json.MarshalIndent(hosts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal results: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write results file: %w", err)
+	}
+	return nil
+}
+
+// Enrichment decodifica o resultado de um módulo anexado ao host em out.
+// Funciona tanto para resultados recém-produzidos quanto para os carregados de um JSON.
+func (h *Host) Enrichment(module string, out interface{}) bool {
+	return decodeEnrichment(h.Enrichments, module, out)
+}
+
+// decodeEnrichment converte o valor do módulo via JSON para o tipo de out.
+func decodeEnrichment(enrichments map[string]interface{}, module string, out interface{}) bool {
+	value, ok := enrichments[module]
+	if !ok {
+		return false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
+
+// AddHostname adiciona um nome ao host, sem duplicatas.
+func (h *Host) AddHostname(name string) {
+	for _, n := range h.Hostnames {
+		if strings.EqualFold(n, name) {
+			return
+		}
+	}
+	h.Hostnames = append(h.Hostnames, name)
+}
@@ -0,0 +1,84 @@
+package results
+
+import (
+	"github.com/tomsteele/go-nmap"
+)
+
+// Script é o resultado de um script NSE, com a saída textual e a árvore estruturada
+// (<table>/<elem>) convertida em mapas e listas.
+type Script struct {
+	ID     string      `json:"id"`
+	Output string      `json:"output,omitempty"`
+	Data   interface{} `json:"data,omitempty"` // map[string]interface{}, []interface{} ou string
+}
+
+// Tree converte a árvore do script em mapas (chaves) e listas (itens sem chave).
+func (s Script) Tree() map[string]interface{} {
+	if m, ok := s.Data.(map[string]interface{}); ok {
+		return m
+	}
+	return nil
+}
+
+// FromNmapScripts converte os scripts de uma porta ou de um host.
+func FromNmapScripts(scripts []nmap.Script) []Script {
+	var out []Script
+	for _, s := range scripts {
+		out = append(out, Script{ID: s.Id, Output: s.Output, Data: scriptTree(s.Elements, s.Tables)})
+	}
+	return out
+}
+
+// itemsKey agrupa os itens sem chave quando o nível também possui itens com chave.
+const itemsKey = "_items"
+
+// scriptTree monta a árvore de um nível do script. Se nenhum item tiver chave, o nível vira
+// uma lista; caso contrário, um mapa (itens sem chave ficam em "_items").
+func scriptTree(elements []nmap.Element, tables []nmap.Table) interface{} {
+	if len(elements) == 0 && len(tables) == 0 {
+		return nil
+	}
+	keyed := false
+	for _, e := range elements {
+		if e.Key != "" {
+			keyed = true
+		}
+	}
+	for _, t := range tables {
+		if t.Key != "" {
+			keyed = true
+		}
+	}
+
+	var items []interface{}
+	if !keyed {
+		for _, e := range elements {
+			items = append(items, e.Value)
+		}
+		for _, t := range tables {
+			items = append(items, scriptTree(t.Elements, t.Table))
+		}
+		return items
+	}
+
+	tree := make(map[string]interface{})
+	for _, e := range elements {
+		if e.Key == "" {
+			items = append(items, e.Value)
+			continue
+		}
+		tree[e.Key] = e.Value
+	}
+	for _, t := range tables {
+		sub := scriptTree(t.Elements, t.Table)
+		if t.Key == "" {
+			items = append(items, sub)
+			continue
+		}
+		tree[t.Key] = sub
+	}
+	if len(items) > 0 {
+		tree[itemsKey] = items
+	}
+	return tree
+}
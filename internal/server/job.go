@@ -0,0 +1,243 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// Estados de um job.
+const (
+	StatusQueued   = "queued"
+	StatusRunning  = "running"
+	StatusDone     = "done"
+	StatusFailed   = "failed"
+	StatusCanceled = "canceled"
+)
+
+// EventJobStatus é o tipo do evento de progresso que informa a mudança de estado do job.
+const EventJobStatus = "job_status"
+
+// Etapas que podem ser pedidas em um job, na ordem em que são executadas.
+const (
+	StageDiscovery    = "discovery"
+	StagePortScan     = "portscan"
+	StageEnumeration  = "enumeration"
+	StageVulnAnalysis = "vulnanalysis"
+)
+
+// Stages lista as etapas aceitas em JobRequest.Stages, na ordem de execução.
+var Stages = []string{StageDiscovery, StagePortScan, StageEnumeration, StageVulnAnalysis}
+
+// JobRequest é o corpo do POST /api/jobs. Os campos vazios vêm do perfil e, na falta dele, da configuração.
+type JobRequest struct {
+	Targets  []string `json:"targets"`             // IPs ou CIDRs
+	Profile  string   `json:"profile,omitempty"`   // Perfil de scan (como --profile)
+	Stages   []string `json:"stages,omitempty"`    // Etapas a executar (vazio = todas)
+	Mode     string   `json:"mode,omitempty"`      // Modo do scan
+	Category string   `json:"category,omitempty"`  // Categorias de portas
+	Ports    string   `json:"ports,omitempty"`     // Lista ou range de portas TCP
+	UDPPorts string   `json:"udp_ports,omitempty"` // Lista de portas UDP
+}
+
+// Validate confere os alvos e as etapas pedidas. Arquivos de alvos não são aceitos pela API.
+func (r *JobRequest) Validate() error {
+	if len(r.Targets) == 0 {
+		return errors.New("targets is required")
+	}
+	for _, target := range r.Targets {
+		if !util.IsValidTarget(target) {
+			return fmt.Errorf("invalid target %q (expected an IP address or CIDR range)", target)
+		}
+	}
+	for _, stage := range r.Stages {
+		if !containsStage(stage) {
+			return fmt.Errorf("unknown stage %q (available: %s, %s, %s, %s)", stage, StageDiscovery, StagePortScan, StageEnumeration, StageVulnAnalysis)
+		}
+	}
+	if len(r.Stages) > 0 && !r.HasStage(StagePortScan) && (r.HasStage(StageEnumeration) || r.HasStage(StageVulnAnalysis)) {
+		return fmt.Errorf("stages %s and %s need %s", StageEnumeration, StageVulnAnalysis, StagePortScan)
+	}
+	return nil
+}
+
+// HasStage indica se a etapa foi pedida; sem etapas informadas, todas são executadas.
+func (r *JobRequest) HasStage(stage string) bool {
+	if len(r.Stages) == 0 {
+		return true
+	}
+	for _, s := range r.Stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// containsStage indica se a etapa existe.
+func containsStage(stage string) bool {
+	for _, s := range Stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Job é um scan submetido pela API: o pedido, o estado, os eventos de progresso e os resultados.
+type Job struct {
+	ID       string     `json:"id"`
+	Request  JobRequest `json:"request"`
+	Status   string     `json:"status"`
+	Stage    string     `json:"stage,omitempty"` // Etapa em execução ou a última executada
+	Error    string     `json:"error,omitempty"`
+	Created  time.Time  `json:"created"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+
+	stages   []fullrecon.Stage
+	state    *fullrecon.State
+	cancel   context.CancelFunc
+	mu       sync.Mutex
+	events   []notify.Event
+	changed  chan struct{} // Fechado e substituído a cada evento, para acordar os streams SSE
+	hosts    int           // Hosts novos notificados até o momento
+	findings int           // Achados notificados até o momento
+}
+
+// newJob cria um job na fila com um ID aleatório.
+func newJob(req JobRequest) *Job {
+	id := make([]byte, 6)
+	_, _ = rand.Read(id)
+	job := &Job{
+		ID:      hex.EncodeToString(id),
+		Request: req,
+		Created: time.Now(),
+		changed: make(chan struct{}),
+	}
+	job.setStatusLocked(StatusQueued, "")
+	return job
+}
+
+// JobSummary é a visão do job retornada pela API.
+type JobSummary struct {
+	*Job
+	Hosts    int `json:"hosts"`
+	Findings int `json:"findings"`
+}
+
+// Summary retorna uma cópia consistente do job com a contagem de hosts e achados.
+func (j *Job) Summary() JobSummary {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	copied := &Job{ID: j.ID, Request: j.Request, Status: j.Status, Stage: j.Stage, Error: j.Error,
+		Created: j.Created, Started: j.Started, Finished: j.Finished}
+	return JobSummary{Job: copied, Hosts: j.hosts, Findings: j.findings}
+}
+
+// Results retorna os hosts e os achados produzidos até o momento.
+func (j *Job) Results() ([]results.Host, []findings.Finding) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.state == nil {
+		// Durante a execução, o State ainda é alterado pelas etapas; só o resultado final é exposto.
+		return []results.Host{}, []findings.Finding{}
+	}
+	hosts, list := j.state.Hosts, j.state.Findings
+	if hosts == nil {
+		hosts = []results.Host{}
+	}
+	if list == nil {
+		list = []findings.Finding{}
+	}
+	return hosts, list
+}
+
+// done indica se o job terminou (concluído, com falha ou cancelado).
+func (j *Job) done() bool {
+	return j.Status == StatusDone || j.Status == StatusFailed || j.Status == StatusCanceled
+}
+
+// setStatus muda o estado do job e publica o evento correspondente.
+func (j *Job) setStatus(status, errMessage string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.setStatusLocked(status, errMessage)
+}
+
+// setStatusLocked é setStatus com j.mu já obtido.
+func (j *Job) setStatusLocked(status, errMessage string) {
+	now := time.Now()
+	j.Status, j.Error = status, errMessage
+	switch status {
+	case StatusRunning:
+		j.Started = &now
+	case StatusDone, StatusFailed, StatusCanceled:
+		j.Finished = &now
+	}
+	message := "Job " + j.ID + " " + status
+	if errMessage != "" {
+		message += ": " + errMessage
+	}
+	j.publishLocked(notify.Event{Type: EventJobStatus, Time: now, Source: "server", New: status, Message: message})
+}
+
+// publish acrescenta um evento ao histórico do job e acorda os streams.
+func (j *Job) publish(event notify.Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.publishLocked(event)
+}
+
+// publishLocked é publish com j.mu já obtido.
+func (j *Job) publishLocked(event notify.Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	switch event.Type {
+	case notify.EventStageStarted:
+		j.Stage = event.Stage
+	case notify.EventNewHost:
+		j.hosts++
+	case notify.EventFinding:
+		j.findings++
+	}
+	j.events = append(j.events, event)
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// eventsFrom retorna os eventos a partir de from, o canal fechado no próximo evento e se o job terminou.
+func (j *Job) eventsFrom(from int) ([]notify.Event, <-chan struct{}, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var events []notify.Event
+	if from < len(j.events) {
+		events = append(events, j.events[from:]...)
+	}
+	return events, j.changed, j.done()
+}
+
+// jobNotifier entrega ao histórico do job os eventos do orquestrador.
+type jobNotifier struct {
+	job *Job
+}
+
+// Name retorna o nome do notificador.
+func (n jobNotifier) Name() string { return "job " + n.job.ID }
+
+// Notify publica o evento no histórico do job.
+func (n jobNotifier) Notify(ctx context.Context, event notify.Event) error {
+	n.job.publish(event)
+	return nil
+}
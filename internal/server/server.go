@@ -0,0 +1,429 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+	"github.com/Arthx-x/arthxrecon/internal/notify"
+	"github.com/Arthx-x/arthxrecon/util"
+)
+
+// ModuleName é o nome do estágio nos logs.
+const ModuleName = "server"
+
+// Limites da API.
+const (
+	maxRequestBody    = 1 << 20          // Tamanho máximo do corpo do POST /api/jobs
+	keepAliveInterval = 15 * time.Second // Intervalo dos comentários que mantêm o stream SSE aberto
+	shutdownTimeout   = 10 * time.Second // Espera pelas requisições em andamento ao encerrar
+)
+
+// ServerParams reúne os parâmetros do servidor.
+type ServerParams struct {
+	Listen    string // Endereço HTTP (ex.: 127.0.0.1:8080)
+	Token     string // Token exigido no cabeçalho Authorization: Bearer
+	Workers   int    // Jobs executados ao mesmo tempo
+	QueueSize int    // Jobs aguardando na fila; acima disso o POST retorna 503
+	Keep      int    // Jobs concluídos mantidos na API; os mais antigos são removidos (0 mantém todos)
+
+	// Build monta as etapas do job a partir do pedido, do perfil e da configuração.
+	Build func(id string, req JobRequest) ([]fullrecon.Stage, error)
+	// Notifier são os notificadores configurados, que também recebem os eventos dos jobs.
+	Notifier *notify.Dispatcher
+	// OnFinish é chamado ao fim de cada job, um por vez (ex.: registro de achados).
+	OnFinish func(state *fullrecon.State)
+}
+
+// Server expõe a API HTTP que enfileira e executa os jobs com o orquestrador do fullrecon.
+type Server struct {
+	Params ServerParams
+
+	mu       sync.Mutex
+	jobs     map[string]*Job
+	order    []*Job // Jobs na ordem de submissão
+	queue    chan *Job
+	finishMu sync.Mutex
+}
+
+// NewServer é a factory que cria o Server.
+func NewServer(params ServerParams) (*Server, error) {
+	if params.Token == "" {
+		return nil, errors.New("an API token is required")
+	}
+	if params.Workers <= 0 {
+		return nil, fmt.Errorf("workers must be positive, got %d", params.Workers)
+	}
+	if params.QueueSize <= 0 {
+		return nil, fmt.Errorf("queue size must be positive, got %d", params.QueueSize)
+	}
+	if params.Keep < 0 {
+		return nil, fmt.Errorf("keep must not be negative, got %d", params.Keep)
+	}
+	if params.Build == nil {
+		return nil, errors.New("no job builder")
+	}
+	return &Server{
+		Params: params,
+		jobs:   make(map[string]*Job),
+		queue:  make(chan *Job, params.QueueSize),
+	}, nil
+}
+
+// Handler retorna as rotas da API com a autenticação aplicada.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/health", s.handleHealth)
+	mux.Handle("POST /api/jobs", s.auth(s.handleSubmit))
+	mux.Handle("GET /api/jobs", s.auth(s.handleList))
+	mux.Handle("GET /api/jobs/{id}", s.auth(s.handleGet))
+	mux.Handle("DELETE /api/jobs/{id}", s.auth(s.handleCancel))
+	mux.Handle("GET /api/jobs/{id}/events", s.auth(s.handleEvents))
+	mux.Handle("GET /api/jobs/{id}/results", s.auth(s.handleResults))
+	return mux
+}
+
+// ListenAndServe inicia os workers e atende a API até ctx ser cancelado. Os jobs em execução
+// são cancelados ao encerrar.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.Params.Listen)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve é ListenAndServe sobre um listener já aberto.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	var workers sync.WaitGroup
+	for i := 0; i < s.Params.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			s.worker(ctx)
+		}()
+	}
+
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		// Os streams SSE terminam quando ctx é cancelado, sem travar o Shutdown.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- httpServer.Serve(listener) }()
+	util.StageLogger(ModuleName).Info().Str("listen", listener.Addr().String()).Int("workers", s.Params.Workers).Msg("API server started")
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		err = httpServer.Shutdown(shutdownCtx)
+		cancel()
+	}
+	workers.Wait()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// worker executa os jobs da fila até ctx ser cancelado.
+func (s *Server) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.queue:
+			s.run(ctx, job)
+		}
+	}
+}
+
+// run executa um job. Falhas (inclusive panics) encerram apenas o job.
+func (s *Server) run(ctx context.Context, job *Job) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	job.mu.Lock()
+	if job.Status == StatusCanceled {
+		job.mu.Unlock()
+		return
+	}
+	job.cancel = cancel
+	job.setStatusLocked(StatusRunning, "")
+	job.mu.Unlock()
+
+	logger := util.StageLogger(ModuleName).With().Str("job", job.ID).Logger()
+	logger.Info().Strs("targets", job.Request.Targets).Msg("Job started")
+
+	state := &fullrecon.State{Targets: job.Request.Targets}
+	orchestrator := fullrecon.NewFullReconOrchestrator(job.stages...)
+	orchestrator.Notifier = s.Params.Notifier.With(jobNotifier{job: job})
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return orchestrator.Run(jobCtx, state)
+	}()
+
+	job.mu.Lock()
+	job.state = state
+	job.mu.Unlock()
+	if s.Params.OnFinish != nil {
+		s.finishMu.Lock()
+		s.Params.OnFinish(state)
+		s.finishMu.Unlock()
+	}
+
+	switch {
+	case jobCtx.Err() != nil:
+		job.setStatus(StatusCanceled, "")
+		logger.Info().Msg("Job canceled")
+	case err != nil:
+		job.setStatus(StatusFailed, err.Error())
+		logger.Error().Err(err).Msg("Job failed")
+	default:
+		job.setStatus(StatusDone, "")
+		logger.Info().Int("hosts", len(state.Hosts)).Int("findings", len(state.Findings)).Msg("Job finished")
+	}
+	s.prune()
+}
+
+// prune remove os jobs concluídos mais antigos além de Params.Keep, com os seus eventos e
+// resultados. Jobs na fila ou em execução nunca são removidos.
+func (s *Server) prune() {
+	if s.Params.Keep <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	done := make([]bool, len(s.order))
+	finished := 0
+	for i, job := range s.order {
+		job.mu.Lock()
+		done[i] = job.done()
+		job.mu.Unlock()
+		if done[i] {
+			finished++
+		}
+	}
+	kept := s.order[:0]
+	var removed []string
+	for i, job := range s.order {
+		if done[i] && finished > s.Params.Keep {
+			delete(s.jobs, job.ID)
+			removed = append(removed, job.ID)
+			finished--
+			continue
+		}
+		kept = append(kept, job)
+	}
+	clear(s.order[len(kept):])
+	s.order = kept
+	if len(removed) > 0 {
+		util.StageLogger(ModuleName).Debug().Strs("jobs", removed).Int("keep", s.Params.Keep).Msg("Old jobs removed")
+	}
+}
+
+// auth exige o token no cabeçalho Authorization: Bearer.
+func (s *Server) auth(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.Params.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="arthxrecon"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		next(w, r)
+	})
+}
+
+// handleHealth responde sem autenticação, para verificações de disponibilidade.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleSubmit valida o pedido, monta as etapas e coloca o job na fila.
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req JobRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job := newJob(req)
+	stages, err := s.Params.Build(job.ID, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	job.stages = stages
+
+	s.mu.Lock()
+	select {
+	case s.queue <- job:
+		s.jobs[job.ID] = job
+		s.order = append(s.order, job)
+		s.mu.Unlock()
+	default:
+		s.mu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, "job queue is full")
+		return
+	}
+	util.StageLogger(ModuleName).Info().Str("job", job.ID).Strs("targets", req.Targets).Str("profile", req.Profile).Msg("Job queued")
+	w.Header().Set("Location", "/api/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job.Summary())
+}
+
+// handleList lista os jobs, do mais recente para o mais antigo; ?status= filtra pelo estado.
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	s.mu.Lock()
+	jobs := append([]*Job(nil), s.order...)
+	s.mu.Unlock()
+	list := make([]JobSummary, 0, len(jobs))
+	for i := len(jobs) - 1; i >= 0; i-- {
+		summary := jobs[i].Summary()
+		if status == "" || summary.Status == status {
+			list = append(list, summary)
+		}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// handleGet retorna o estado de um job.
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	if job := s.job(w, r); job != nil {
+		writeJSON(w, http.StatusOK, job.Summary())
+	}
+}
+
+// handleCancel cancela um job: na fila, ele não será executado; em execução, o processo externo da
+// etapa atual (nmap, masscan, plugins) é encerrado e as etapas seguintes não começam.
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	job := s.job(w, r)
+	if job == nil {
+		return
+	}
+	job.mu.Lock()
+	status := job.Status
+	switch status {
+	case StatusQueued:
+		job.setStatusLocked(StatusCanceled, "")
+	case StatusRunning:
+		job.cancel()
+	}
+	job.mu.Unlock()
+	if status != StatusQueued && status != StatusRunning {
+		writeError(w, http.StatusConflict, "job already "+status)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job.Summary())
+	if status == StatusQueued {
+		s.prune()
+	}
+}
+
+// handleResults retorna os hosts e achados de um job concluído.
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request) {
+	job := s.job(w, r)
+	if job == nil {
+		return
+	}
+	hosts, list := job.Results()
+	summary := job.Summary()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":       summary.ID,
+		"status":   summary.Status,
+		"hosts":    hosts,
+		"findings": list,
+	})
+}
+
+// handleEvents transmite os eventos de progresso do job por SSE: o histórico (a partir de
+// Last-Event-ID, se informado) e os novos eventos, até o job terminar.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	job := s.job(w, r)
+	if job == nil {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	cursor := 0
+	if last, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil && last > 0 {
+		cursor = last
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		events, changed, done := job.eventsFrom(cursor)
+		for _, event := range events {
+			cursor++
+			data, _ := json.Marshal(event)
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", cursor, event.Type, data)
+		}
+		flusher.Flush()
+		if done {
+			return
+		}
+		select {
+		case <-changed:
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// job busca o job do caminho; quando não existe, responde 404 e retorna nil.
+func (s *Server) job(w http.ResponseWriter, r *http.Request) *Job {
+	s.mu.Lock()
+	job := s.jobs[r.PathValue("id")]
+	s.mu.Unlock()
+	if job == nil {
+		writeError(w, http.StatusNotFound, "job not found")
+	}
+	return job
+}
+
+// writeJSON responde com o valor em JSON.
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value)
+}
+
+// writeError responde com {"error": message}.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
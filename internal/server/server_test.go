@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	fullrecon "github.com/Arthx-x/arthxrecon/internal/fullRecon"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// noopStage é uma etapa que termina sem fazer nada.
+type noopStage struct{}
+
+func (noopStage) Name() string                                          { return "Noop" }
+func (noopStage) Run(ctx context.Context, state *fullrecon.State) error { return nil }
+
+// request faz uma requisição autenticada ao handler e decodifica a resposta em out.
+func request(t *testing.T, h http.Handler, method, path, body string, out interface{}) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: %v: %s", method, path, err, rec.Body)
+		}
+	}
+	return rec.Code
+}
+
+func TestPruneFinishedJobs(t *testing.T) {
+	s, err := NewServer(ServerParams{Token: "token", Workers: 1, QueueSize: 10, Keep: 2,
+		Build: func(string, JobRequest) ([]fullrecon.Stage, error) { return []fullrecon.Stage{noopStage{}}, nil }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := s.Handler()
+	var ids []string
+	for range 5 {
+		var summary JobSummary
+		if code := request(t, h, http.MethodPost, "/api/jobs", `{"targets":["127.0.0.1"]}`, &summary); code != http.StatusAccepted {
+			t.Fatalf("submit: %d", code)
+		}
+		ids = append(ids, summary.ID)
+	}
+
+	// Os três primeiros terminam; os dois últimos continuam na fila.
+	for range 3 {
+		s.run(context.Background(), <-s.queue)
+	}
+	var list []JobSummary
+	request(t, h, http.MethodGet, "/api/jobs", "", &list)
+	if len(list) != 4 {
+		t.Fatalf("%d jobs listed, want 4", len(list))
+	}
+	if code := request(t, h, http.MethodGet, "/api/jobs/"+ids[0], "", nil); code != http.StatusNotFound {
+		t.Errorf("oldest finished job: %d, want 404", code)
+	}
+	for _, id := range ids[1:] {
+		if code := request(t, h, http.MethodGet, "/api/jobs/"+id, "", nil); code != http.StatusOK {
+			t.Errorf("job %s: %d", id, code)
+		}
+	}
+
+	// Cancelar um job na fila também o conclui.
+	if code := request(t, h, http.MethodDelete, "/api/jobs/"+ids[4], "", nil); code != http.StatusAccepted {
+		t.Fatalf("cancel: %d", code)
+	}
+	request(t, h, http.MethodGet, "/api/jobs", "", &list)
+	if len(list) != 3 || list[0].ID != ids[4] || list[1].ID != ids[3] || list[2].ID != ids[2] {
+		t.Errorf("jobs after cancel: %+v", list)
+	}
+
+	if _, err := NewServer(ServerParams{Token: "token", Workers: 1, QueueSize: 1, Keep: -1, Build: s.Params.Build}); err == nil {
+		t.Error("NewServer accepted a negative keep")
+	}
+}
+
+// blockingStage é uma etapa que sinaliza o início e só termina quando ctx é cancelado.
+type blockingStage struct{ started chan struct{} }
+
+func (blockingStage) Name() string { return "Blocking" }
+func (s blockingStage) Run(ctx context.Context, state *fullrecon.State) error {
+	close(s.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// hostStage é uma etapa que acrescenta um host ao resultado.
+type hostStage struct{}
+
+func (hostStage) Name() string { return "Hosts" }
+func (hostStage) Run(ctx context.Context, state *fullrecon.State) error {
+	state.Hosts = append(state.Hosts, results.Host{Address: "127.0.0.1"})
+	return nil
+}
+
+// newTestServer cria um Server cujos jobs executam as etapas de stages.
+func newTestServer(t *testing.T, queue int, stages func() []fullrecon.Stage) *Server {
+	t.Helper()
+	s, err := NewServer(ServerParams{Token: "token", Workers: 1, QueueSize: queue,
+		Build: func(string, JobRequest) ([]fullrecon.Stage, error) { return stages(), nil }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// submit enfileira um job e retorna o seu ID.
+func submit(t *testing.T, h http.Handler) string {
+	t.Helper()
+	var summary JobSummary
+	if code := request(t, h, http.MethodPost, "/api/jobs", `{"targets":["127.0.0.1"]}`, &summary); code != http.StatusAccepted {
+		t.Fatalf("submit: %d", code)
+	}
+	return summary.ID
+}
+
+func TestAuth(t *testing.T) {
+	h := newTestServer(t, 1, func() []fullrecon.Stage { return nil }).Handler()
+	for name, header := range map[string]string{"missing": "", "bad": "Bearer wrong", "scheme": "Basic token"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s token: %d %q", name, rec.Code, rec.Header().Get("WWW-Authenticate"))
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("health without token: %d", rec.Code)
+	}
+}
+
+func TestSubmitQueueFull(t *testing.T) {
+	s := newTestServer(t, 1, func() []fullrecon.Stage { return []fullrecon.Stage{noopStage{}} })
+	h := s.Handler()
+	submit(t, h)
+	var body map[string]string
+	if code := request(t, h, http.MethodPost, "/api/jobs", `{"targets":["127.0.0.1"]}`, &body); code != http.StatusServiceUnavailable {
+		t.Errorf("submit with the queue full: %d %v", code, body)
+	}
+	var list []JobSummary
+	request(t, h, http.MethodGet, "/api/jobs", "", &list)
+	if len(list) != 1 {
+		t.Errorf("%d jobs listed, want only the queued one", len(list))
+	}
+	if code := request(t, h, http.MethodPost, "/api/jobs", `{"targets":["not a target"]}`, nil); code != http.StatusBadRequest {
+		t.Errorf("invalid target: %d", code)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	started := make(chan struct{})
+	s := newTestServer(t, 2, func() []fullrecon.Stage { return []fullrecon.Stage{blockingStage{started: started}} })
+	h := s.Handler()
+	running, queued := submit(t, h), submit(t, h)
+
+	// O job na fila é cancelado sem executar.
+	var summary JobSummary
+	if code := request(t, h, http.MethodDelete, "/api/jobs/"+queued, "", &summary); code != http.StatusAccepted || summary.Status != StatusCanceled {
+		t.Fatalf("cancel queued: %d %s", code, summary.Status)
+	}
+
+	// O job em execução é interrompido no meio da etapa.
+	done := make(chan struct{})
+	go func() {
+		s.run(context.Background(), <-s.queue)
+		close(done)
+	}()
+	<-started
+	if code := request(t, h, http.MethodDelete, "/api/jobs/"+running, "", nil); code != http.StatusAccepted {
+		t.Fatalf("cancel running: %d", code)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the running job did not stop after cancel")
+	}
+	request(t, h, http.MethodGet, "/api/jobs/"+running, "", &summary)
+	if summary.Status != StatusCanceled {
+		t.Errorf("running job after cancel: %s", summary.Status)
+	}
+
+	// O job da fila cancelado não é executado pelo worker.
+	s.run(context.Background(), <-s.queue)
+	var canceled JobSummary
+	request(t, h, http.MethodGet, "/api/jobs/"+queued, "", &canceled)
+	if canceled.Status != StatusCanceled || canceled.Started != nil {
+		t.Errorf("canceled queued job: %s, started %v", canceled.Status, canceled.Started)
+	}
+
+	var body map[string]string
+	if code := request(t, h, http.MethodDelete, "/api/jobs/"+running, "", &body); code != http.StatusConflict || body["error"] != "job already canceled" {
+		t.Errorf("cancel a finished job: %d %v", code, body)
+	}
+	if code := request(t, h, http.MethodDelete, "/api/jobs/unknown", "", nil); code != http.StatusNotFound {
+		t.Errorf("cancel an unknown job: %d", code)
+	}
+}
+
+func TestEventsReplay(t *testing.T) {
+	s := newTestServer(t, 1, func() []fullrecon.Stage { return []fullrecon.Stage{noopStage{}} })
+	h := s.Handler()
+	id := submit(t, h)
+	s.run(context.Background(), <-s.queue)
+
+	stream := func(lastID string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/api/jobs/"+id+"/events", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("events: %d %q", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		var ids []string
+		for _, line := range strings.Split(rec.Body.String(), "\n") {
+			if v, ok := strings.CutPrefix(line, "id: "); ok {
+				ids = append(ids, v)
+			}
+		}
+		return ids
+	}
+
+	// queued, running, stage_started, stage_finished... e done: o stream termina com o job.
+	all := stream("")
+	if len(all) < 3 || all[0] != "1" {
+		t.Fatalf("full stream ids: %v", all)
+	}
+	replay := stream("2")
+	if len(replay) != len(all)-2 || replay[0] != "3" || replay[len(replay)-1] != all[len(all)-1] {
+		t.Errorf("replay from 2: %v, full stream %v", replay, all)
+	}
+	if ids := stream(all[len(all)-1]); len(ids) != 0 {
+		t.Errorf("replay from the last event: %v", ids)
+	}
+	if ids := stream("garbage"); len(ids) != len(all) {
+		t.Errorf("invalid Last-Event-ID: %v", ids)
+	}
+}
+
+func TestResults(t *testing.T) {
+	s := newTestServer(t, 1, func() []fullrecon.Stage { return []fullrecon.Stage{hostStage{}} })
+	h := s.Handler()
+	id := submit(t, h)
+
+	var body struct {
+		Status   string         `json:"status"`
+		Hosts    []results.Host `json:"hosts"`
+		Findings []any          `json:"findings"`
+	}
+	if code := request(t, h, http.MethodGet, "/api/jobs/"+id+"/results", "", &body); code != http.StatusOK {
+		t.Fatalf("results: %d", code)
+	}
+	if body.Status != StatusQueued || body.Hosts == nil || len(body.Hosts) != 0 || body.Findings == nil {
+		t.Errorf("results before the job ends: %+v", body)
+	}
+
+	s.run(context.Background(), <-s.queue)
+	request(t, h, http.MethodGet, "/api/jobs/"+id+"/results", "", &body)
+	if body.Status != StatusDone || len(body.Hosts) != 1 || body.Hosts[0].Address != "127.0.0.1" {
+		t.Errorf("results after the job ends: %+v", body)
+	}
+}
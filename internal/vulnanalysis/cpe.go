@@ -0,0 +1,124 @@
+package vulnanalysis
+
+import (
+	"strings"
+)
+
+// CPE contém os campos de um CPE relevantes para o matching.
+type CPE struct {
+	Part    string `json:"part"` // a (aplicação), o (sistema operacional), h (hardware)
+	Vendor  string `json:"vendor"`
+	Product string `json:"product"`
+	Version string `json:"version,omitempty"` // "" ou "*" = qualquer versão; "-" = não se aplica (NA)
+	Update  string `json:"update,omitempty"`
+}
+
+// ParseCPE interpreta um CPE no formato URI 2.2 ("cpe:/a:openbsd:openssh:7.4")
+// ou formatado 2.3 ("cpe:2.3:a:openbsd:openssh:7.4:*:*:*:*:*:*:*").
+func ParseCPE(raw string) (CPE, bool) {
+	raw = strings.TrimSpace(strings.ToLower(raw))
+	var fields []string
+	switch {
+	case strings.HasPrefix(raw, "cpe:2.3:"):
+		fields = splitCPE23(raw[len("cpe:2.3:"):])
+	case strings.HasPrefix(raw, "cpe:/"):
+		fields = strings.Split(raw[len("cpe:/"):], ":")
+	default:
+		return CPE{}, false
+	}
+	if len(fields) < 3 || fields[1] == "" || fields[2] == "" {
+		return CPE{}, false
+	}
+	cpe := CPE{Part: fields[0], Vendor: unescapeCPE(fields[1]), Product: unescapeCPE(fields[2])}
+	if len(fields) > 3 {
+		if version := unescapeCPE(fields[3]); version == cpeNA {
+			cpe.Version = cpeNA
+		} else {
+			cpe.Version = normalizeAny(version)
+		}
+	}
+	if len(fields) > 4 {
+		cpe.Update = normalizeAny(unescapeCPE(fields[4]))
+	}
+	return cpe, true
+}
+
+// Key retorna a chave usada no índice (vendor:product).
+func (c CPE) Key() string {
+	return c.Vendor + ":" + c.Product
+}
+
+// NA indica se o CPE declara que o produto não tem versão ("-").
+func (c CPE) NA() bool {
+	return c.Version == cpeNA
+}
+
+// FullVersion junta versão e update (ex.: "7.4" + "p1" = "7.4p1"). Um CPE NA não tem versão.
+func (c CPE) FullVersion() string {
+	if c.NA() {
+		return ""
+	}
+	if c.Update == "" {
+		return c.Version
+	}
+	return c.Version + c.Update
+}
+
+// splitCPE23 separa os campos de um CPE 2.3, respeitando ":" escapado com "\".
+func splitCPE23(s string) []string {
+	var fields []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			fields = append(fields, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(fields, current.String())
+}
+
+// unescapeCPE remove os escapes de um campo de CPE 2.3 e a codificação de URI do 2.2.
+func unescapeCPE(s string) string {
+	s = strings.ReplaceAll(s, "%21", "!")
+	s = strings.ReplaceAll(s, "%2f", "/")
+	s = strings.ReplaceAll(s, "%7e", "~")
+	var out strings.Builder
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// cpeNA é o valor lógico NA ("não se aplica") do CPE, distinto de ANY ("*").
+const cpeNA = "-"
+
+// normalizeAny converte os valores "qualquer" (* e -) em string vazia. Na versão, o NA é
+// preservado por ParseCPE; no update, "-" (sem update) equivale a não informar.
+func normalizeAny(s string) string {
+	if s == "*" || s == "-" {
+		return ""
+	}
+	return s
+}
+
+// productKey normaliza um nome de produto do Nmap para comparação com o campo product do CPE.
+// Ex.: "Apache httpd" -> "apache_httpd", "OpenSSH" -> "openssh".
+func productKey(product string) string {
+	return strings.Join(strings.Fields(strings.ToLower(product)), "_")
+}
@@ -0,0 +1,343 @@
+package vulnanalysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+)
+
+// FeedFormat identifica o formato compacto gerado por Import.
+const FeedFormat = "arthxrecon-cve-1"
+
+// Feed é a base local de vulnerabilidades, no formato compacto.
+type Feed struct {
+	Format  string    `json:"format"`
+	Updated time.Time `json:"updated"`
+	Sources []string  `json:"sources,omitempty"` // Arquivos de origem da última importação
+	Entries []Entry   `json:"entries"`
+}
+
+// Entry é uma vulnerabilidade e as faixas de CPE/versão afetadas.
+type Entry struct {
+	ID        string     `json:"id"`
+	Summary   string     `json:"summary,omitempty"`
+	CVSS      float64    `json:"cvss"`
+	Severity  string     `json:"severity"`
+	Published string     `json:"published,omitempty"`
+	Modified  string     `json:"modified,omitempty"` // Última alteração no NVD, usada para desempatar entradas repetidas
+	Affected  []Affected `json:"affected"`
+}
+
+// Affected descreve um CPE vulnerável e, opcionalmente, a faixa de versões afetadas.
+// Sem limites, vale a versão do próprio CPE; com CPE "*" e sem limites, todas as versões.
+type Affected struct {
+	CPE                   string `json:"cpe"`
+	VersionStartIncluding string `json:"version_start_including,omitempty"`
+	VersionStartExcluding string `json:"version_start_excluding,omitempty"`
+	VersionEndIncluding   string `json:"version_end_including,omitempty"`
+	VersionEndExcluding   string `json:"version_end_excluding,omitempty"`
+}
+
+// LoadFeed lê a base local. Aceita o formato compacto e também os JSON do NVD (1.1 e 2.0).
+func LoadFeed(path string) (*Feed, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vulnerability feed: %w", err)
+	}
+	feed, err := decodeFeed(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode vulnerability feed %s: %w", path, err)
+	}
+	return feed, nil
+}
+
+// SaveFeed grava a base local no formato compacto.
+func SaveFeed(path string, feed *Feed) error {
+	data, err := json.Marshal(feed)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vulnerability feed: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write vulnerability feed: %w", err)
+	}
+	return nil
+}
+
+// decodeFeed identifica o formato do documento pelas chaves de topo e o converte para o formato compacto.
+func decodeFeed(data []byte) (*Feed, error) {
+	var probe struct {
+		Format          string          `json:"format"`
+		CVEItems        json.RawMessage `json:"CVE_Items"`
+		Vulnerabilities json.RawMessage `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+	switch {
+	case probe.Format == FeedFormat:
+		var feed Feed
+		if err := json.Unmarshal(data, &feed); err != nil {
+			return nil, err
+		}
+		return &feed, nil
+	case probe.CVEItems != nil:
+		return decodeNVD11(data)
+	case probe.Vulnerabilities != nil:
+		return decodeNVD20(data)
+	}
+	return nil, errors.New("unknown feed format (expected arthxrecon compact, NVD 1.1 or NVD 2.0 JSON)")
+}
+
+// nvd11Node é um nó de configuração do NVD 1.1.
+type nvd11Node struct {
+	Children []nvd11Node `json:"children"`
+	CPEMatch []nvdMatch  `json:"cpe_match"`
+}
+
+// nvdMatch é um critério de CPE vulnerável (mesmos campos no 1.1 e no 2.0, exceto o CPE).
+type nvdMatch struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	CPE23URI              string `json:"cpe23Uri"` // NVD 1.1
+	Criteria              string `json:"criteria"` // NVD 2.0
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+// affected converte o critério para o formato compacto.
+func (m nvdMatch) affected() Affected {
+	cpe := m.Criteria
+	if cpe == "" {
+		cpe = m.CPE23URI
+	}
+	return Affected{
+		CPE:                   cpe,
+		VersionStartIncluding: m.VersionStartIncluding,
+		VersionStartExcluding: m.VersionStartExcluding,
+		VersionEndIncluding:   m.VersionEndIncluding,
+		VersionEndExcluding:   m.VersionEndExcluding,
+	}
+}
+
+// collectMatches percorre recursivamente os nós do NVD 1.1 e retorna os critérios vulneráveis.
+// Os operadores AND/OR não são avaliados: cada CPE vulnerável é tratado de forma independente.
+func collectMatches(nodes []nvd11Node) []Affected {
+	var out []Affected
+	for _, node := range nodes {
+		for _, m := range node.CPEMatch {
+			if m.Vulnerable {
+				out = append(out, m.affected())
+			}
+		}
+		out = append(out, collectMatches(node.Children)...)
+	}
+	return out
+}
+
+// decodeNVD11 converte o feed JSON 1.1 do NVD (nvdcve-1.1-*.json).
+func decodeNVD11(data []byte) (*Feed, error) {
+	var doc struct {
+		CVEItems []struct {
+			CVE struct {
+				Meta struct {
+					ID string `json:"ID"`
+				} `json:"CVE_data_meta"`
+				Description struct {
+					Data []struct {
+						Lang  string `json:"lang"`
+						Value string `json:"value"`
+					} `json:"description_data"`
+				} `json:"description"`
+			} `json:"cve"`
+			Configurations struct {
+				Nodes []nvd11Node `json:"nodes"`
+			} `json:"configurations"`
+			Impact struct {
+				V3 struct {
+					CVSS struct {
+						BaseScore    float64 `json:"baseScore"`
+						BaseSeverity string  `json:"baseSeverity"`
+					} `json:"cvssV3"`
+				} `json:"baseMetricV3"`
+				V2 struct {
+					CVSS struct {
+						BaseScore float64 `json:"baseScore"`
+					} `json:"cvssV2"`
+					Severity string `json:"severity"`
+				} `json:"baseMetricV2"`
+			} `json:"impact"`
+			Published string `json:"publishedDate"`
+			Modified  string `json:"lastModifiedDate"`
+		} `json:"CVE_Items"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{Format: FeedFormat}
+	for _, item := range doc.CVEItems {
+		entry := Entry{ID: item.CVE.Meta.ID, Published: item.Published, Modified: item.Modified}
+		for _, d := range item.CVE.Description.Data {
+			if d.Lang == "en" {
+				entry.Summary = d.Value
+				break
+			}
+		}
+		switch {
+		case item.Impact.V3.CVSS.BaseScore > 0:
+			entry.CVSS = item.Impact.V3.CVSS.BaseScore
+			entry.Severity = strings.ToLower(item.Impact.V3.CVSS.BaseSeverity)
+		case item.Impact.V2.CVSS.BaseScore > 0:
+			entry.CVSS = item.Impact.V2.CVSS.BaseScore
+			entry.Severity = strings.ToLower(item.Impact.V2.Severity)
+		}
+		if entry.Severity == "" {
+			entry.Severity = findings.SeverityFromCVSS(entry.CVSS)
+		}
+		entry.Affected = collectMatches(item.Configurations.Nodes)
+		if entry.ID != "" && len(entry.Affected) > 0 {
+			feed.Entries = append(feed.Entries, entry)
+		}
+	}
+	return feed, nil
+}
+
+// nvdMetric é uma métrica CVSS do NVD 2.0 (v2, v3.0, v3.1 ou v4.0).
+type nvdMetric struct {
+	Type     string `json:"type"` // Primary ou Secondary
+	CVSSData struct {
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+	BaseSeverity string `json:"baseSeverity"` // v2 traz a severidade fora de cvssData
+}
+
+// decodeNVD20 converte o formato JSON 2.0 do NVD (API e feeds nvdcve-2.0-*.json).
+func decodeNVD20(data []byte) (*Feed, error) {
+	var doc struct {
+		Vulnerabilities []struct {
+			CVE struct {
+				ID           string `json:"id"`
+				Published    string `json:"published"`
+				Modified     string `json:"lastModified"`
+				Descriptions []struct {
+					Lang  string `json:"lang"`
+					Value string `json:"value"`
+				} `json:"descriptions"`
+				Metrics struct {
+					V40 []nvdMetric `json:"cvssMetricV40"`
+					V31 []nvdMetric `json:"cvssMetricV31"`
+					V30 []nvdMetric `json:"cvssMetricV30"`
+					V2  []nvdMetric `json:"cvssMetricV2"`
+				} `json:"metrics"`
+				Configurations []struct {
+					Nodes []struct {
+						CPEMatch []nvdMatch `json:"cpeMatch"`
+					} `json:"nodes"`
+				} `json:"configurations"`
+			} `json:"cve"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{Format: FeedFormat}
+	for _, v := range doc.Vulnerabilities {
+		entry := Entry{ID: v.CVE.ID, Published: v.CVE.Published, Modified: v.CVE.Modified}
+		for _, d := range v.CVE.Descriptions {
+			if d.Lang == "en" {
+				entry.Summary = d.Value
+				break
+			}
+		}
+		// Usa a métrica mais recente disponível, preferindo a primária (NVD).
+		for _, metrics := range [][]nvdMetric{v.CVE.Metrics.V31, v.CVE.Metrics.V30, v.CVE.Metrics.V40, v.CVE.Metrics.V2} {
+			if m, ok := primaryMetric(metrics); ok {
+				entry.CVSS = m.CVSSData.BaseScore
+				entry.Severity = strings.ToLower(m.CVSSData.BaseSeverity)
+				if entry.Severity == "" {
+					entry.Severity = strings.ToLower(m.BaseSeverity)
+				}
+				break
+			}
+		}
+		if entry.Severity == "" {
+			entry.Severity = findings.SeverityFromCVSS(entry.CVSS)
+		}
+		for _, config := range v.CVE.Configurations {
+			for _, node := range config.Nodes {
+				for _, m := range node.CPEMatch {
+					if m.Vulnerable {
+						entry.Affected = append(entry.Affected, m.affected())
+					}
+				}
+			}
+		}
+		if entry.ID != "" && len(entry.Affected) > 0 {
+			feed.Entries = append(feed.Entries, entry)
+		}
+	}
+	return feed, nil
+}
+
+// primaryMetric escolhe a métrica primária da lista ou, na falta dela, a primeira.
+func primaryMetric(metrics []nvdMetric) (nvdMetric, bool) {
+	if len(metrics) == 0 {
+		return nvdMetric{}, false
+	}
+	for _, m := range metrics {
+		if m.Type == "Primary" {
+			return m, true
+		}
+	}
+	return metrics[0], true
+}
+
+// mergeFeeds une vários feeds. Entre entradas com o mesmo ID vence a alterada por último no NVD;
+// sem data comparável, vence a do feed posterior na lista.
+func mergeFeeds(feeds ...*Feed) *Feed {
+	byID := make(map[string]Entry)
+	for _, f := range feeds {
+		for _, e := range f.Entries {
+			if current, ok := byID[e.ID]; ok && modifiedAfter(current, e) {
+				continue
+			}
+			byID[e.ID] = e
+		}
+	}
+	merged := &Feed{Format: FeedFormat}
+	for _, e := range byID {
+		merged.Entries = append(merged.Entries, e)
+	}
+	sort.Slice(merged.Entries, func(i, j int) bool { return merged.Entries[i].ID < merged.Entries[j].ID })
+	return merged
+}
+
+// nvdTimeLayouts são os formatos de data dos feeds 1.1 ("2019-10-09T23:00Z") e 2.0
+// ("2023-11-07T03:26:06.187").
+var nvdTimeLayouts = []string{"2006-01-02T15:04Z07:00", "2006-01-02T15:04:05.999999999", time.RFC3339Nano}
+
+// modifiedAfter indica se a entrada a foi alterada depois de b.
+func modifiedAfter(a, b Entry) bool {
+	ta, okA := parseNVDTime(a.Modified)
+	tb, okB := parseNVDTime(b.Modified)
+	return okA && okB && ta.After(tb)
+}
+
+// parseNVDTime interpreta uma data do NVD (sem fuso, UTC).
+func parseNVDTime(s string) (time.Time, bool) {
+	for _, layout := range nvdTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,167 @@
+package vulnanalysis
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxFeedFile limita o tamanho de cada JSON extraído de um arquivo compactado.
+const maxFeedFile = 2 << 30
+
+// Import lê os arquivos baixados (JSON, .json.gz, .zip ou .tar.gz com feeds do NVD ou no
+// formato compacto), une as entradas e substitui a base local em feedPath.
+// Com merge, as entradas da base atual são mantidas e atualizadas pelas importadas.
+func Import(feedPath string, archives []string, merge bool) (*Feed, error) {
+	var feeds []*Feed
+	if merge {
+		if current, err := LoadFeed(feedPath); err == nil {
+			feeds = append(feeds, current)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	for _, archive := range archives {
+		docs, err := readArchive(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", archive, err)
+		}
+		if len(docs) == 0 {
+			return nil, fmt.Errorf("no JSON feed found in %s", archive)
+		}
+		// Ordem estável entre execuções: em IDs repetidos com a mesma data, vence o último nome.
+		names := make([]string, 0, len(docs))
+		for name := range docs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			feed, err := decodeFeed(docs[name])
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+			}
+			feeds = append(feeds, feed)
+		}
+	}
+
+	merged := mergeFeeds(feeds...)
+	merged.Updated = time.Now().UTC()
+	for _, archive := range archives {
+		merged.Sources = append(merged.Sources, filepath.Base(archive))
+	}
+	if dir := filepath.Dir(feedPath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create feed directory: %w", err)
+		}
+	}
+	// Grava em arquivo temporário e renomeia, para não corromper a base em caso de falha.
+	tmp := feedPath + ".tmp"
+	if err := SaveFeed(tmp, merged); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, feedPath); err != nil {
+		return nil, fmt.Errorf("failed to replace vulnerability feed: %w", err)
+	}
+	return merged, nil
+}
+
+// readArchive extrai os documentos JSON de um arquivo, indexados pelo nome interno.
+func readArchive(path string) (map[string][]byte, error) {
+	lower := strings.ToLower(path)
+	docs := make(map[string][]byte)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || !isFeedName(f.Name) {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := readFeedFile(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			docs[f.Name] = data
+		}
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if hdr.Typeflag != tar.TypeReg || !isFeedName(hdr.Name) {
+				continue
+			}
+			data, err := readFeedFile(hdr.Name, tr)
+			if err != nil {
+				return nil, err
+			}
+			docs[hdr.Name] = data
+		}
+	default:
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		data, err := readFeedFile(path, file)
+		if err != nil {
+			return nil, err
+		}
+		docs[filepath.Base(path)] = data
+	}
+	return docs, nil
+}
+
+// isFeedName indica se o arquivo interno é um JSON (opcionalmente gzip).
+func isFeedName(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".json") || strings.HasSuffix(lower, ".json.gz")
+}
+
+// readFeedFile lê o conteúdo, descompactando gzip quando necessário.
+func readFeedFile(name string, r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxFeedFile))
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(strings.ToLower(name), ".gz") || bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return io.ReadAll(io.LimitReader(gz, maxFeedFile))
+	}
+	return data, nil
+}
@@ -0,0 +1,125 @@
+package vulnanalysis
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// versionPart é um segmento de versão: numérico ou alfabético.
+type versionPart struct {
+	num     int
+	text    string
+	numeric bool
+}
+
+// splitVersion divide uma versão em segmentos numéricos e alfabéticos.
+// Ex.: "7.4p1" -> [7 4 p 1], "2.4.49-beta" -> [2 4 49 beta].
+func splitVersion(version string) []versionPart {
+	var parts []versionPart
+	var current strings.Builder
+	numeric := false
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		part := versionPart{text: current.String(), numeric: numeric}
+		if numeric {
+			part.num, _ = strconv.Atoi(part.text)
+		}
+		parts = append(parts, part)
+		current.Reset()
+	}
+	for _, r := range strings.ToLower(version) {
+		switch {
+		case unicode.IsDigit(r):
+			if !numeric {
+				flush()
+			}
+			numeric = true
+			current.WriteRune(r)
+		case unicode.IsLetter(r):
+			if numeric {
+				flush()
+			}
+			numeric = false
+			current.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return parts
+}
+
+// CompareVersions compara duas versões segmento a segmento e retorna -1, 0 ou 1.
+// Segmentos numéricos são comparados como inteiros e alfabéticos em ordem lexical.
+// Um sufixo alfabético logo após o fim da outra versão indica pré-release
+// ("1.0rc1" < "1.0"), exceto os patch levels do OpenSSH ("7.4p1" > "7.4") e as
+// letras de release do OpenSSL ("1.0.2k" > "1.0.2").
+func CompareVersions(a, b string) int {
+	pa, pb := splitVersion(a), splitVersion(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		if i >= len(pa) || i >= len(pb) {
+			var s int
+			if i >= len(pa) {
+				s = -tailSign(pb, i)
+			} else {
+				s = tailSign(pa, i)
+			}
+			if s != 0 {
+				return s
+			}
+			continue
+		}
+		x, y := pa[i], pb[i]
+		switch {
+		case x.numeric && y.numeric:
+			if x.num != y.num {
+				return sign(x.num - y.num)
+			}
+		case x.numeric != y.numeric:
+			// Número vence texto: "1.0.1" > "1.0rc1".
+			if x.numeric {
+				return 1
+			}
+			return -1
+		default:
+			if c := strings.Compare(x.text, y.text); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// tailSign indica se o segmento excedente parts[i] torna a versão maior (1) ou menor (-1).
+func tailSign(parts []versionPart, i int) int {
+	part := parts[i]
+	if part.numeric {
+		if part.num == 0 {
+			return 0
+		}
+		return 1
+	}
+	switch part.text {
+	case "p", "patch", "pl", "sp", "update", "u", "r":
+		return 1
+	}
+	// Letra final após um número é release do OpenSSL ("1.0.2k", e "1.0.2za" depois do "z").
+	last := i == len(parts)-1 && i > 0 && parts[i-1].numeric
+	if last && (len(part.text) == 1 || len(part.text) == 2 && part.text[0] == 'z') {
+		return 1
+	}
+	return -1
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,232 @@
+package vulnanalysis
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Arthx-x/arthxrecon/internal/findings"
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+// ModuleName é o nome do módulo usado em logs e nos resultados.
+const ModuleName = "vulnanalysis"
+
+// Match é uma vulnerabilidade associada a um serviço.
+type Match struct {
+	CVE      string  `json:"cve"`
+	CVSS     float64 `json:"cvss"`
+	Severity string  `json:"severity"`
+	Summary  string  `json:"summary,omitempty"`
+	Address  string  `json:"address"`
+	Port     int     `json:"port"`
+	Protocol string  `json:"protocol"`
+	Product  string  `json:"product,omitempty"`
+	Version  string  `json:"version"`
+	CPE      string  `json:"cpe,omitempty"` // CPE do serviço usado no matching
+	Rule     string  `json:"rule"`          // Critério do feed que casou (CPE e faixa de versões)
+	Method   string  `json:"method"`        // cpe ou product
+}
+
+// Finding converte a vulnerabilidade em um achado.
+func (m Match) Finding() findings.Finding {
+	title := m.CVE
+	if m.Product != "" {
+		title = fmt.Sprintf("%s in %s %s", m.CVE, m.Product, m.Version)
+	}
+	return findings.New(ModuleName, findings.Slug(m.CVE), title, m.Severity, m.Address, m.Port, m.Protocol).
+		WithEvidence(m.Rule).
+		WithDetails("cve", m.CVE, "cvss", strconv.FormatFloat(m.CVSS, 'f', 1, 64), "cpe", m.CPE, "summary", m.Summary, "method", m.Method)
+}
+
+// rule é uma entrada do índice: um critério de CPE afetado de uma vulnerabilidade.
+type rule struct {
+	entry    *Entry
+	affected Affected
+	cpe      CPE
+}
+
+// Index organiza o feed por vendor:product e por product para consultas rápidas.
+type Index struct {
+	byKey     map[string][]rule
+	byProduct map[string][]rule
+	entries   int
+}
+
+// NewIndex monta o índice a partir do feed.
+func NewIndex(feed *Feed) *Index {
+	idx := &Index{byKey: make(map[string][]rule), byProduct: make(map[string][]rule)}
+	for i := range feed.Entries {
+		entry := &feed.Entries[i]
+		for _, a := range entry.Affected {
+			cpe, ok := ParseCPE(a.CPE)
+			if !ok {
+				continue
+			}
+			r := rule{entry: entry, affected: a, cpe: cpe}
+			idx.byKey[cpe.Key()] = append(idx.byKey[cpe.Key()], r)
+			idx.byProduct[cpe.Product] = append(idx.byProduct[cpe.Product], r)
+		}
+		idx.entries++
+	}
+	return idx
+}
+
+// Entries retorna a quantidade de vulnerabilidades indexadas.
+func (idx *Index) Entries() int {
+	return idx.entries
+}
+
+// matches verifica se a versão está coberta pelo critério. Um CPE NA ("-") só casa com serviços
+// sem versão, pois o produto não tem versões.
+func (r rule) matches(version string) bool {
+	if r.cpe.NA() {
+		return version == "" || version == cpeNA
+	}
+	a := r.affected
+	hasRange := a.VersionStartIncluding != "" || a.VersionStartExcluding != "" ||
+		a.VersionEndIncluding != "" || a.VersionEndExcluding != ""
+	if !hasRange {
+		if r.cpe.Version == "" {
+			// CPE sem versão e sem faixa: todas as versões afetadas.
+			return true
+		}
+		return CompareVersions(version, r.cpe.FullVersion()) == 0
+	}
+	if a.VersionStartIncluding != "" && CompareVersions(version, a.VersionStartIncluding) < 0 {
+		return false
+	}
+	if a.VersionStartExcluding != "" && CompareVersions(version, a.VersionStartExcluding) <= 0 {
+		return false
+	}
+	if a.VersionEndIncluding != "" && CompareVersions(version, a.VersionEndIncluding) > 0 {
+		return false
+	}
+	if a.VersionEndExcluding != "" && CompareVersions(version, a.VersionEndExcluding) >= 0 {
+		return false
+	}
+	return true
+}
+
+// describe descreve o critério para o relatório (ex.: "cpe:2.3:a:openbsd:openssh:*... <8.5").
+func (r rule) describe() string {
+	var bounds []string
+	if r.affected.VersionStartIncluding != "" {
+		bounds = append(bounds, ">="+r.affected.VersionStartIncluding)
+	}
+	if r.affected.VersionStartExcluding != "" {
+		bounds = append(bounds, ">"+r.affected.VersionStartExcluding)
+	}
+	if r.affected.VersionEndIncluding != "" {
+		bounds = append(bounds, "<="+r.affected.VersionEndIncluding)
+	}
+	if r.affected.VersionEndExcluding != "" {
+		bounds = append(bounds, "<"+r.affected.VersionEndExcluding)
+	}
+	if len(bounds) == 0 {
+		return r.affected.CPE
+	}
+	return r.affected.CPE + " " + strings.Join(bounds, " ")
+}
+
+// candidate é uma forma de identificar o serviço: um CPE ou apenas o nome do produto.
+type candidate struct {
+	cpe     CPE
+	raw     string
+	version string
+	method  string
+}
+
+// candidates monta as identificações do serviço, priorizando os CPEs do -sV.
+func candidates(svc results.Service) []candidate {
+	var out []candidate
+	for _, raw := range svc.CPEs {
+		cpe, ok := ParseCPE(raw)
+		if !ok {
+			continue
+		}
+		version := cpe.FullVersion()
+		if version == "" {
+			version = firstToken(svc.Version)
+		}
+		out = append(out, candidate{cpe: cpe, raw: raw, version: version, method: "cpe"})
+	}
+	if len(out) == 0 && svc.Product != "" {
+		out = append(out, candidate{cpe: CPE{Product: productKey(svc.Product)}, version: firstToken(svc.Version), method: "product"})
+	}
+	return out
+}
+
+// firstToken retorna a primeira palavra da versão reportada pelo Nmap (ex.: "7.4p1 Debian" -> "7.4p1").
+func firstToken(version string) string {
+	fields := strings.Fields(version)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// MatchService retorna as vulnerabilidades que afetam o serviço.
+// Serviços sem versão conhecida só são avaliados contra CPEs NA, para evitar falsos positivos.
+func (idx *Index) MatchService(address string, svc results.Service) []Match {
+	seen := make(map[string]bool)
+	var matches []Match
+	for _, c := range candidates(svc) {
+		rules := idx.byProduct[c.cpe.Product]
+		if c.method == "cpe" {
+			rules = idx.byKey[c.cpe.Key()]
+		}
+		for _, r := range rules {
+			if seen[r.entry.ID] || (c.version == "" && !r.cpe.NA()) || !r.matches(c.version) {
+				continue
+			}
+			seen[r.entry.ID] = true
+			matches = append(matches, Match{
+				CVE:      r.entry.ID,
+				CVSS:     r.entry.CVSS,
+				Severity: r.entry.Severity,
+				Summary:  r.entry.Summary,
+				Address:  address,
+				Port:     svc.Port,
+				Protocol: svc.Protocol,
+				Product:  svc.Product,
+				Version:  c.version,
+				CPE:      c.raw,
+				Rule:     r.describe(),
+				Method:   c.method,
+			})
+		}
+	}
+	return matches
+}
+
+// Run avalia todos os serviços (TCP e UDP) dos hosts, anexa as vulnerabilidades encontradas
+// ao registro de cada serviço e retorna a lista ordenada por CVSS.
+func (idx *Index) Run(hosts []results.Host, minCVSS float64) []Match {
+	var all []Match
+	for i := range hosts {
+		for _, services := range [][]results.Service{hosts[i].Services, hosts[i].UDPServices} {
+			for j := range services {
+				var kept []Match
+				for _, m := range idx.MatchService(hosts[i].Address, services[j]) {
+					if m.CVSS >= minCVSS {
+						kept = append(kept, m)
+					}
+				}
+				if len(kept) == 0 {
+					continue
+				}
+				services[j].SetEnrichment(ModuleName, kept)
+				all = append(all, kept...)
+			}
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].CVSS != all[j].CVSS {
+			return all[i].CVSS > all[j].CVSS
+		}
+		return all[i].CVE < all[j].CVE
+	})
+	return all
+}
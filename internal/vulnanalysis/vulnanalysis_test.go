@@ -0,0 +1,151 @@
+package vulnanalysis
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Arthx-x/arthxrecon/internal/results"
+)
+
+func TestParseCPEKeepsNA(t *testing.T) {
+	tests := []struct {
+		raw     string
+		version string
+		na      bool
+		full    string
+	}{
+		{"cpe:2.3:h:acme:router:-:*:*:*:*:*:*:*", "-", true, ""},
+		{"cpe:2.3:a:acme:app:*:*:*:*:*:*:*:*", "", false, ""},
+		{"cpe:/a:openbsd:openssh:7.4:p1", "7.4", false, "7.4p1"},
+		{"cpe:2.3:a:openbsd:openssh:7.4:-:*:*:*:*:*:*", "7.4", false, "7.4"},
+	}
+	for _, tt := range tests {
+		cpe, ok := ParseCPE(tt.raw)
+		if !ok || cpe.Version != tt.version || cpe.NA() != tt.na || cpe.FullVersion() != tt.full {
+			t.Errorf("ParseCPE(%q) = %+v (NA %v, full %q)", tt.raw, cpe, cpe.NA(), cpe.FullVersion())
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0.0", "1.0", 0},
+		{"2.4.49", "2.4.50", -1},
+		{"10.0", "9.9", 1},
+		{"1.0.1", "1.0rc1", 1},
+		{"1.0rc1", "1.0", -1},
+		{"1.0-beta", "1.0", -1},
+		{"1.0alpha2", "1.0", -1},
+		{"2.0-dev", "2.0", -1},
+		{"3.1pre", "3.1", -1},
+		{"1.0rc1", "1.0rc2", -1},
+		{"7.4p1", "7.4", 1},
+		{"7.4p1", "7.4p2", -1},
+		{"8.0p1", "7.9p1", 1},
+		{"1.0.2k", "1.0.2", 1},
+		{"1.1.1w", "1.1.1", 1},
+		{"1.0.2", "1.0.2k", -1},
+		{"1.0.2k", "1.0.2zg", -1},
+		{"1.0.2zg", "1.0.2", 1},
+		{"1.0.2zh", "1.0.2zg", 1},
+		{"1.1.1", "1.0.2zh", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	// Faixa típica de um CVE do OpenSSL: as letras de release ficam dentro dela.
+	r := rule{affected: Affected{VersionStartIncluding: "1.0.2", VersionEndExcluding: "1.0.2zg"}}
+	for version, want := range map[string]bool{"1.0.2": true, "1.0.2k": true, "1.0.2zf": true, "1.0.2zg": false, "1.0.1u": false, "1.1.1w": false} {
+		if got := r.matches(version); got != want {
+			t.Errorf("matches(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestMatchServiceNA(t *testing.T) {
+	idx := NewIndex(&Feed{Entries: []Entry{
+		{ID: "CVE-NA", Affected: []Affected{{CPE: "cpe:2.3:h:acme:router:-:*:*:*:*:*:*:*"}}},
+		{ID: "CVE-ANY", Affected: []Affected{{CPE: "cpe:2.3:h:acme:router:*:*:*:*:*:*:*:*"}}},
+	}})
+	tests := []struct {
+		name string
+		svc  results.Service
+		want []string
+	}{
+		{"na cpe", results.Service{Port: 80, CPEs: []string{"cpe:/h:acme:router:-"}}, []string{"CVE-NA"}},
+		{"no version", results.Service{Port: 80, CPEs: []string{"cpe:/h:acme:router"}}, []string{"CVE-NA"}},
+		{"versioned", results.Service{Port: 80, CPEs: []string{"cpe:/h:acme:router:2.1"}}, []string{"CVE-ANY"}},
+		{"version from nmap", results.Service{Port: 80, Version: "2.1", CPEs: []string{"cpe:/h:acme:router:-"}}, []string{"CVE-ANY"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, m := range idx.MatchService("10.0.0.1", tt.svc) {
+				got = append(got, m.CVE)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("MatchService = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeFeedsPrefersLastModified(t *testing.T) {
+	older := &Feed{Entries: []Entry{{ID: "CVE-1", Summary: "old", Modified: "2023-11-07T03:26:06.187"}}}
+	newer := &Feed{Entries: []Entry{{ID: "CVE-1", Summary: "new", Modified: "2024-01-02T10:00Z"}}}
+	undated := &Feed{Entries: []Entry{{ID: "CVE-1", Summary: "undated"}}}
+
+	for name, feeds := range map[string][]*Feed{
+		"newer last":  {older, newer},
+		"newer first": {newer, older},
+	} {
+		if got := mergeFeeds(feeds...).Entries; len(got) != 1 || got[0].Summary != "new" {
+			t.Errorf("%s: got %+v", name, got)
+		}
+	}
+	if got := mergeFeeds(newer, undated).Entries; got[0].Summary != "undated" {
+		t.Errorf("without a date the later feed wins, got %q", got[0].Summary)
+	}
+}
+
+func TestImportIsDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "feeds.zip")
+	file, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(file)
+	// Mesma CVE e mesma data em dois documentos: vence sempre o último em ordem de nome.
+	for _, name := range []string{"b.json", "a.json", "c.json"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(`{"format":"` + FeedFormat + `","entries":[{"id":"CVE-1","summary":"` + name +
+			`","modified":"2024-01-01T00:00Z","affected":[{"cpe":"cpe:2.3:a:acme:app:1.0"}]}]}`))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	for i := 0; i < 10; i++ {
+		feed, err := Import(filepath.Join(dir, "feed.json"), []string{archive}, false)
+		if err != nil {
+			t.Fatalf("Import: %v", err)
+		}
+		if len(feed.Entries) != 1 || feed.Entries[0].Summary != "c.json" {
+			t.Fatalf("run %d: got %+v", i, feed.Entries)
+		}
+	}
+}
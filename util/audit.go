@@ -0,0 +1,443 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Events recorded in the audit log.
+const (
+	AuditSession    = "session"     // First entry of a run that generated traffic: arthxrecon argv
+	AuditCommand    = "command"     // External process about to start: full argv
+	AuditCommandEnd = "command_end" // External process finished: exit code and duration
+	AuditProbe      = "probe"       // Native probe batch about to start: targets, ports and rate
+	AuditProbeEnd   = "probe_end"   // Native probe batch finished: duration
+)
+
+// AuditEntry is one line of the audit log. Every entry carries the run, operator, workspace and
+// scope hash, so it can be read on its own; Prev chains it to the previous line of the file.
+type AuditEntry struct {
+	Time        time.Time `json:"time"`
+	Event       string    `json:"event"`
+	RunID       string    `json:"run_id"`
+	Operator    string    `json:"operator"`
+	Workspace   string    `json:"workspace"`
+	ScopeFile   string    `json:"scope_file,omitempty"`
+	ScopeHash   string    `json:"scope_sha256,omitempty"`
+	Argv        []string  `json:"argv,omitempty"`        // session and command entries
+	Module      string    `json:"module,omitempty"`      // probe entries
+	Targets     []string  `json:"targets,omitempty"`     // probe entries
+	TCPPorts    string    `json:"tcp_ports,omitempty"`   // probe entries, ranges collapsed
+	UDPPorts    string    `json:"udp_ports,omitempty"`   // probe entries, ranges collapsed
+	Concurrency int       `json:"concurrency,omitempty"` // Simultaneous connections of a probe batch
+	Rate        int       `json:"rate,omitempty"`        // Packets or connections per second (0 = unlimited)
+	ExitCode    *int      `json:"exit_code,omitempty"`   // command_end entries
+	Duration    string    `json:"duration,omitempty"`    // command_end and probe_end entries
+	Error       string    `json:"error,omitempty"`
+	Prev        string    `json:"prev"` // SHA-256 of the previous line ("" for the first one)
+}
+
+// Auditor appends entries to the audit log. The file is only opened for appending, never
+// rotated or rewritten, and is created on the first entry, so runs without traffic leave no trace.
+type Auditor struct {
+	Path      string
+	Operator  string
+	Workspace string
+	ScopeFile string
+	ScopeHash string
+
+	mu      sync.Mutex
+	file    *os.File
+	prev    string
+	session bool
+}
+
+// Audit is the audit log of the current run; nil when audit.enabled is false.
+var Audit *Auditor
+
+// NewAuditor creates the auditor described by the [audit] settings, filling in the operator
+// (current user) and the workspace (working directory) when they are not set, and hashing the scope file.
+func NewAuditor(cfg AuditConfig) (*Auditor, error) {
+	a := &Auditor{Path: cfg.File, Operator: cfg.Operator, Workspace: cfg.Workspace, ScopeFile: cfg.ScopeFile}
+	if a.Operator == "" {
+		if u, err := user.Current(); err == nil {
+			a.Operator = u.Username
+		} else {
+			a.Operator = os.Getenv("USER")
+		}
+	}
+	if a.Workspace == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("audit workspace: %w", err)
+		}
+		a.Workspace = dir
+	}
+	if a.ScopeFile != "" {
+		data, err := os.ReadFile(a.ScopeFile)
+		if err != nil {
+			return nil, fmt.Errorf("audit scope file: %w", err)
+		}
+		sum := sha256.Sum256(data)
+		a.ScopeHash = hex.EncodeToString(sum[:])
+	}
+	return a, nil
+}
+
+// InitializeAudit sets Audit from the configuration loaded by LoadConfig.
+func InitializeAudit(cfg *Config) error {
+	Audit = nil
+	if !cfg.Audit.Enabled {
+		return nil
+	}
+	a, err := NewAuditor(cfg.Audit)
+	if err != nil {
+		return err
+	}
+	Audit = a
+	return nil
+}
+
+// Record fills in the common fields of the entry and appends it to the audit log, preceded by
+// the session entry of the run when it is the first one. The file is synced after every entry.
+func (a *Auditor) Record(entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		if err := a.open(); err != nil {
+			return err
+		}
+	}
+	if !a.session {
+		if err := a.write(AuditEntry{Event: AuditSession, Argv: os.Args}); err != nil {
+			return err
+		}
+		a.session = true
+	}
+	return a.write(entry)
+}
+
+// open opens the audit log for appending and reads the hash of its last line.
+func (a *Auditor) open() error {
+	if dir := filepath.Dir(a.Path); dir != "." {
+		if err := EnsureDir(dir); err != nil {
+			return fmt.Errorf("failed to create audit directory %s: %w", dir, err)
+		}
+	}
+	entries, err := ReadAudit(a.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(entries) > 0 {
+		a.prev = entries[len(entries)-1].hash
+	}
+	file, err := os.OpenFile(a.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	a.file = file
+	return nil
+}
+
+// write completes the entry, appends it as one JSON line and advances the hash chain.
+func (a *Auditor) write(entry AuditEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	entry.Time = entry.Time.UTC()
+	entry.RunID, entry.Operator, entry.Workspace = RunID, a.Operator, a.Workspace
+	entry.ScopeFile, entry.ScopeHash = a.ScopeFile, a.ScopeHash
+	entry.Prev = a.prev
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := a.file.Sync(); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	a.prev = lineHash(line)
+	return nil
+}
+
+// AuditProcess records an external process about to start and returns the function that records
+// its end. Without an audit log both are no-ops.
+func AuditProcess(argv []string) (func(exitCode int, elapsed time.Duration, err error), error) {
+	if Audit == nil {
+		return func(int, time.Duration, error) {}, nil
+	}
+	if err := Audit.Record(AuditEntry{Event: AuditCommand, Argv: argv}); err != nil {
+		return nil, err
+	}
+	return func(exitCode int, elapsed time.Duration, err error) {
+		entry := AuditEntry{Event: AuditCommandEnd, Argv: argv, ExitCode: &exitCode, Duration: elapsed.String()}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		if err := Audit.Record(entry); err != nil {
+			log.Error().Err(err).Msg("Failed to record audit entry")
+		}
+	}, nil
+}
+
+// ProbeBatch describes a batch of connections made by a native scanner: the targets, the ports
+// and the rate. The scanners add every target before probing and record the batch with Start.
+type ProbeBatch struct {
+	Module      string
+	Concurrency int
+	Rate        int
+
+	targets  []string
+	seen     map[string]bool
+	tcp, udp map[int]bool
+	started  time.Time
+}
+
+// NewProbeBatch creates an empty batch for a module, limited by the rate of the limiter of ctx.
+func NewProbeBatch(ctx context.Context, module string, concurrency int) *ProbeBatch {
+	return &ProbeBatch{Module: module, Concurrency: concurrency, Rate: LimiterFrom(ctx).Rate, seen: make(map[string]bool), tcp: make(map[int]bool), udp: make(map[int]bool)}
+}
+
+// Add adds a target and, when port is positive, one of its ports ("tcp" or "udp").
+func (b *ProbeBatch) Add(address, protocol string, port int) {
+	if !b.seen[address] {
+		b.seen[address] = true
+		b.targets = append(b.targets, address)
+	}
+	switch {
+	case port <= 0:
+	case protocol == "udp":
+		b.udp[port] = true
+	default:
+		b.tcp[port] = true
+	}
+}
+
+// Len returns the number of targets of the batch.
+func (b *ProbeBatch) Len() int {
+	return len(b.targets)
+}
+
+// Start records the batch before the first probe. Empty batches are not recorded.
+func (b *ProbeBatch) Start() {
+	b.started = time.Now()
+	if Audit == nil || b.Len() == 0 {
+		return
+	}
+	sort.Strings(b.targets)
+	entry := AuditEntry{
+		Event:       AuditProbe,
+		Module:      b.Module,
+		Targets:     b.targets,
+		TCPPorts:    FormatPorts(setToSorted(b.tcp)),
+		UDPPorts:    FormatPorts(setToSorted(b.udp)),
+		Concurrency: b.Concurrency,
+		Rate:        b.Rate,
+	}
+	if err := Audit.Record(entry); err != nil {
+		log.Error().Err(err).Str("module", b.Module).Msg("Failed to record audit entry")
+	}
+}
+
+// Finish records the end of the batch started with Start.
+func (b *ProbeBatch) Finish() {
+	if Audit == nil || b.Len() == 0 {
+		return
+	}
+	entry := AuditEntry{Event: AuditProbeEnd, Module: b.Module, Duration: time.Since(b.started).String()}
+	if err := Audit.Record(entry); err != nil {
+		log.Error().Err(err).Str("module", b.Module).Msg("Failed to record audit entry")
+	}
+}
+
+// AuditRecord is an entry read back from the audit log, with the result of the chain check.
+type AuditRecord struct {
+	AuditEntry
+	Line  int  // Line number in the file
+	Valid bool // Prev matches the hash of the previous line
+
+	hash string
+}
+
+// ReadAudit reads every entry of an audit log and checks the hash chain.
+func ReadAudit(path string) ([]AuditRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		records []AuditRecord
+		prev    string
+		number  int
+	)
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			number++
+			line = bytes.TrimRight(line, "\r\n")
+			var record AuditRecord
+			if jerr := json.Unmarshal(line, &record.AuditEntry); jerr != nil {
+				return records, fmt.Errorf("%s:%d: invalid audit entry: %w", path, number, jerr)
+			}
+			record.Line, record.hash = number, lineHash(line)
+			record.Valid = record.Prev == prev
+			prev = record.hash
+			records = append(records, record)
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+	}
+}
+
+// AuditHead returns the hash of the last entry, which pins the whole chain (e.g., in a report).
+func AuditHead(records []AuditRecord) string {
+	if len(records) == 0 {
+		return ""
+	}
+	return records[len(records)-1].hash
+}
+
+// AuditFormats lists the formats accepted by ExportAudit.
+var AuditFormats = []string{"json", "csv", "markdown"}
+
+// ExportAudit writes the records in one of AuditFormats.
+func ExportAudit(w io.Writer, records []AuditRecord, format string) error {
+	switch format {
+	case "json":
+		entries := make([]AuditEntry, 0, len(records))
+		for _, r := range records {
+			entries = append(entries, r.AuditEntry)
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "csv":
+		out := csv.NewWriter(w)
+		out.Write([]string{"time", "event", "run_id", "operator", "workspace", "scope_sha256", "module", "command", "targets", "tcp_ports", "udp_ports", "concurrency", "rate", "exit_code", "duration", "error", "chain"})
+		for _, r := range records {
+			exitCode := ""
+			if r.ExitCode != nil {
+				exitCode = strconv.Itoa(*r.ExitCode)
+			}
+			out.Write([]string{
+				r.Time.Format(time.RFC3339), r.Event, r.RunID, r.Operator, r.Workspace, r.ScopeHash, r.Module,
+				strings.Join(r.Argv, " "), strings.Join(r.Targets, " "), r.TCPPorts, r.UDPPorts,
+				strconv.Itoa(r.Concurrency), strconv.Itoa(r.Rate), exitCode, r.Duration, r.Error, chainStatus(r),
+			})
+		}
+		out.Flush()
+		return out.Error()
+	case "markdown":
+		return exportAuditMarkdown(w, records)
+	}
+	return fmt.Errorf("unknown audit format %q (expected %s)", format, strings.Join(AuditFormats, ", "))
+}
+
+// exportAuditMarkdown writes the records as a table per run, ready for the final report.
+func exportAuditMarkdown(w io.Writer, records []AuditRecord) error {
+	var b strings.Builder
+	b.WriteString("# Audit trail\n\n")
+	broken := 0
+	for _, r := range records {
+		if !r.Valid {
+			broken++
+		}
+	}
+	fmt.Fprintf(&b, "- Entries: %d\n", len(records))
+	if head := AuditHead(records); head != "" {
+		fmt.Fprintf(&b, "- Chain head (SHA-256): `%s`\n", head)
+	}
+	if broken == 0 {
+		b.WriteString("- Integrity: hash chain verified\n")
+	} else {
+		fmt.Fprintf(&b, "- Integrity: **%d entries do not match the previous line**\n", broken)
+	}
+
+	run := ""
+	for _, r := range records {
+		if r.RunID != run {
+			run = r.RunID
+			fmt.Fprintf(&b, "\n## Run %s\n\n", run)
+			fmt.Fprintf(&b, "- Operator: %s\n- Workspace: %s\n", r.Operator, r.Workspace)
+			if r.ScopeFile != "" {
+				fmt.Fprintf(&b, "- Scope: %s (SHA-256 `%s`)\n", r.ScopeFile, r.ScopeHash)
+			}
+			b.WriteString("\n| Time (UTC) | Event | Action | Result |\n|---|---|---|---|\n")
+		}
+		action, result := "", r.Duration
+		switch r.Event {
+		case AuditSession, AuditCommand, AuditCommandEnd:
+			action = "`" + strings.Join(r.Argv, " ") + "`"
+		case AuditProbe:
+			action = fmt.Sprintf("%s: %s", r.Module, strings.Join(r.Targets, ", "))
+			if r.TCPPorts != "" {
+				action += " tcp/" + r.TCPPorts
+			}
+			if r.UDPPorts != "" {
+				action += " udp/" + r.UDPPorts
+			}
+			result = fmt.Sprintf("concurrency %d", r.Concurrency)
+			if r.Rate > 0 {
+				result += fmt.Sprintf(", %d/s", r.Rate)
+			}
+		case AuditProbeEnd:
+			action = r.Module
+		}
+		if r.ExitCode != nil {
+			result = fmt.Sprintf("exit %d, %s", *r.ExitCode, r.Duration)
+		}
+		if r.Error != "" {
+			result += " (" + r.Error + ")"
+		}
+		if !r.Valid {
+			result += " **chain broken**"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", r.Time.Format("2006-01-02 15:04:05"), r.Event, strings.ReplaceAll(action, "|", "\\|"), strings.ReplaceAll(result, "|", "\\|"))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// chainStatus describes the chain check of a record.
+func chainStatus(r AuditRecord) string {
+	if r.Valid {
+		return "ok"
+	}
+	return "broken"
+}
+
+// lineHash returns the SHA-256 of an audit line, without the line break.
+func lineHash(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,279 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestAuditor returns an auditor writing to a log in a temporary directory.
+func newTestAuditor(t *testing.T, path string) *Auditor {
+	t.Helper()
+	a, err := NewAuditor(AuditConfig{File: path, Operator: "tester", Workspace: "/engagements/acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if a.file != nil {
+			a.file.Close()
+		}
+	})
+	return a
+}
+
+// writeAuditLog records a command with its end and a probe batch, and returns the log lines.
+func writeAuditLog(t *testing.T, path string) [][]byte {
+	t.Helper()
+	a := newTestAuditor(t, path)
+	exitCode := 0
+	for _, entry := range []AuditEntry{
+		{Event: AuditCommand, Argv: []string{"nmap", "-sS", "10.0.0.0/24"}},
+		{Event: AuditCommandEnd, Argv: []string{"nmap", "-sS", "10.0.0.0/24"}, ExitCode: &exitCode, Duration: "1m2s"},
+		{Event: AuditProbe, Module: "banner", Targets: []string{"10.0.0.5"}, TCPPorts: "21-22", Concurrency: 10, Rate: 100},
+	} {
+		if err := a.Record(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.SplitAfter(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+}
+
+// invalidLines returns the line numbers whose chain check failed.
+func invalidLines(records []AuditRecord) []int {
+	var lines []int
+	for _, r := range records {
+		if !r.Valid {
+			lines = append(lines, r.Line)
+		}
+	}
+	return lines
+}
+
+func TestAuditChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	lines := writeAuditLog(t, path)
+	records, err := ReadAudit(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 || records[0].Event != AuditSession || records[0].Prev != "" {
+		t.Fatalf("records: %+v", records)
+	}
+	if broken := invalidLines(records); broken != nil {
+		t.Errorf("clean chain broken at lines %v", broken)
+	}
+	for i, r := range records {
+		if r.Operator != "tester" || r.Workspace != "/engagements/acme" || r.RunID != RunID || r.Line != i+1 {
+			t.Errorf("line %d: common fields %+v", i+1, r.AuditEntry)
+		}
+		if i > 0 && r.Prev != lineHash(bytes.TrimSuffix(lines[i-1], []byte("\n"))) {
+			t.Errorf("line %d: prev %s is not the hash of the previous line", i+1, r.Prev)
+		}
+	}
+	if AuditHead(records) != lineHash(bytes.TrimSuffix(lines[3], []byte("\n"))) {
+		t.Error("chain head is not the hash of the last line")
+	}
+}
+
+func TestAuditTampering(t *testing.T) {
+	for name, tc := range map[string]struct {
+		tamper func(lines [][]byte) [][]byte
+		broken []int
+	}{
+		"edited line": {func(lines [][]byte) [][]byte {
+			lines[1] = bytes.Replace(lines[1], []byte("10.0.0.0/24"), []byte("10.0.0.0/16"), 1)
+			return lines
+		}, []int{3}},
+		"deleted line": {func(lines [][]byte) [][]byte {
+			return append(lines[:1], lines[2:]...)
+		}, []int{2}},
+		"reordered lines": {func(lines [][]byte) [][]byte {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		}, []int{2, 3, 4}},
+		"deleted first line": {func(lines [][]byte) [][]byte {
+			return lines[1:]
+		}, []int{1}},
+	} {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		lines := tc.tamper(writeAuditLog(t, path))
+		if err := os.WriteFile(path, bytes.Join(lines, nil), 0600); err != nil {
+			t.Fatal(err)
+		}
+		records, err := ReadAudit(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := invalidLines(records); !reflect.DeepEqual(got, tc.broken) {
+			t.Errorf("%s: broken lines %v, want %v", name, got, tc.broken)
+		}
+		for _, r := range records {
+			if want := map[bool]string{true: "ok", false: "broken"}[r.Valid]; chainStatus(r) != want {
+				t.Errorf("%s: line %d status %q", name, r.Line, chainStatus(r))
+			}
+		}
+	}
+
+	// A line that is no longer JSON is reported with its number.
+	path := filepath.Join(t.TempDir(), "audit.log")
+	lines := writeAuditLog(t, path)
+	lines[2] = []byte("{truncated\n")
+	_ = os.WriteFile(path, bytes.Join(lines, nil), 0600)
+	if _, err := ReadAudit(path); err == nil || !strings.Contains(err.Error(), "audit.log:3: invalid audit entry") {
+		t.Errorf("invalid line error: %v", err)
+	}
+}
+
+func TestAuditAppendsAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeAuditLog(t, path)
+	first, err := ReadAudit(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second run opens the same log and continues the chain from its last line.
+	second := newTestAuditor(t, path)
+	if err := second.Record(AuditEntry{Event: AuditCommand, Argv: []string{"masscan"}}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := ReadAudit(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(first)+2 || records[len(first)].Event != AuditSession || records[len(first)].Prev != AuditHead(first) {
+		t.Fatalf("appended records: %+v", records[len(first):])
+	}
+	if broken := invalidLines(records); broken != nil {
+		t.Errorf("chain broken at lines %v after a second run", broken)
+	}
+}
+
+func TestExportAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writeAuditLog(t, path)
+	records, err := ReadAudit(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := ExportAudit(&out, records, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var entries []AuditEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	for i := range entries {
+		if !reflect.DeepEqual(entries[i], records[i].AuditEntry) {
+			t.Errorf("json entry %d: %+v, want %+v", i, entries[i], records[i].AuditEntry)
+		}
+	}
+
+	out.Reset()
+	if err := ExportAudit(&out, records, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(records)+1 || rows[0][1] != "event" || rows[0][16] != "chain" {
+		t.Fatalf("csv rows: %v", rows)
+	}
+	for i, r := range records {
+		row := rows[i+1]
+		if row[0] != r.Time.Format(time.RFC3339) || row[1] != r.Event || row[2] != r.RunID || row[3] != "tester" || row[16] != "ok" {
+			t.Errorf("csv row %d: %v", i+1, row)
+		}
+	}
+	if command, end, probe := rows[2], rows[3], rows[4]; command[7] != "nmap -sS 10.0.0.0/24" || end[13] != "0" || end[14] != "1m2s" ||
+		probe[6] != "banner" || probe[8] != "10.0.0.5" || probe[9] != "21-22" || probe[11] != "10" || probe[12] != "100" {
+		t.Errorf("csv fields: %v / %v / %v", command, end, probe)
+	}
+
+	out.Reset()
+	if err := ExportAudit(&out, records, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	md := out.String()
+	for _, want := range []string{
+		"- Entries: 4", "`" + AuditHead(records) + "`", "hash chain verified", "## Run " + RunID, "- Operator: tester",
+		"| command | `nmap -sS 10.0.0.0/24` |", "| exit 0, 1m2s |", "| probe | banner: 10.0.0.5 tcp/21-22 | concurrency 10, 100/s |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown without %q:\n%s", want, md)
+		}
+	}
+	if rows := strings.Count(md, "\n| 20"); rows != len(records) {
+		t.Errorf("markdown has %d entry rows, want %d", rows, len(records))
+	}
+
+	// A broken chain is flagged in the summary and on the entry.
+	records[2].Valid = false
+	out.Reset()
+	_ = ExportAudit(&out, records, "markdown")
+	if md := out.String(); !strings.Contains(md, "**1 entries do not match the previous line**") || strings.Count(md, "**chain broken**") != 1 {
+		t.Errorf("markdown of a broken chain:\n%s", md)
+	}
+
+	if err := ExportAudit(&out, records, "xml"); err == nil || !strings.Contains(err.Error(), "json, csv, markdown") {
+		t.Errorf("unknown format: %v", err)
+	}
+}
+
+func TestProbeBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	Audit = newTestAuditor(t, path)
+	defer func() { Audit = nil }()
+
+	// An empty batch leaves no trace.
+	empty := NewProbeBatch(context.Background(), "ssh", 5)
+	empty.Start()
+	empty.Finish()
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("empty batch created the audit log: %v", err)
+	}
+
+	batch := NewProbeBatch(WithLimiter(context.Background(), NewRateLimiter(50)), "snmp", 4)
+	batch.Add("10.0.0.9", "udp", 161)
+	batch.Add("10.0.0.2", "tcp", 22)
+	batch.Add("10.0.0.2", "tcp", 23)
+	batch.Add("10.0.0.2", "tcp", 24)
+	batch.Add("10.0.0.9", "udp", 162)
+	batch.Add("10.0.0.3", "tcp", 0)
+	if batch.Len() != 3 {
+		t.Errorf("Len = %d", batch.Len())
+	}
+	batch.Start()
+	batch.Finish()
+
+	records, err := ReadAudit(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[1].Event != AuditProbe || records[2].Event != AuditProbeEnd {
+		t.Fatalf("records: %+v", records)
+	}
+	probe := records[1]
+	if !reflect.DeepEqual(probe.Targets, []string{"10.0.0.2", "10.0.0.3", "10.0.0.9"}) || probe.TCPPorts != "22-24" || probe.UDPPorts != "161-162" ||
+		probe.Module != "snmp" || probe.Concurrency != 4 || probe.Rate != 50 {
+		t.Errorf("probe entry: %+v", probe.AuditEntry)
+	}
+	if end := records[2]; end.Module != "snmp" || end.Duration == "" {
+		t.Errorf("probe end entry: %+v", end.AuditEntry)
+	}
+}
@@ -0,0 +1,162 @@
+package util
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// topPortsTable is the bundled nmap-services frequency table, most frequently open TCP ports first.
+//
+//go:embed top-ports.txt
+var topPortsTable string
+
+// PseudoCategories maps the top-N categories to the number of ports taken from the frequency table.
+// They are TCP only and can be overridden by a category of the same name in the configuration.
+var PseudoCategories = map[string]int{"top100": 100, "top1000": 1000}
+
+// TopPorts returns the n most frequently open TCP ports of the bundled frequency table.
+func TopPorts(n int) []int {
+	var ports []int
+	for _, line := range strings.Split(topPortsTable, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if len(ports) == n {
+			break
+		}
+		if port, err := strconv.Atoi(line); err == nil {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// CategoryNames returns the configured categories and the pseudo-categories, sorted.
+func (c *Config) CategoryNames() []string {
+	var names []string
+	for name := range c.Categories {
+		names = append(names, name)
+	}
+	for name := range PseudoCategories {
+		if _, ok := c.Categories[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasCategory reports whether name is a configured category or a pseudo-category.
+func (c *Config) HasCategory(name string) bool {
+	if _, ok := c.Categories[name]; ok {
+		return true
+	}
+	_, ok := PseudoCategories[name]
+	return ok
+}
+
+// ExpandCategory resolves a category into sorted TCP and UDP ports. Besides ports and ranges,
+// a category may include other categories by name; T: and U: apply to the ports that follow them,
+// and included categories keep their own protocols. The name "all" is the union of the configured categories.
+func (c *Config) ExpandCategory(name string) (tcp, udp []int, err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	tcpSet, udpSet := make(map[int]bool), make(map[int]bool)
+	if name == "all" {
+		for _, category := range c.CategoryNames() {
+			if _, ok := c.Categories[category]; !ok {
+				continue
+			}
+			if err := c.expandCategory(category, tcpSet, udpSet, nil); err != nil {
+				return nil, nil, err
+			}
+		}
+	} else if err := c.expandCategory(name, tcpSet, udpSet, nil); err != nil {
+		return nil, nil, err
+	}
+	return setToSorted(tcpSet), setToSorted(udpSet), nil
+}
+
+// expandCategory adds the ports of a category to the sets; stack holds the categories being
+// expanded, to detect include cycles.
+func (c *Config) expandCategory(name string, tcp, udp map[int]bool, stack []string) error {
+	for _, parent := range stack {
+		if parent == name {
+			return fmt.Errorf("category %q includes itself (%s -> %s)", stack[0], strings.Join(stack, " -> "), name)
+		}
+	}
+	spec, ok := c.Categories[name]
+	if !ok {
+		n, pseudo := PseudoCategories[name]
+		if !pseudo {
+			if len(stack) > 0 {
+				return fmt.Errorf("category %q includes unknown category %q", stack[len(stack)-1], name)
+			}
+			return fmt.Errorf("unknown category %q (available: %s)", name, strings.Join(c.CategoryNames(), ", "))
+		}
+		for _, port := range TopPorts(n) {
+			tcp[port] = true
+		}
+		return nil
+	}
+
+	stack = append(stack, name)
+	target := tcp
+	for _, token := range strings.Split(spec, ",") {
+		token = strings.TrimSpace(token)
+		switch {
+		case len(token) >= 2 && strings.EqualFold(token[:2], "U:"):
+			target, token = udp, token[2:]
+		case len(token) >= 2 && strings.EqualFold(token[:2], "T:"):
+			target, token = tcp, token[2:]
+		}
+		if token == "" {
+			return fmt.Errorf("category %q: empty port in list", name)
+		}
+		if token[0] < '0' || token[0] > '9' {
+			if err := c.expandCategory(strings.ToLower(token), tcp, udp, stack); err != nil {
+				return err
+			}
+			continue
+		}
+		first, last, err := ParsePortRange(token)
+		if err != nil {
+			return fmt.Errorf("category %q: %w", name, err)
+		}
+		for port := first; port <= last; port++ {
+			target[port] = true
+		}
+	}
+	return nil
+}
+
+// FormatPorts joins sorted ports, collapsing consecutive ports into ranges (e.g., "21-23,80").
+func FormatPorts(ports []int) string {
+	var parts []string
+	for i := 0; i < len(ports); {
+		j := i
+		for j+1 < len(ports) && ports[j+1] == ports[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", ports[i], ports[j]))
+		} else {
+			parts = append(parts, strconv.Itoa(ports[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// setToSorted returns the ports of a set in ascending order.
+func setToSorted(set map[int]bool) []int {
+	ports := make([]int, 0, len(set))
+	for port := range set {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports
+}
@@ -0,0 +1,109 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// RunCommand runs an external process and logs its exact command line, exit code and duration
+// as structured fields ("cmdline", "exit_code", "duration"). Failures are logged at error level;
+// exit_code is -1 when the process did not start or was killed.
+// The process is recorded in the audit log before it starts; when that fails, it is not run.
+// When cmd writes to a TailBuffer, a failure also logs the tail of the output ("output").
+// Outside the scan window the process waits to start and, once running, is paused until the window opens again.
+// When ctx is done before the window opens, the process is not started and ctx.Err() is returned.
+func RunCommand(ctx context.Context, logger *zerolog.Logger, cmd *exec.Cmd) error {
+	WaitWindow(ctx)
+	if err := ctx.Err(); err != nil {
+		logger.Warn().Err(err).Strs("cmdline", cmd.Args).Msg("External process not started")
+		return err
+	}
+	finish, err := AuditProcess(cmd.Args)
+	if err != nil {
+		logger.Error().Err(err).Strs("cmdline", cmd.Args).Msg("External process not started")
+		return err
+	}
+	start := time.Now()
+	if err = cmd.Start(); err == nil {
+		if Window != nil {
+			done := make(chan struct{})
+			go superviseWindow(cmd, done)
+			err = cmd.Wait()
+			close(done)
+		} else {
+			err = cmd.Wait()
+		}
+	}
+	elapsed := time.Since(start)
+
+	exitCode := -1
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	finish(exitCode, elapsed, err)
+	event := logger.Info()
+	if err != nil {
+		event = logger.Error().Err(err)
+		if output, ok := cmd.Stderr.(*TailBuffer); ok {
+			event = event.Str("output", output.String())
+		} else if output, ok := cmd.Stdout.(*TailBuffer); ok {
+			event = event.Str("output", output.String())
+		}
+	}
+	event.Strs("cmdline", cmd.Args).
+		Int("exit_code", exitCode).
+		Dur("duration", elapsed).
+		Msgf("External process %s finished", cmd.Args[0])
+	return err
+}
+
+// commandTailSize is how much of a process's combined output is kept for error reports.
+const commandTailSize = 2048
+
+// TailBuffer is an io.Writer that keeps only the last bytes written to it, so a process's
+// output can be reported on failure without holding all of it in memory.
+type TailBuffer struct {
+	size      int
+	data      []byte
+	truncated bool
+}
+
+// NewTailBuffer returns a TailBuffer that keeps up to size bytes (commandTailSize when size <= 0).
+func NewTailBuffer(size int) *TailBuffer {
+	if size <= 0 {
+		size = commandTailSize
+	}
+	return &TailBuffer{size: size}
+}
+
+// Write appends p and drops the oldest bytes beyond the buffer size.
+func (t *TailBuffer) Write(p []byte) (int, error) {
+	t.data = append(t.data, p...)
+	if over := len(t.data) - t.size; over > 0 {
+		t.data = append(t.data[:0], t.data[over:]...)
+		t.truncated = true
+	}
+	return len(p), nil
+}
+
+// String returns the kept output on a single line, with "..." in front when older output was dropped.
+func (t *TailBuffer) String() string {
+	out := strings.Join(strings.Fields(strings.ToValidUTF8(string(t.data), "")), " ")
+	if t.truncated && out != "" {
+		out = "..." + out
+	}
+	return out
+}
+
+// CommandError wraps the error of an external process with the tail of its output, when there is any.
+func CommandError(name string, err error, output *TailBuffer) error {
+	if tail := output.String(); tail != "" {
+		return fmt.Errorf("%s execution failed: %w: %s", name, err, tail)
+	}
+	return fmt.Errorf("%s execution failed: %w", name, err)
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestTailBuffer(t *testing.T) {
+	tail := NewTailBuffer(16)
+	if tail.String() != "" {
+		t.Errorf("empty buffer = %q", tail.String())
+	}
+	tail.Write([]byte("Starting Nmap\n"))
+	if got := tail.String(); got != "Starting Nmap" {
+		t.Errorf("got %q", got)
+	}
+	tail.Write([]byte("QUITTING!\nFailed to open device\n"))
+	if got := tail.String(); got != "...to open device" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	exit := errors.New("exit status 1")
+	output := NewTailBuffer(0)
+	if err := CommandError("nmap", exit, output); err.Error() != "nmap execution failed: exit status 1" || !errors.Is(err, exit) {
+		t.Errorf("without output: %v", err)
+	}
+	output.Write([]byte(strings.Repeat("x", commandTailSize) + "\nYou requested a scan type which requires root privileges.\n"))
+	err := CommandError("nmap", exit, output)
+	if !errors.Is(err, exit) || !strings.HasSuffix(err.Error(), "requires root privileges.") || len(err.Error()) > commandTailSize+64 {
+		t.Errorf("with output: %.80q", err)
+	}
+}
+
+func TestRunCommandClosedWindow(t *testing.T) {
+	// A one-minute window two hours from now is closed for the whole test.
+	opens := time.Now().Add(2 * time.Hour)
+	window, err := ParseScanWindow(fmt.Sprintf("%s-%s", opens.Format("15:04"), opens.Add(time.Minute).Format("15:04")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Window = window
+	defer func() { Window = nil }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	logger := zerolog.Nop()
+	cmd := exec.Command("true")
+	if err := RunCommand(ctx, &logger, cmd); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RunCommand with the window closed = %v", err)
+	}
+	if cmd.Process != nil {
+		t.Error("the process started after the context was done")
+	}
+}
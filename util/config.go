@@ -0,0 +1,662 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config holds the application configuration read from the TOML file.
+// Every value has a default, so the file only needs the settings being changed.
+type Config struct {
+	LogFile     string             `toml:"log_file"`    // Path to the log file (empty logs to the console only)
+	Verbose     bool               `toml:"verbose"`     // Verbose mode flag (if true, logs also go to console in friendly format)
+	Log         LogConfig          `toml:"log"`         // Log level and rotation
+	Scan        ScanConfig         `toml:"scan"`        // Defaults for the scan flags
+	Discovery   DiscoveryConfig    `toml:"discovery"`   // Host discovery settings
+	Output      OutputConfig       `toml:"output"`      // Output directories of each stage
+	Paths       PathsConfig        `toml:"paths"`       // Auxiliary files and directories
+	Enumeration EnumerationConfig  `toml:"enumeration"` // Defaults for the enumeration modules
+	Modes       map[string]string  `toml:"modes"`       // Scan mode -> Nmap timing options
+	Categories  map[string]string  `toml:"categories"`  // Port category -> ports, ranges and included categories (U: marks UDP ports)
+	Profiles    map[string]Profile `toml:"profiles"`    // Named scan profiles selected with --profile
+	Limits      LimitsConfig       `toml:"limits"`      // Global rate limit and scan window
+	Audit       AuditConfig        `toml:"audit"`       // Append-only audit trail of the traffic generated
+	Monitor     MonitorConfig      `toml:"monitor"`     // Defaults of the continuous monitoring mode
+	Notifiers   []NotifierConfig   `toml:"notifiers"`   // Destinations of the change events ([[notifiers]] tables)
+	Server      ServerConfig       `toml:"server"`      // REST API of the serve command
+	Distributed DistributedConfig  `toml:"distributed"` // Coordinator and remote agents of the distributed mode
+
+	Path    string            `toml:"-"` // File the configuration was read from (empty when only defaults are used)
+	sources map[string]string // Setting key -> source (file or environment variable); missing keys are defaults
+}
+
+// LogConfig holds the log level and the rotation of the log file.
+type LogConfig struct {
+	Level      string `toml:"level"`       // trace, debug, info, warn or error (empty: debug when verbose, info otherwise)
+	MaxSize    int    `toml:"max_size"`    // Size in megabytes that rotates the log file (0 disables)
+	MaxAge     string `toml:"max_age"`     // Age of the oldest entry that rotates the log file, e.g., "168h" (empty disables)
+	MaxBackups int    `toml:"max_backups"` // Rotated log files kept (0 keeps all)
+}
+
+// maxAge parses MaxAge; an empty value disables the age limit.
+func (l LogConfig) maxAge() (time.Duration, error) {
+	if l.MaxAge == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(l.MaxAge)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", l.MaxAge)
+	}
+	return d, nil
+}
+
+// LimitsConfig holds the limits shared by every scanner.
+type LimitsConfig struct {
+	MaxRate int    `toml:"max_rate"` // Packets or connections per second (0 is unlimited); --max-rate for Nmap, --rate for Masscan
+	Window  string `toml:"window"`   // Daily scan window, e.g., "22:00-06:00" (empty allows scanning at any time)
+}
+
+// AuditConfig holds the settings of the audit log.
+type AuditConfig struct {
+	Enabled   bool   `toml:"enabled"`    // Records external commands and native probe batches
+	File      string `toml:"file"`       // Append-only audit log (JSON lines)
+	Operator  string `toml:"operator"`   // Operator recorded in every entry (empty: current user)
+	Workspace string `toml:"workspace"`  // Engagement workspace recorded in every entry (empty: working directory)
+	ScopeFile string `toml:"scope_file"` // Authorized scope file whose SHA-256 is recorded in every entry
+}
+
+// ServerConfig holds the settings of the serve command. The API token comes from $ARTHXRECON_SERVER_TOKEN
+// or token_file, never from this file, so it does not show up in "config show".
+type ServerConfig struct {
+	Listen    string `toml:"listen"`     // Address of the HTTP API
+	Workers   int    `toml:"workers"`    // Jobs run at the same time
+	Queue     int    `toml:"queue"`      // Jobs waiting to run; further submissions are rejected
+	Keep      int    `toml:"keep"`       // Finished jobs kept by the API; older ones are removed (0 keeps all)
+	TokenFile string `toml:"token_file"` // File holding the API token (empty: $ARTHXRECON_SERVER_TOKEN or a generated token)
+}
+
+// DistributedConfig holds the settings of the coordinator and agent commands. The shared token comes
+// from $ARTHXRECON_AGENT_TOKEN or token_file, never from this file.
+type DistributedConfig struct {
+	Listen        string `toml:"listen"`          // Address the coordinator listens on for agents
+	Coordinator   string `toml:"coordinator"`     // URL of the coordinator the agents connect to
+	TokenFile     string `toml:"token_file"`      // File holding the shared token (empty: $ARTHXRECON_AGENT_TOKEN)
+	TLSCert       string `toml:"tls_cert"`        // Certificate of the coordinator (empty: plain HTTP, still authenticated)
+	TLSKey        string `toml:"tls_key"`         // Private key of the coordinator certificate
+	CACert        string `toml:"ca_cert"`         // CA the agents trust for the coordinator certificate (empty: system CAs)
+	ChunkSize     int    `toml:"chunk_size"`      // Addresses per host discovery chunk
+	HostsPerChunk int    `toml:"hosts_per_chunk"` // Live hosts per port scan chunk
+	LeaseTimeout  string `toml:"lease_timeout"`   // Time without a heartbeat that requeues a chunk, e.g., "2m"
+	MaxAttempts   int    `toml:"max_attempts"`    // Deliveries of a chunk before it is reported as failed
+
+	UnreachableTimeout string `toml:"unreachable_timeout"` // Wait for an agent reaching the pending chunks once nothing runs ("0s": forever)
+}
+
+// MonitorConfig holds the defaults of the monitor command.
+type MonitorConfig struct {
+	Interval string `toml:"interval"` // Time between the start of two runs, e.g., "6h"
+	Keep     int    `toml:"keep"`     // Runs kept in the output directory (0 keeps all)
+}
+
+// NotifierConfig is a destination of the scan and change events, one [[notifiers]] table each.
+type NotifierConfig struct {
+	Type        string            `toml:"type"`         // log, file or webhook
+	Path        string            `toml:"path"`         // File the events are appended to (type "file")
+	URL         string            `toml:"url"`          // Endpoint the events are posted to (type "webhook")
+	Format      string            `toml:"format"`       // Webhook payload: generic (default), slack or discord
+	Headers     map[string]string `toml:"headers"`      // Extra HTTP headers of the webhook requests (e.g., Authorization)
+	Retries     *int              `toml:"retries"`      // Webhook retries after a failed delivery, with exponential backoff (unset: 3)
+	Timeout     string            `toml:"timeout"`      // Timeout of each webhook request, e.g., "10s" (empty: 10s)
+	Events      []string          `toml:"events"`       // Event types delivered (empty delivers all)
+	MinSeverity string            `toml:"min_severity"` // Lowest severity of the finding events delivered (empty: high)
+	Template    string            `toml:"template"`     // Go template of the message, e.g., "{{.Host}}: {{.Message}}" (empty: the default message)
+}
+
+// NotifierTypes lists the notifier types accepted in [[notifiers]].
+var NotifierTypes = []string{"file", "log", "webhook"}
+
+// WebhookFormats lists the payload formats of the webhook notifier.
+var WebhookFormats = []string{"generic", "slack", "discord"}
+
+// validate checks the settings of the notifier type; events, severities and templates are checked
+// when the notifiers are created.
+func (n NotifierConfig) validate() []string {
+	var problems []string
+	switch n.Type {
+	case "log":
+	case "file":
+		if strings.TrimSpace(n.Path) == "" {
+			problems = append(problems, "path is required for the file notifier")
+		}
+	case "webhook":
+		if u, err := url.Parse(n.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("invalid webhook url %q (expected http or https)", n.URL))
+		}
+		if n.Format != "" && !containsString(WebhookFormats, n.Format) {
+			problems = append(problems, fmt.Sprintf("unknown webhook format %q (available: %s)", n.Format, strings.Join(WebhookFormats, ", ")))
+		}
+		if n.Retries != nil && *n.Retries < 0 {
+			problems = append(problems, fmt.Sprintf("retries must not be negative, got %d", *n.Retries))
+		}
+		if n.Timeout != "" {
+			if d, err := time.ParseDuration(n.Timeout); err != nil || d <= 0 {
+				problems = append(problems, fmt.Sprintf("invalid timeout %q", n.Timeout))
+			}
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unknown type %q (available: %s)", n.Type, strings.Join(NotifierTypes, ", ")))
+	}
+	return problems
+}
+
+// ScanConfig holds the defaults of the scan flags.
+type ScanConfig struct {
+	Mode     string `toml:"mode"`     // Default scan mode (a key of [modes])
+	Category string `toml:"category"` // Default port categories for the port scan, separated by commas
+}
+
+// DiscoveryConfig holds the host discovery settings.
+type DiscoveryConfig struct {
+	ProbePorts string `toml:"probe_ports"` // TCP ports probed with -PS during host discovery
+}
+
+// OutputConfig holds the output directory of each stage.
+type OutputConfig struct {
+	HostDiscovery string `toml:"host_discovery"`
+	PortScan      string `toml:"port_scan"`
+	Enumeration   string `toml:"enumeration"`
+	VulnAnalysis  string `toml:"vuln_analysis"`
+	Findings      string `toml:"findings"`
+	Monitor       string `toml:"monitor"`
+}
+
+// PathsConfig holds auxiliary files and directories.
+type PathsConfig struct {
+	VulnFeed string `toml:"vuln_feed"` // Local vulnerability feed
+	Plugins  string `toml:"plugins"`   // Directory with external plugin manifests
+}
+
+// EnumerationConfig holds the defaults of the enumeration flags.
+type EnumerationConfig struct {
+	Timeout       int    `toml:"timeout"`        // Connection timeout in seconds
+	Threads       int    `toml:"threads"`        // Number of concurrent connections/module runs
+	ModuleTimeout string `toml:"module_timeout"` // Timeout per module run, default and/or per module (e.g., "2m,ssh=30s")
+}
+
+// modeAliases maps the numeric modes accepted by the flags to their names.
+var modeAliases = map[string]string{"1": "stealth", "2": "normal", "3": "aggressive"}
+
+// AppConfig is the effective configuration, set by Config.Apply.
+var AppConfig = DefaultConfig()
+
+// DefaultConfig returns the built-in configuration.
+func DefaultConfig() *Config {
+	return &Config{
+		LogFile: "",
+		Verbose: false,
+		Log: LogConfig{
+			MaxSize:    10,
+			MaxAge:     "168h",
+			MaxBackups: 5,
+		},
+		Scan: ScanConfig{Mode: "normal"},
+		Discovery: DiscoveryConfig{
+			ProbePorts: "22,2222,53,80,443,445,3389",
+		},
+		Output: OutputConfig{
+			HostDiscovery: "hostDiscovery",
+			PortScan:      "portScan",
+			Enumeration:   "enumeration",
+			VulnAnalysis:  "vulnAnalysis",
+			Findings:      "findings",
+			Monitor:       "monitor",
+		},
+		Paths: PathsConfig{
+			VulnFeed: "config/cve-feed.json",
+			Plugins:  "plugins",
+		},
+		Enumeration: EnumerationConfig{
+			Timeout:       5,
+			Threads:       20,
+			ModuleTimeout: "2m",
+		},
+		Modes: map[string]string{
+			"stealth":    "-T2",
+			"normal":     "",
+			"aggressive": "-T4",
+			"passive":    "",
+		},
+		Categories: map[string]string{
+			"top12":    "21,22,2222,23,53,80,135,139,443,445,3389,8080",
+			"database": "3306,5432,1433,1521,27017,6379,9042,9160,50000,8086,5984,7474,7687,11211,3050,9092,1527,2638,8529,28015,2424,26257,9200",
+			"web":      "80,443,8080,8443,8000,3000,5000,4200,8888,8081,8001,3001,9000,9090,1313,8008,8880",
+			"network":  "10000,20000,902,903,8006,10050,10051,23560,17778,3000,55000,9090,5666,5665,19999,443,8000,8089,6557,8980,9100,9000,8443",
+			"firewall": "4444,4433,4443,443,8443",
+			"windows":  "88,389,636,593,3268,3269,5985,5986,U:88,U:137,U:389",
+			"vpn":      "22,2222,3389,1194,1723,5900,5901,5985,5986,443,4443,8443,5938,992,8080,6000,5902,U:500,U:4500,U:1701,U:1194",
+			"udp":      "U:53,67,69,123,137,161,162,500,514,520,623,1194,1434,1701,1812,1900,4500,5353,11211",
+		},
+		Profiles: builtinProfiles(),
+		Audit: AuditConfig{
+			Enabled: true,
+			File:    "audit/audit.log",
+		},
+		Monitor: MonitorConfig{Interval: "6h"},
+		Server: ServerConfig{
+			Listen:  "127.0.0.1:8080",
+			Workers: 1,
+			Queue:   20,
+			Keep:    100,
+		},
+		Distributed: DistributedConfig{
+			Listen:        "0.0.0.0:8090",
+			ChunkSize:     256,
+			HostsPerChunk: 16,
+			LeaseTimeout:  "2m",
+			MaxAttempts:   3,
+
+			UnreachableTimeout: "0s",
+		},
+	}
+}
+
+// LoadConfig builds the configuration from the defaults, the TOML file at path (skipped when path
+// is empty) and the ARTHXRECON_* environment overrides, in this order, and validates the result.
+func LoadConfig(path string) (*Config, error) {
+	cfg := DefaultConfig()
+	cfg.sources = make(map[string]string)
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+		meta, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			var keys []string
+			for _, key := range undecoded {
+				keys = append(keys, key.String())
+			}
+			return nil, fmt.Errorf("invalid config %s: unknown keys: %s", path, strings.Join(keys, ", "))
+		}
+		cfg.Path = path
+		for _, key := range meta.Keys() {
+			cfg.sources[settingKey(key)] = SourceFile
+		}
+	}
+	if err := cfg.applyEnv(os.Environ()); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		if path == "" {
+			return nil, fmt.Errorf("invalid config:\n%w", err)
+		}
+		return nil, fmt.Errorf("invalid config %s:\n%w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks every setting and reports all the problems found, one per line.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("  %s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if _, err := c.LogLevel(); err != nil {
+		fail("log.level", "%v", err)
+	}
+	if c.Log.MaxSize < 0 {
+		fail("log.max_size", "must not be negative, got %d", c.Log.MaxSize)
+	}
+	if _, err := c.Log.maxAge(); err != nil {
+		fail("log.max_age", "%v", err)
+	}
+	if c.Log.MaxBackups < 0 {
+		fail("log.max_backups", "must not be negative, got %d", c.Log.MaxBackups)
+	}
+	if _, ok := c.Modes[c.Scan.Mode]; !ok && modeAliases[c.Scan.Mode] == "" {
+		fail("scan.mode", "unknown mode %q (available: %s)", c.Scan.Mode, strings.Join(c.ModeNames(), ", "))
+	}
+	for _, category := range strings.Split(c.Scan.Category, ",") {
+		if category = strings.TrimSpace(strings.ToLower(category)); category != "" && category != "all" {
+			if !c.HasCategory(category) {
+				fail("scan.category", "unknown category %q", category)
+			}
+		}
+	}
+	if c.Discovery.ProbePorts != "" {
+		if err := ValidatePortSpec(c.Discovery.ProbePorts); err != nil {
+			fail("discovery.probe_ports", "%v", err)
+		} else if strings.Contains(strings.ToUpper(c.Discovery.ProbePorts), "U:") {
+			fail("discovery.probe_ports", "only TCP ports can be probed")
+		}
+	}
+	for key, dir := range map[string]string{
+		"output.host_discovery": c.Output.HostDiscovery,
+		"output.port_scan":      c.Output.PortScan,
+		"output.enumeration":    c.Output.Enumeration,
+		"output.vuln_analysis":  c.Output.VulnAnalysis,
+		"output.findings":       c.Output.Findings,
+		"output.monitor":        c.Output.Monitor,
+		"paths.vuln_feed":       c.Paths.VulnFeed,
+		"paths.plugins":         c.Paths.Plugins,
+	} {
+		if strings.TrimSpace(dir) == "" {
+			fail(key, "must not be empty")
+		}
+	}
+	if c.Limits.MaxRate < 0 {
+		fail("limits.max_rate", "must not be negative, got %d", c.Limits.MaxRate)
+	}
+	if _, err := ParseScanWindow(c.Limits.Window); err != nil {
+		fail("limits.window", "%v", err)
+	}
+	if c.Audit.Enabled && strings.TrimSpace(c.Audit.File) == "" {
+		fail("audit.file", "must not be empty when the audit log is enabled")
+	}
+	if c.Audit.ScopeFile != "" {
+		if info, err := os.Stat(c.Audit.ScopeFile); err != nil {
+			fail("audit.scope_file", "%v", err)
+		} else if info.IsDir() {
+			fail("audit.scope_file", "%s is a directory", c.Audit.ScopeFile)
+		}
+	}
+	if d, err := time.ParseDuration(c.Monitor.Interval); err != nil || d <= 0 {
+		fail("monitor.interval", "invalid duration %q", c.Monitor.Interval)
+	}
+	if c.Monitor.Keep < 0 {
+		fail("monitor.keep", "must not be negative, got %d", c.Monitor.Keep)
+	}
+	if strings.TrimSpace(c.Server.Listen) == "" {
+		fail("server.listen", "must not be empty")
+	}
+	if c.Server.TokenFile != "" {
+		if info, err := os.Stat(c.Server.TokenFile); err != nil {
+			fail("server.token_file", "%v", err)
+		} else if info.IsDir() {
+			fail("server.token_file", "%s is a directory", c.Server.TokenFile)
+		}
+	}
+	if c.Server.Workers <= 0 {
+		fail("server.workers", "must be positive, got %d", c.Server.Workers)
+	}
+	if c.Server.Queue <= 0 {
+		fail("server.queue", "must be positive, got %d", c.Server.Queue)
+	}
+	if c.Server.Keep < 0 {
+		fail("server.keep", "must not be negative, got %d", c.Server.Keep)
+	}
+	if strings.TrimSpace(c.Distributed.Listen) == "" {
+		fail("distributed.listen", "must not be empty")
+	}
+	if c.Distributed.Coordinator != "" {
+		if u, err := url.Parse(c.Distributed.Coordinator); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("distributed.coordinator", "invalid url %q (expected http or https)", c.Distributed.Coordinator)
+		}
+	}
+	for key, path := range map[string]string{
+		"distributed.token_file": c.Distributed.TokenFile,
+		"distributed.tls_cert":   c.Distributed.TLSCert,
+		"distributed.tls_key":    c.Distributed.TLSKey,
+		"distributed.ca_cert":    c.Distributed.CACert,
+	} {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil {
+			fail(key, "%v", err)
+		} else if info.IsDir() {
+			fail(key, "%s is a directory", path)
+		}
+	}
+	if (c.Distributed.TLSCert == "") != (c.Distributed.TLSKey == "") {
+		fail("distributed.tls_key", "tls_cert and tls_key must be set together")
+	}
+	if c.Distributed.ChunkSize <= 0 {
+		fail("distributed.chunk_size", "must be positive, got %d", c.Distributed.ChunkSize)
+	}
+	if c.Distributed.HostsPerChunk <= 0 {
+		fail("distributed.hosts_per_chunk", "must be positive, got %d", c.Distributed.HostsPerChunk)
+	}
+	if d, err := time.ParseDuration(c.Distributed.LeaseTimeout); err != nil || d < 10*time.Second {
+		fail("distributed.lease_timeout", "invalid duration %q (minimum 10s)", c.Distributed.LeaseTimeout)
+	}
+	if c.Distributed.MaxAttempts <= 0 {
+		fail("distributed.max_attempts", "must be positive, got %d", c.Distributed.MaxAttempts)
+	}
+	if d, err := time.ParseDuration(c.Distributed.UnreachableTimeout); err != nil || d < 0 {
+		fail("distributed.unreachable_timeout", "invalid duration %q", c.Distributed.UnreachableTimeout)
+	}
+	for i, n := range c.Notifiers {
+		for _, problem := range n.validate() {
+			fail(fmt.Sprintf("notifiers[%d]", i), "%s", problem)
+		}
+	}
+	if c.Enumeration.Timeout <= 0 {
+		fail("enumeration.timeout", "must be a positive number of seconds, got %d", c.Enumeration.Timeout)
+	}
+	if c.Enumeration.Threads <= 0 {
+		fail("enumeration.threads", "must be positive, got %d", c.Enumeration.Threads)
+	}
+	for _, entry := range strings.Split(c.Enumeration.ModuleTimeout, ",") {
+		_, value, found := strings.Cut(entry, "=")
+		if !found {
+			value = entry
+		}
+		if d, err := time.ParseDuration(strings.TrimSpace(value)); err != nil || d <= 0 {
+			fail("enumeration.module_timeout", "invalid duration %q", strings.TrimSpace(entry))
+		}
+	}
+	for name, options := range c.Modes {
+		if name == "" || strings.ContainsAny(name, " ,") {
+			fail("modes", "invalid mode name %q", name)
+		}
+		for _, opt := range strings.Fields(options) {
+			if !strings.HasPrefix(opt, "-") {
+				fail("modes."+name, "option %q must start with '-'", opt)
+			}
+		}
+	}
+	if _, ok := c.Modes["normal"]; !ok {
+		fail("modes", "the \"normal\" mode must be defined")
+	}
+	for name, ports := range c.Categories {
+		switch {
+		case name == "all":
+			fail("categories.all", "\"all\" is reserved for every category")
+		case name == "" || name[0] < 'a' || name[0] > 'z' || name != strings.ToLower(name) || strings.ContainsAny(name, " ,:;="):
+			fail("categories", "invalid category name %q (start with a letter, lowercase, without spaces, ',', ':', ';' or '=')", name)
+		case strings.TrimSpace(ports) == "":
+			fail("categories."+name, "empty port list")
+		default:
+			if _, _, err := c.ExpandCategory(name); err != nil {
+				fail("categories."+name, "%v", err)
+			}
+		}
+	}
+	for _, name := range c.ProfileNames() {
+		if name == "" || strings.ContainsAny(name, " ,") {
+			fail("profiles", "invalid profile name %q", name)
+			continue
+		}
+		profile, _, err := c.ResolveProfile(name)
+		if err != nil {
+			fail("profiles."+name, "%v", err)
+			continue
+		}
+		for _, problem := range c.validateProfile(profile) {
+			fail("profiles."+name, "%s", problem)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
+
+// ModeNames returns the configured scan modes, sorted.
+func (c *Config) ModeNames() []string {
+	var names []string
+	for name := range c.Modes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TimingOptions returns the Nmap options of a scan mode. Modes 1, 2 and 3 are aliases of
+// stealth, normal and aggressive; an empty mode uses scan.mode.
+func (c *Config) TimingOptions(mode string) ([]string, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = c.Scan.Mode
+	}
+	if alias, ok := modeAliases[mode]; ok {
+		mode = alias
+	}
+	options, ok := c.Modes[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown scan mode %q (available: %s)", mode, strings.Join(c.ModeNames(), ", "))
+	}
+	return strings.Fields(options), nil
+}
+
+// Apply makes c the effective configuration and updates the output and path settings and the
+// scan limits used across the application.
+func (c *Config) Apply() {
+	AppConfig = c
+	HostDiscoveryName = c.Output.HostDiscovery
+	PortScanName = c.Output.PortScan
+	EnumerationName = c.Output.Enumeration
+	VulnAnalysisName = c.Output.VulnAnalysis
+	FindingsName = c.Output.Findings
+	FindingsPath = filepath.Join(c.Output.Findings, "findings.json")
+	MonitorName = c.Output.Monitor
+	VulnFeedPath = c.Paths.VulnFeed
+	PluginsDir = c.Paths.Plugins
+	HostDiscoveryFlagNmap = ""
+	if c.Discovery.ProbePorts != "" {
+		HostDiscoveryFlagNmap = "-PS" + c.Discovery.ProbePorts
+	}
+	// Already checked by Validate.
+	Window, _ = ParseScanWindow(c.Limits.Window)
+	SetMaxRate(c.Limits.MaxRate)
+}
+
+// Lookup returns the value of a setting by its TOML key (e.g., "scan.mode", "categories.web").
+func (c *Config) Lookup(key string) (string, bool) {
+	if name, ok := strings.CutPrefix(key, "modes."); ok {
+		value, found := c.Modes[name]
+		return value, found
+	}
+	if name, ok := strings.CutPrefix(key, "categories."); ok {
+		value, found := c.Categories[name]
+		return value, found
+	}
+	switch field := c.fields()[key].(type) {
+	case *string:
+		return *field, true
+	case *bool:
+		return strconv.FormatBool(*field), true
+	case *int:
+		return strconv.Itoa(*field), true
+	}
+	return "", false
+}
+
+// Set changes a setting by its TOML key, converting the value to the type of the setting.
+// Entries of [modes] and [categories] are created when missing.
+func (c *Config) Set(key, value string) error {
+	if name, ok := strings.CutPrefix(key, "modes."); ok && name != "" {
+		c.Modes[name] = value
+		return nil
+	}
+	if name, ok := strings.CutPrefix(key, "categories."); ok && name != "" {
+		c.Categories[name] = value
+		return nil
+	}
+	switch field := c.fields()[key].(type) {
+	case *string:
+		*field = value
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid boolean %q", key, value)
+		}
+		*field = b
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: invalid integer %q", key, value)
+		}
+		*field = n
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	return nil
+}
+
+// fields maps the TOML key of each scalar setting to a pointer to its value.
+func (c *Config) fields() map[string]interface{} {
+	return map[string]interface{}{
+		"log_file":                        &c.LogFile,
+		"verbose":                         &c.Verbose,
+		"log.level":                       &c.Log.Level,
+		"log.max_size":                    &c.Log.MaxSize,
+		"log.max_age":                     &c.Log.MaxAge,
+		"log.max_backups":                 &c.Log.MaxBackups,
+		"scan.mode":                       &c.Scan.Mode,
+		"scan.category":                   &c.Scan.Category,
+		"discovery.probe_ports":           &c.Discovery.ProbePorts,
+		"output.host_discovery":           &c.Output.HostDiscovery,
+		"output.port_scan":                &c.Output.PortScan,
+		"output.enumeration":              &c.Output.Enumeration,
+		"output.vuln_analysis":            &c.Output.VulnAnalysis,
+		"output.findings":                 &c.Output.Findings,
+		"output.monitor":                  &c.Output.Monitor,
+		"paths.vuln_feed":                 &c.Paths.VulnFeed,
+		"paths.plugins":                   &c.Paths.Plugins,
+		"enumeration.timeout":             &c.Enumeration.Timeout,
+		"enumeration.threads":             &c.Enumeration.Threads,
+		"enumeration.module_timeout":      &c.Enumeration.ModuleTimeout,
+		"limits.max_rate":                 &c.Limits.MaxRate,
+		"limits.window":                   &c.Limits.Window,
+		"audit.enabled":                   &c.Audit.Enabled,
+		"audit.file":                      &c.Audit.File,
+		"audit.operator":                  &c.Audit.Operator,
+		"audit.workspace":                 &c.Audit.Workspace,
+		"audit.scope_file":                &c.Audit.ScopeFile,
+		"monitor.interval":                &c.Monitor.Interval,
+		"monitor.keep":                    &c.Monitor.Keep,
+		"server.listen":                   &c.Server.Listen,
+		"server.workers":                  &c.Server.Workers,
+		"server.queue":                    &c.Server.Queue,
+		"server.keep":                     &c.Server.Keep,
+		"server.token_file":               &c.Server.TokenFile,
+		"distributed.listen":              &c.Distributed.Listen,
+		"distributed.coordinator":         &c.Distributed.Coordinator,
+		"distributed.token_file":          &c.Distributed.TokenFile,
+		"distributed.tls_cert":            &c.Distributed.TLSCert,
+		"distributed.tls_key":             &c.Distributed.TLSKey,
+		"distributed.ca_cert":             &c.Distributed.CACert,
+		"distributed.chunk_size":          &c.Distributed.ChunkSize,
+		"distributed.hosts_per_chunk":     &c.Distributed.HostsPerChunk,
+		"distributed.lease_timeout":       &c.Distributed.LeaseTimeout,
+		"distributed.max_attempts":        &c.Distributed.MaxAttempts,
+		"distributed.unreachable_timeout": &c.Distributed.UnreachableTimeout,
+	}
+}
@@ -0,0 +1,196 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a config file in a temporary directory and returns its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultConfigIsValid(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("default configuration:\n%v", err)
+	}
+	cfg, err := LoadConfig("")
+	if err != nil || cfg.Path != "" || cfg.Scan.Mode != "normal" {
+		t.Errorf("LoadConfig without a file = %+v, %v", cfg, err)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `
+[scan]
+mode = "aggressive"
+category = "web"
+
+[limits]
+max_rate = 300
+window = "22:00-06:00"
+
+[categories]
+custom = "8000-8010,U:161"
+`)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Path != path || cfg.Scan.Mode != "aggressive" || cfg.Limits.MaxRate != 300 || cfg.Categories["custom"] != "8000-8010,U:161" {
+		t.Errorf("loaded config: %+v", cfg)
+	}
+	// The file only changes what it sets.
+	if cfg.Server.Workers != 1 || cfg.Categories["web"] == "" || cfg.Modes["stealth"] != "-T2" {
+		t.Errorf("defaults lost: workers %d, categories %v", cfg.Server.Workers, cfg.Categories)
+	}
+
+	for name, tc := range map[string]struct {
+		content string
+		want    []string
+	}{
+		"unknown keys": {"verbos = true\n[scan]\nmod = \"normal\"\n", []string{"invalid config " + "%s" + ": unknown keys: verbos, scan.mod"}},
+		"syntax error": {"[scan\nmode = 1\n", []string{"invalid config %s: "}},
+		"wrong type":   {"[scan]\nmode = 3\n", []string{"invalid config %s: "}},
+		"invalid values": {"[scan]\nmode = \"turbo\"\n[server]\nworkers = 0\n", []string{
+			"invalid config %s:\n",
+			`  scan.mode: unknown mode "turbo" (available: aggressive, normal, passive, stealth)`,
+			"  server.workers: must be positive, got 0",
+		}},
+	} {
+		path := writeConfig(t, tc.content)
+		_, err := LoadConfig(path)
+		if err == nil {
+			t.Errorf("%s: accepted", name)
+			continue
+		}
+		for _, want := range tc.want {
+			if want = strings.ReplaceAll(want, "%s", path); !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: error %q does not contain %q", name, err, want)
+			}
+		}
+	}
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.toml")); err == nil || !strings.HasPrefix(err.Error(), "failed to read config") {
+		t.Errorf("missing file: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		change func(c *Config)
+		want   string
+	}{
+		"mode":                {func(c *Config) { c.Scan.Mode = "turbo" }, `scan.mode: unknown mode "turbo" (available: aggressive, normal, passive, stealth)`},
+		"mode alias":          {func(c *Config) { c.Scan.Mode = "3" }, ""},
+		"category":            {func(c *Config) { c.Scan.Category = "web, nope" }, `scan.category: unknown category "nope"`},
+		"all category":        {func(c *Config) { c.Scan.Category = "all" }, ""},
+		"window hour":         {func(c *Config) { c.Limits.Window = "25:00-06:00" }, "limits.window: "},
+		"window zone":         {func(c *Config) { c.Limits.Window = "22:00-06:00 cet" }, `limits.window: invalid scan window "22:00-06:00 cet": unknown time zone "cet" (expected local or utc)`},
+		"window empty range":  {func(c *Config) { c.Limits.Window = "08:00-08:00" }, `limits.window: invalid scan window range "08:00-08:00": empty range`},
+		"window":              {func(c *Config) { c.Limits.Window = "22:00-06:00,12:00-13:00 utc" }, ""},
+		"log max age":         {func(c *Config) { c.Log.MaxAge = "1 week" }, `log.max_age: invalid duration "1 week"`},
+		"log level":           {func(c *Config) { c.Log.Level = "loud" }, "log.level: "},
+		"monitor interval":    {func(c *Config) { c.Monitor.Interval = "0s" }, `monitor.interval: invalid duration "0s"`},
+		"lease timeout":       {func(c *Config) { c.Distributed.LeaseTimeout = "5s" }, `distributed.lease_timeout: invalid duration "5s" (minimum 10s)`},
+		"unreachable timeout": {func(c *Config) { c.Distributed.UnreachableTimeout = "-1m" }, `distributed.unreachable_timeout: invalid duration "-1m"`},
+		"module timeout":      {func(c *Config) { c.Enumeration.ModuleTimeout = "2m,ssh=fast" }, `enumeration.module_timeout: invalid duration "ssh=fast"`},
+		"negative rate":       {func(c *Config) { c.Limits.MaxRate = -1 }, "limits.max_rate: must not be negative, got -1"},
+		"probe ports":         {func(c *Config) { c.Discovery.ProbePorts = "22,U:53" }, "discovery.probe_ports: only TCP ports can be probed"},
+		"empty output":        {func(c *Config) { c.Output.PortScan = " " }, "output.port_scan: must not be empty"},
+		"tls pair":            {func(c *Config) { c.Distributed.TLSKey, c.Distributed.TLSCert = "", "" }, ""},
+		"coordinator url":     {func(c *Config) { c.Distributed.Coordinator = "10.0.0.5:8090" }, `distributed.coordinator: invalid url "10.0.0.5:8090" (expected http or https)`},
+		"mode option":         {func(c *Config) { c.Modes["fast"] = "-T5 min-rate" }, `modes.fast: option "min-rate" must start with '-'`},
+		"normal mode":         {func(c *Config) { delete(c.Modes, "normal") }, `modes: the "normal" mode must be defined`},
+		"reserved category":   {func(c *Config) { c.Categories["all"] = "80" }, `categories.all: "all" is reserved for every category`},
+		"category name":       {func(c *Config) { c.Categories["Web2"] = "80" }, `categories: invalid category name "Web2"`},
+		"category ports":      {func(c *Config) { c.Categories["bad"] = "80,99999" }, "categories.bad: "},
+		"notifier path":       {func(c *Config) { c.Notifiers = []NotifierConfig{{Type: "file"}} }, "notifiers[0]: path is required for the file notifier"},
+		"notifier":            {func(c *Config) { c.Notifiers = []NotifierConfig{{Type: "webhook", URL: "ftp://x"}} }, `notifiers[0]: invalid webhook url "ftp://x" (expected http or https)`},
+		"notifier type":       {func(c *Config) { c.Notifiers = []NotifierConfig{{Type: "email"}} }, `notifiers[0]: unknown type "email" (available: file, log, webhook)`},
+		"scope file":          {func(c *Config) { c.Audit.ScopeFile = "/nonexistent/scope.txt" }, "audit.scope_file: "},
+	} {
+		cfg := DefaultConfig()
+		tc.change(cfg)
+		err := cfg.Validate()
+		switch {
+		case tc.want == "" && err != nil:
+			t.Errorf("%s: %v", name, err)
+		case tc.want != "" && (err == nil || !strings.Contains(err.Error(), "  "+tc.want)):
+			t.Errorf("%s: error %v, want %q", name, err, tc.want)
+		}
+	}
+
+	// Every problem is reported, one per line and sorted by key.
+	cfg := DefaultConfig()
+	cfg.Server.Workers, cfg.Log.MaxSize, cfg.Scan.Mode = 0, -1, "turbo"
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("invalid config accepted")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "  log.max_size:") || !strings.HasPrefix(lines[1], "  scan.mode:") || !strings.HasPrefix(lines[2], "  server.workers:") {
+		t.Errorf("error lines: %q", lines)
+	}
+}
+
+func TestSetLookup(t *testing.T) {
+	cfg := DefaultConfig()
+	for key, value := range map[string]string{
+		"scan.mode": "stealth", "limits.max_rate": "250", "audit.enabled": "false", "categories.lab": "8000-8100", "modes.fast": "-T5",
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Errorf("Set(%s): %v", key, err)
+		}
+		if got, ok := cfg.Lookup(key); !ok || got != value {
+			t.Errorf("Lookup(%s) = %q, %v", key, got, ok)
+		}
+	}
+	if cfg.Limits.MaxRate != 250 || cfg.Audit.Enabled || cfg.Categories["lab"] != "8000-8100" {
+		t.Errorf("typed values: %+v", cfg)
+	}
+
+	for key, want := range map[string]string{
+		"limits.max_rate": `limits.max_rate: invalid integer "fast"`,
+		"audit.enabled":   `audit.enabled: invalid boolean "fast"`,
+		"scan.nope":       `unknown setting "scan.nope"`,
+		"profiles.quick":  `unknown setting "profiles.quick"`,
+	} {
+		if err := cfg.Set(key, "fast"); err == nil || err.Error() != want {
+			t.Errorf("Set(%s) = %v, want %q", key, err, want)
+		}
+	}
+	if _, ok := cfg.Lookup("scan.nope"); ok {
+		t.Error("Lookup of an unknown key succeeded")
+	}
+
+	// Every scalar setting can be read back and is listed by Keys.
+	keys := make(map[string]bool)
+	for _, key := range cfg.Keys() {
+		keys[key] = true
+	}
+	for key := range cfg.fields() {
+		if _, ok := cfg.Lookup(key); !ok || !keys[key] {
+			t.Errorf("setting %s: lookup %v, listed %v", key, ok, keys[key])
+		}
+	}
+}
+
+func TestTimingOptions(t *testing.T) {
+	cfg := DefaultConfig()
+	for mode, want := range map[string]string{"": "", "1": "-T2", "Aggressive": "-T4", " 3 ": "-T4", "normal": ""} {
+		got, err := cfg.TimingOptions(mode)
+		if err != nil || strings.Join(got, " ") != want {
+			t.Errorf("TimingOptions(%q) = %v, %v", mode, got, err)
+		}
+	}
+	if _, err := cfg.TimingOptions("9"); err == nil || err.Error() != `unknown scan mode "9" (available: aggressive, normal, passive, stealth)` {
+		t.Errorf("unknown mode: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Sources of a setting, reported by Config.Source.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+)
+
+// EnvPrefix is the prefix of the environment variables that override settings
+// (e.g., ARTHXRECON_SCAN_MODE overrides scan.mode).
+const EnvPrefix = "ARTHXRECON_"
+
+// ConfigEnv names the environment variable holding the path of the configuration file.
+const ConfigEnv = EnvPrefix + "CONFIG"
+
+// ServerTokenEnv names the environment variable holding the token of the REST API (serve).
+const ServerTokenEnv = EnvPrefix + "SERVER_TOKEN"
+
+// AgentTokenEnv names the environment variable holding the token shared by the coordinator and its agents.
+const AgentTokenEnv = EnvPrefix + "AGENT_TOKEN"
+
+// envReserved lists the ARTHXRECON_* variables that are not setting overrides.
+var envReserved = map[string]bool{
+	ConfigEnv:            true,
+	ServerTokenEnv:       true,
+	AgentTokenEnv:        true,
+	EnvPrefix + "PLUGIN": true, // Set by the application for external plugins
+}
+
+// FindConfig returns the configuration file to load and where its path came from, trying in order
+// the --config flag, $ARTHXRECON_CONFIG, $XDG_CONFIG_HOME/arthxrecon/config.toml (~/.config when
+// unset) and ConfigFilePath in the working directory. Explicit paths (flag or variable) must exist;
+// when no file is found the path is empty and only the defaults and environment overrides apply.
+func FindConfig(flagPath string) (path, origin string, err error) {
+	type candidate struct{ path, origin string }
+
+	for _, c := range []candidate{{flagPath, "--config"}, {os.Getenv(ConfigEnv), "$" + ConfigEnv}} {
+		if c.path == "" {
+			continue
+		}
+		if _, err := os.Stat(c.path); err != nil {
+			return "", c.origin, fmt.Errorf("config file from %s: %w", c.origin, err)
+		}
+		return c.path, c.origin, nil
+	}
+
+	var search []candidate
+	if dir, err := os.UserConfigDir(); err == nil {
+		search = append(search, candidate{filepath.Join(dir, "arthxrecon", "config.toml"), "user config directory"})
+	}
+	search = append(search, candidate{ConfigFilePath, "working directory"})
+	for _, c := range search {
+		_, err := os.Stat(c.path)
+		if err == nil {
+			return c.path, c.origin, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", c.origin, fmt.Errorf("config file %s: %w", c.path, err)
+		}
+	}
+	return "", "", nil
+}
+
+// EnvName returns the environment variable that overrides a setting.
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// applyEnv applies the ARTHXRECON_* overrides found in environ ("NAME=value" entries).
+// ARTHXRECON_MODES_<NAME> and ARTHXRECON_CATEGORIES_<NAME> set or create entries of those tables.
+func (c *Config) applyEnv(environ []string) error {
+	keys := make(map[string]string)
+	for key := range c.fields() {
+		keys[EnvName(key)] = key
+	}
+
+	var errs []error
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, EnvPrefix) || envReserved[name] {
+			continue
+		}
+		key, ok := keys[name]
+		if rest, table := strings.CutPrefix(name, EnvPrefix+"MODES_"); table {
+			key, ok = "modes."+strings.ToLower(rest), rest != ""
+		} else if rest, table := strings.CutPrefix(name, EnvPrefix+"CATEGORIES_"); table {
+			key, ok = "categories."+strings.ToLower(rest), rest != ""
+		}
+		if !ok {
+			errs = append(errs, fmt.Errorf("  %s: unknown setting override", name))
+			continue
+		}
+		if err := c.Set(key, value); err != nil {
+			errs = append(errs, fmt.Errorf("  %s: %w", name, err))
+			continue
+		}
+		c.sources[key] = "env " + name
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid environment overrides:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// settingKey converts a key read from the TOML file into the setting it belongs to:
+// profile options are reported as the profile itself (profiles.<name>) and the notifier tables
+// as the whole list (notifiers).
+func settingKey(key toml.Key) string {
+	if len(key) > 2 && key[0] == "profiles" {
+		key = key[:2]
+	}
+	if len(key) > 1 && key[0] == "notifiers" {
+		key = key[:1]
+	}
+	return strings.Join(key, ".")
+}
+
+// Keys returns every setting key: the scalar settings, the entries of [modes] and [categories]
+// and the profiles, sorted.
+func (c *Config) Keys() []string {
+	var keys []string
+	for key := range c.fields() {
+		keys = append(keys, key)
+	}
+	for name := range c.Modes {
+		keys = append(keys, "modes."+name)
+	}
+	for name := range c.Categories {
+		keys = append(keys, "categories."+name)
+	}
+	for name := range c.Profiles {
+		keys = append(keys, "profiles."+name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetFrom changes a setting like Set and records its source (e.g., "flag --log-level").
+func (c *Config) SetFrom(key, value, source string) error {
+	if err := c.Set(key, value); err != nil {
+		return err
+	}
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+	c.sources[key] = source
+	return nil
+}
+
+// Source returns where a setting came from: SourceDefault, SourceFile, "env <VARIABLE>" or the source given to SetFrom.
+func (c *Config) Source(key string) string {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	return SourceDefault
+}
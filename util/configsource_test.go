@@ -0,0 +1,148 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// touchConfig creates an empty config file at path, with its parent directories.
+func touchConfig(t *testing.T, path string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	t.Setenv("HOME", filepath.Join(dir, "home"))
+	t.Setenv(ConfigEnv, "")
+
+	find := func(flagPath string) (string, string) {
+		t.Helper()
+		path, origin, err := FindConfig(flagPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return path, origin
+	}
+
+	// Without any file only the defaults apply.
+	if path, origin := find(""); path != "" || origin != "" {
+		t.Errorf("no config: %q from %q", path, origin)
+	}
+
+	// Each source found shadows the ones after it: --config > $ARTHXRECON_CONFIG > user directory > working directory.
+	cwd := touchConfig(t, filepath.Join(dir, ConfigFilePath))
+	if path, origin := find(""); path != ConfigFilePath || origin != "working directory" {
+		t.Errorf("working directory: %q from %q (created %s)", path, origin, cwd)
+	}
+	userDir, err := os.UserConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := touchConfig(t, filepath.Join(userDir, "arthxrecon", "config.toml"))
+	if path, origin := find(""); path != user || origin != "user config directory" {
+		t.Errorf("user config directory: %q from %q", path, origin)
+	}
+	env := touchConfig(t, filepath.Join(dir, "env.toml"))
+	t.Setenv(ConfigEnv, env)
+	if path, origin := find(""); path != env || origin != "$"+ConfigEnv {
+		t.Errorf("environment: %q from %q", path, origin)
+	}
+	flag := touchConfig(t, filepath.Join(dir, "flag.toml"))
+	if path, origin := find(flag); path != flag || origin != "--config" {
+		t.Errorf("flag: %q from %q", path, origin)
+	}
+
+	// Explicit paths must exist, even when another file would be found.
+	if _, origin, err := FindConfig(filepath.Join(dir, "missing.toml")); err == nil || origin != "--config" || !strings.HasPrefix(err.Error(), "config file from --config: ") {
+		t.Errorf("missing --config: %v from %q", err, origin)
+	}
+	t.Setenv(ConfigEnv, filepath.Join(dir, "missing.toml"))
+	if _, _, err := FindConfig(""); err == nil || !strings.HasPrefix(err.Error(), "config file from $"+ConfigEnv+": ") {
+		t.Errorf("missing $%s: %v", ConfigEnv, err)
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	path := writeConfig(t, "[scan]\nmode = \"aggressive\"\ncategory = \"web\"\n")
+	t.Setenv(ConfigEnv, path) // Reserved: not a setting override
+	t.Setenv("ARTHXRECON_SCAN_MODE", "stealth")
+	t.Setenv("ARTHXRECON_LIMITS_MAX_RATE", "150")
+	t.Setenv("ARTHXRECON_AUDIT_ENABLED", "false")
+	t.Setenv("ARTHXRECON_MONITOR_INTERVAL", "30m")
+	t.Setenv("ARTHXRECON_CATEGORIES_LAB", "8000-8100")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Scan.Mode != "stealth" || cfg.Limits.MaxRate != 150 || cfg.Audit.Enabled || cfg.Monitor.Interval != "30m" || cfg.Categories["lab"] != "8000-8100" {
+		t.Errorf("overrides not applied: %+v", cfg)
+	}
+
+	// Each setting reports where its value came from.
+	if err := cfg.SetFrom("log.level", "debug", "flag --log-level"); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"scan.mode":        "env ARTHXRECON_SCAN_MODE",
+		"scan.category":    SourceFile,
+		"limits.max_rate":  "env ARTHXRECON_LIMITS_MAX_RATE",
+		"monitor.interval": "env ARTHXRECON_MONITOR_INTERVAL",
+		"categories.lab":   "env ARTHXRECON_CATEGORIES_LAB",
+		"categories.web":   SourceDefault,
+		"server.workers":   SourceDefault,
+		"log.level":        "flag --log-level",
+	} {
+		if got := cfg.Source(key); got != want {
+			t.Errorf("Source(%s) = %q, want %q", key, got, want)
+		}
+	}
+	if EnvName("distributed.lease_timeout") != "ARTHXRECON_DISTRIBUTED_LEASE_TIMEOUT" || EnvName("output.port-scan") != "ARTHXRECON_OUTPUT_PORT_SCAN" {
+		t.Errorf("EnvName: %s", EnvName("distributed.lease_timeout"))
+	}
+}
+
+func TestEnvOverridesRejected(t *testing.T) {
+	for name, tc := range map[string]struct {
+		env  map[string]string
+		want []string
+	}{
+		"integer": {map[string]string{"ARTHXRECON_LIMITS_MAX_RATE": "fast"},
+			[]string{"invalid environment overrides:\n", `  ARTHXRECON_LIMITS_MAX_RATE: limits.max_rate: invalid integer "fast"`}},
+		"boolean": {map[string]string{"ARTHXRECON_AUDIT_ENABLED": "maybe"},
+			[]string{`  ARTHXRECON_AUDIT_ENABLED: audit.enabled: invalid boolean "maybe"`}},
+		"unknown": {map[string]string{"ARTHXRECON_SCAN_TURBO": "1", "ARTHXRECON_MODES_": "-T5"},
+			[]string{"  ARTHXRECON_SCAN_TURBO: unknown setting override", "  ARTHXRECON_MODES_: unknown setting override"}},
+		// Durations are strings until validated, so they fail with the setting name.
+		"duration": {map[string]string{"ARTHXRECON_MONITOR_INTERVAL": "soon"},
+			[]string{"invalid config:\n", `  monitor.interval: invalid duration "soon"`}},
+		"mode": {map[string]string{"ARTHXRECON_SCAN_MODE": "turbo"},
+			[]string{`  scan.mode: unknown mode "turbo"`}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+			_, err := LoadConfig("")
+			if err == nil {
+				t.Fatal("accepted")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -16,13 +16,38 @@ var (
 
 	FatalErrHD         = "Host Discovery Failed!"
 	FatalErrPS         = "Port Scan Failed!"
+	FatalErrEnum       = "Enumeration Failed!"
+	FatalErrVuln       = "Vulnerability Analysis Failed!"
+	FatalErrFR         = "Full Recon Failed!"
+	FatalErrMonitor    = "Monitor Failed!"
+	FatalErrServe      = "API Server Failed!"
+	FatalErrCoord      = "Coordinator Failed!"
+	FatalErrAgent      = "Agent Failed!"
+	FatalErrConfig     = "Invalid configuration!"
 	FallbackConsoleMsg = "Failed to open log file, using console output" // FallbackConsoleMsg is the message used when the log file cannot be opened.
 	HDAppDescription   = "Executes host discovery using Nmap"
+	EnumAppDescription = "Runs enumeration modules against the port scan results"
+	VulnAppDescription = "Matches identified services against the local CVE feed"
+
+	FullReconAppDescription = "Runs the full pipeline: host discovery, port scan, enumeration and vulnerability analysis"
+	ConfigAppDescription    = "Inspects the effective configuration and where each setting comes from"
+	AuditAppDescription     = "Exports and verifies the append-only audit log of the traffic generated"
+	MonitorAppDescription   = "Repeats host discovery and port scan on an interval and reports the changes between runs"
+	ServeAppDescription     = "Serves a REST API to submit scan jobs, follow their progress and fetch their results"
+	CoordAppDescription     = "Splits host discovery and port scan into chunks run by remote agents, by the subnets each one reaches"
+	AgentAppDescription     = "Runs host discovery and port scan chunks from a coordinator inside a network segment"
 
 	//CONST
 	DefaultTimeFormat     = zerolog.TimeFormatUnix // DefaultTimeFormat defines the default time field format for Zerolog.
-	ConfigFilePath        = "config/config.toml"   // ConfigFilePath is the path to the configuration file.
+	ConfigFilePath        = "config/config.toml"   // ConfigFilePath is the configuration file looked up in the working directory.
 	HostDiscoveryName     = "hostDiscovery"
 	PortScanName          = "portScan"
+	EnumerationName       = "enumeration"
+	VulnAnalysisName      = "vulnAnalysis"
+	FindingsName          = "findings"
+	MonitorName           = "monitor"
+	FindingsPath          = "findings/findings.json" // FindingsPath is the registry shared by all modules across runs.
+	VulnFeedPath          = "config/cve-feed.json"   // VulnFeedPath is the local vulnerability feed used by vulnanalysis.
+	PluginsDir            = "plugins"                // PluginsDir holds the manifests of external enumeration plugins.
 	HostDiscoveryFlagNmap = "-PS22,2222,53,80,443,445,3389"
 )
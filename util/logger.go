@@ -1,64 +1,76 @@
 package util
 
 import (
+	"crypto/rand"
 	"fmt"
+	"io"
 	"os"
 	"time"
 
-	"github.com/BurntSushi/toml"
 	"github.com/fatih/color"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
-// Config holds the logger configuration read from the TOML file.
-type Config struct {
-	LogFile string `toml:"log_file"` // Path to the log file
-	Verbose bool   `toml:"verbose"`  // Verbose mode flag (if true, logs also go to console in friendly format)
+// RunID identifies the current execution; it is attached to every log event as "run_id".
+var RunID = NewUUID()
+
+// NewUUID returns a random (version 4) UUID.
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
 }
 
-// InitializeLogger configures the global logger using Zerolog.
-// It reads the configuration from the TOML file at ConfigFilePath.
-func InitializeLogger() {
-	// Open the TOML configuration file.
-	configFile, err := os.Open(ConfigFilePath)
-	if err != nil {
-		// Fallback: use console output if the configuration file cannot be read.
-		zerolog.TimeFieldFormat = DefaultTimeFormat
-		log.Logger = log.Output(os.Stderr)
-		return
+// LogLevel returns the configured log level: log_level, or debug when verbose and info otherwise.
+func (c *Config) LogLevel() (zerolog.Level, error) {
+	if c.Log.Level == "" {
+		if c.Verbose {
+			return zerolog.DebugLevel, nil
+		}
+		return zerolog.InfoLevel, nil
+	}
+	level, err := zerolog.ParseLevel(c.Log.Level)
+	if err != nil || level == zerolog.NoLevel {
+		return zerolog.NoLevel, fmt.Errorf("invalid log level %q (expected trace, debug, info, warn, error, fatal or disabled)", c.Log.Level)
 	}
-	defer configFile.Close()
+	return level, nil
+}
+
+// InitializeLogger configures the global logger using Zerolog.
+// It uses the log settings of the configuration loaded by LoadConfig.
+func InitializeLogger(cfg *Config) {
+	// O nível já foi validado em Config.Validate.
+	level, _ := cfg.LogLevel()
+	zerolog.SetGlobalLevel(level)
 
-	// Decode the TOML configuration.
-	var cfg Config
-	decoder := toml.NewDecoder(configFile)
-	if _, err := decoder.Decode(&cfg); err != nil {
+	if cfg.LogFile == "" {
+		// No log file: use console output only.
 		zerolog.TimeFieldFormat = DefaultTimeFormat
-		log.Logger = log.Output(os.Stderr)
+		log.Logger = log.Output(os.Stderr).With().Str("run_id", RunID).Logger()
 		return
 	}
 
 	// Define um formato de tempo mais legível para o Zerolog.
 	zerolog.TimeFieldFormat = time.RFC3339
 
-	// Define o nível global de log com base no verbose.
-	if cfg.Verbose {
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	} else {
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	}
-
-	// Abre ou cria o arquivo de log definido no TOML.
-	logFile, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	// Abre ou cria o arquivo de log definido no TOML, com rotação por tamanho e idade.
+	// Os limites já foram validados em Config.Validate.
+	maxAge, _ := cfg.Log.maxAge()
+	logFile, err := OpenRotatingFile(cfg.LogFile, int64(cfg.Log.MaxSize)<<20, maxAge, cfg.Log.MaxBackups)
 	if err != nil {
+		log.Logger = log.Output(os.Stderr).With().Str("run_id", RunID).Logger()
 		log.Error().Err(err).Msg(FallbackConsoleMsg)
-		log.Logger = log.Output(os.Stderr)
 		return
 	}
 
 	// Se verbose, criamos um ConsoleWriter para saída amigável no console
 	// e combinamos com o arquivo usando MultiLevelWriter.
+	var out io.Writer = logFile
 	if cfg.Verbose {
 		// Create a ConsoleWriter with fatih/color to format field names in cyan.
 		consoleWriter := zerolog.ConsoleWriter{
@@ -73,23 +85,32 @@ func InitializeLogger() {
 				// Você pode customizar o valor se desejar; aqui usamos o valor padrão.
 				return fmt.Sprintf("%s", i)
 			},
+			// O run_id fica apenas no arquivo, para não poluir o console.
+			FieldsExclude: []string{"run_id"},
 		}
-
 		// Combine a saída amigável do console com o arquivo de log.
-		multi := zerolog.MultiLevelWriter(consoleWriter, logFile)
-		log.Logger = zerolog.New(multi).
-			Level(zerolog.GlobalLevel()).
-			With().
-			Timestamp().
-			Logger()
-	} else {
-		// Modo não-verbose: somente log para o arquivo.
-		log.Logger = zerolog.New(logFile).
-			Level(zerolog.GlobalLevel()).
-			With().
-			Timestamp().
-			Logger()
+		out = zerolog.MultiLevelWriter(consoleWriter, logFile)
 	}
 
-	log.Debug().Msg("Logger initialized")
+	log.Logger = zerolog.New(out).
+		Level(level).
+		With().
+		Timestamp().
+		Str("run_id", RunID).
+		Logger()
+
+	log.Debug().Strs("args", os.Args).Msg("Logger initialized")
+}
+
+// StageLogger returns a sub-logger of the global logger for a pipeline stage
+// (e.g., "hostdiscovery", "portscan"), tagged with the "stage" field.
+func StageLogger(stage string) *zerolog.Logger {
+	logger := log.With().Str("stage", stage).Logger()
+	return &logger
+}
+
+// ModuleLogger returns a sub-logger for an enumeration module, tagged with the "stage" and "module" fields.
+func ModuleLogger(module string) *zerolog.Logger {
+	logger := log.With().Str("stage", "enumeration").Str("module", module).Logger()
+	return &logger
 }
@@ -0,0 +1,167 @@
+package util
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp added to the name of rotated log files.
+const backupTimeFormat = "20060102T150405.000"
+
+// RotatingFile is a log file that is rotated when it grows past MaxSize or when its oldest
+// entry is older than MaxAge. Rotated files are renamed to <name>-<timestamp><ext> and only the
+// newest MaxBackups are kept. Zero values disable the corresponding limit.
+type RotatingFile struct {
+	Path       string
+	MaxSize    int64         // Maximum size in bytes
+	MaxAge     time.Duration // Maximum age of the oldest entry
+	MaxBackups int           // Rotated files kept
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	started time.Time // Time of the first entry of the current file
+}
+
+// OpenRotatingFile opens (or creates) the log file, rotating it first if it is already over the limits.
+func OpenRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{Path: path, MaxSize: maxSize, MaxAge: maxAge, MaxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	if r.size > 0 && r.expired(0) {
+		if err := r.rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Write appends p to the log file, rotating it before the write when a limit would be exceeded.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size > 0 && r.expired(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size == 0 {
+		r.started = time.Now()
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current log file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// expired reports whether writing n more bytes exceeds the size limit or the file is too old.
+func (r *RotatingFile) expired(n int64) bool {
+	if r.MaxSize > 0 && r.size+n > r.MaxSize {
+		return true
+	}
+	return r.MaxAge > 0 && time.Since(r.started) > r.MaxAge
+}
+
+// open opens the log file for appending and finds its size and the time of its first entry.
+func (r *RotatingFile) open() error {
+	if dir := filepath.Dir(r.Path); dir != "." {
+		if err := EnsureDir(dir); err != nil {
+			return fmt.Errorf("failed to create log directory %s: %w", dir, err)
+		}
+	}
+	file, err := os.OpenFile(r.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size, r.started = file, info.Size(), firstEntryTime(r.Path, info.ModTime())
+	return nil
+}
+
+// rotate renames the current file to a timestamped backup, opens a new one and prunes old backups.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	ext := filepath.Ext(r.Path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(r.Path, ext), time.Now().Format(backupTimeFormat), ext)
+	if err := os.Rename(r.Path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.prune()
+	return nil
+}
+
+// prune removes the oldest backups beyond MaxBackups.
+func (r *RotatingFile) prune() {
+	if r.MaxBackups <= 0 {
+		return
+	}
+	ext := filepath.Ext(r.Path)
+	base := strings.TrimSuffix(r.Path, ext)
+	matches, err := filepath.Glob(base + "-*" + ext)
+	if err != nil {
+		return
+	}
+	// Only names with a rotation timestamp are backups: arthxrecon-old.log is left alone.
+	var backups []string
+	for _, name := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, base+"-"), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, name)
+		}
+	}
+	if len(backups) <= r.MaxBackups {
+		return
+	}
+	// The timestamp in the name sorts the backups from the oldest to the newest.
+	sort.Strings(backups)
+	for _, old := range backups[:len(backups)-r.MaxBackups] {
+		os.Remove(old)
+	}
+}
+
+// firstEntryTime reads the "time" field of the first JSON entry of a log file, falling back to
+// the given time when the file is empty or the entry cannot be parsed.
+func firstEntryTime(path string, fallback time.Time) time.Time {
+	file, err := os.Open(path)
+	if err != nil {
+		return fallback
+	}
+	defer file.Close()
+	line, err := bufio.NewReader(file).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return fallback
+	}
+	var entry struct {
+		Time string `json:"time"`
+	}
+	if json.Unmarshal(line, &entry) != nil {
+		return fallback
+	}
+	if t, err := time.Parse(time.RFC3339, entry.Time); err == nil {
+		return t
+	}
+	return fallback
+}
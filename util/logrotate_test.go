@@ -0,0 +1,176 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logFiles lists the names in dir, sorted.
+func logFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isBackup reports whether name is a rotated copy of arthxrecon.log.
+func isBackup(name string) bool {
+	stamp, ok := strings.CutPrefix(strings.TrimSuffix(name, ".log"), "arthxrecon-")
+	if !ok {
+		return false
+	}
+	_, err := time.Parse(backupTimeFormat, stamp)
+	return err == nil
+}
+
+func TestRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "arthxrecon.log")
+	r, err := OpenRotatingFile(path, 50, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	line := []byte(strings.Repeat("x", 29) + "\n")
+	for i := 0; i < 2; i++ {
+		if _, err := r.Write(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The second line does not fit: the first one went to a backup before it was written.
+	names := logFiles(t, dir)
+	if len(names) != 2 || !isBackup(names[0]) || names[1] != "arthxrecon.log" {
+		t.Fatalf("files after rotation: %v", names)
+	}
+	for _, name := range names {
+		if data, _ := os.ReadFile(filepath.Join(dir, name)); string(data) != string(line) {
+			t.Errorf("%s: %q", name, data)
+		}
+	}
+
+	// A line larger than the limit is still written to an empty file.
+	big := []byte(strings.Repeat("y", 80) + "\n")
+	if _, err := r.Write(big); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(big) {
+		t.Errorf("current file: %q", data)
+	}
+}
+
+func TestRotateByAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "arthxrecon.log")
+
+	// A file whose first entry is older than MaxAge is rotated when opened.
+	old := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	if err := os.WriteFile(path, []byte(`{"level":"info","time":"`+old+`"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenRotatingFile(path, 0, time.Hour, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if names := logFiles(t, dir); len(names) != 2 || !isBackup(names[0]) {
+		t.Fatalf("old file not rotated on open: %v", names)
+	}
+
+	// A recent first entry keeps the file; once it ages, the next write rotates it.
+	if _, err := r.Write([]byte("{}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if names := logFiles(t, dir); len(names) != 2 {
+		t.Fatalf("recent file rotated: %v", names)
+	}
+	r.started = time.Now().Add(-61 * time.Minute)
+	time.Sleep(2 * time.Millisecond) // Distinct backup timestamps
+	if _, err := r.Write([]byte("{}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if names := logFiles(t, dir); len(names) != 3 {
+		t.Errorf("aged file not rotated on write: %v", names)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "arthxrecon.log")
+	start := time.Now().Add(-time.Hour)
+	var backups []string
+	for i := 0; i < 4; i++ {
+		name := "arthxrecon-" + start.Add(time.Duration(i)*time.Minute).Format(backupTimeFormat) + ".log"
+		backups = append(backups, name)
+		touchConfig(t, filepath.Join(dir, name))
+	}
+	// Files that only share the prefix are not backups and are never removed.
+	unrelated := []string{"arthxrecon-old.log", "arthxrecon-2024.log", "arthxrecon-20240101T000000.000.log.gz"}
+	for _, name := range unrelated {
+		touchConfig(t, filepath.Join(dir, name))
+	}
+
+	r, err := OpenRotatingFile(path, 10, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := r.Write([]byte("first line\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write([]byte("second line\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	names := logFiles(t, dir)
+	var kept []string
+	for _, name := range names {
+		if isBackup(name) {
+			kept = append(kept, name)
+		}
+	}
+	// The two newest backups are kept: the last existing one and the file just rotated.
+	if len(kept) != 2 || kept[0] != backups[3] {
+		t.Errorf("backups kept: %v (all files %v)", kept, names)
+	}
+	for _, name := range unrelated {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("unrelated file %s removed", name)
+		}
+	}
+}
+
+func TestFirstEntryTime(t *testing.T) {
+	dir := t.TempDir()
+	fallback := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for content, want := range map[string]time.Time{
+		`{"level":"info","time":"2024-03-02T10:20:30Z","message":"a"}` + "\n" + `{"time":"2024-04-01T00:00:00Z"}` + "\n": time.Date(2024, 3, 2, 10, 20, 30, 0, time.UTC),
+		`{"time":"2024-03-02T10:20:30-03:00"}`:  time.Date(2024, 3, 2, 13, 20, 30, 0, time.UTC),
+		"":                                      fallback,
+		"plain text log\n":                      fallback,
+		`{"time":"yesterday"}` + "\n":           fallback,
+		`{"level":"info","message":"x"}` + "\n": fallback,
+	} {
+		path := filepath.Join(dir, "arthxrecon.log")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := firstEntryTime(path, fallback); !got.Equal(want) {
+			t.Errorf("firstEntryTime(%q) = %v, want %v", content, got, want)
+		}
+	}
+	if got := firstEntryTime(filepath.Join(dir, "missing.log"), fallback); !got.Equal(fallback) {
+		t.Errorf("missing file: %v", got)
+	}
+}
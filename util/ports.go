@@ -0,0 +1,42 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePortRange parses a port ("80") or a port range ("8000-8100") within 1-65535.
+func ParsePortRange(token string) (int, int, error) {
+	start, end, isRange := strings.Cut(token, "-")
+	if !isRange {
+		end = start
+	}
+	first, err1 := strconv.Atoi(strings.TrimSpace(start))
+	last, err2 := strconv.Atoi(strings.TrimSpace(end))
+	if err1 != nil || err2 != nil || first < 1 || last > 65535 || first > last {
+		return 0, 0, fmt.Errorf("invalid port %q", token)
+	}
+	return first, last, nil
+}
+
+// ValidatePortSpec checks a port list in Nmap syntax (e.g., "22,80-90,U:53,161").
+func ValidatePortSpec(spec string) error {
+	if strings.TrimSpace(spec) == "" {
+		return errors.New("empty port list")
+	}
+	for _, token := range strings.Split(spec, ",") {
+		token = strings.TrimSpace(token)
+		if len(token) >= 2 && (strings.EqualFold(token[:2], "U:") || strings.EqualFold(token[:2], "T:")) {
+			token = token[2:]
+		}
+		if token == "" {
+			return errors.New("empty port in list")
+		}
+		if _, _, err := ParsePortRange(token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
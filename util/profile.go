@@ -0,0 +1,202 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Engines lists the host discovery engines accepted by profiles and the --engine flag.
+var Engines = []string{"nmap", "masscan"}
+
+// Profile is a named set of scan options selected with --profile.
+// Unset fields are inherited from the parent profile; flags given on the command line take precedence.
+type Profile struct {
+	Inherits    string   `toml:"inherits,omitempty"`    // Parent profile
+	Description string   `toml:"description,omitempty"` // Short description shown by "profiles list"
+	Engine      string   `toml:"engine,omitempty"`      // Host discovery engine: nmap or masscan
+	Mode        string   `toml:"mode,omitempty"`        // Scan mode (a key of [modes])
+	Category    string   `toml:"category,omitempty"`    // Port categories, separated by commas
+	Simple      *bool    `toml:"simple,omitempty"`      // Simple port scan (-sS) instead of -sV -sC
+	Options     []string `toml:"options,omitempty"`     // Extra Nmap options for the port scan
+	Threads     *int     `toml:"threads,omitempty"`     // Concurrent enumeration module runs
+	Modules     []string `toml:"modules,omitempty"`     // Enabled enumeration modules (empty enables all)
+	MaxRate     *int     `toml:"max_rate,omitempty"`    // Maximum packets per second sent by the scanners (0 is unlimited)
+}
+
+// builtinProfiles returns the profiles shipped with the application.
+func builtinProfiles() map[string]Profile {
+	yes, no := true, false
+	fastThreads, stealthThreads := 50, 5
+	stealthRate := 50
+	return map[string]Profile{
+		"internal-fast": {
+			Description: "Quick sweep of internal networks: aggressive timing, common internal services, SYN scan",
+			Engine:      "nmap",
+			Mode:        "aggressive",
+			Category:    "top12,windows,database,web",
+			Simple:      &yes,
+			Threads:     &fastThreads,
+		},
+		"external-stealth": {
+			Description: "Low-noise scan of internet-facing hosts: slow timing, rate limited, exposed services only",
+			Engine:      "nmap",
+			Mode:        "stealth",
+			Category:    "top12,web,vpn,firewall",
+			Simple:      &no,
+			Options:     []string{"-Pn", "--max-retries", "2"},
+			Threads:     &stealthThreads,
+			Modules:     []string{"banner", "ssh", "ftp", "dns"},
+			MaxRate:     &stealthRate,
+		},
+		"ctf": {
+			Inherits:    "internal-fast",
+			Description: "Single-target labs: every category with version detection and default scripts",
+			Category:    "all",
+			Simple:      &no,
+			Options:     []string{"-Pn"},
+		},
+	}
+}
+
+// merge returns the parent profile with the fields set in child applied on top.
+func (p Profile) merge(child Profile) Profile {
+	p.Inherits = child.Inherits
+	p.Description = child.Description
+	if child.Engine != "" {
+		p.Engine = child.Engine
+	}
+	if child.Mode != "" {
+		p.Mode = child.Mode
+	}
+	if child.Category != "" {
+		p.Category = child.Category
+	}
+	if child.Simple != nil {
+		p.Simple = child.Simple
+	}
+	if child.Options != nil {
+		p.Options = child.Options
+	}
+	if child.Threads != nil {
+		p.Threads = child.Threads
+	}
+	if child.Modules != nil {
+		p.Modules = child.Modules
+	}
+	if child.MaxRate != nil {
+		p.MaxRate = child.MaxRate
+	}
+	return p
+}
+
+// ProfileNames returns the configured profiles, sorted.
+func (c *Config) ProfileNames() []string {
+	var names []string
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveProfile returns the profile with the options inherited from its parents, and the
+// inheritance chain starting at the profile itself.
+func (c *Config) ResolveProfile(name string) (Profile, []string, error) {
+	var (
+		chain   []string
+		visited = make(map[string]bool)
+	)
+	for current := name; current != ""; {
+		if visited[current] {
+			return Profile{}, nil, fmt.Errorf("profile %q: inheritance cycle (%s -> %s)", name, strings.Join(chain, " -> "), current)
+		}
+		profile, ok := c.Profiles[current]
+		if !ok {
+			if current == name {
+				return Profile{}, nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+			}
+			return Profile{}, nil, fmt.Errorf("profile %q inherits from unknown profile %q", chain[len(chain)-1], current)
+		}
+		visited[current] = true
+		chain = append(chain, current)
+		current = profile.Inherits
+	}
+
+	var resolved Profile
+	for i := len(chain) - 1; i >= 0; i-- {
+		resolved = resolved.merge(c.Profiles[chain[i]])
+	}
+	return resolved, chain, nil
+}
+
+// validateProfile checks the resolved options of a profile.
+func (c *Config) validateProfile(p Profile) []string {
+	var problems []string
+	if p.Engine != "" && !containsString(Engines, p.Engine) {
+		problems = append(problems, fmt.Sprintf("unknown engine %q (available: %s)", p.Engine, strings.Join(Engines, ", ")))
+	}
+	if p.Mode != "" {
+		if _, err := c.TimingOptions(p.Mode); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	for _, category := range strings.Split(p.Category, ",") {
+		if category = strings.TrimSpace(strings.ToLower(category)); category != "" && category != "all" {
+			if !c.HasCategory(category) {
+				problems = append(problems, fmt.Sprintf("unknown category %q", category))
+			}
+		}
+	}
+	if p.Threads != nil && *p.Threads <= 0 {
+		problems = append(problems, fmt.Sprintf("threads must be positive, got %d", *p.Threads))
+	}
+	if p.MaxRate != nil && *p.MaxRate < 0 {
+		problems = append(problems, fmt.Sprintf("max_rate must not be negative, got %d", *p.MaxRate))
+	}
+	return problems
+}
+
+// Lookup returns a resolved option by its key (engine, mode, category, simple, options,
+// threads, modules or max_rate), formatted as the corresponding flag value. Unset options return ok=false.
+func (p Profile) Lookup(key string) (string, bool) {
+	switch key {
+	case "engine":
+		return p.Engine, p.Engine != ""
+	case "mode":
+		return p.Mode, p.Mode != ""
+	case "category":
+		return p.Category, p.Category != ""
+	case "simple":
+		if p.Simple != nil {
+			return strconv.FormatBool(*p.Simple), true
+		}
+	case "options":
+		return strings.Join(p.Options, " "), p.Options != nil
+	case "threads":
+		if p.Threads != nil {
+			return strconv.Itoa(*p.Threads), true
+		}
+	case "modules":
+		return strings.Join(p.Modules, ","), p.Modules != nil
+	case "max_rate":
+		if p.MaxRate != nil {
+			return strconv.Itoa(*p.MaxRate), true
+		}
+	}
+	return "", false
+}
+
+// ProfileKeys lists the options of a profile, in display order.
+var ProfileKeys = []string{"engine", "mode", "category", "simple", "options", "threads", "modules", "max_rate"}
+
+// containsString reports whether list contains value.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
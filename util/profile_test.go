@@ -0,0 +1,92 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveProfile(t *testing.T) {
+	yes, no := true, false
+	rate, threads := 20, 8
+	cfg := DefaultConfig()
+	cfg.Profiles["base"] = Profile{Description: "base", Engine: "masscan", Mode: "stealth", Simple: &yes, MaxRate: &rate, Threads: &threads, Options: []string{"-Pn"}}
+	cfg.Profiles["middle"] = Profile{Inherits: "base", Description: "middle", Category: "web", Simple: &no}
+	cfg.Profiles["leaf"] = Profile{Inherits: "middle", Description: "leaf", Mode: "aggressive", Options: []string{}}
+
+	profile, chain, err := cfg.ResolveProfile("leaf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(chain, []string{"leaf", "middle", "base"}) {
+		t.Errorf("chain = %v", chain)
+	}
+	// The nearest profile wins; unset fields are inherited, and an empty list still overrides.
+	if profile.Description != "leaf" || profile.Inherits != "middle" || profile.Engine != "masscan" || profile.Mode != "aggressive" ||
+		profile.Category != "web" || profile.Options == nil || len(profile.Options) != 0 {
+		t.Errorf("resolved profile: %+v", profile)
+	}
+	// Pointer fields tell "unset" from false or zero.
+	if profile.Simple == nil || *profile.Simple || profile.MaxRate == nil || *profile.MaxRate != 20 || profile.Threads == nil || *profile.Threads != 8 {
+		t.Errorf("pointer fields: simple %v, max_rate %v, threads %v", profile.Simple, profile.MaxRate, profile.Threads)
+	}
+	for key, want := range map[string]string{"simple": "false", "max_rate": "20", "threads": "8", "options": "", "engine": "masscan"} {
+		if got, ok := profile.Lookup(key); !ok || got != want {
+			t.Errorf("Lookup(%s) = %q, %v", key, got, ok)
+		}
+	}
+	if _, ok := profile.Lookup("modules"); ok {
+		t.Error("unset modules reported as set")
+	}
+
+	zero := 0
+	cfg.Profiles["unlimited"] = Profile{Inherits: "base", MaxRate: &zero}
+	if profile, _, _ := cfg.ResolveProfile("unlimited"); profile.MaxRate == nil || *profile.MaxRate != 0 || !*profile.Simple {
+		t.Errorf("zero max_rate not kept: %+v", profile)
+	}
+
+	for name, tc := range map[string]struct {
+		profiles map[string]Profile
+		resolve  string
+		want     string
+	}{
+		"cycle": {map[string]Profile{"a": {Inherits: "b"}, "b": {Inherits: "c"}, "c": {Inherits: "a"}}, "a",
+			`profile "a": inheritance cycle (a -> b -> c -> a)`},
+		"self": {map[string]Profile{"a": {Inherits: "a"}}, "a", `profile "a": inheritance cycle (a -> a)`},
+		"missing parent": {map[string]Profile{"a": {Inherits: "b"}, "b": {Inherits: "gone"}}, "a",
+			`profile "b" inherits from unknown profile "gone"`},
+		"unknown": {map[string]Profile{"a": {}, "b": {}}, "c", `unknown profile "c" (available: a, b)`},
+	} {
+		c := DefaultConfig()
+		c.Profiles = tc.profiles
+		if _, _, err := c.ResolveProfile(tc.resolve); err == nil || err.Error() != tc.want {
+			t.Errorf("%s: error %v, want %q", name, err, tc.want)
+		}
+	}
+}
+
+func TestBuiltinProfiles(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, name := range cfg.ProfileNames() {
+		profile, _, err := cfg.ResolveProfile(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if problems := cfg.validateProfile(profile); problems != nil {
+			t.Errorf("%s: %s", name, strings.Join(problems, "; "))
+		}
+	}
+	ctf, chain, _ := cfg.ResolveProfile("ctf")
+	if !reflect.DeepEqual(chain, []string{"ctf", "internal-fast"}) || ctf.Mode != "aggressive" || ctf.Category != "all" || *ctf.Simple || *ctf.Threads != 50 {
+		t.Errorf("ctf: %v %+v", chain, ctf)
+	}
+
+	// Profiles are checked with the rest of the configuration.
+	threads := 0
+	cfg.Profiles["broken"] = Profile{Inherits: "ctf", Mode: "turbo", Threads: &threads}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), `profiles.broken: unknown scan mode "turbo"`) || !strings.Contains(err.Error(), "threads must be positive, got 0") {
+		t.Errorf("invalid profile: %v", err)
+	}
+}
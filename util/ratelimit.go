@@ -0,0 +1,112 @@
+package util
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces the probes of the native engines (connections, DNS queries, SNMP requests)
+// evenly, so that at most Rate of them start per second. A zero Rate is unlimited.
+type RateLimiter struct {
+	Rate int
+
+	parent *RateLimiter // Limiter shared with other jobs, also waited for by each probe
+	mu     sync.Mutex
+	next   time.Time // Earliest start of the next probe
+}
+
+// NewRateLimiter creates a limiter of rate probes per second (0 or less is unlimited).
+func NewRateLimiter(rate int) *RateLimiter {
+	if rate < 0 {
+		rate = 0
+	}
+	return &RateLimiter{Rate: rate}
+}
+
+// NewJobLimiter creates the limiter of one of several jobs run side by side (serve, agent, monitor):
+// rate probes per second for the job, as an additional cap on the global Limiter that the jobs
+// share, so that together they never exceed it. Rate is the tighter of both limits.
+func NewJobLimiter(rate int) *RateLimiter {
+	l := NewRateLimiter(rate)
+	l.parent = Limiter
+	if parent := l.parent.Rate; parent > 0 && (l.Rate == 0 || parent < l.Rate) {
+		l.Rate = parent
+	}
+	return l
+}
+
+// Wait blocks until the next probe may start or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.Rate <= 0 {
+		return ctx.Err()
+	}
+	if err := l.wait(ctx); err != nil {
+		return err
+	}
+	if l.parent != nil {
+		return l.parent.Wait(ctx)
+	}
+	return nil
+}
+
+// wait spaces the probes of l itself.
+func (l *RateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(time.Second / time.Duration(l.Rate))
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Limiter is the process-wide rate limiter of the native engines, set by SetMaxRate. Jobs that
+// run side by side (serve, agent) carry their own limiter in the context, chained to this one;
+// see NewJobLimiter and WithLimiter.
+var Limiter = NewRateLimiter(0)
+
+// limiterKey is the context key of the limiter set by WithLimiter.
+type limiterKey struct{}
+
+// WithLimiter returns a copy of ctx whose probes are spaced by l instead of the global Limiter.
+func WithLimiter(ctx context.Context, l *RateLimiter) context.Context {
+	return context.WithValue(ctx, limiterKey{}, l)
+}
+
+// LimiterFrom returns the limiter carried by ctx, or the global Limiter when there is none.
+func LimiterFrom(ctx context.Context) *RateLimiter {
+	if l, ok := ctx.Value(limiterKey{}).(*RateLimiter); ok && l != nil {
+		return l
+	}
+	return Limiter
+}
+
+// SetMaxRate sets the global rate of the native engines, in probes per second (0 is unlimited).
+// The external scanners receive the same limit as --max-rate (Nmap) or --rate (Masscan).
+func SetMaxRate(rate int) {
+	Limiter = NewRateLimiter(rate)
+}
+
+// DialContext waits for the limiter of ctx (see LimiterFrom) and then connects to address with dialer.
+func DialContext(ctx context.Context, dialer *net.Dialer, network, address string) (net.Conn, error) {
+	if err := LimiterFrom(ctx).Wait(ctx); err != nil {
+		return nil, err
+	}
+	return dialer.DialContext(ctx, network, address)
+}
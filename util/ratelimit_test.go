@@ -0,0 +1,72 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiterFrom(t *testing.T) {
+	if LimiterFrom(context.Background()) != Limiter {
+		t.Error("a context without a limiter does not use the global Limiter")
+	}
+	job := NewRateLimiter(5)
+	ctx := WithLimiter(context.Background(), job)
+	if LimiterFrom(ctx) != job {
+		t.Error("the limiter of the context is ignored")
+	}
+	if LimiterFrom(WithLimiter(ctx, nil)) != Limiter {
+		t.Error("a nil limiter does not fall back to the global Limiter")
+	}
+	if NewProbeBatch(ctx, "test", 1).Rate != 5 {
+		t.Error("the probe batch does not record the rate of the context")
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(20)
+	start := time.Now()
+	for range 3 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 probes at 20/s took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow := NewRateLimiter(1)
+	slow.Wait(context.Background())
+	if err := slow.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait on a cancelled context = %v", err)
+	}
+}
+
+func TestNewJobLimiter(t *testing.T) {
+	defer SetMaxRate(0)
+	SetMaxRate(20)
+	if l := NewJobLimiter(50); l.Rate != 20 {
+		t.Errorf("job rate above the global one = %d, want 20", l.Rate)
+	}
+	if l := NewJobLimiter(0); l.Rate != 20 {
+		t.Errorf("unlimited job rate = %d, want the global 20", l.Rate)
+	}
+
+	// Two jobs of 20/s sharing a global limit of 20/s get 20 probes per second together.
+	a, b := NewJobLimiter(20), NewJobLimiter(20)
+	start := time.Now()
+	for range 2 {
+		a.Wait(context.Background())
+		b.Wait(context.Background())
+	}
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("4 probes of two jobs at a global 20/s took %s", elapsed)
+	}
+
+	SetMaxRate(0)
+	if l := NewJobLimiter(5); l.Rate != 5 {
+		t.Errorf("job rate without a global limit = %d, want 5", l.Rate)
+	}
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// LoadScope interpreta o escopo autorizado: um arquivo (um IP/CIDR por linha) ou uma lista separada por vírgulas.
+func LoadScope(input string) ([]*net.IPNet, error) {
+	var entries []string
+	if info, err := os.Stat(input); err == nil && !info.IsDir() {
+		file, err := os.Open(input)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			entries = append(entries, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		entries = strings.Split(input, ",")
+	}
+
+	var scope []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope entry %q: %w", entry, err)
+		}
+		scope = append(scope, network)
+	}
+	return scope, nil
+}
+
+// InScope indica se o IP pertence a alguma das redes do escopo.
+func InScope(ip string, scope []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range scope {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendTargetsToFile adiciona os alvos ao arquivo (um por linha), ignorando os que já existem nele.
+// Retorna os alvos efetivamente adicionados.
+func AppendTargetsToFile(filePath string, targets []string) ([]string, error) {
+	existing := make(map[string]bool)
+	if data, err := os.ReadFile(filePath); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			existing[strings.TrimSpace(line)] = true
+		}
+	}
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var added []string
+	for _, target := range targets {
+		if existing[target] {
+			continue
+		}
+		if _, err := file.WriteString(target + "\n"); err != nil {
+			return added, err
+		}
+		existing[target] = true
+		added = append(added, target)
+	}
+	return added, nil
+}
+
+// SubnetInScope indica se a rede (CIDR) está inteiramente contida em alguma rede do escopo.
+func SubnetInScope(subnet string, scope []*net.IPNet) bool {
+	_, network, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return false
+	}
+	bits, _ := network.Mask.Size()
+	for _, s := range scope {
+		scopeBits, _ := s.Mask.Size()
+		if s.Contains(network.IP) && scopeBits <= bits {
+			return true
+		}
+	}
+	return false
+}
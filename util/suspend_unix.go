@@ -0,0 +1,18 @@
+//go:build !windows
+
+package util
+
+import (
+	"os"
+	"syscall"
+)
+
+// suspendProcess stops the process (SIGSTOP) without terminating it.
+func suspendProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGSTOP)
+}
+
+// resumeProcess continues a process stopped by suspendProcess (SIGCONT).
+func resumeProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGCONT)
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package util
+
+import (
+	"errors"
+	"os"
+)
+
+// errSuspendUnsupported is returned on Windows, where the process keeps running outside the scan window.
+var errSuspendUnsupported = errors.New("pausing external processes is not supported on Windows")
+
+// suspendProcess is not supported on Windows.
+func suspendProcess(p *os.Process) error {
+	return errSuspendUnsupported
+}
+
+// resumeProcess is not supported on Windows.
+func resumeProcess(p *os.Process) error {
+	return errSuspendUnsupported
+}
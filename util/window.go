@@ -0,0 +1,211 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ScanWindow is a set of daily time ranges in which scanning is allowed, e.g., "22:00-06:00"
+// or "12:00-13:00,22:00-06:00 utc". Ranges ending before they start cross midnight.
+type ScanWindow struct {
+	Spec     string
+	Location *time.Location
+
+	ranges [][2]int // Start and end, in minutes since midnight
+}
+
+// Window is the scan window of the current run; nil means scanning is always allowed.
+var Window *ScanWindow
+
+// ParseScanWindow parses a scan window: ranges "HH:MM-HH:MM" separated by commas, optionally
+// followed by "local" (default) or "utc". An empty spec returns nil (no window).
+func ParseScanWindow(spec string) (*ScanWindow, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	w := &ScanWindow{Spec: spec, Location: time.Local}
+	ranges := spec
+	if i := strings.LastIndex(spec, " "); i >= 0 {
+		switch strings.ToLower(spec[i+1:]) {
+		case "local":
+		case "utc":
+			w.Location = time.UTC
+		default:
+			return nil, fmt.Errorf("invalid scan window %q: unknown time zone %q (expected local or utc)", spec, spec[i+1:])
+		}
+		ranges = spec[:i]
+	}
+	for _, r := range strings.Split(ranges, ",") {
+		from, to, ok := strings.Cut(strings.TrimSpace(r), "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid scan window range %q (expected HH:MM-HH:MM)", r)
+		}
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, fmt.Errorf("invalid scan window range %q: empty range", r)
+		}
+		w.ranges = append(w.ranges, [2]int{start, end})
+	}
+	return w, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", strings.TrimSpace(s))
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Open reports whether t is inside the window.
+func (w *ScanWindow) Open(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	t = t.In(w.Location)
+	m := t.Hour()*60 + t.Minute()
+	for _, r := range w.ranges {
+		if r[0] < r[1] && m >= r[0] && m < r[1] || r[0] > r[1] && (m >= r[0] || m < r[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns when the window opens next: t itself when it is already open.
+func (w *ScanWindow) NextOpen(t time.Time) time.Time {
+	if w.Open(t) {
+		return t
+	}
+	var next time.Time
+	for _, r := range w.ranges {
+		if at := w.nextClock(t, r[0]); next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next
+}
+
+// NextClose returns when the window closes next, following adjacent ranges; t itself when it is closed.
+func (w *ScanWindow) NextClose(t time.Time) time.Time {
+	for i := 0; i < 2*len(w.ranges) && w.Open(t); i++ {
+		var next time.Time
+		for _, r := range w.ranges {
+			if at := w.nextClock(t, r[1]); next.IsZero() || at.Before(next) {
+				next = at
+			}
+		}
+		t = next
+	}
+	return t
+}
+
+// nextClock returns the first time after t at the given minutes since midnight.
+func (w *ScanWindow) nextClock(t time.Time, minutes int) time.Time {
+	t = t.In(w.Location)
+	at := time.Date(t.Year(), t.Month(), t.Day(), minutes/60, minutes%60, 0, 0, w.Location)
+	if !at.After(t) {
+		at = time.Date(t.Year(), t.Month(), t.Day()+1, minutes/60, minutes%60, 0, 0, w.Location)
+	}
+	return at
+}
+
+// String returns the window as configured.
+func (w *ScanWindow) String() string {
+	return w.Spec
+}
+
+var (
+	pauseMu     sync.Mutex
+	pausedUntil time.Time // Opening already announced, so concurrent scanners report a pause once
+)
+
+// WaitWindow blocks while the scan window is closed, until it opens or ctx is done.
+// The scanners call it before each host or job, so a pause never loses the work already done.
+func WaitWindow(ctx context.Context) {
+	for {
+		now := time.Now()
+		if Window.Open(now) {
+			return
+		}
+		next := Window.NextOpen(now)
+		announcePause(next)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// announcePause reports once that the scanners are paused until the window opens at next.
+func announcePause(next time.Time) {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	if pausedUntil.Equal(next) {
+		return
+	}
+	pausedUntil = next
+	fmt.Printf("%s Outside the scan window (%s), paused until %s\n", MarkerYellow, Window, Yellow(next.Local().Format("2006-01-02 15:04")))
+	log.Info().Str("window", Window.String()).Time("until", next).Msg("Outside the scan window, scanners paused")
+}
+
+// superviseWindow suspends the running process when the scan window closes and resumes it when
+// the window opens again, until done is closed.
+func superviseWindow(cmd *exec.Cmd, done <-chan struct{}) {
+	for {
+		closeAt := Window.NextClose(time.Now())
+		timer := time.NewTimer(time.Until(closeAt))
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		next := Window.NextOpen(time.Now())
+		if err := suspendProcess(cmd.Process); err != nil {
+			if errors.Is(err, os.ErrProcessDone) {
+				return
+			}
+			log.Warn().Err(err).Strs("cmdline", cmd.Args).Msg("Failed to pause the process outside the scan window")
+			return
+		}
+		fmt.Printf("%s Outside the scan window (%s), %s paused until %s\n", MarkerYellow, Window, cmd.Args[0], Yellow(next.Local().Format("2006-01-02 15:04")))
+		log.Info().Strs("cmdline", cmd.Args).Time("until", next).Msg("External process paused outside the scan window")
+
+		timer = time.NewTimer(time.Until(next))
+		select {
+		case <-done:
+			timer.Stop()
+			resumeProcess(cmd.Process)
+			return
+		case <-timer.C:
+		}
+		if err := resumeProcess(cmd.Process); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			log.Warn().Err(err).Strs("cmdline", cmd.Args).Msg("Failed to resume the process")
+			return
+		}
+		fmt.Printf("%s Scan window open, %s resumed\n", MarkerGreen, cmd.Args[0])
+		log.Info().Strs("cmdline", cmd.Args).Msg("External process resumed")
+	}
+}
@@ -0,0 +1,102 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// at returns a fixed UTC time on 2024-03-10 (days past the 10th are the following days).
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 3, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseScanWindow(t *testing.T) {
+	for spec, want := range map[string]*time.Location{
+		"22:00-06:00":                  time.Local,
+		"22:00-06:00 local":            time.Local,
+		"22:00-06:00 utc":              time.UTC,
+		" 22:00-06:00 UTC ":            time.UTC,
+		"12:00-13:00, 22:00-06:00 utc": time.UTC,
+	} {
+		w, err := ParseScanWindow(spec)
+		if err != nil {
+			t.Errorf("%q: %v", spec, err)
+			continue
+		}
+		if w.Location != want || w.String() != strings.TrimSpace(spec) {
+			t.Errorf("%q: location %v, spec %q", spec, w.Location, w)
+		}
+	}
+	if w, err := ParseScanWindow(" "); w != nil || err != nil {
+		t.Errorf("empty spec: %v, %v", w, err)
+	}
+
+	for spec, want := range map[string]string{
+		"22:00-06:00 cet":    `invalid scan window "22:00-06:00 cet": unknown time zone "cet" (expected local or utc)`,
+		"22:00":              `invalid scan window range "22:00" (expected HH:MM-HH:MM)`,
+		"24:00-06:00":        `invalid time "24:00" (expected HH:MM)`,
+		"22:00-6h":           `invalid time "6h" (expected HH:MM)`,
+		"08:00-08:00 utc":    `invalid scan window range "08:00-08:00": empty range`,
+		"12:00-13:00,,14:00": `invalid scan window range "" (expected HH:MM-HH:MM)`,
+	} {
+		if _, err := ParseScanWindow(spec); err == nil || err.Error() != want {
+			t.Errorf("%q: error %v, want %q", spec, err, want)
+		}
+	}
+}
+
+func TestScanWindowTimes(t *testing.T) {
+	for _, tc := range []struct {
+		spec      string
+		now       time.Time
+		open      bool
+		nextOpen  time.Time
+		nextClose time.Time
+	}{
+		// Range crossing midnight.
+		{"22:00-06:00 utc", at(10, 21, 59), false, at(10, 22, 0), at(10, 21, 59)},
+		{"22:00-06:00 utc", at(10, 22, 0), true, at(10, 22, 0), at(11, 6, 0)},
+		{"22:00-06:00 utc", at(10, 23, 59), true, at(10, 23, 59), at(11, 6, 0)},
+		{"22:00-06:00 utc", at(11, 0, 0), true, at(11, 0, 0), at(11, 6, 0)},
+		{"22:00-06:00 utc", at(11, 5, 59), true, at(11, 5, 59), at(11, 6, 0)},
+		{"22:00-06:00 utc", at(11, 6, 0), false, at(11, 22, 0), at(11, 6, 0)},
+		{"22:00-06:00 utc", at(11, 12, 0), false, at(11, 22, 0), at(11, 12, 0)},
+		// Range ending at midnight, and the earliest of several ranges.
+		{"20:00-00:00 utc", at(10, 23, 30), true, at(10, 23, 30), at(11, 0, 0)},
+		{"12:00-13:00,22:00-06:00 utc", at(10, 7, 0), false, at(10, 12, 0), at(10, 7, 0)},
+		{"12:00-13:00,22:00-06:00 utc", at(10, 12, 30), true, at(10, 12, 30), at(10, 13, 0)},
+		{"12:00-13:00,22:00-06:00 utc", at(10, 13, 0), false, at(10, 22, 0), at(10, 13, 0)},
+		// Adjacent ranges close at the end of the last one, across midnight.
+		{"22:00-00:00,00:00-06:00 utc", at(10, 23, 0), true, at(10, 23, 0), at(11, 6, 0)},
+		{"20:00-22:00,22:00-23:00 utc", at(10, 21, 0), true, at(10, 21, 0), at(10, 23, 0)},
+		{"20:00-22:00,21:00-02:00 utc", at(10, 20, 30), true, at(10, 20, 30), at(11, 2, 0)},
+	} {
+		w, err := ParseScanWindow(tc.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if open := w.Open(tc.now); open != tc.open {
+			t.Errorf("%s at %s: open %v", tc.spec, tc.now.Format("Jan 2 15:04"), open)
+		}
+		if next := w.NextOpen(tc.now); !next.Equal(tc.nextOpen) {
+			t.Errorf("%s at %s: opens %s, want %s", tc.spec, tc.now.Format("Jan 2 15:04"), next, tc.nextOpen)
+		}
+		if next := w.NextClose(tc.now); !next.Equal(tc.nextClose) {
+			t.Errorf("%s at %s: closes %s, want %s", tc.spec, tc.now.Format("Jan 2 15:04"), next, tc.nextClose)
+		}
+	}
+
+	// The utc suffix compares the clock in UTC whatever the zone of the time given.
+	w, _ := ParseScanWindow("22:00-06:00 utc")
+	brt := time.FixedZone("BRT", -3*60*60)
+	if now := time.Date(2024, 3, 10, 20, 0, 0, 0, brt); !w.Open(now) || !w.NextClose(now).Equal(at(11, 6, 0)) {
+		t.Errorf("20:00 BRT (23:00 UTC): open %v, closes %s", w.Open(now), w.NextClose(now))
+	}
+
+	// Without a window scanning is always allowed.
+	var none *ScanWindow
+	if now := at(10, 3, 0); !none.Open(now) || !none.NextOpen(now).Equal(now) {
+		t.Error("nil window is closed")
+	}
+}